@@ -0,0 +1,140 @@
+package gosumo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Default limits used to split logs into batches. Sumo Logic recommends
+// keeping payloads under 1MB.
+const (
+	DefaultMaxBatchBytes = 1 << 20
+	DefaultMaxBatchLogs  = 0
+)
+
+// WithMaxBatchBytes sets the maximum size in bytes of the uncompressed payload
+// of a single request. Logs that exceed the limit are split across multiple
+// requests. A single log larger than the limit is sent on its own. A value of
+// 0 disables the limit.
+func WithMaxBatchBytes(n int) Option {
+	return func(c *Client) error {
+		if n < 0 {
+			return fmt.Errorf("max batch bytes cannot be negative, got: %d", n)
+		}
+		c.maxBatchBytes = n
+		return nil
+	}
+}
+
+// WithMaxBatchLogs sets the maximum number of logs in a single request. A
+// value of 0 disables the limit.
+func WithMaxBatchLogs(n int) Option {
+	return func(c *Client) error {
+		if n < 0 {
+			return fmt.Errorf("max batch logs cannot be negative, got: %d", n)
+		}
+		c.maxBatchLogs = n
+		return nil
+	}
+}
+
+// batcher accumulates lines into a newline delimited payload and posts it
+// whenever adding another line would exceed the Client's batch limits, so
+// only one batch is held in memory at a time. The payload buffer is reused
+// between batches.
+type batcher struct {
+	c   *Client
+	ctx context.Context
+	cfg postConfig
+
+	buf     *bytes.Buffer
+	count   int
+	batches int
+	errs    []error
+}
+
+// newBatcher creates a batcher that posts with the provided context and
+// settings. Its buffer comes from the pool and is returned by close.
+func (c *Client) newBatcher(ctx context.Context, cfg postConfig) *batcher {
+	return &batcher{c: c, ctx: ctx, cfg: cfg, buf: getBuffer()}
+}
+
+// add appends a line to the current batch, posting the batch first if the
+// line would not fit. Empty lines, such as logs discarded by the Client's
+// Sampler, are skipped. It returns false once the context is done.
+func (b *batcher) add(line []byte) bool {
+	if len(line) == 0 {
+		return b.ctx.Err() == nil
+	}
+	full := b.c.maxBatchLogs > 0 && b.count >= b.c.maxBatchLogs
+	if !full && b.c.maxBatchBytes > 0 && b.count > 0 {
+		full = b.buf.Len()+1+len(line) > b.c.maxBatchBytes
+	}
+	if full {
+		b.flush()
+	}
+	if b.count > 0 {
+		b.buf.WriteByte('\n')
+	}
+	b.buf.Write(line)
+	b.count++
+	return b.ctx.Err() == nil
+}
+
+// flush posts the current batch, if there is one, and records any error.
+func (b *batcher) flush() {
+	if b.count == 0 {
+		return
+	}
+	b.batches++
+	cfg := b.cfg
+	cfg.logs = b.count
+	if err := b.c.post(b.ctx, b.buf.Bytes(), cfg); err != nil {
+		b.errs = append(b.errs, fmt.Errorf("batch %d: %w", b.batches, err))
+	} else {
+		b.c.stats.logsSent.Add(uint64(b.count))
+	}
+	b.buf.Reset()
+	b.count = 0
+}
+
+// close posts the final batch and returns the combined error of every batch
+// that failed. A single failed batch is returned as-is. The batcher must not
+// be used afterwards.
+func (b *batcher) close() error {
+	b.flush()
+	putBuffer(b.buf)
+	b.buf = nil
+	if len(b.errs) == 0 {
+		return nil
+	}
+	if b.batches == 1 {
+		return errors.Unwrap(b.errs[0])
+	}
+	return ErrPostingLogs{
+		Message: fmt.Sprintf("%d of %d batches failed: %v", len(b.errs), b.batches, b.errs[0]),
+		Err:     errors.Join(b.errs...),
+	}
+}
+
+// discard returns the batcher's buffer to the pool without posting the
+// current batch. The batcher must not be used afterwards.
+func (b *batcher) discard() {
+	putBuffer(b.buf)
+	b.buf = nil
+}
+
+// postLines splits the lines into batches and posts each of them. Every batch
+// is attempted, unless the context is done, and the errors of any failed
+// batches are combined into the returned ErrPostingLogs.
+func (c *Client) postLines(ctx context.Context, lines [][]byte, cfg postConfig) error {
+	b := c.newBatcher(ctx, cfg)
+	for _, line := range lines {
+		if !b.add(line) {
+			break
+		}
+	}
+	return b.close()
+}
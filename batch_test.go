@@ -0,0 +1,64 @@
+package gosumo_test
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/byitkc/gosumo"
+)
+
+func TestPostBatching(t *testing.T) {
+	logs := []string{"one", "two", "three", "four", "five"}
+	tests := []struct {
+		name    string
+		opts    []gosumo.Option
+		batches [][]string
+	}{
+		{"single batch", nil, [][]string{logs}},
+		{"max logs", []gosumo.Option{gosumo.WithMaxBatchLogs(2)}, [][]string{{"one", "two"}, {"three", "four"}, {"five"}}},
+		// "one\ntwo\nthree" would be 13 bytes, while "three\nfour" is 10.
+		{"max bytes", []gosumo.Option{gosumo.WithMaxBatchBytes(10)}, [][]string{{"one", "two"}, {"three", "four"}, {"five"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := newCollector(t)
+			c, err := col.NewClient(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.PostLogsString(strings.Join(logs, "\n")); err != nil {
+				t.Fatal(err)
+			}
+			var batches [][]string
+			for _, r := range col.Requests() {
+				batches = append(batches, r.Lines())
+			}
+			if !slices.EqualFunc(batches, tt.batches, slices.Equal) {
+				t.Errorf("batches = %q, want %q", batches, tt.batches)
+			}
+			stats := c.Stats()
+			if stats.LogsSent != uint64(len(logs)) || stats.BatchesSent != uint64(len(tt.batches)) {
+				t.Errorf("Stats = %+v", stats)
+			}
+		})
+	}
+}
+
+func TestPostBatchingFailure(t *testing.T) {
+	col := newCollector(t)
+	col.RespondWithStatus(http.StatusOK, http.StatusBadRequest)
+	c, err := col.NewClient(gosumo.WithMaxBatchLogs(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.PostLogsString("one\ntwo\nthree")
+	if !errors.Is(err, gosumo.ErrClientError) || !strings.Contains(err.Error(), "batch 2") {
+		t.Fatalf("post error = %v, want the error of batch 2", err)
+	}
+	if got, want := col.Lines(), []string{"one", "two", "three"}; !slices.Equal(got, want) {
+		t.Errorf("collector received %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+package gosumo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without making a request while the Client's
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerConfig controls the Client's circuit breaker.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed posts that opens
+	// the circuit.
+	FailureThreshold int
+	// OpenDuration is how long the circuit stays open, failing fast, before
+	// probe requests are allowed through, one at a time.
+	OpenDuration time.Duration
+	// HalfOpenProbes is the number of consecutive successful probes needed
+	// to close the circuit again. A failed probe opens it immediately.
+	HalfOpenProbes int
+}
+
+// DefaultBreakerConfig is a reasonable BreakerConfig for posting to Sumo
+// Logic.
+var DefaultBreakerConfig = BreakerConfig{
+	FailureThreshold: 5,
+	OpenDuration:     30 * time.Second,
+	HalfOpenProbes:   1,
+}
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails every request with ErrCircuitOpen.
+	BreakerOpen
+	// BreakerHalfOpen lets one probe request through at a time to check
+	// whether the endpoint has recovered, failing the others with
+	// ErrCircuitOpen.
+	BreakerHalfOpen
+)
+
+// String returns the name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker that opens after a number of
+// consecutive failed posts, so an outage at Sumo Logic fails fast instead of
+// adding latency to the application. Only transport errors, throttling and
+// server errors count as failures, since a rejected request means the
+// endpoint is reachable. While the circuit is open a BufferedSender keeps its
+// logs buffered instead of dropping them.
+func WithCircuitBreaker(cfg BreakerConfig) Option {
+	return func(c *Client) error {
+		if cfg.FailureThreshold < 1 {
+			return fmt.Errorf("failure threshold must be at least 1, got: %d", cfg.FailureThreshold)
+		}
+		if cfg.OpenDuration <= 0 {
+			return fmt.Errorf("open duration must be greater than zero, got: %s", cfg.OpenDuration)
+		}
+		if cfg.HalfOpenProbes < 1 {
+			cfg.HalfOpenProbes = 1
+		}
+		c.breaker = &breaker{cfg: cfg}
+		return nil
+	}
+}
+
+// BreakerState returns the current state of the Client's circuit breaker. It
+// is always closed unless WithCircuitBreaker is used.
+func (c *Client) BreakerState() BreakerState {
+	if c.breaker == nil {
+		return BreakerClosed
+	}
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	return c.breaker.current(time.Now())
+}
+
+// breaker is a consecutive failure circuit breaker.
+type breaker struct {
+	cfg BreakerConfig
+
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	successes int
+	openedAt  time.Time
+	// probing is set while a half-open probe is in flight.
+	probing bool
+}
+
+// current returns the state at now, moving from open to half-open once the
+// open duration has passed. It must be called with the lock held.
+func (b *breaker) current(now time.Time) BreakerState {
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= b.cfg.OpenDuration {
+		b.state = BreakerHalfOpen
+		b.successes = 0
+	}
+	return b.state
+}
+
+// allow reports whether a request may be made, and whether it is the probe
+// of the half-open state, which must be ended by record or endProbe. Only one
+// probe is allowed at a time.
+func (b *breaker) allow(now time.Time) (ok, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.current(now) {
+	case BreakerOpen:
+		return false, false
+	case BreakerHalfOpen:
+		if b.probing {
+			return false, false
+		}
+		b.probing = true
+		return true, true
+	}
+	return true, false
+}
+
+// endProbe ends a probe without recording its result, such as when its
+// context was cancelled, so another probe can be made.
+func (b *breaker) endProbe() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// record records the result of a post, ending the probe if it was one. Nil
+// errors and errors that do not indicate an unhealthy endpoint count as
+// successes.
+func (b *breaker) record(now time.Time, err error, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		b.probing = false
+	}
+	state := b.current(now)
+	if err == nil || !isEndpointFailure(err) {
+		b.failures = 0
+		if state == BreakerHalfOpen {
+			b.successes++
+			if b.successes >= b.cfg.HalfOpenProbes {
+				b.state = BreakerClosed
+			}
+		}
+		return
+	}
+	b.failures++
+	if state == BreakerHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = now
+		b.failures = 0
+	}
+}
+
+// isEndpointFailure reports whether the error means the endpoint is
+// unhealthy, rather than the request being rejected.
+func isEndpointFailure(err error) bool {
+	if errors.Is(err, ErrClientError) {
+		return false
+	}
+	var httpErr HTTPError
+	return !errors.As(err, &httpErr) || httpErr.IsThrottled() || httpErr.IsServerError()
+}
@@ -0,0 +1,161 @@
+package gosumo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var (
+	errServer = HTTPError{StatusCode: http.StatusServiceUnavailable}
+	errClient = HTTPError{StatusCode: http.StatusBadRequest}
+)
+
+func TestBreaker(t *testing.T) {
+	start := time.Now()
+	b := &breaker{cfg: BreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute, HalfOpenProbes: 2}}
+	check := func(now time.Time, wantOK, wantProbe bool) {
+		t.Helper()
+		if ok, probe := b.allow(now); ok != wantOK || probe != wantProbe {
+			t.Fatalf("allow = %v, %v, want %v, %v", ok, probe, wantOK, wantProbe)
+		}
+	}
+	state := func(now time.Time, want BreakerState) {
+		t.Helper()
+		if got := b.current(now); got != want {
+			t.Fatalf("state = %s, want %s", got, want)
+		}
+	}
+
+	// Rejected requests do not count as failures.
+	b.record(start, errServer, false)
+	b.record(start, errClient, false)
+	b.record(start, errServer, false)
+	state(start, BreakerClosed)
+	b.record(start, errServer, false)
+	state(start, BreakerOpen)
+	check(start.Add(time.Second), false, false)
+
+	// Once the open duration has passed a single probe is let through.
+	halfOpen := start.Add(time.Minute)
+	check(halfOpen, true, true)
+	check(halfOpen, false, false)
+	b.record(halfOpen, nil, true)
+	state(halfOpen, BreakerHalfOpen)
+	check(halfOpen, true, true)
+	b.record(halfOpen, nil, true)
+	state(halfOpen, BreakerClosed)
+	check(halfOpen, true, false)
+
+	// A failed probe opens the circuit again.
+	b.record(halfOpen, errServer, false)
+	b.record(halfOpen, errServer, false)
+	halfOpen = halfOpen.Add(time.Minute)
+	check(halfOpen, true, true)
+	b.record(halfOpen, errors.New("connection refused"), true)
+	state(halfOpen, BreakerOpen)
+
+	// A probe that ends without a result lets another through.
+	halfOpen = halfOpen.Add(time.Minute)
+	check(halfOpen, true, true)
+	b.endProbe()
+	check(halfOpen, true, true)
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.URL, WithInsecureURL(), WithCircuitBreaker(BreakerConfig{FailureThreshold: 2, OpenDuration: time.Hour}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := c.PostLogsString("line"); !errors.Is(err, ErrServerError) {
+			t.Fatalf("post error = %v, want ErrServerError", err)
+		}
+	}
+	if got := c.BreakerState(); got != BreakerOpen {
+		t.Fatalf("BreakerState = %s, want open", got)
+	}
+	if err := c.PostLogsString("line"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("post error = %v, want ErrCircuitOpen", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server received %d requests, want 2", n)
+	}
+}
+
+func TestClientCircuitBreakerSingleProbe(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		// The probe is held until the other posts are rejected.
+		<-release
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.URL, WithInsecureURL(), WithCircuitBreaker(BreakerConfig{FailureThreshold: 1, OpenDuration: 10 * time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostLogsString("line"); !errors.Is(err, ErrServerError) {
+		t.Fatalf("post error = %v, want ErrServerError", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	probe := make(chan error, 1)
+	go func() { probe <- c.PostLogsString("probe") }()
+	for deadline := time.Now().Add(5 * time.Second); requests.Load() != 2; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			close(release)
+			t.Fatal("the probe was not sent")
+		}
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.PostLogsString("line")
+		}()
+	}
+	wg.Wait()
+	close(release)
+	for _, err := range errs {
+		if !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("post during the probe = %v, want ErrCircuitOpen", err)
+		}
+	}
+	if err := <-probe; err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+	if got := c.BreakerState(); got != BreakerClosed {
+		t.Errorf("BreakerState = %s, want closed", got)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server received %d requests, want 2", n)
+	}
+}
+
+func TestWithCircuitBreakerValidation(t *testing.T) {
+	for _, cfg := range []BreakerConfig{
+		{FailureThreshold: 0, OpenDuration: time.Second},
+		{FailureThreshold: 1},
+	} {
+		if _, err := NewClient("https://collectors.sumologic.com/receiver/v1/http/token", WithCircuitBreaker(cfg)); err == nil {
+			t.Errorf("WithCircuitBreaker(%+v) succeeded, want an error", cfg)
+		}
+	}
+}
@@ -0,0 +1,439 @@
+package gosumo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults used by the BufferedSender.
+const (
+	DefaultFlushInterval    = 5 * time.Second
+	DefaultFlushLogs        = 1000
+	DefaultFlushBytes       = 512 << 10
+	DefaultMaxBufferedBytes = 64 << 20
+)
+
+// BufferedSender accumulates logs in memory and posts them in the background
+// with a LogSender, usually a Client. Logs are flushed when the flush interval
+// elapses or when the number or size of buffered logs reaches the configured
+// thresholds, so callers never block on a request to Sumo Logic.
+type BufferedSender struct {
+	sender LogSender
+	opts   []PostOption
+
+	flushInterval    time.Duration
+	flushLogs        int
+	flushBytes       int
+	maxBuffered      int
+	maxBufferedBytes int
+	overflow         OverflowPolicy
+	onError          func(error)
+	spool            *spool
+	dedupe           *deduper
+
+	mu       sync.Mutex
+	space    *sync.Cond
+	buf      [][]byte
+	bufBytes int
+	closed   bool
+	dropped  atomic.Uint64
+
+	flushMu sync.Mutex
+	trigger chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	// ctx is used by the background flushes, and cancelled when the context
+	// passed to CloseContext is done before they complete.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// BufferOption configures a BufferedSender when it is created with
+// NewBufferedSender.
+type BufferOption func(*BufferedSender) error
+
+// WithFlushInterval sets how often buffered logs are flushed in the
+// background.
+func WithFlushInterval(d time.Duration) BufferOption {
+	return func(s *BufferedSender) error {
+		if d <= 0 {
+			return fmt.Errorf("flush interval must be greater than zero, got: %s", d)
+		}
+		s.flushInterval = d
+		return nil
+	}
+}
+
+// WithFlushLogs sets the number of buffered logs that triggers a flush. A
+// value of 0 disables the threshold.
+func WithFlushLogs(n int) BufferOption {
+	return func(s *BufferedSender) error {
+		if n < 0 {
+			return fmt.Errorf("flush logs cannot be negative, got: %d", n)
+		}
+		s.flushLogs = n
+		return nil
+	}
+}
+
+// WithFlushBytes sets the size in bytes of buffered logs that triggers a
+// flush. A value of 0 disables the threshold.
+func WithFlushBytes(n int) BufferOption {
+	return func(s *BufferedSender) error {
+		if n < 0 {
+			return fmt.Errorf("flush bytes cannot be negative, got: %d", n)
+		}
+		s.flushBytes = n
+		return nil
+	}
+}
+
+// WithMaxBufferedLogs caps the number of logs held in memory. When the buffer
+// is full it is spilled to the spool if one is configured with WithSpool,
+// otherwise the overflow policy is applied, see WithOverflowPolicy. A value
+// of 0, the default, leaves the number of logs unbounded.
+func WithMaxBufferedLogs(n int) BufferOption {
+	return func(s *BufferedSender) error {
+		if n < 0 {
+			return fmt.Errorf("max buffered logs cannot be negative, got: %d", n)
+		}
+		s.maxBuffered = n
+		return nil
+	}
+}
+
+// WithMaxBufferedBytes caps the size in bytes of the logs held in memory,
+// which is DefaultMaxBufferedBytes by default. The buffer is full when a new
+// log would take it over the cap, and is then handled as for
+// WithMaxBufferedLogs. Logs only build up while flushes cannot take them,
+// such as while a Client's circuit breaker is open. A value of 0 leaves the
+// size unbounded.
+func WithMaxBufferedBytes(n int) BufferOption {
+	return func(s *BufferedSender) error {
+		if n < 0 {
+			return fmt.Errorf("max buffered bytes cannot be negative, got: %d", n)
+		}
+		s.maxBufferedBytes = n
+		return nil
+	}
+}
+
+// WithErrorHandler sets a function that is called with the error of any
+// background flush that fails. By default these errors are discarded.
+func WithErrorHandler(fn func(error)) BufferOption {
+	return func(s *BufferedSender) error {
+		s.onError = fn
+		return nil
+	}
+}
+
+// WithPostOptions sets the PostOptions used for every flush, for example to
+// set the source category of the buffered logs.
+func WithPostOptions(opts ...PostOption) BufferOption {
+	return func(s *BufferedSender) error {
+		if c, ok := s.sender.(*Client); ok {
+			if _, err := c.buildPostConfig(opts); err != nil {
+				return err
+			}
+		}
+		s.opts = opts
+		return nil
+	}
+}
+
+// NewBufferedSender creates and returns a new BufferedSender that posts with
+// the provided LogSender, and starts flushing in the background. Close must be
+// called to stop the background flushing and post any remaining logs.
+func NewBufferedSender(sender LogSender, opts ...BufferOption) (*BufferedSender, error) {
+	if sender == nil {
+		return nil, ErrBuildingClient{Message: "unable to build buffered sender: sender cannot be nil"}
+	}
+	s := &BufferedSender{
+		sender:           sender,
+		flushInterval:    DefaultFlushInterval,
+		flushLogs:        DefaultFlushLogs,
+		flushBytes:       DefaultFlushBytes,
+		maxBufferedBytes: DefaultMaxBufferedBytes,
+		trigger:          make(chan struct{}, 1),
+		stop:             make(chan struct{}),
+		done:             make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, ErrBuildingClient{
+				Message: fmt.Sprintf("unable to build buffered sender: %v", err),
+				Err:     err,
+			}
+		}
+	}
+	s.space = sync.NewCond(&s.mu)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	go s.run()
+	return s, nil
+}
+
+// Send encodes the log and adds it to the buffer. When the sender is a Client
+// the log is encoded with its Serializer, otherwise as JSON. The log can be
+// anything accepted by PostLogs, and when encoded as JSON structs must have
+// JSON metadata on all of their fields unless the Client was created with
+// WithoutJSONValidation. The buffer is handled as for Enqueue.
+func (s *BufferedSender) Send(v any) error {
+	return s.send(context.Background(), v, s.flushInterval)
+}
+
+// SendContext is like Send but, with OverflowBlock, waits for room in the
+// buffer until the context is done, as for EnqueueContext.
+func (s *BufferedSender) SendContext(ctx context.Context, v any) error {
+	return s.send(ctx, v, 0)
+}
+
+// send implements Send and SendContext, see enqueue for ctx and maxWait.
+func (s *BufferedSender) send(ctx context.Context, v any, maxWait time.Duration) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	line, err := s.encode(v, buf)
+	if err != nil {
+		return ErrParsingLogs{
+			Message: fmt.Sprintf("error parsing log: %v", err),
+			Err:     err,
+		}
+	}
+	return s.enqueue(ctx, line, maxWait)
+}
+
+// encode implements the encoding for Send. The returned line may point into
+// buf.
+func (s *BufferedSender) encode(v any, buf *bytes.Buffer) ([]byte, error) {
+	c, ok := s.sender.(*Client)
+	if !ok {
+		return marshalLog(v, true)
+	}
+	if c.validatesJSON() {
+		if err := checkJSONMetadata(v); err != nil {
+			return nil, err
+		}
+	}
+	return c.newLogEncoder(buf).encode(v)
+}
+
+// Enqueue adds a single, already encoded, log line to the buffer. The line
+// should not contain a newline character. When the buffer is full and the
+// overflow policy is OverflowBlock, it waits for a flush to make room for at
+// most the flush interval, and then returns ErrBufferFull, since a flush that
+// could not make room by then, such as while a Client's circuit breaker is
+// open, is unlikely to do so soon. Use EnqueueContext to wait longer.
+func (s *BufferedSender) Enqueue(line []byte) error {
+	return s.enqueue(context.Background(), line, s.flushInterval)
+}
+
+// EnqueueContext is like Enqueue but, with OverflowBlock, waits for room in
+// the buffer until the context is done, returning ErrBufferFull wrapping the
+// error of the context.
+func (s *BufferedSender) EnqueueContext(ctx context.Context, line []byte) error {
+	return s.enqueue(ctx, line, 0)
+}
+
+// enqueue implements Enqueue and EnqueueContext. With OverflowBlock it waits
+// for room until ctx is done, or for at most maxWait unless it is 0.
+func (s *BufferedSender) enqueue(ctx context.Context, line []byte, maxWait time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSenderClosed
+	}
+	now := time.Now()
+	var key string
+	if s.dedupe != nil {
+		key = string(line)
+		if s.dedupe.collapse(key, now) {
+			return nil
+		}
+	}
+	if c, ok := s.sender.(*Client); ok {
+		if line = c.prepareLine(line, now); line == nil {
+			return nil
+		}
+	}
+	if ok, err := s.makeRoom(ctx, len(line)+1, maxWait); !ok {
+		return err
+	}
+	line = append([]byte(nil), line...)
+	s.buf = append(s.buf, line)
+	if s.dedupe != nil {
+		s.dedupe.record(key, len(s.buf)-1, now)
+	}
+	s.bufBytes += len(line) + 1
+	if (s.flushLogs > 0 && len(s.buf) >= s.flushLogs) || (s.flushBytes > 0 && s.bufBytes >= s.flushBytes) {
+		s.triggerFlush()
+	}
+	return nil
+}
+
+// triggerFlush asks the background goroutine to flush without waiting for
+// the flush interval.
+func (s *BufferedSender) triggerFlush() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Flush posts all buffered logs and waits for the requests to complete.
+func (s *BufferedSender) Flush() error {
+	return s.FlushContext(context.Background())
+}
+
+// FlushContext is like Flush but uses the provided context for the requests.
+// If the post fails because the endpoint is unavailable and a spool is
+// configured, the logs are written to the spool and no error is returned.
+func (s *BufferedSender) FlushContext(ctx context.Context) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	if c, ok := s.sender.(*Client); ok && c.BreakerState() == BreakerOpen {
+		if s.spool == nil {
+			return ErrPostingLogs{
+				Message: "error posting logs: circuit breaker is open, logs remain buffered",
+				Err:     ErrCircuitOpen,
+			}
+		}
+		return s.spill(ErrCircuitOpen)
+	}
+	lines := s.take()
+	if len(lines) == 0 {
+		return nil
+	}
+	payload := joinLines(lines)
+	err := s.post(ctx, payload)
+	if err != nil && s.spool != nil && isEndpointFailure(err) {
+		if spoolErr := s.spool.write(payload); spoolErr == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// take removes and returns every buffered log.
+func (s *BufferedSender) take() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dedupe != nil {
+		s.dedupe.apply(s.buf)
+	}
+	lines := s.buf
+	s.buf = nil
+	s.bufBytes = 0
+	s.space.Broadcast()
+	return lines
+}
+
+// spill writes the buffered logs to the spool because of cause. The logs stay
+// buffered if they cannot be written.
+func (s *BufferedSender) spill(cause error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.buf) == 0 {
+		return nil
+	}
+	if s.dedupe != nil {
+		s.dedupe.apply(s.buf)
+	}
+	if err := s.spool.write(joinLines(s.buf)); err != nil {
+		return ErrPostingLogs{
+			Message: fmt.Sprintf("error posting logs: %v, and spooling failed: %v", cause, err),
+			Err:     errors.Join(cause, err),
+		}
+	}
+	s.buf = nil
+	s.bufBytes = 0
+	s.space.Broadcast()
+	return nil
+}
+
+// replaySpool posts any spooled payloads, stopping at the first failure
+// that means the endpoint is unavailable. Payloads the endpoint rejects are
+// dropped, after a Client has passed them to its dead letter handler, see
+// WithDeadLetter. Nothing is replayed while the circuit breaker is open.
+func (s *BufferedSender) replaySpool(ctx context.Context) error {
+	if s.spool == nil {
+		return nil
+	}
+	if c, ok := s.sender.(*Client); ok && c.BreakerState() == BreakerOpen {
+		return nil
+	}
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	return s.spool.replay(func(payload []byte) error {
+		return s.post(ctx, payload)
+	})
+}
+
+// post sends a payload of logs that were prepared when they were enqueued.
+func (s *BufferedSender) post(ctx context.Context, payload []byte) error {
+	if c, ok := s.sender.(*Client); ok {
+		return c.sendPayload(ctx, payload, s.opts, false)
+	}
+	return s.sender.Send(ctx, payload, s.opts...)
+}
+
+// joinLines joins log lines into a newline delimited payload.
+func joinLines(lines [][]byte) []byte {
+	return bytes.Join(lines, []byte("\n"))
+}
+
+// Close stops the background flushing and posts any remaining logs. Logs
+// cannot be added once the BufferedSender is closed.
+func (s *BufferedSender) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// CloseContext is like Close but uses the provided context for the final
+// flush. A background flush that is still in progress, such as one waiting
+// to retry, is waited for until the context is done and then cancelled, so
+// CloseContext returns by the deadline of the context.
+func (s *BufferedSender) CloseContext(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.space.Broadcast()
+	s.mu.Unlock()
+	close(s.stop)
+	defer s.cancel()
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		s.cancel()
+		<-s.done
+	}
+	return s.FlushContext(ctx)
+}
+
+// run flushes the buffer in the background until the BufferedSender is
+// closed.
+func (s *BufferedSender) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.trigger:
+		}
+		err := s.FlushContext(s.ctx)
+		if err == nil {
+			err = s.replaySpool(s.ctx)
+		}
+		if err != nil && s.onError != nil {
+			s.onError(err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package gosumo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// funcSender is a LogSender that calls a function.
+type funcSender func(ctx context.Context, payload []byte) error
+
+func (f funcSender) Send(ctx context.Context, payload []byte, _ ...PostOption) error {
+	return f(ctx, payload)
+}
+
+func TestBufferedSenderCloseContextCancelsBackgroundFlush(t *testing.T) {
+	started := make(chan struct{})
+	var once sync.Once
+	sender := funcSender(func(ctx context.Context, payload []byte) error {
+		once.Do(func() { close(started) })
+		// Stands in for a flush that keeps retrying an unavailable endpoint.
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	var mu sync.Mutex
+	var flushErrs []error
+	s, err := NewBufferedSender(sender, WithFlushLogs(1), WithErrorHandler(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		flushErrs = append(flushErrs, err)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Enqueue([]byte("line")); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	s.CloseContext(ctx)
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("CloseContext took %s, want it to return once its context is done", d)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(flushErrs) != 1 || !errors.Is(flushErrs[0], context.Canceled) {
+		t.Errorf("background flush errors = %v, want the cancellation", flushErrs)
+	}
+}
@@ -0,0 +1,98 @@
+package gosumo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PostLogsChan will consume logs from the provided channel and post them in
+// batches using the provided Client until the channel is closed or the
+// context is done, so pipelines built on channels can send to Sumo Logic
+// directly. Logs are encoded as they are for PostLogs, except for []byte
+// values, which are posted as-is as already encoded lines.
+//
+// A batch is posted once it reaches the Client's batch limits, and any
+// pending logs are posted every DefaultFlushInterval. Logs that cannot be
+// encoded are skipped. The returned channel receives the combined error of
+// every failed batch and skipped log, or nil, once the last batch has been
+// posted, and is then closed.
+func PostLogsChan[T any](ctx context.Context, c *Client, logs <-chan T, opts ...PostOption) <-chan error {
+	done := make(chan error, 1)
+	cfg, err := c.newPostConfig(opts)
+	if err != nil {
+		done <- err
+		close(done)
+		return done
+	}
+	go func() {
+		defer close(done)
+		done <- consumeLogs(ctx, c, logs, cfg)
+	}()
+	return done
+}
+
+// consumeLogs implements PostLogsChan.
+func consumeLogs[T any](ctx context.Context, c *Client, logs <-chan T, cfg postConfig) error {
+	ticker := time.NewTicker(DefaultFlushInterval)
+	defer ticker.Stop()
+	b := c.newBatcher(ctx, cfg)
+	line := getBuffer()
+	defer putBuffer(line)
+	enc := c.newLogEncoder(line)
+	var parseErrs []error
+loop:
+	for {
+		var v T
+		var ok bool
+		select {
+		case v, ok = <-logs:
+			if !ok {
+				break loop
+			}
+		case <-ticker.C:
+			b.flush()
+			continue
+		case <-ctx.Done():
+			break loop
+		}
+		encoded, err := encodeChanLog(c, enc, v)
+		if err != nil {
+			parseErrs = append(parseErrs, err)
+			continue
+		}
+		if len(encoded) == 0 {
+			continue
+		}
+		if !b.add(c.prepareLine(encoded, time.Now())) {
+			break
+		}
+	}
+	postErr := b.close()
+	if len(parseErrs) == 0 {
+		return postErr
+	}
+	parseErr := ErrParsingLogs{
+		Message: fmt.Sprintf("skipped %d logs that could not be parsed: %v", len(parseErrs), parseErrs[0]),
+		Err:     errors.Join(parseErrs...),
+	}
+	if postErr == nil {
+		return parseErr
+	}
+	return errors.Join(postErr, parseErr)
+}
+
+// encodeChanLog returns the line for a log received by PostLogsChan. The
+// returned slice is only valid until the next call.
+func encodeChanLog(c *Client, enc *logEncoder, v any) ([]byte, error) {
+	if raw, ok := v.([]byte); ok {
+		return raw, nil
+	}
+	if c.validatesJSON() {
+		if err := checkJSONMetadata(v); err != nil {
+			return nil, err
+		}
+	}
+	return enc.encode(v)
+}
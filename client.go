@@ -0,0 +1,378 @@
+package gosumo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+// Client posts logs to a Sumo Logic HTTP source. It wraps a LogEndpoint along
+// with the http.Client that is used to send requests, so callers can control
+// timeouts, proxies and transports.
+type Client struct {
+	endpoint   LogEndpoint
+	urlPolicy  urlPolicy
+	httpClient *http.Client
+	transport  *transportConfig
+	timeout    time.Duration
+
+	compression        Compression
+	minCompressionSize int
+
+	retry            RetryPolicy
+	maxErrorBodySize int
+	isSuccess        func(statusCode int) bool
+
+	headers sourceHeaders
+	fields  map[string]string
+
+	dimensions map[string]string
+	metadata   map[string]string
+
+	maxSeries     int
+	onCardinality func(CardinalityWarning)
+
+	maxBatchBytes int
+	maxBatchLogs  int
+	timestamp     *timestampConfig
+	redactor      *redactor
+	sampler       Sampler
+
+	serializer         Serializer
+	skipJSONValidation bool
+
+	requestLimiter *rate.Limiter
+	byteLimiter    *rate.Limiter
+	throttle       *throttle
+	breaker        *breaker
+
+	stats      clientStats
+	observers  []func(AttemptInfo)
+	deadLetter func(payload []byte, err error)
+	tracer     trace.Tracer
+	logger     *slog.Logger
+
+	middleware []Middleware
+	roundTrip  RoundTripFunc
+}
+
+// Option configures a Client when it is created with NewClient.
+type Option func(*Client) error
+
+// WithHTTPClient sets the http.Client used to post logs. If it is not provided
+// http.DefaultClient is used.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) error {
+		if hc == nil {
+			return fmt.Errorf("http client cannot be nil")
+		}
+		c.httpClient = hc
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout for each request made by the Client. The
+// timeout is applied to a copy of the configured http.Client so a client
+// shared with the rest of the application is not modified.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return fmt.Errorf("timeout must be greater than zero, got: %s", d)
+		}
+		c.timeout = d
+		return nil
+	}
+}
+
+// DefaultMaxErrorBodySize is the number of bytes of an error response body
+// that are kept in an HTTPError.
+const DefaultMaxErrorBodySize = 1024
+
+// WithMaxErrorBodySize sets the number of bytes of an error response body that
+// are kept in an HTTPError and included in the error message. Longer bodies
+// are truncated, and a value of 0 discards the body.
+func WithMaxErrorBodySize(n int) Option {
+	return func(c *Client) error {
+		if n < 0 {
+			return fmt.Errorf("max error body size cannot be negative, got: %d", n)
+		}
+		c.maxErrorBodySize = n
+		return nil
+	}
+}
+
+// IsSuccessStatus reports whether the status code is in the 2xx range.
+func IsSuccessStatus(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
+
+// WithSuccessStatus sets the function used to decide whether a response
+// status code means the payload was accepted. By default any 2xx status code
+// is treated as success, see IsSuccessStatus.
+func WithSuccessStatus(fn func(statusCode int) bool) Option {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("success status function cannot be nil")
+		}
+		c.isSuccess = fn
+		return nil
+	}
+}
+
+// WithoutJSONValidation disables the check that logs are structs with JSON
+// metadata on every field, for callers that know their types encode
+// correctly and want to avoid the reflection.
+func WithoutJSONValidation() Option {
+	return func(c *Client) error {
+		c.skipJSONValidation = true
+		return nil
+	}
+}
+
+// NewClient creates and returns a new Client that posts to the provided
+// endpoint URL. Options are applied in order and an error is returned if the
+// URL or any of the options are invalid.
+func NewClient(endpointURL string, opts ...Option) (*Client, error) {
+	c := &Client{
+		httpClient:         http.DefaultClient,
+		minCompressionSize: DefaultMinCompressionSize,
+		retry:              noRetryPolicy,
+		maxErrorBodySize:   DefaultMaxErrorBodySize,
+		isSuccess:          IsSuccessStatus,
+		maxBatchBytes:      DefaultMaxBatchBytes,
+		maxBatchLogs:       DefaultMaxBatchLogs,
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, ErrBuildingClient{
+				Message: fmt.Sprintf("unable to build client: %v", err),
+				Err:     err,
+			}
+		}
+	}
+	e, err := newLogEndpoint(endpointURL, c.urlPolicy)
+	if err != nil {
+		return nil, err
+	}
+	c.endpoint = e
+	if c.transport != nil {
+		hc, err := c.transport.apply(c.httpClient)
+		if err != nil {
+			return nil, ErrBuildingClient{
+				Message: fmt.Sprintf("unable to build client: %v", err),
+				Err:     err,
+			}
+		}
+		c.httpClient = hc
+	}
+	if c.timeout > 0 {
+		hc := *c.httpClient
+		hc.Timeout = c.timeout
+		c.httpClient = &hc
+	}
+	c.roundTrip = c.buildRoundTrip()
+	return c, nil
+}
+
+// Endpoint returns the LogEndpoint the Client posts to.
+func (c *Client) Endpoint() LogEndpoint {
+	return c.endpoint
+}
+
+// post sends the payload to the Client's endpoint, compressing it first if
+// compression is enabled and the payload is large enough. Transient failures
+// are retried according to the Client's RetryPolicy. Any failure is returned
+// as an ErrPostingLogs.
+func (c *Client) post(ctx context.Context, payload []byte, cfg postConfig) error {
+	if err := c.tracedSend(ctx, payload, cfg); err != nil {
+		c.stats.failures.Add(1)
+		err = ErrPostingLogs{
+			Message: fmt.Sprintf("error posting logs: %v", err),
+			Err:     err,
+		}
+		c.sendDeadLetter(payload, err)
+		return err
+	}
+	return nil
+}
+
+// send implements post, returning the underlying error and recording the
+// outcome in res. When the circuit breaker is enabled the result is recorded
+// against it.
+func (c *Client) send(ctx context.Context, payload []byte, cfg postConfig, res *postResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.breaker == nil {
+		return c.sendWithRetry(ctx, payload, cfg, res)
+	}
+	ok, probe := c.breaker.allow(time.Now())
+	if !ok {
+		return ErrCircuitOpen
+	}
+	err := c.sendWithRetry(ctx, payload, cfg, res)
+	if ctx.Err() == nil {
+		c.breaker.record(time.Now(), err, probe)
+	} else if probe {
+		c.breaker.endProbe()
+	}
+	return err
+}
+
+// sendWithRetry compresses the payload and makes attempts until one succeeds,
+// the error is not retryable or the attempts are exhausted.
+func (c *Client) sendWithRetry(ctx context.Context, payload []byte, cfg postConfig, res *postResult) error {
+	encoding := ""
+	if c.compression != NoCompression && len(payload) >= c.minCompressionSize {
+		compressed, err := compress(c.compression, payload)
+		if err != nil {
+			return fmt.Errorf("error compressing payload: %w", err)
+		}
+		defer putBuffer(compressed)
+		payload = compressed.Bytes()
+		encoding = c.compression.String()
+	}
+	res.bytes = len(payload)
+	var lastErr error
+	for attempt := 1; attempt <= c.retry.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			c.stats.retries.Add(1)
+		}
+		res.attempts = attempt
+		err := c.observedAttempt(ctx, attempt, payload, encoding, cfg, res)
+		if err == nil {
+			c.stats.batchesSent.Add(1)
+			c.stats.bytesSent.Add(uint64(len(payload)))
+			return nil
+		}
+		lastErr = err
+		if c.throttle != nil && errors.Is(err, ErrThrottled) {
+			var httpErr HTTPError
+			errors.As(err, &httpErr)
+			c.throttle.throttled(time.Now(), httpErr.RetryAfter)
+		}
+		if attempt == c.retry.MaxAttempts || !c.shouldRetry(ctx, err) {
+			break
+		}
+		var httpErr HTTPError
+		errors.As(err, &httpErr)
+		if err := sleep(ctx, c.retry.RetryDelay(attempt, httpErr.RetryAfter)); err != nil {
+			return err
+		}
+	}
+	return lastErr
+}
+
+// shouldRetry reports whether the error from an attempt should be retried.
+// Unexpected responses are retried if they are marked as retryable, and
+// transport errors are retried unless the context is done.
+func (c *Client) shouldRetry(ctx context.Context, err error) bool {
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Retryable
+	}
+	return ctx.Err() == nil
+}
+
+// observedAttempt waits for any throttling and rate limits, makes an attempt
+// and reports it to the observers.
+func (c *Client) observedAttempt(ctx context.Context, attempt int, payload []byte, encoding string, cfg postConfig, res *postResult) error {
+	if c.throttle != nil {
+		if err := c.throttle.wait(ctx); err != nil {
+			return err
+		}
+	}
+	if err := c.waitRateLimit(ctx, len(payload)); err != nil {
+		return err
+	}
+	start := time.Now()
+	statusCode, err := c.attempt(ctx, payload, encoding, cfg)
+	res.statusCode = statusCode
+	if len(c.observers) > 0 || c.logger != nil {
+		info := AttemptInfo{
+			Attempt:    attempt,
+			Bytes:      len(payload),
+			StatusCode: statusCode,
+			Duration:   time.Since(start),
+			Err:        err,
+		}
+		if c.logger != nil {
+			c.logAttempt(info)
+		}
+		c.observe(info)
+	}
+	return err
+}
+
+// attempt makes a single request to the endpoint and returns the status code
+// of the response, or 0 if no response was received. Unexpected responses are
+// returned as an HTTPError.
+func (c *Client) attempt(ctx context.Context, payload []byte, encoding string, cfg postConfig) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	if cfg.contentType != "" {
+		req.Header.Set("Content-Type", cfg.contentType)
+	}
+	cfg.headers.apply(req.Header)
+	applyFields(req.Header, cfg.fields)
+	if len(cfg.dimensions) > 0 {
+		req.Header.Set(HeaderDimensions, encodeFields(cfg.dimensions))
+	}
+	if len(cfg.metadata) > 0 {
+		req.Header.Set(HeaderMetadata, encodeFields(cfg.metadata))
+	}
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if !c.isSuccess(resp.StatusCode) {
+		retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return resp.StatusCode, HTTPError{
+			StatusCode:   resp.StatusCode,
+			ResponseBody: readErrorBody(resp.Body, c.maxErrorBodySize),
+			RetryAfter:   retryAfter,
+			Retryable:    c.retry.retryable(resp.StatusCode),
+		}
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// readErrorBody reads up to limit bytes of an error response body, marking
+// the result if the body was truncated. The rest of the body is discarded so
+// the connection can be reused.
+func readErrorBody(r io.Reader, limit int) string {
+	if limit == 0 {
+		io.Copy(io.Discard, r)
+		return ""
+	}
+	b, _ := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	n, _ := io.Copy(io.Discard, r)
+	truncated := len(b) > limit || n > 0
+	if len(b) > limit {
+		b = b[:limit]
+	}
+	body := strings.TrimSpace(strings.ToValidUTF8(string(b), ""))
+	if truncated {
+		body += "... (truncated)"
+	}
+	return body
+}
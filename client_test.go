@@ -0,0 +1,154 @@
+package gosumo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+	"github.com/byitkc/gosumo/gosumotest"
+)
+
+func newCollector(t *testing.T) *gosumotest.Collector {
+	t.Helper()
+	c := gosumotest.NewCollector()
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestPostContext(t *testing.T) {
+	col := newCollector(t)
+	c, err := col.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostLogsStringContext(context.Background(), "one"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gosumo.PostLogsContext(context.Background(), c, []map[string]string{{"msg": "two"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := col.Lines(), []string{"one", `{"msg":"two"}`}; !slices.Equal(got, want) {
+		t.Errorf("collector received %q, want %q", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.PostLogsStringContext(ctx, "three"); !errors.Is(err, context.Canceled) {
+		t.Errorf("post with a cancelled context = %v, want context.Canceled", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	if err := gosumo.PostLogsContext(ctx, c, []map[string]string{{"msg": "four"}}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("post with an expired context = %v, want context.DeadlineExceeded", err)
+	}
+	if n := len(col.Requests()); n != 2 {
+		t.Errorf("collector received %d requests, want the 2 with a live context", n)
+	}
+}
+
+// fastRetry retries quickly so the tests do not wait on the backoff.
+var fastRetry = gosumo.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond, Multiplier: 2}
+
+func TestPostRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []int
+		policy    gosumo.RetryPolicy
+		wantErr   error
+		requests  int
+		retries   uint64
+	}{
+		{name: "success", requests: 1, policy: fastRetry},
+		{name: "transient failures", responses: []int{503, 429}, policy: fastRetry, requests: 3, retries: 2},
+		{name: "attempts exhausted", responses: []int{500, 502, 504}, policy: fastRetry, wantErr: gosumo.ErrServerError, requests: 3, retries: 2},
+		{name: "client error", responses: []int{400}, policy: fastRetry, wantErr: gosumo.ErrClientError, requests: 1},
+		{name: "retries disabled", responses: []int{503}, policy: gosumo.RetryPolicy{MaxAttempts: 1}, wantErr: gosumo.ErrServerError, requests: 1},
+		{
+			name:      "custom retryable",
+			responses: []int{503, 409},
+			policy: gosumo.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Retryable: func(code int) bool {
+				return code == http.StatusServiceUnavailable
+			}},
+			wantErr:  gosumo.ErrClientError,
+			requests: 2,
+			retries:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := newCollector(t)
+			col.RespondWithStatus(tt.responses...)
+			c, err := col.NewClient(gosumo.WithRetry(tt.policy))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.PostLogsString("line")
+			if tt.wantErr == nil && err != nil || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("post error = %v, want %v", err, tt.wantErr)
+			}
+			if n := len(col.Requests()); n != tt.requests {
+				t.Errorf("collector received %d requests, want %d", n, tt.requests)
+			}
+			stats := c.Stats()
+			if stats.Retries != tt.retries {
+				t.Errorf("Stats.Retries = %d, want %d", stats.Retries, tt.retries)
+			}
+			if failed := tt.wantErr != nil; failed != (stats.Failures == 1) {
+				t.Errorf("Stats.Failures = %d", stats.Failures)
+			}
+		})
+	}
+}
+
+func TestPostRetryAfter(t *testing.T) {
+	col := newCollector(t)
+	col.Respond(gosumotest.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"1"}}})
+	c, err := col.NewClient(gosumo.WithRetry(gosumo.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := c.PostLogsString("line"); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < time.Second {
+		t.Errorf("retried after %s, want the Retry-After of 1s", d)
+	}
+}
+
+func TestAdaptiveThrottling(t *testing.T) {
+	col := newCollector(t)
+	col.RespondWithStatus(http.StatusTooManyRequests)
+	c, err := col.NewClient(
+		gosumo.WithRetry(fastRetry),
+		gosumo.WithAdaptiveThrottling(gosumo.ThrottleConfig{InitialDelay: 20 * time.Millisecond, MaxDelay: time.Second, CoolDown: time.Hour}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state := c.ThrottleState(); state.Throttled {
+		t.Fatalf("ThrottleState = %+v before any 429", state)
+	}
+	if err := c.PostLogsString("line"); err != nil {
+		t.Fatal(err)
+	}
+	state := c.ThrottleState()
+	if !state.Throttled || state.Delay != 20*time.Millisecond || !state.Until.Equal(state.LastThrottled.Add(time.Hour)) {
+		t.Errorf("ThrottleState = %+v, want throttled with the initial delay", state)
+	}
+	// Requests are now spaced by the delay.
+	start := time.Now()
+	for range 3 {
+		if err := c.PostLogsString("line"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Errorf("3 throttled posts took %s, want them spaced by the delay", d)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/byitkc/gosumo/sumoapi"
+)
+
+// runCollectors runs an action on the collectors of the account.
+func runCollectors(ctx context.Context, args []string) error {
+	return runActions(ctx, "collectors", []command{
+		{"list", "print every collector", runCollectorsList},
+		{"get", "print a collector by ID or name", runCollectorsGet},
+		{"create", "create a hosted collector from JSON", runCollectorsCreate},
+		{"delete", "delete a collector along with its sources", runCollectorsDelete},
+	}, args)
+}
+
+func runCollectorsList(ctx context.Context, args []string) error {
+	fs := newFlagSet("collectors list", "[flags]")
+	var af apiFlags
+	af.register(fs)
+	filter := fs.String("filter", "", "only list the collectors matching `filter`: installed, hosted, dead or alive")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usagef("unexpected arguments: %v", fs.Args())
+	}
+	client, err := af.client(ctx)
+	if err != nil {
+		return err
+	}
+	collectors, err := sumoapi.Collect(client.IterateCollectors(ctx, *filter))
+	if err != nil {
+		return err
+	}
+	return writeJSON(nonNil(collectors))
+}
+
+func runCollectorsGet(ctx context.Context, args []string) error {
+	fs := newFlagSet("collectors get", "[flags] collector")
+	var af apiFlags
+	af.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("expected a single collector ID or name, got %d arguments", fs.NArg())
+	}
+	client, err := af.client(ctx)
+	if err != nil {
+		return err
+	}
+	collector, err := lookupCollector(ctx, client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return writeJSON(collector)
+}
+
+func runCollectorsCreate(ctx context.Context, args []string) error {
+	fs := newFlagSet("collectors create", "[flags]")
+	var af apiFlags
+	af.register(fs)
+	file := fs.String("file", "-", "read the collector as JSON from `file`, or from stdin if it is -")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usagef("unexpected arguments: %v", fs.Args())
+	}
+	var collector sumoapi.Collector
+	if err := readJSON(*file, &collector); err != nil {
+		return err
+	}
+	client, err := af.client(ctx)
+	if err != nil {
+		return err
+	}
+	created, err := client.CreateCollector(ctx, collector)
+	if err != nil {
+		return err
+	}
+	return writeJSON(created)
+}
+
+func runCollectorsDelete(ctx context.Context, args []string) error {
+	fs := newFlagSet("collectors delete", "[flags] collector")
+	var af apiFlags
+	af.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("expected a single collector ID or name, got %d arguments", fs.NArg())
+	}
+	client, err := af.client(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := collectorID(ctx, client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return client.DeleteCollector(ctx, id)
+}
+
+// lookupCollector returns the collector with the ID, or with the name if s
+// is not a number.
+func lookupCollector(ctx context.Context, client *sumoapi.Client, s string) (*sumoapi.Collector, error) {
+	if id, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return client.GetCollector(ctx, id)
+	}
+	return client.GetCollectorByName(ctx, s)
+}
+
+// collectorID returns the ID of the collector given by ID or name, looking
+// the name up if needed.
+func collectorID(ctx context.Context, client *sumoapi.Client, s string) (int64, error) {
+	if id, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return id, nil
+	}
+	collector, err := client.GetCollectorByName(ctx, s)
+	if err != nil {
+		return 0, err
+	}
+	return collector.ID, nil
+}
+
+// nonNil returns an empty slice for nil, so that it is printed as [] rather
+// than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
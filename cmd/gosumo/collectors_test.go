@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/byitkc/gosumo/sumoapi"
+)
+
+// fakeAPI is a management API holding collectors in memory.
+type fakeAPI struct {
+	*httptest.Server
+	mu         sync.Mutex
+	collectors map[int64]sumoapi.Collector
+	nextID     int64
+}
+
+func newFakeAPI(t *testing.T, collectors ...sumoapi.Collector) *fakeAPI {
+	api := &fakeAPI{collectors: make(map[int64]sumoapi.Collector), nextID: 100}
+	for _, c := range collectors {
+		api.collectors[c.ID] = c
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/collectors", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		var list []sumoapi.Collector
+		if r.URL.Query().Get("offset") == "0" {
+			for _, c := range api.collectors {
+				list = append(list, c)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"collectors": list})
+	})
+	mux.HandleFunc("GET /api/v1/collectors/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		api.write(w, func(c sumoapi.Collector) bool { return c.ID == id })
+	})
+	mux.HandleFunc("GET /api/v1/collectors/name/{name}", func(w http.ResponseWriter, r *http.Request) {
+		api.write(w, func(c sumoapi.Collector) bool { return c.Name == r.PathValue("name") })
+	})
+	mux.HandleFunc("POST /api/v1/collectors", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Collector sumoapi.Collector `json:"collector"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		api.mu.Lock()
+		api.nextID++
+		body.Collector.ID = api.nextID
+		api.collectors[body.Collector.ID] = body.Collector
+		api.mu.Unlock()
+		json.NewEncoder(w).Encode(body)
+	})
+	mux.HandleFunc("DELETE /api/v1/collectors/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		if _, ok := api.collectors[id]; !ok {
+			notFound(w)
+			return
+		}
+		delete(api.collectors, id)
+	})
+	api.Server = httptest.NewServer(mux)
+	t.Cleanup(api.Close)
+	return api
+}
+
+// write writes the first collector matching the function.
+func (api *fakeAPI) write(w http.ResponseWriter, match func(sumoapi.Collector) bool) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	for _, c := range api.collectors {
+		if match(c) {
+			json.NewEncoder(w).Encode(map[string]any{"collector": c})
+			return
+		}
+	}
+	notFound(w)
+}
+
+func notFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`{"status":404,"code":"collectors.notfound","message":"not found"}`))
+}
+
+// runAPI runs the command with the flags of the fake API, and returns its
+// output and error.
+func (api *fakeAPI) runAPI(t *testing.T, run func(context.Context, []string) error, args ...string) (string, error) {
+	t.Helper()
+	args = append([]string{"-access-id", "id", "-access-key", "key", "-base-url", api.URL + "/api"}, args...)
+	var err error
+	out := capture(t, &os.Stdout, func() { err = run(context.Background(), args) })
+	return out, err
+}
+
+func TestCollectorsCommands(t *testing.T) {
+	api := newFakeAPI(t,
+		sumoapi.Collector{ID: 1, Name: "web", CollectorType: "Hosted"},
+		sumoapi.Collector{ID: 2, Name: "db", CollectorType: "Installable"},
+	)
+
+	out, err := api.runAPI(t, runCollectorsList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list []sumoapi.Collector
+	if err := json.Unmarshal([]byte(out), &list); err != nil || len(list) != 2 {
+		t.Fatalf("collectors list printed %q, %v", out, err)
+	}
+
+	for _, arg := range []string{"1", "web"} {
+		out, err := api.runAPI(t, runCollectorsGet, arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var c sumoapi.Collector
+		if err := json.Unmarshal([]byte(out), &c); err != nil || c.ID != 1 || c.Name != "web" {
+			t.Errorf("collectors get %s printed %q, %v", arg, out, err)
+		}
+	}
+	if _, err := api.runAPI(t, runCollectorsGet, "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("collectors get of a missing collector = %v, want not found", err)
+	}
+	if _, err := api.runAPI(t, runCollectorsGet); err == nil {
+		t.Error("collectors get without a collector succeeded")
+	}
+
+	file := filepath.Join(t.TempDir(), "collector.json")
+	os.WriteFile(file, []byte(`{"name": "new", "category": "prod"}`), 0o644)
+	out, err = api.runAPI(t, runCollectorsCreate, "-file", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var created sumoapi.Collector
+	if err := json.Unmarshal([]byte(out), &created); err != nil || created.ID == 0 || created.Name != "new" {
+		t.Errorf("collectors create printed %q, %v", out, err)
+	}
+	os.WriteFile(file, []byte(`{"name": `), 0o644)
+	if _, err := api.runAPI(t, runCollectorsCreate, "-file", file); err == nil {
+		t.Error("collectors create with invalid JSON succeeded")
+	}
+
+	if _, err := api.runAPI(t, runCollectorsDelete, "db"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.runAPI(t, runCollectorsDelete, "2"); err == nil {
+		t.Error("deleting a deleted collector succeeded")
+	}
+	out, err = api.runAPI(t, runCollectorsList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, `"db"`) || !strings.Contains(out, `"new"`) {
+		t.Errorf("collectors list after create and delete printed %s", out)
+	}
+}
+
+func TestCollectorsListEmpty(t *testing.T) {
+	api := newFakeAPI(t)
+	out, err := api.runAPI(t, runCollectorsList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "[]\n" {
+		t.Errorf("collectors list printed %q, want an empty array", out)
+	}
+}
+
+func TestCollectorsUsage(t *testing.T) {
+	testUsage(t, []usageTest{
+		{"no action", []string{"collectors"}, 2, "Actions:"},
+		{"unknown action", []string{"sources", "nosuch"}, 2, `unknown action "nosuch"`},
+		{"get without a collector", []string{"collectors", "get", "-access-id", "id", "-access-key", "key"}, 2, "expected a single collector"},
+	})
+}
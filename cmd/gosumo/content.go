@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/byitkc/gosumo/sumoapi"
+)
+
+// runContent runs an action on the content library.
+func runContent(ctx context.Context, args []string) error {
+	return runActions(ctx, "content", []command{
+		{"export", "write the dashboards and searches of a folder to JSON files", runContentExport},
+		{"import", "import JSON files written by export into a folder", runContentImport},
+	}, args)
+}
+
+func runContentExport(ctx context.Context, args []string) error {
+	fs := newFlagSet("content export", "[flags] [item]")
+	var af apiFlags
+	af.register(fs)
+	dir := fs.String("dir", ".", "write the files under `directory`, with a subdirectory for each folder")
+	types := fs.String("types", sumoapi.ContentDashboard+","+sumoapi.ContentSearch, "comma separated item `types` to export, or all for every type")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), `Usage: gosumo content export [flags] [item]
+
+The item is a path in the content library such as /Library/Users/me@example.com/Prod,
+or an ID, and defaults to the personal folder. Everything in a folder is exported.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return usagef("expected at most one item, got %d", fs.NArg())
+	}
+	client, err := af.client(ctx)
+	if err != nil {
+		return err
+	}
+	item, err := lookupContent(ctx, client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	e := &contentExporter{client: client}
+	if *types != "all" {
+		e.types = make(map[string]bool)
+		for _, t := range strings.Split(*types, ",") {
+			e.types[strings.TrimSpace(t)] = true
+		}
+	}
+	if item.IsFolder() {
+		return e.exportFolder(ctx, item.ID, *dir)
+	}
+	return e.exportItem(ctx, item, *dir)
+}
+
+// contentExporter writes items of the content library to files.
+type contentExporter struct {
+	client *sumoapi.Client
+	// types are the item types exported, or nil for every type.
+	types map[string]bool
+}
+
+// exportFolder writes the items in the folder to dir, and the items in its
+// subfolders to subdirectories named after them.
+func (e *contentExporter) exportFolder(ctx context.Context, id, dir string) error {
+	folder, err := e.client.GetFolder(ctx, id)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]bool)
+	for _, child := range folder.Children {
+		if !child.IsFolder() && e.types != nil && !e.types[child.ItemType] {
+			continue
+		}
+		name := fileName(child.Name)
+		if names[name] {
+			// Items are imported by name, so the definitions could not be
+			// imported back.
+			return fmt.Errorf("folder %q has more than one item named %q", folder.Name, child.Name)
+		}
+		names[name] = true
+		if child.IsFolder() {
+			err = e.exportFolder(ctx, child.ID, filepath.Join(dir, name))
+		} else {
+			err = e.exportItem(ctx, &child, dir)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportItem writes the definition of the item to a file named after it in
+// dir, and prints the path of the file.
+func (e *contentExporter) exportItem(ctx context.Context, item *sumoapi.ContentItem, dir string) error {
+	def, err := e.client.ExportContent(ctx, item.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", item.Name, err)
+	}
+	// The definition is indented so that changes to it read well in diffs.
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, def, "", "  "); err != nil {
+		return fmt.Errorf("%s: error formatting definition: %w", item.Name, err)
+	}
+	buf.WriteByte('\n')
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, fileName(item.Name)+".json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Println(path)
+	return nil
+}
+
+func runContentImport(ctx context.Context, args []string) error {
+	fs := newFlagSet("content import", "[flags] file|directory...")
+	var af apiFlags
+	af.register(fs)
+	folder := fs.String("folder", "", "import into the folder with `path` or ID in the content library, instead of the personal folder")
+	overwrite := fs.Bool("overwrite", false, "replace the items with the same name in the folder instead of failing")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usagef("at least one file or directory is required")
+	}
+	client, err := af.client(ctx)
+	if err != nil {
+		return err
+	}
+	target, err := lookupContent(ctx, client, *folder)
+	if err != nil {
+		return err
+	}
+	if !target.IsFolder() {
+		return fmt.Errorf("%q is a %s, not a folder", target.Name, target.ItemType)
+	}
+	im := &contentImporter{client: client, overwrite: *overwrite}
+	for _, path := range fs.Args() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			err = im.importDir(ctx, target.ID, path)
+		} else {
+			err = im.importFile(ctx, target.ID, path)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contentImporter imports files written by contentExporter.
+type contentImporter struct {
+	client    *sumoapi.Client
+	overwrite bool
+}
+
+// importDir imports the JSON files in dir into the folder, and those in its
+// subdirectories into the subfolders with their names, which are created if
+// needed.
+func (im *contentImporter) importDir(ctx context.Context, folderID, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		switch {
+		case entry.IsDir():
+			id, err := im.subfolder(ctx, folderID, entry.Name())
+			if err != nil {
+				return err
+			}
+			if err := im.importDir(ctx, id, path); err != nil {
+				return err
+			}
+		case filepath.Ext(path) == ".json":
+			if err := im.importFile(ctx, folderID, path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importFile imports the definition in the file into the folder, and prints
+// the path of the file.
+func (im *contentImporter) importFile(ctx context.Context, folderID, path string) error {
+	def, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !json.Valid(def) {
+		return fmt.Errorf("%s: invalid JSON", path)
+	}
+	if err := im.client.ImportContent(ctx, folderID, def, im.overwrite); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	fmt.Println(path)
+	return nil
+}
+
+// subfolder returns the ID of the folder with the name in the parent folder,
+// creating it if it does not exist.
+func (im *contentImporter) subfolder(ctx context.Context, parentID, name string) (string, error) {
+	parent, err := im.client.GetFolder(ctx, parentID)
+	if err != nil {
+		return "", err
+	}
+	for _, child := range parent.Children {
+		if child.IsFolder() && child.Name == name {
+			return child.ID, nil
+		}
+	}
+	folder, err := im.client.CreateFolder(ctx, parentID, name, "")
+	if err != nil {
+		return "", err
+	}
+	return folder.ID, nil
+}
+
+// lookupContent returns the item of the content library with the path, or
+// with the ID if s does not start with a slash. An empty s is the personal
+// folder.
+func lookupContent(ctx context.Context, client *sumoapi.Client, s string) (*sumoapi.ContentItem, error) {
+	switch {
+	case s == "":
+		return client.PersonalFolder(ctx)
+	case strings.HasPrefix(s, "/"):
+		return client.GetContentByPath(ctx, s)
+	}
+	path, err := client.ContentPath(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetContentByPath(ctx, path)
+}
+
+// fileName returns the name of the file or directory for an item, which
+// cannot contain path separators.
+func fileName(name string) string {
+	name = strings.NewReplacer("/", "_", `\`, "_").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		name = "_" + name
+	}
+	return name
+}
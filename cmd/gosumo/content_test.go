@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestFileName(t *testing.T) {
+	for name, want := range map[string]string{
+		"Errors":    "Errors",
+		"a/b\\c":    "a_b_c",
+		"":          "_",
+		".":         "_.",
+		"..":        "_..",
+		"CPU (avg)": "CPU (avg)",
+	} {
+		if got := fileName(name); got != want {
+			t.Errorf("fileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestContentUsage(t *testing.T) {
+	testUsage(t, []usageTest{
+		{"content import without files", []string{"content", "import"}, 2, "at least one file"},
+		{"content export with two items", []string{"content", "export", "a", "b"}, 2, "at most one item"},
+	})
+}
@@ -0,0 +1,170 @@
+// Command gosumo ships logs to a Sumo Logic HTTP source from the command
+// line, for use in cron jobs and shell pipelines, and follows log files as a
+// lightweight alternative to installing a collector. It also runs searches,
+// manages collectors and sources, and exports and imports content through
+// the management API, with the credentials read from SUMO_ACCESS_ID and
+// SUMO_ACCESS_KEY.
+//
+// Usage:
+//
+//	gosumo <command> [flags]
+//
+// Run gosumo <command> -h for the flags of a command.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// command is a subcommand of gosumo.
+type command struct {
+	name string
+	// summary is shown in the list of commands.
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+// commands returns the subcommands of gosumo.
+func commands() []command {
+	return []command{
+		{"ship", "post logs read from stdin to an HTTP source", runShip},
+		{"tail", "follow files and post the lines appended to them", runTail},
+		{"search", "run a search and print its messages or records", runSearch},
+		{"collectors", "list, get, create or delete collectors", runCollectors},
+		{"sources", "list, get, create or delete the sources of a collector", runSources},
+		{"content", "export or import dashboards and searches as JSON files", runContent},
+	}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stderr))
+}
+
+// run runs the command named by the first argument and returns the exit
+// code: 0 on success, 2 for usage errors and 1 for any other error.
+func run(ctx context.Context, args []string, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+	for _, cmd := range commands() {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(ctx, args[1:])
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.As(err, new(usageError)):
+			// An empty message is a flag error already reported by the flag set.
+			if err.Error() != "" {
+				fmt.Fprintf(stderr, "gosumo %s: %v\n", cmd.name, err)
+			}
+			return 2
+		}
+		fmt.Fprintf(stderr, "gosumo %s: %v\n", cmd.name, err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "gosumo: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+// usage writes the list of commands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gosumo <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run gosumo <command> -h for the flags of a command.")
+}
+
+// runActions runs the action of the command named by the first argument,
+// for commands such as collectors that group several actions.
+func runActions(ctx context.Context, name string, actions []command, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		fmt.Fprintf(os.Stderr, "Usage: gosumo %s <action> [flags]\n\nActions:\n", name)
+		for _, a := range actions {
+			fmt.Fprintf(os.Stderr, "  %-12s %s\n", a.name, a.summary)
+		}
+		fmt.Fprintf(os.Stderr, "\nRun gosumo %s <action> -h for the flags of an action.\n", name)
+		if len(args) == 0 {
+			return usageError{}
+		}
+		return flag.ErrHelp
+	}
+	names := make([]string, len(actions))
+	for i, a := range actions {
+		if a.name == args[0] {
+			return a.run(ctx, args[1:])
+		}
+		names[i] = a.name
+	}
+	return usagef("unknown action %q, must be one of: %s", args[0], strings.Join(names, ", "))
+}
+
+// usageError is returned by commands for invalid flags or arguments.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// usagef returns a usageError with the formatted message.
+func usagef(format string, args ...any) error {
+	return usageError{fmt.Sprintf(format, args...)}
+}
+
+// newFlagSet returns a flag set for the command that reports errors instead
+// of exiting, with the usage line shown by -h.
+func newFlagSet(name, usageLine string) *flag.FlagSet {
+	fs := flag.NewFlagSet("gosumo "+name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gosumo %s %s\n\nFlags:\n", name, usageLine)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the arguments, returning an empty usageError for invalid
+// flags, which the flag set has already reported along with its usage.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{}
+	}
+	return nil
+}
+
+// isFlagSet reports whether the flag was given on the command line.
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
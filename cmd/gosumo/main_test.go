@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/byitkc/gosumo"
+)
+
+// capture returns what fn writes to the file, which is os.Stdout or
+// os.Stderr.
+func capture(t *testing.T, file **os.File, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := *file
+	*file = w
+	defer func() { *file = orig }()
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	fn()
+	w.Close()
+	return <-out
+}
+
+// unsetenv unsets the environment variable for the duration of the test.
+func unsetenv(t *testing.T, key string) {
+	t.Setenv(key, "")
+	os.Unsetenv(key)
+}
+
+// usageTest runs the command and checks its exit code and what it writes to
+// stderr.
+type usageTest struct {
+	name   string
+	args   []string
+	code   int
+	stderr string
+}
+
+func testUsage(t *testing.T, tests []usageTest) {
+	t.Helper()
+	unsetenv(t, gosumo.EnvHTTPSourceURL)
+	unsetenv(t, envAccessID)
+	unsetenv(t, envAccessKey)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			// Usage of actions and flag sets goes to os.Stderr, which is
+			// captured too.
+			out := capture(t, &os.Stderr, func() {
+				if code := run(context.Background(), tt.args, &stderr); code != tt.code {
+					t.Errorf("exit code = %d, want %d", code, tt.code)
+				}
+			})
+			if all := stderr.String() + out; !strings.Contains(all, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", all, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	testUsage(t, []usageTest{
+		{"no command", nil, 2, "Usage: gosumo <command>"},
+		{"help", []string{"help"}, 0, "Commands:"},
+		{"unknown command", []string{"nosuch"}, 2, `unknown command "nosuch"`},
+		{"command help", []string{"ship", "-h"}, 0, "Usage: gosumo ship"},
+		{"invalid flag", []string{"ship", "-nosuch"}, 2, "flag provided but not defined"},
+		{"unexpected argument", []string{"ship", "extra"}, 2, "unexpected arguments"},
+		{"invalid format", []string{"ship", "-format", "xml"}, 2, "invalid -format"},
+		{"invalid compression", []string{"ship", "-url", "https://collectors.sumologic.com/receiver/v1/http/x", "-compression", "zip"}, 2, "invalid -compression"},
+		{"no endpoint", []string{"ship"}, 2, "no endpoint"},
+	})
+}
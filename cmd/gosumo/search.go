@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/byitkc/gosumo/sumoapi"
+)
+
+// runSearch runs a search job and prints its messages or records.
+func runSearch(ctx context.Context, args []string) error {
+	fs := newFlagSet("search", "[flags] query")
+	var af apiFlags
+	af.register(fs)
+	from := fs.String("from", "15m", "start of the time range, as a `time` in RFC 3339 or a duration before now such as 1h")
+	to := fs.String("to", "now", "end of the time range, as a `time` like -from")
+	records := fs.Bool("records", false, "print the aggregate records of the query instead of the messages")
+	output := fs.String("output", "json", "output `format`: json, csv or table")
+	fields := fs.String("fields", "", "comma separated `fields` to print, instead of every field of the results")
+	limit := fs.Int("limit", 0, "stop after `number` results; 0 prints every result")
+	timeZone := fs.String("timezone", "UTC", "time `zone` of the time values in the results")
+	byReceipt := fs.Bool("by-receipt-time", false, "search by the time logs were received instead of their message time")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("expected a single query argument, got %d", fs.NArg())
+	}
+	now := time.Now()
+	start, err := parseTimeFlag(*from, now)
+	if err != nil {
+		return usagef("invalid -from: %v", err)
+	}
+	end, err := parseTimeFlag(*to, now)
+	if err != nil {
+		return usagef("invalid -to: %v", err)
+	}
+	if !start.Before(end) {
+		return usagef("-from must be before -to")
+	}
+	newPrinter, ok := resultPrinters[*output]
+	if !ok {
+		return usagef("invalid -output: %q, must be json, csv or table", *output)
+	}
+	client, err := af.client(ctx)
+	if err != nil {
+		return err
+	}
+	req := sumoapi.SearchJobRequest{
+		Query:         fs.Arg(0),
+		From:          start,
+		To:            end,
+		TimeZone:      *timeZone,
+		ByReceiptTime: *byReceipt,
+		RecordsOnly:   *records,
+	}
+	it := client.SearchMessages(ctx, req)
+	if *records {
+		it = client.SearchRecords(ctx, req)
+	}
+	defer it.Close()
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	var p resultPrinter
+	for n := 0; (*limit == 0 || n < *limit) && it.Next(); n++ {
+		if p == nil {
+			p = newPrinter(w, resultColumns(it.Fields(), *fields))
+		}
+		if err := p.print(it.Result()); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	if p == nil {
+		p = newPrinter(w, resultColumns(it.Fields(), *fields))
+	}
+	return p.close()
+}
+
+// parseTimeFlag parses a time given in RFC 3339, as a duration before now,
+// or as "now".
+func parseTimeFlag(s string, now time.Time) (time.Time, error) {
+	if s == "now" {
+		return now, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d.Abs()), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a duration nor an RFC 3339 time", s)
+	}
+	return t, nil
+}
+
+// resultColumns returns the columns printed for the results: the selected
+// fields, or every field of the results in the order the API returned them.
+func resultColumns(fields []sumoapi.SearchField, selected string) []string {
+	if selected != "" {
+		var cols []string
+		for _, f := range strings.Split(selected, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				cols = append(cols, f)
+			}
+		}
+		return cols
+	}
+	cols := make([]string, len(fields))
+	for i, f := range fields {
+		cols[i] = f.Name
+	}
+	return cols
+}
+
+// resultPrinter writes search results in an output format.
+type resultPrinter interface {
+	print(r sumoapi.SearchResult) error
+	// close writes anything that remains once every result was printed.
+	close() error
+}
+
+// resultPrinters creates a resultPrinter for each output format, given the
+// columns to print.
+var resultPrinters = map[string]func(w io.Writer, cols []string) resultPrinter{
+	"json": func(w io.Writer, cols []string) resultPrinter {
+		return &jsonPrinter{w: w, cols: cols}
+	},
+	"csv": func(w io.Writer, cols []string) resultPrinter {
+		return &csvPrinter{w: csv.NewWriter(w), cols: cols}
+	},
+	"table": func(w io.Writer, cols []string) resultPrinter {
+		return &tablePrinter{w: tabwriter.NewWriter(w, 0, 4, 2, ' ', 0), cols: cols}
+	},
+}
+
+// jsonPrinter writes the results as a JSON array of objects.
+type jsonPrinter struct {
+	w     io.Writer
+	cols  []string
+	count int
+}
+
+func (p *jsonPrinter) print(r sumoapi.SearchResult) error {
+	obj := make(map[string]string, len(p.cols))
+	for _, c := range p.cols {
+		if v, ok := r.Map[c]; ok {
+			obj[c] = v
+		}
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if p.count == 0 {
+		sep = "[\n  "
+	}
+	p.count++
+	_, err = fmt.Fprintf(p.w, "%s%s", sep, b)
+	return err
+}
+
+func (p *jsonPrinter) close() error {
+	end := "\n]\n"
+	if p.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(p.w, end)
+	return err
+}
+
+// csvPrinter writes the results as CSV with a header row.
+type csvPrinter struct {
+	w       *csv.Writer
+	cols    []string
+	started bool
+}
+
+func (p *csvPrinter) print(r sumoapi.SearchResult) error {
+	if !p.started {
+		p.started = true
+		if err := p.w.Write(p.cols); err != nil {
+			return err
+		}
+	}
+	return p.w.Write(row(r, p.cols))
+}
+
+func (p *csvPrinter) close() error {
+	if !p.started && len(p.cols) > 0 {
+		p.w.Write(p.cols)
+	}
+	p.w.Flush()
+	return p.w.Error()
+}
+
+// tablePrinter writes the results as aligned columns with a header row.
+// Values are printed on a single line.
+type tablePrinter struct {
+	w       *tabwriter.Writer
+	cols    []string
+	started bool
+}
+
+func (p *tablePrinter) print(r sumoapi.SearchResult) error {
+	if !p.started {
+		p.started = true
+		if _, err := fmt.Fprintln(p.w, strings.Join(p.cols, "\t")); err != nil {
+			return err
+		}
+	}
+	values := row(r, p.cols)
+	for i, v := range values {
+		values[i] = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(v)
+	}
+	_, err := fmt.Fprintln(p.w, strings.Join(values, "\t"))
+	return err
+}
+
+func (p *tablePrinter) close() error {
+	if !p.started && len(p.cols) > 0 {
+		fmt.Fprintln(p.w, strings.Join(p.cols, "\t"))
+	}
+	return p.w.Flush()
+}
+
+// row returns the values of the columns of the result.
+func row(r sumoapi.SearchResult, cols []string) []string {
+	values := make([]string, len(cols))
+	for i, c := range cols {
+		values[i] = r.Map[c]
+	}
+	return values
+}
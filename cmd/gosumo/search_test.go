@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo/sumoapi"
+)
+
+func TestParseTimeFlag(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+		err  bool
+	}{
+		{"now", now, false},
+		{"15m", now.Add(-15 * time.Minute), false},
+		{"-2h", now.Add(-2 * time.Hour), false},
+		{"2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+		{"2024-01-02", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseTimeFlag(tt.in, now)
+		if (err != nil) != tt.err || !got.Equal(tt.want) {
+			t.Errorf("parseTimeFlag(%q) = %s, %v, want %s", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestResultColumns(t *testing.T) {
+	fields := []sumoapi.SearchField{{Name: "_messagetime"}, {Name: "_raw"}}
+	if got := resultColumns(fields, ""); len(got) != 2 || got[0] != "_messagetime" || got[1] != "_raw" {
+		t.Errorf("resultColumns without selection = %q", got)
+	}
+	if got := resultColumns(fields, " _raw, ,host "); len(got) != 2 || got[0] != "_raw" || got[1] != "host" {
+		t.Errorf("resultColumns with selection = %q", got)
+	}
+}
+
+func TestResultPrinters(t *testing.T) {
+	results := []sumoapi.SearchResult{
+		{Map: map[string]string{"host": "a", "_raw": "first\tline"}},
+		{Map: map[string]string{"_raw": "second,\nline"}},
+	}
+	tests := []struct {
+		format string
+		want   string
+		empty  string
+	}{
+		{
+			format: "json",
+			want:   "[\n  {\"_raw\":\"first\\tline\",\"host\":\"a\"},\n  {\"_raw\":\"second,\\nline\"}\n]\n",
+			empty:  "[]\n",
+		},
+		{
+			format: "csv",
+			want:   "host,_raw\na,first\tline\n,\"second,\nline\"\n",
+			empty:  "host,_raw\n",
+		},
+		{
+			format: "table",
+			want:   "host  _raw\na     first line\n      second, line\n",
+			empty:  "host  _raw\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+			p := resultPrinters[tt.format](&out, []string{"host", "_raw"})
+			for _, r := range results {
+				if err := p.print(r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := p.close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+			out.Reset()
+			if err := resultPrinters[tt.format](&out, []string{"host", "_raw"}).close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.String(); got != tt.empty {
+				t.Errorf("output without results = %q, want %q", got, tt.empty)
+			}
+		})
+	}
+}
+
+func TestSearchUsage(t *testing.T) {
+	testUsage(t, []usageTest{
+		{"search without query", []string{"search"}, 2, "expected a single query"},
+		{"invalid time range", []string{"search", "-from", "1h", "-to", "2h", "*"}, 2, "-from must be before -to"},
+		{"invalid output", []string{"search", "-output", "xml", "*"}, 2, "invalid -output"},
+		{"no credentials", []string{"search", "*"}, 2, "no credentials"},
+	})
+}
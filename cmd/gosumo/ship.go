@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/byitkc/gosumo"
+)
+
+// endpointFlags configure the Client that posts to an HTTP source. The
+// endpoint is taken from -url, a configuration file given with -config, or
+// the environment variables read by gosumo.NewClientFromEnv, in that order,
+// and the other flags override the settings from the file or environment.
+type endpointFlags struct {
+	url         string
+	config      string
+	category    string
+	name        string
+	host        string
+	fields      string
+	compression string
+	batchBytes  int
+	batchLogs   int
+	timeout     time.Duration
+	retries     int
+
+	fs *flag.FlagSet
+}
+
+// register adds the flags to the flag set.
+func (f *endpointFlags) register(fs *flag.FlagSet) {
+	f.fs = fs
+	fs.StringVar(&f.url, "url", "", "`URL` of the HTTP source, instead of "+gosumo.EnvHTTPSourceURL)
+	fs.StringVar(&f.config, "config", "", "read the client settings from the YAML or JSON `file`")
+	fs.StringVar(&f.category, "category", "", "source `category` of the logs")
+	fs.StringVar(&f.name, "name", "", "source `name` of the logs")
+	fs.StringVar(&f.host, "host", "", "source `host` of the logs")
+	fs.StringVar(&f.fields, "fields", "", "`fields` attached to the logs, as key=value pairs separated by commas")
+	fs.StringVar(&f.compression, "compression", "", "compress payloads with `algorithm`: none, gzip or deflate")
+	fs.IntVar(&f.batchBytes, "batch-bytes", 0, "maximum size of a batch in `bytes`")
+	fs.IntVar(&f.batchLogs, "batch-logs", 0, "maximum `number` of logs in a batch")
+	fs.DurationVar(&f.timeout, "timeout", 0, "timeout of each request")
+	fs.IntVar(&f.retries, "retries", 4, "`number` of times a failed post is retried, unless -config sets them")
+}
+
+// client returns the Client described by the flags.
+func (f *endpointFlags) client() (*gosumo.Client, error) {
+	var opts []gosumo.Option
+	if f.category != "" {
+		opts = append(opts, gosumo.WithSourceCategory(f.category))
+	}
+	if f.name != "" {
+		opts = append(opts, gosumo.WithSourceName(f.name))
+	}
+	if f.host != "" {
+		opts = append(opts, gosumo.WithSourceHost(f.host))
+	}
+	if f.fields != "" {
+		fields, err := gosumo.ParseFields(f.fields)
+		if err != nil {
+			return nil, usagef("invalid -fields: %v", err)
+		}
+		opts = append(opts, gosumo.WithFields(fields))
+	}
+	if f.compression != "" {
+		alg, err := gosumo.ParseCompression(f.compression)
+		if err != nil {
+			return nil, usagef("invalid -compression: %v", err)
+		}
+		opts = append(opts, gosumo.WithCompression(alg))
+	}
+	if f.batchBytes > 0 {
+		opts = append(opts, gosumo.WithMaxBatchBytes(f.batchBytes))
+	}
+	if f.batchLogs > 0 {
+		opts = append(opts, gosumo.WithMaxBatchLogs(f.batchLogs))
+	}
+	if f.timeout > 0 {
+		opts = append(opts, gosumo.WithTimeout(f.timeout))
+	}
+	if f.retries < 0 {
+		return nil, usagef("invalid -retries: must not be negative, got: %d", f.retries)
+	}
+	if f.config == "" || isFlagSet(f.fs, "retries") {
+		retry := gosumo.DefaultRetryPolicy
+		retry.MaxAttempts = f.retries + 1
+		opts = append(opts, gosumo.WithRetry(retry))
+	}
+	switch {
+	case f.url != "":
+		return gosumo.NewClient(f.url, opts...)
+	case f.config != "":
+		cfg, err := gosumo.LoadConfig(f.config)
+		if err != nil {
+			return nil, err
+		}
+		return cfg.NewClient(opts...)
+	}
+	if _, ok := os.LookupEnv(gosumo.EnvHTTPSourceURL); !ok {
+		return nil, usagef("no endpoint: set -url, -config or %s", gosumo.EnvHTTPSourceURL)
+	}
+	return gosumo.NewClientFromEnv(opts...)
+}
+
+// runShip posts the logs read from stdin.
+func runShip(ctx context.Context, args []string) error {
+	fs := newFlagSet("ship", "[flags] < logs")
+	var ef endpointFlags
+	ef.register(fs)
+	format := fs.String("format", "lines", "format of the input: lines, or json for JSON values or arrays of them")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usagef("unexpected arguments: %v", fs.Args())
+	}
+	var in io.Reader
+	switch *format {
+	case "lines":
+		in = os.Stdin
+	case "json":
+		in = jsonLines(os.Stdin)
+	default:
+		return usagef("invalid -format: %q, must be lines or json", *format)
+	}
+	client, err := ef.client()
+	if err != nil {
+		return err
+	}
+	return client.PostLogsReader(ctx, in)
+}
+
+// jsonLines returns a reader of the JSON values read from r, which may be
+// separated by whitespace or newlines, with one value per line. The elements
+// of top level arrays are written as separate values.
+func jsonLines(r io.Reader) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		w := bufio.NewWriter(pw)
+		err := writeJSONLines(json.NewDecoder(r), w)
+		if err == nil {
+			err = w.Flush()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// writeJSONLines writes every value decoded by dec as a line.
+func writeJSONLines(dec *json.Decoder, w *bufio.Writer) error {
+	for n := 1; ; n++ {
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("invalid json value %d: %w", n, err)
+		}
+		values := []json.RawMessage{v}
+		if len(v) > 0 && v[0] == '[' {
+			values = nil
+			if err := json.Unmarshal(v, &values); err != nil {
+				return fmt.Errorf("invalid json value %d: %w", n, err)
+			}
+		}
+		for _, v := range values {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("invalid json value %d: %w", n, err)
+			}
+			w.Write(b)
+			w.WriteByte('\n')
+		}
+	}
+}
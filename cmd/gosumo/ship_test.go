@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteJSONLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr string
+	}{
+		{"values", `{"a":1} {"b": 2}` + "\n" + `"text"`, "{\"a\":1}\n{\"b\":2}\n\"text\"\n", ""},
+		{"arrays", `[{"a":1},{"b":2}] [3]`, "{\"a\":1}\n{\"b\":2}\n3\n", ""},
+		{"indented", "{\n  \"a\": [1, 2]\n}", "{\"a\":[1,2]}\n", ""},
+		{"empty", "", "", ""},
+		{"invalid", `{"a":1} {"b":`, "{\"a\":1}\n", "invalid json value 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := bufio.NewWriter(&out)
+			err := writeJSONLines(json.NewDecoder(strings.NewReader(tt.in)), w)
+			w.Flush()
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("writeJSONLines error = %v, want %q", err, tt.wantErr)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("writeJSONLines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/byitkc/gosumo/sumoapi"
+)
+
+// runSources runs an action on the sources of a collector.
+func runSources(ctx context.Context, args []string) error {
+	return runActions(ctx, "sources", []command{
+		{"list", "print the sources of a collector", runSourcesList},
+		{"get", "print a source by ID or name", runSourcesGet},
+		{"create", "create a source from JSON", runSourcesCreate},
+		{"delete", "delete a source", runSourcesDelete},
+	}, args)
+}
+
+func runSourcesList(ctx context.Context, args []string) error {
+	fs := newFlagSet("sources list", "[flags] collector")
+	var af apiFlags
+	af.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("expected a single collector ID or name, got %d arguments", fs.NArg())
+	}
+	client, err := af.client(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := collectorID(ctx, client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	sources, err := client.ListSources(ctx, id)
+	if err != nil {
+		return err
+	}
+	return writeJSON(nonNil(sources))
+}
+
+func runSourcesGet(ctx context.Context, args []string) error {
+	fs := newFlagSet("sources get", "[flags] collector source")
+	var af apiFlags
+	af.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return usagef("expected a collector and a source, by ID or name, got %d arguments", fs.NArg())
+	}
+	client, err := af.client(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := collectorID(ctx, client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	source, err := lookupSource(ctx, client, id, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	return writeJSON(source)
+}
+
+func runSourcesCreate(ctx context.Context, args []string) error {
+	fs := newFlagSet("sources create", "[flags] collector")
+	var af apiFlags
+	af.register(fs)
+	file := fs.String("file", "-", "read the source as JSON from `file`, or from stdin if it is -")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("expected a single collector ID or name, got %d arguments", fs.NArg())
+	}
+	var source sumoapi.Source
+	if err := readJSON(*file, &source); err != nil {
+		return err
+	}
+	client, err := af.client(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := collectorID(ctx, client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var created *sumoapi.Source
+	if source.SourceType == sumoapi.SourceHTTP {
+		// The upload URL of an HTTP source is only read back this way.
+		created, err = client.CreateHTTPSource(ctx, id, source)
+	} else {
+		created, err = client.CreateSource(ctx, id, source)
+	}
+	if err != nil {
+		return err
+	}
+	return writeJSON(created)
+}
+
+func runSourcesDelete(ctx context.Context, args []string) error {
+	fs := newFlagSet("sources delete", "[flags] collector source")
+	var af apiFlags
+	af.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return usagef("expected a collector and a source, by ID or name, got %d arguments", fs.NArg())
+	}
+	client, err := af.client(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := collectorID(ctx, client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	source, err := lookupSource(ctx, client, id, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	return client.DeleteSource(ctx, id, source.ID)
+}
+
+// lookupSource returns the source of the collector with the ID, or with the
+// name if s is not a number.
+func lookupSource(ctx context.Context, client *sumoapi.Client, collectorID int64, s string) (*sumoapi.Source, error) {
+	if id, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return client.GetSource(ctx, collectorID, id)
+	}
+	sources, err := client.ListSources(ctx, collectorID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sources {
+		if sources[i].Name == s {
+			return &sources[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no source named %q in collector %d", s, collectorID)
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/byitkc/gosumo"
+)
+
+const (
+	// fingerprintSize is the number of leading bytes of a file whose
+	// checksum identifies it in the state file, so a position is not
+	// applied to a different file that was rotated into its path.
+	fingerprintSize = 1024
+	// maxReadSize caps the bytes read from a file per poll, so a large
+	// backlog is posted in pieces.
+	maxReadSize = 8 << 20
+)
+
+// runTail follows the files and posts the lines appended to them.
+func runTail(ctx context.Context, args []string) error {
+	flags := newFlagSet("tail", "[flags] file...")
+	var ef endpointFlags
+	ef.register(flags)
+	statePath := flags.String("state", "", "record the position in each file in `file`, to resume from it after a restart")
+	fromStart := flags.Bool("from-start", false, "read files without a recorded position from the start instead of the end")
+	poll := flags.Duration("poll", time.Second, "how often the files are checked for new lines")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usagef("at least one file is required")
+	}
+	if *poll <= 0 {
+		return usagef("invalid -poll: must be greater than zero, got: %s", *poll)
+	}
+	client, err := ef.client()
+	if err != nil {
+		return err
+	}
+	state, err := loadTailState(*statePath)
+	if err != nil {
+		return err
+	}
+	files := make([]*tailedFile, flags.NArg())
+	for i, path := range flags.Args() {
+		if path, err = filepath.Abs(path); err != nil {
+			return err
+		}
+		files[i] = &tailedFile{
+			path:       path,
+			fromStart:  *fromStart,
+			saved:      state.Files[path],
+			batchBytes: cmp.Or(ef.batchBytes, gosumo.DefaultMaxBatchBytes),
+			batchLogs:  ef.batchLogs,
+		}
+	}
+	defer func() {
+		for _, f := range files {
+			f.close()
+		}
+	}()
+	t := time.NewTicker(*poll)
+	defer t.Stop()
+	for {
+		for _, f := range files {
+			if err := f.forward(ctx, client, ef.name == ""); err != nil && ctx.Err() == nil {
+				// The lines are read again on the next poll.
+				fmt.Fprintf(os.Stderr, "gosumo tail: %s: %v\n", f.path, err)
+			}
+			if f.f != nil {
+				state.Files[f.path] = f.position()
+			}
+		}
+		if err := state.save(*statePath); err != nil {
+			fmt.Fprintf(os.Stderr, "gosumo tail: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// tailedFile is a file being followed.
+type tailedFile struct {
+	path      string
+	fromStart bool
+	// saved is the position recorded in the state file, which is used
+	// when the file is first opened.
+	saved filePosition
+	// batchBytes and batchLogs limit the lines posted at once, which match
+	// the batch limits of the Client so that each post is a single request.
+	// A limit of 0 disables it.
+	batchBytes int
+	batchLogs  int
+
+	f      *os.File
+	info   fs.FileInfo
+	offset int64
+	// draining is set when the path has been rotated to a new file, so the
+	// rest of the open file is read before switching to it.
+	draining bool
+}
+
+// filePosition is the position in a file recorded in the state file.
+type filePosition struct {
+	Offset int64 `json:"offset"`
+	// Fingerprint is the CRC-32 checksum of the first FingerprintLen bytes
+	// of the file.
+	Fingerprint    uint32 `json:"fingerprint"`
+	FingerprintLen int    `json:"fingerprint_len"`
+}
+
+// forward posts the complete lines appended to the file since the last
+// call, opening it or following its rotation as needed. The lines are posted
+// in batches and the offset is advanced past each batch once it has been
+// posted, so after a failure only the lines that were not posted are read
+// again.
+func (t *tailedFile) forward(ctx context.Context, client *gosumo.Client, sourceName bool) error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	var opts []gosumo.PostOption
+	if sourceName {
+		opts = append(opts, gosumo.PostWithSourceName(t.path))
+	}
+	for t.f != nil && ctx.Err() == nil {
+		data, err := t.read()
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			if t.draining {
+				// The new file is opened on the next poll.
+				t.close()
+			}
+			return nil
+		}
+		for len(data) > 0 {
+			n := t.nextBatch(data)
+			if err := client.PostLogsReader(ctx, bytes.NewReader(data[:n]), opts...); err != nil {
+				return err
+			}
+			t.offset += int64(n)
+			data = data[n:]
+		}
+	}
+	return ctx.Err()
+}
+
+// nextBatch returns the length of the leading lines of data that fit in the
+// batch limits. A line larger than batchBytes is a batch on its own.
+func (t *tailedFile) nextBatch(data []byte) int {
+	n, logs := 0, 0
+	for n < len(data) {
+		end := len(data)
+		if i := bytes.IndexByte(data[n:], '\n'); i >= 0 {
+			end = n + i + 1
+		}
+		if logs > 0 && t.batchBytes > 0 && end > t.batchBytes {
+			break
+		}
+		n = end
+		logs++
+		if t.batchLogs > 0 && logs >= t.batchLogs {
+			break
+		}
+	}
+	return n
+}
+
+// check opens the file if it is not open, and detects rotation and
+// truncation. A missing file is waited for.
+func (t *tailedFile) check() error {
+	info, err := os.Stat(t.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Whatever file appears at the path later is read in full.
+		t.fromStart = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if t.f == nil {
+		return t.open(info)
+	}
+	switch {
+	case !os.SameFile(info, t.info):
+		t.draining, t.fromStart = true, true
+	case info.Size() < t.offset:
+		// The file was truncated in place, so it is read from the start.
+		t.offset = 0
+	}
+	return nil
+}
+
+// open opens the file, resuming from the saved position if it is for the
+// same file.
+func (t *tailedFile) open(info fs.FileInfo) error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	t.f, t.info, t.draining = f, info, false
+	switch {
+	case t.saved != (filePosition{}):
+		t.offset = 0
+		if t.saved.Offset <= info.Size() {
+			if sum, _, err := fingerprint(f, t.saved.FingerprintLen); err == nil && sum == t.saved.Fingerprint {
+				t.offset = t.saved.Offset
+			}
+		}
+		t.saved = filePosition{}
+	case t.fromStart:
+		t.offset = 0
+	default:
+		t.offset = info.Size()
+	}
+	return nil
+}
+
+// read returns the complete lines after the offset, up to maxReadSize. When
+// draining a rotated file, a final line without a newline is included.
+func (t *tailedFile) read() ([]byte, error) {
+	info, err := t.f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	remaining := info.Size() - t.offset
+	if remaining <= 0 {
+		return nil, nil
+	}
+	buf := make([]byte, min(remaining, maxReadSize))
+	n, err := t.f.ReadAt(buf, t.offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	data := buf[:n]
+	if t.draining && int64(n) == remaining {
+		return data, nil
+	}
+	if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+		return data[:i+1], nil
+	}
+	if len(data) == maxReadSize {
+		// A line longer than the read is posted in pieces.
+		return data, nil
+	}
+	return nil, nil
+}
+
+// position returns the position to record in the state file.
+func (t *tailedFile) position() filePosition {
+	p := filePosition{Offset: t.offset}
+	if t.f != nil {
+		p.Fingerprint, p.FingerprintLen, _ = fingerprint(t.f, fingerprintSize)
+	}
+	return p
+}
+
+// close closes the file if it is open.
+func (t *tailedFile) close() {
+	if t.f != nil {
+		t.f.Close()
+		t.f = nil
+	}
+}
+
+// fingerprint returns the checksum of up to n leading bytes of the file and
+// the number of bytes it covers.
+func fingerprint(f *os.File, n int) (uint32, int, error) {
+	buf := make([]byte, n)
+	read, err := f.ReadAt(buf, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, 0, err
+	}
+	return crc32.ChecksumIEEE(buf[:read]), read, nil
+}
+
+// tailState is the content of the state file.
+type tailState struct {
+	// Files holds the position in each file by absolute path.
+	Files map[string]filePosition `json:"files"`
+}
+
+// loadTailState reads the state file at path. A missing file, or an empty
+// path, is an empty state.
+func loadTailState(path string) (*tailState, error) {
+	s := &tailState{Files: make(map[string]filePosition)}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("error parsing state %s: %w", path, err)
+	}
+	if s.Files == nil {
+		s.Files = make(map[string]filePosition)
+	}
+	return s, nil
+}
+
+// save writes the state to the file at path, unless path is empty. It is
+// written to a temporary file and renamed, so a crash never leaves a
+// partially written state.
+func (s *tailState) save(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding state: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("error writing state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error writing state: %w", err)
+	}
+	return nil
+}
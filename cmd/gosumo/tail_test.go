@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/byitkc/gosumo/gosumotest"
+)
+
+// tailTest follows a file and posts its lines to a Collector.
+type tailTest struct {
+	t    *testing.T
+	col  *gosumotest.Collector
+	path string
+	file *tailedFile
+}
+
+func newTailTest(t *testing.T, fromStart bool) *tailTest {
+	col := gosumotest.NewCollector()
+	t.Cleanup(col.Close)
+	path := filepath.Join(t.TempDir(), "app.log")
+	tt := &tailTest{t: t, col: col, path: path, file: &tailedFile{path: path, fromStart: fromStart}}
+	t.Cleanup(tt.file.close)
+	return tt
+}
+
+// write appends to the file, creating it if needed.
+func (tt *tailTest) write(s string) {
+	tt.t.Helper()
+	f, err := os.OpenFile(tt.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		tt.t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(s); err != nil {
+		tt.t.Fatal(err)
+	}
+}
+
+// forward forwards the file and checks the lines posted by it.
+func (tt *tailTest) forward(want ...string) {
+	tt.t.Helper()
+	client, err := tt.col.NewClient()
+	if err != nil {
+		tt.t.Fatal(err)
+	}
+	tt.col.Reset()
+	if err := tt.file.forward(context.Background(), client, true); err != nil {
+		tt.t.Fatal(err)
+	}
+	if got := tt.col.Lines(); !slices.Equal(got, want) {
+		tt.t.Errorf("posted %q, want %q", got, want)
+	}
+	for _, r := range tt.col.Requests() {
+		if r.SourceName != tt.path {
+			tt.t.Errorf("source name = %q, want the path", r.SourceName)
+		}
+	}
+}
+
+func TestTailFollowsAppendedLines(t *testing.T) {
+	tt := newTailTest(t, false)
+	tt.write("existing\n")
+	tt.forward()
+	tt.write("one\ntwo\npart")
+	tt.forward("one", "two")
+	tt.write("ial\n")
+	tt.forward("partial")
+	tt.forward()
+}
+
+func TestTailFromStart(t *testing.T) {
+	tt := newTailTest(t, true)
+	// The file does not exist yet.
+	tt.forward()
+	tt.write("first\n")
+	tt.forward("first")
+}
+
+func TestTailRotation(t *testing.T) {
+	tt := newTailTest(t, true)
+	tt.write("old\n")
+	tt.forward("old")
+	tt.write("last line of old file")
+	if err := os.Rename(tt.path, tt.path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	tt.write("new\n")
+	// The rest of the rotated file is drained, including its final line,
+	// before the new file is read from the start.
+	tt.forward("last line of old file")
+	tt.forward("new")
+}
+
+func TestTailTruncation(t *testing.T) {
+	tt := newTailTest(t, true)
+	tt.write("before truncation\n")
+	tt.forward("before truncation")
+	if err := os.Truncate(tt.path, 0); err != nil {
+		t.Fatal(err)
+	}
+	tt.write("after\n")
+	tt.forward("after")
+}
+
+func TestTailResumesFromState(t *testing.T) {
+	tt := newTailTest(t, false)
+	tt.write("one\n")
+	tt.forward()
+	tt.write("two\n")
+	tt.forward("two")
+	state := &tailState{Files: map[string]filePosition{tt.path: tt.file.position()}}
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	if err := state.save(statePath); err != nil {
+		t.Fatal(err)
+	}
+	tt.file.close()
+
+	loaded, err := loadTailState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tt.write("three\n")
+	tt.file = &tailedFile{path: tt.path, saved: loaded.Files[tt.path]}
+	tt.forward("three")
+
+	// A position for a different file at the path is not used.
+	tt.file.close()
+	if err := os.WriteFile(tt.path, []byte("replaced\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tt.file = &tailedFile{path: tt.path, saved: loaded.Files[tt.path]}
+	tt.forward("replaced")
+}
+
+func TestTailFailedPostIsRetried(t *testing.T) {
+	tt := newTailTest(t, true)
+	tt.write("line\n")
+	tt.col.RespondWithStatus(400)
+	client, err := tt.col.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tt.file.forward(context.Background(), client, false); err == nil {
+		t.Fatal("forward succeeded, want the post error")
+	}
+	// The offset is not advanced, so the line is posted again.
+	tt.forward("line")
+}
+
+func TestTailPartialFailure(t *testing.T) {
+	tt := newTailTest(t, true)
+	tt.file.batchLogs = 2
+	tt.write("one\ntwo\nthree\nfour\nfive\n")
+	// The first batch is posted and the second one fails.
+	tt.col.RespondWithStatus(200, 400)
+	client, err := tt.col.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tt.file.forward(context.Background(), client, false); err == nil {
+		t.Fatal("forward succeeded, want the post error")
+	}
+	// Only the lines that were not posted are posted again.
+	tt.forward("three", "four", "five")
+}
+
+func TestTailNextBatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		batchBytes int
+		batchLogs  int
+		data       string
+		want       string
+	}{
+		{"no limits", 0, 0, "a\nb\nc\n", "a\nb\nc\n"},
+		{"logs", 0, 2, "a\nb\nc\n", "a\nb\n"},
+		{"bytes", 5, 0, "a\nb\nc\n", "a\nb\n"},
+		{"line larger than bytes", 2, 0, "long\nb\n", "long\n"},
+		{"final line without newline", 0, 0, "a\nb", "a\nb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &tailedFile{batchBytes: tt.batchBytes, batchLogs: tt.batchLogs}
+			if got := tt.data[:f.nextBatch([]byte(tt.data))]; got != tt.want {
+				t.Errorf("nextBatch(%q) = %q, want %q", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTailState(t *testing.T) {
+	dir := t.TempDir()
+	s, err := loadTailState(filepath.Join(dir, "missing.json"))
+	if err != nil || len(s.Files) != 0 {
+		t.Errorf("loadTailState of a missing file = %+v, %v", s, err)
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	os.WriteFile(invalid, []byte("{"), 0o600)
+	if _, err := loadTailState(invalid); err == nil {
+		t.Error("loadTailState of invalid JSON succeeded")
+	}
+}
+
+func TestTailUsage(t *testing.T) {
+	testUsage(t, []usageTest{
+		{"tail without files", []string{"tail", "-url", "https://collectors.sumologic.com/receiver/v1/http/x"}, 2, "at least one file"},
+		{"invalid poll", []string{"tail", "-poll", "0s", "file"}, 2, "invalid -poll"},
+	})
+}
@@ -16,12 +16,13 @@ type Log struct {
 
 func main() {
 	logs := generateLogs()
-	sumoLogEndpoint, err := gosumo.NewLogEndpoint("<endpointURL>")
+	client, err := gosumo.NewClient("<endpointURL>", gosumo.WithTimeout(time.Second*30))
 	if err != nil {
-		slog.Error("error intializing Log Endpoint", "error", err)
+		slog.Error("error intializing Sumo Logic client", "error", err)
+		os.Exit(1)
 	}
 
-	if err := gosumo.PostLogs(sumoLogEndpoint, logs); err != nil {
+	if err := gosumo.PostLogs(client, logs); err != nil {
 		slog.Error("error posting logs to Sumo Logic", "error", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,96 @@
+package gosumo
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// DefaultMinCompressionSize is the payload size in bytes below which payloads
+// are posted uncompressed when compression is enabled.
+const DefaultMinCompressionSize = 1024
+
+// Compression is the algorithm used to compress payloads before they are
+// posted to Sumo Logic.
+type Compression int
+
+const (
+	// NoCompression posts payloads as-is.
+	NoCompression Compression = iota
+	// Gzip compresses payloads with gzip.
+	Gzip
+	// Deflate compresses payloads with deflate (zlib format).
+	Deflate
+)
+
+// String returns the Content-Encoding value for the Compression.
+func (c Compression) String() string {
+	switch c {
+	case Gzip:
+		return "gzip"
+	case Deflate:
+		return "deflate"
+	default:
+		return "none"
+	}
+}
+
+// ParseCompression returns the Compression named by s, which is one of the
+// values returned by Compression.String. An empty string is NoCompression.
+func ParseCompression(s string) (Compression, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none":
+		return NoCompression, nil
+	case "gzip":
+		return Gzip, nil
+	case "deflate":
+		return Deflate, nil
+	}
+	return NoCompression, fmt.Errorf("unsupported compression: %q", s)
+}
+
+// WithCompression enables compression of payloads using the provided
+// algorithm. Payloads smaller than the minimum compression size are not
+// compressed, see WithMinCompressionSize.
+func WithCompression(alg Compression) Option {
+	return func(c *Client) error {
+		switch alg {
+		case NoCompression, Gzip, Deflate:
+		default:
+			return fmt.Errorf("unsupported compression: %d", alg)
+		}
+		c.compression = alg
+		return nil
+	}
+}
+
+// WithMinCompressionSize sets the payload size in bytes below which payloads
+// are not compressed. The default is DefaultMinCompressionSize, and a value of
+// 0 compresses every payload.
+func WithMinCompressionSize(n int) Option {
+	return func(c *Client) error {
+		if n < 0 {
+			return fmt.Errorf("minimum compression size cannot be negative, got: %d", n)
+		}
+		c.minCompressionSize = n
+		return nil
+	}
+}
+
+// compress compresses the payload using the provided algorithm into a buffer
+// from the pool. The caller must return the buffer with putBuffer once it is
+// no longer needed.
+func compress(alg Compression, p []byte) (*bytes.Buffer, error) {
+	buf := getBuffer()
+	w, pool := getCompressWriter(alg, buf)
+	defer pool.Put(w)
+	if _, err := w.Write(p); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,42 @@
+package gosumo_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/byitkc/gosumo"
+)
+
+func TestPostCompression(t *testing.T) {
+	tests := []struct {
+		name     string
+		alg      gosumo.Compression
+		opts     []gosumo.Option
+		encoding string
+	}{
+		{"none", gosumo.NoCompression, nil, ""},
+		{"gzip", gosumo.Gzip, []gosumo.Option{gosumo.WithMinCompressionSize(0)}, "gzip"},
+		{"deflate", gosumo.Deflate, []gosumo.Option{gosumo.WithMinCompressionSize(0)}, "deflate"},
+		// Payloads smaller than DefaultMinCompressionSize are not compressed.
+		{"below min size", gosumo.Gzip, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := newCollector(t)
+			c, err := col.NewClient(append([]gosumo.Option{gosumo.WithCompression(tt.alg)}, tt.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.PostLogsString("some\nlogs"); err != nil {
+				t.Fatal(err)
+			}
+			r := col.Requests()[0]
+			if r.ContentEncoding != tt.encoding {
+				t.Errorf("Content-Encoding = %q, want %q", r.ContentEncoding, tt.encoding)
+			}
+			if got, want := r.Lines(), []string{"some", "logs"}; !slices.Equal(got, want) {
+				t.Errorf("lines = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package gosumo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the settings of a Client, and optionally of a BufferedSender
+// built on top of it, so shipping behaviour can be managed as a configuration
+// file instead of code. It can be loaded from YAML or JSON with LoadConfig.
+// Settings that are left out keep the defaults used by NewClient and
+// NewBufferedSender.
+type Config struct {
+	// Endpoint is the URL of the HTTP source. It is required.
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	// Timeout is the timeout for each request.
+	Timeout Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Source sets the source headers sent with every request.
+	Source SourceConfig `json:"source,omitempty" yaml:"source,omitempty"`
+	// Fields are attached to every request using the X-Sumo-Fields header.
+	Fields map[string]string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// Compression is one of none, gzip or deflate.
+	Compression string `json:"compression,omitempty" yaml:"compression,omitempty"`
+	// MinCompressionSize is the payload size in bytes below which payloads
+	// are not compressed.
+	MinCompressionSize *int `json:"min_compression_size,omitempty" yaml:"min_compression_size,omitempty"`
+	// Batch sets the batch limits.
+	Batch BatchConfig `json:"batch,omitempty" yaml:"batch,omitempty"`
+	// Retry enables retries when it is set.
+	Retry *RetryConfig `json:"retry,omitempty" yaml:"retry,omitempty"`
+	// Buffer configures a BufferedSender, see BufferOptions.
+	Buffer BufferConfig `json:"buffer,omitempty" yaml:"buffer,omitempty"`
+}
+
+// SourceConfig holds the source headers of a Config.
+type SourceConfig struct {
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+	Host     string `json:"host,omitempty" yaml:"host,omitempty"`
+	Category string `json:"category,omitempty" yaml:"category,omitempty"`
+}
+
+// BatchConfig holds the batch limits of a Config.
+type BatchConfig struct {
+	MaxBytes *int `json:"max_bytes,omitempty" yaml:"max_bytes,omitempty"`
+	MaxLogs  *int `json:"max_logs,omitempty" yaml:"max_logs,omitempty"`
+}
+
+// RetryConfig holds the retry policy of a Config. Settings that are left out
+// are taken from DefaultRetryPolicy.
+type RetryConfig struct {
+	MaxAttempts    int      `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"`
+	InitialBackoff Duration `json:"initial_backoff,omitempty" yaml:"initial_backoff,omitempty"`
+	MaxBackoff     Duration `json:"max_backoff,omitempty" yaml:"max_backoff,omitempty"`
+	Multiplier     float64  `json:"multiplier,omitempty" yaml:"multiplier,omitempty"`
+	Jitter         *float64 `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+}
+
+// BufferConfig holds the BufferedSender settings of a Config.
+type BufferConfig struct {
+	FlushInterval Duration `json:"flush_interval,omitempty" yaml:"flush_interval,omitempty"`
+	FlushLogs     *int     `json:"flush_logs,omitempty" yaml:"flush_logs,omitempty"`
+	FlushBytes    *int     `json:"flush_bytes,omitempty" yaml:"flush_bytes,omitempty"`
+	MaxLogs       *int     `json:"max_logs,omitempty" yaml:"max_logs,omitempty"`
+	MaxBytes      *int     `json:"max_bytes,omitempty" yaml:"max_bytes,omitempty"`
+	// Overflow is one of error, block, drop-oldest or drop-newest.
+	Overflow string `json:"overflow,omitempty" yaml:"overflow,omitempty"`
+	// SpoolDir enables spooling to disk, capped at SpoolMaxBytes.
+	SpoolDir      string `json:"spool_dir,omitempty" yaml:"spool_dir,omitempty"`
+	SpoolMaxBytes int64  `json:"spool_max_bytes,omitempty" yaml:"spool_max_bytes,omitempty"`
+}
+
+// Duration is a time.Duration that is written in configuration files as a
+// string such as "5s" or "1m30s".
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"5s\"")
+	}
+	return d.parse(s)
+}
+
+// MarshalJSON writes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalYAML parses a duration string.
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: duration must be a string such as \"5s\"", node.Line)
+	}
+	if err := d.parse(node.Value); err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	return nil
+}
+
+// MarshalYAML writes the duration as a string.
+func (d Duration) MarshalYAML() (any, error) {
+	return time.Duration(d).String(), nil
+}
+
+// parse sets the duration from s.
+func (d *Duration) parse(s string) error {
+	v, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", s)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// LoadConfig reads and validates the Config in the file at path. Files ending
+// in .yaml or .yml are parsed as YAML and anything else as JSON. Unknown
+// settings are rejected, so typos are caught instead of being ignored.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config: %w", err)
+	}
+	var cfg Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error parsing config %s: %w", path, err)
+		}
+	default:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("error parsing config %s: %w", path, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks every setting of the Config and returns an error naming
+// the first one that is invalid.
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.Endpoint) == "" {
+		return fmt.Errorf("endpoint: is required")
+	}
+	if err := validateEndpointURL(c.Endpoint, urlPolicy{}); err != nil {
+		return fmt.Errorf("endpoint: %w", err)
+	}
+	opts, err := c.ClientOptions()
+	if err != nil {
+		return err
+	}
+	scratch := &Client{}
+	for _, opt := range opts {
+		if err := opt(scratch); err != nil {
+			return err
+		}
+	}
+	// The spool is checked separately, since building it creates the
+	// directory.
+	if c.Buffer.SpoolDir != "" && c.Buffer.SpoolMaxBytes <= 0 {
+		return fmt.Errorf("buffer.spool_max_bytes: must be greater than zero, got: %d", c.Buffer.SpoolMaxBytes)
+	}
+	bufOpts, err := c.bufferOptions(false)
+	if err != nil {
+		return err
+	}
+	for _, opt := range bufOpts {
+		if err := opt(&BufferedSender{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewClient creates and returns a new Client from the Config. The provided
+// options are applied after the ones from the Config, so they take
+// precedence.
+func (c *Config) NewClient(opts ...Option) (*Client, error) {
+	cfgOpts, err := c.ClientOptions()
+	if err != nil {
+		return nil, ErrBuildingClient{
+			Message: fmt.Sprintf("unable to build client: %v", err),
+			Err:     err,
+		}
+	}
+	return NewClient(c.Endpoint, append(cfgOpts, opts...)...)
+}
+
+// ClientOptions returns the Options described by the Config. Errors from the
+// options name the setting they came from.
+func (c *Config) ClientOptions() ([]Option, error) {
+	var opts []Option
+	add := func(key string, opt Option) {
+		opts = append(opts, configOption(key, opt))
+	}
+	if c.Timeout != 0 {
+		add("timeout", WithTimeout(time.Duration(c.Timeout)))
+	}
+	if c.Source.Name != "" {
+		add("source.name", WithSourceName(c.Source.Name))
+	}
+	if c.Source.Host != "" {
+		add("source.host", WithSourceHost(c.Source.Host))
+	}
+	if c.Source.Category != "" {
+		add("source.category", WithSourceCategory(c.Source.Category))
+	}
+	if len(c.Fields) > 0 {
+		add("fields", WithFields(c.Fields))
+	}
+	if c.Compression != "" {
+		alg, err := ParseCompression(c.Compression)
+		if err != nil {
+			return nil, fmt.Errorf("compression: %w", err)
+		}
+		add("compression", WithCompression(alg))
+	}
+	if c.MinCompressionSize != nil {
+		add("min_compression_size", WithMinCompressionSize(*c.MinCompressionSize))
+	}
+	if c.Batch.MaxBytes != nil {
+		add("batch.max_bytes", WithMaxBatchBytes(*c.Batch.MaxBytes))
+	}
+	if c.Batch.MaxLogs != nil {
+		add("batch.max_logs", WithMaxBatchLogs(*c.Batch.MaxLogs))
+	}
+	if r := c.Retry; r != nil {
+		p := DefaultRetryPolicy
+		if r.MaxAttempts != 0 {
+			p.MaxAttempts = r.MaxAttempts
+		}
+		if r.InitialBackoff != 0 {
+			p.InitialBackoff = time.Duration(r.InitialBackoff)
+		}
+		if r.MaxBackoff != 0 {
+			p.MaxBackoff = time.Duration(r.MaxBackoff)
+		}
+		if r.Multiplier != 0 {
+			p.Multiplier = r.Multiplier
+		}
+		if r.Jitter != nil {
+			p.Jitter = *r.Jitter
+		}
+		add("retry", WithRetry(p))
+	}
+	return opts, nil
+}
+
+// BufferOptions returns the BufferOptions described by the Config's buffer
+// settings, for use with NewBufferedSender or NewWriter. Errors from the
+// options name the setting they came from.
+func (c *Config) BufferOptions() ([]BufferOption, error) {
+	return c.bufferOptions(true)
+}
+
+// bufferOptions implements BufferOptions, leaving out the spool unless
+// withSpool is set.
+func (c *Config) bufferOptions(withSpool bool) ([]BufferOption, error) {
+	var opts []BufferOption
+	add := func(key string, opt BufferOption) {
+		opts = append(opts, func(s *BufferedSender) error {
+			if err := opt(s); err != nil {
+				return fmt.Errorf("buffer.%s: %w", key, err)
+			}
+			return nil
+		})
+	}
+	b := c.Buffer
+	if b.FlushInterval != 0 {
+		add("flush_interval", WithFlushInterval(time.Duration(b.FlushInterval)))
+	}
+	if b.FlushLogs != nil {
+		add("flush_logs", WithFlushLogs(*b.FlushLogs))
+	}
+	if b.FlushBytes != nil {
+		add("flush_bytes", WithFlushBytes(*b.FlushBytes))
+	}
+	if b.MaxLogs != nil {
+		add("max_logs", WithMaxBufferedLogs(*b.MaxLogs))
+	}
+	if b.MaxBytes != nil {
+		add("max_bytes", WithMaxBufferedBytes(*b.MaxBytes))
+	}
+	if b.Overflow != "" {
+		p, err := parseOverflowPolicy(b.Overflow)
+		if err != nil {
+			return nil, fmt.Errorf("buffer.overflow: %w", err)
+		}
+		add("overflow", WithOverflowPolicy(p))
+	}
+	if b.SpoolDir != "" && withSpool {
+		add("spool_dir", WithSpool(b.SpoolDir, b.SpoolMaxBytes))
+	} else if b.SpoolDir == "" && b.SpoolMaxBytes != 0 {
+		return nil, fmt.Errorf("buffer.spool_max_bytes: requires spool_dir")
+	}
+	return opts, nil
+}
+
+// configOption wraps an Option so its error names the setting it came from.
+func configOption(key string, opt Option) Option {
+	return func(c *Client) error {
+		if err := opt(c); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		return nil
+	}
+}
+
+// parseOverflowPolicy returns the OverflowPolicy named by s, which is one of
+// the values returned by OverflowPolicy.String.
+func parseOverflowPolicy(s string) (OverflowPolicy, error) {
+	for _, p := range []OverflowPolicy{OverflowError, OverflowBlock, OverflowDropOldest, OverflowDropNewest} {
+		if strings.EqualFold(strings.TrimSpace(s), p.String()) {
+			return p, nil
+		}
+	}
+	return OverflowError, fmt.Errorf("unsupported overflow policy: %q", s)
+}
@@ -0,0 +1,36 @@
+package gosumo
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// WithDeadLetter registers a function that is called with the payload and
+// error of every batch that could not be posted, once any retries are
+// exhausted, so the application can persist or re-route the logs instead of
+// losing them. The payload is the uncompressed request body, such as
+// newline delimited logs, and belongs to the function. It is called before
+// the error is returned to the caller, and must be safe for concurrent use.
+//
+// A BufferedSender with a spool also keeps the logs of batches that fail
+// because the endpoint is unavailable, so using both can store those logs
+// twice.
+func WithDeadLetter(fn func(payload []byte, err error)) Option {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("dead letter handler cannot be nil")
+		}
+		c.deadLetter = fn
+		return nil
+	}
+}
+
+// sendDeadLetter passes a copy of a failed payload to the dead letter
+// handler, if there is one. The payload may be a pooled buffer that is reused
+// once post returns, which is why it is copied.
+func (c *Client) sendDeadLetter(payload []byte, err error) {
+	if c.deadLetter == nil {
+		return
+	}
+	c.deadLetter(bytes.Clone(payload), err)
+}
@@ -0,0 +1,32 @@
+package gosumo_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/byitkc/gosumo"
+)
+
+func TestDeadLetter(t *testing.T) {
+	col := newCollector(t)
+	col.RespondWithStatus(http.StatusBadRequest)
+	var payloads []string
+	var errs []error
+	c, err := col.NewClient(gosumo.WithDeadLetter(func(payload []byte, err error) {
+		payloads = append(payloads, string(payload))
+		errs = append(errs, err)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostLogsString("one\ntwo"); err == nil {
+		t.Fatal("post succeeded, want an error")
+	}
+	if err := c.PostLogsString("three"); err != nil {
+		t.Fatal(err)
+	}
+	if len(payloads) != 1 || payloads[0] != "one\ntwo" || !errors.Is(errs[0], gosumo.ErrClientError) {
+		t.Errorf("dead letters = %q, %v", payloads, errs)
+	}
+}
@@ -0,0 +1,109 @@
+package gosumo
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultRepeatField is the field used to record how many times a log was
+// repeated when WithDedupe is given an empty field name.
+const DefaultRepeatField = "repeat_count"
+
+// WithDedupe makes the BufferedSender collapse identical logs into a single
+// record, like syslog does, to reduce ingest volume during error storms. With
+// a window of 0 only consecutive identical logs are collapsed, otherwise any
+// log identical to one enqueued less than window ago is. Logs are compared as
+// they are passed to Send or Enqueue, before the Client adds a timestamp.
+//
+// A record is complete when the buffer is flushed, so a window longer than
+// the flush interval has no effect. When a log was repeated, the number of
+// times it was seen is added to JSON object logs in the named field, and
+// other logs are suffixed with "[repeated N times]".
+func WithDedupe(window time.Duration, field string) BufferOption {
+	return func(s *BufferedSender) error {
+		if window < 0 {
+			return fmt.Errorf("dedupe window cannot be negative, got: %s", window)
+		}
+		if field == "" {
+			field = DefaultRepeatField
+		}
+		s.dedupe = &deduper{window: window, field: field, seen: make(map[string]*repeat)}
+		return nil
+	}
+}
+
+// deduper tracks the logs in a BufferedSender's buffer that can still be
+// collapsed. It is protected by the BufferedSender's lock.
+type deduper struct {
+	window time.Duration
+	field  string
+
+	seen map[string]*repeat
+	last string
+	// start is the sequence number of the first log in the buffer, which
+	// moves forward when the oldest log is dropped.
+	start int
+}
+
+// repeat is a log in the buffer and the number of times it has been seen.
+type repeat struct {
+	seq   int
+	count int
+	first time.Time
+}
+
+// collapse reports whether the log with the provided key repeats one in the
+// buffer, and counts it if so.
+func (d *deduper) collapse(key string, now time.Time) bool {
+	r, ok := d.seen[key]
+	if !ok || r.seq < d.start {
+		return false
+	}
+	if d.window == 0 && key != d.last {
+		return false
+	}
+	if d.window > 0 && now.Sub(r.first) >= d.window {
+		return false
+	}
+	r.count++
+	return true
+}
+
+// record remembers a log that was added to the buffer at index i.
+func (d *deduper) record(key string, i int, now time.Time) {
+	if d.window == 0 {
+		clear(d.seen)
+	}
+	d.seen[key] = &repeat{seq: d.start + i, count: 1, first: now}
+	d.last = key
+}
+
+// dropOldest is called when the oldest log is removed from the buffer.
+func (d *deduper) dropOldest() {
+	d.start++
+}
+
+// apply adds the repeat counts to the buffered lines and forgets every log,
+// since they are about to leave the buffer.
+func (d *deduper) apply(lines [][]byte) {
+	for _, r := range d.seen {
+		i := r.seq - d.start
+		if r.count < 2 || i < 0 || i >= len(lines) {
+			continue
+		}
+		lines[i] = withRepeatCount(lines[i], d.field, r.count)
+	}
+	clear(d.seen)
+	d.last = ""
+	d.start = 0
+}
+
+// withRepeatCount returns the line with the repeat count added.
+func withRepeatCount(line []byte, field string, count int) []byte {
+	if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 && trimmed[0] == '{' {
+		return addJSONField(line, field, strconv.AppendInt(nil, int64(count), 10))
+	}
+	return fmt.Appendf(append([]byte(nil), line...), " [repeated %d times]", count)
+}
@@ -0,0 +1,70 @@
+package gosumo
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// collectorPathRegexp matches the path of a Sumo Logic HTTP source URL.
+var collectorPathRegexp = regexp.MustCompile(`^/receiver/v1/(http|otlp)/[A-Za-z0-9_=+\-]+(/.*)?$`)
+
+// urlPolicy controls how strictly an endpoint URL is validated.
+type urlPolicy struct {
+	insecure     bool
+	validatePath bool
+}
+
+// WithInsecureURL allows the Client to post to endpoint URLs that do not use
+// https, such as an httptest.Server, and skips collector path validation. It
+// should only be used in tests.
+func WithInsecureURL() Option {
+	return func(c *Client) error {
+		c.urlPolicy.insecure = true
+		return nil
+	}
+}
+
+// WithCollectorPathValidation makes the Client check that the endpoint URL
+// has the path of a Sumo Logic HTTP source, /receiver/v1/http/<token>, to
+// catch URLs that have been copied incorrectly.
+func WithCollectorPathValidation() Option {
+	return func(c *Client) error {
+		c.urlPolicy.validatePath = true
+		return nil
+	}
+}
+
+// validateEndpointURL parses the URL and checks it against the policy.
+func validateEndpointURL(endpointURL string, p urlPolicy) error {
+	u, err := url.Parse(endpointURL)
+	if err != nil {
+		return err
+	}
+	if p.insecure {
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("scheme must be http or https, got: '%s'", u.Scheme)
+		}
+	} else if u.Scheme != "https" {
+		return fmt.Errorf("scheme must be https, got: '%s'", u.Scheme)
+	}
+	if u.Host == "" || u.Hostname() == "" {
+		return fmt.Errorf("host cannot be empty")
+	}
+	if p.validatePath && !p.insecure && !collectorPathRegexp.MatchString(u.Path) {
+		return fmt.Errorf("path must be of the form /receiver/v1/http/<token>, got: '%s'", u.Path)
+	}
+	return nil
+}
+
+// newLogEndpoint creates a LogEndpoint after validating the URL against the
+// policy.
+func newLogEndpoint(endpointURL string, p urlPolicy) (LogEndpoint, error) {
+	if err := validateEndpointURL(endpointURL, p); err != nil {
+		return LogEndpoint{}, ErrBuildingClient{
+			Message: fmt.Sprintf("unable to build client using the URL '%s': %v", endpointURL, err),
+			Err:     fmt.Errorf("%w: %w", ErrInvalidURL, err),
+		}
+	}
+	return LogEndpoint{URL: endpointURL}, nil
+}
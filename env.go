@@ -0,0 +1,107 @@
+package gosumo
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv.
+const (
+	EnvHTTPSourceURL      = "SUMO_HTTP_SOURCE_URL"
+	EnvSourceCategory     = "SUMO_SOURCE_CATEGORY"
+	EnvSourceName         = "SUMO_SOURCE_NAME"
+	EnvSourceHost         = "SUMO_SOURCE_HOST"
+	EnvFields             = "SUMO_FIELDS"
+	EnvCompression        = "SUMO_COMPRESSION"
+	EnvMinCompressionSize = "SUMO_MIN_COMPRESSION_SIZE"
+	EnvMaxBatchBytes      = "SUMO_MAX_BATCH_BYTES"
+	EnvMaxBatchLogs       = "SUMO_MAX_BATCH_LOGS"
+	EnvTimeout            = "SUMO_TIMEOUT"
+)
+
+// NewClientFromEnv creates and returns a new Client configured from the
+// environment, so applications can configure shipping without code changes.
+// The endpoint URL is read from SUMO_HTTP_SOURCE_URL, which is required. The
+// other variables are optional:
+//
+//   - SUMO_SOURCE_CATEGORY, SUMO_SOURCE_NAME and SUMO_SOURCE_HOST set the
+//     source headers.
+//   - SUMO_FIELDS sets the fields, as comma separated key=value pairs.
+//   - SUMO_COMPRESSION is one of none, gzip or deflate, and
+//     SUMO_MIN_COMPRESSION_SIZE is a number of bytes.
+//   - SUMO_MAX_BATCH_BYTES and SUMO_MAX_BATCH_LOGS set the batch limits.
+//   - SUMO_TIMEOUT is a duration such as 10s.
+//
+// The provided options are applied after the ones read from the environment,
+// so they take precedence.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	endpointURL, envOpts, err := envOptions(os.LookupEnv)
+	if err != nil {
+		return nil, ErrBuildingClient{
+			Message: fmt.Sprintf("unable to build client from environment: %v", err),
+			Err:     err,
+		}
+	}
+	return NewClient(endpointURL, append(envOpts, opts...)...)
+}
+
+// envOptions returns the endpoint URL and the options described by the
+// variables returned by lookup.
+func envOptions(lookup func(string) (string, bool)) (string, []Option, error) {
+	endpointURL, ok := lookup(EnvHTTPSourceURL)
+	if !ok || endpointURL == "" {
+		return "", nil, fmt.Errorf("%s is not set", EnvHTTPSourceURL)
+	}
+	var opts []Option
+	if v, ok := lookup(EnvSourceCategory); ok && v != "" {
+		opts = append(opts, WithSourceCategory(v))
+	}
+	if v, ok := lookup(EnvSourceName); ok && v != "" {
+		opts = append(opts, WithSourceName(v))
+	}
+	if v, ok := lookup(EnvSourceHost); ok && v != "" {
+		opts = append(opts, WithSourceHost(v))
+	}
+	if v, ok := lookup(EnvFields); ok && v != "" {
+		fields, err := ParseFields(v)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid %s: %w", EnvFields, err)
+		}
+		opts = append(opts, WithFields(fields))
+	}
+	if v, ok := lookup(EnvCompression); ok && v != "" {
+		alg, err := ParseCompression(v)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid %s: %w", EnvCompression, err)
+		}
+		opts = append(opts, WithCompression(alg))
+	}
+	ints := []struct {
+		name string
+		opt  func(int) Option
+	}{
+		{EnvMinCompressionSize, WithMinCompressionSize},
+		{EnvMaxBatchBytes, WithMaxBatchBytes},
+		{EnvMaxBatchLogs, WithMaxBatchLogs},
+	}
+	for _, i := range ints {
+		if v, ok := lookup(i.name); ok && v != "" {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid %s: %q is not a number", i.name, v)
+			}
+			opts = append(opts, i.opt(n))
+		}
+	}
+	if v, ok := lookup(EnvTimeout); ok && v != "" {
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid %s: %w", EnvTimeout, err)
+		}
+		opts = append(opts, WithTimeout(d))
+	}
+	return endpointURL, opts, nil
+}
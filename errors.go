@@ -1,25 +1,176 @@
 package gosumo
 
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors for specific failures. They are wrapped by the error types
+// below and can be checked with errors.Is.
+var (
+	// ErrInvalidURL is returned when an endpoint URL is not valid.
+	ErrInvalidURL = errors.New("invalid endpoint url")
+	// ErrMissingJSONMetadata is returned when a log cannot be encoded
+	// because it is missing JSON metadata.
+	ErrMissingJSONMetadata = errors.New("object is missing json metadata")
+	// ErrSenderClosed is returned when logs are added to a sender that has
+	// been closed.
+	ErrSenderClosed = errors.New("sender is closed")
+	// ErrBufferFull is returned when logs are added to a sender whose buffer
+	// is full.
+	ErrBufferFull = errors.New("buffer is full")
+
+	// ErrThrottled matches an HTTPError with a 429 status code, returned
+	// when Sumo Logic is throttling requests.
+	ErrThrottled = errors.New("throttled by sumo logic")
+	// ErrClientError matches an HTTPError with a 4xx status code other than
+	// 429, which means the request was rejected and should not be retried
+	// without changes.
+	ErrClientError = errors.New("request rejected by sumo logic")
+	// ErrServerError matches an HTTPError with a 5xx status code.
+	ErrServerError = errors.New("sumo logic server error")
+)
+
+// ErrBuildingClient is returned when a Client, or a type built on a Client,
+// cannot be created because of invalid settings. Use
+// errors.Is(err, ErrBuildingClient{}) to check for this category of error.
 type ErrBuildingClient struct {
 	Message string
+	Err     error
 }
 
 func (e ErrBuildingClient) Error() string {
-	return e.Message
+	return errorMessage(e.Message, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ErrBuildingClient) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is an ErrBuildingClient.
+func (e ErrBuildingClient) Is(target error) bool {
+	_, ok := target.(ErrBuildingClient)
+	return ok
 }
 
+// ErrPostingLogs is returned when logs or metrics cannot be posted to Sumo
+// Logic. When the failure is an unexpected response it wraps an HTTPError.
+// Use errors.Is(err, ErrPostingLogs{}) to check for this category of error.
 type ErrPostingLogs struct {
 	Message string
+	Err     error
 }
 
 func (e ErrPostingLogs) Error() string {
-	return e.Message
+	return errorMessage(e.Message, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ErrPostingLogs) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is an ErrPostingLogs.
+func (e ErrPostingLogs) Is(target error) bool {
+	_, ok := target.(ErrPostingLogs)
+	return ok
 }
 
+// ErrParsingLogs is returned when logs or metrics cannot be encoded before
+// they are posted. Use errors.Is(err, ErrParsingLogs{}) to check for this
+// category of error.
 type ErrParsingLogs struct {
 	Message string
+	Err     error
 }
 
 func (e ErrParsingLogs) Error() string {
-	return e.Message
+	return errorMessage(e.Message, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ErrParsingLogs) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is an ErrParsingLogs.
+func (e ErrParsingLogs) Is(target error) bool {
+	_, ok := target.(ErrParsingLogs)
+	return ok
+}
+
+// HTTPError is returned when Sumo Logic responds with an unexpected status
+// code. Use errors.As to retrieve it from the errors returned by the Client.
+type HTTPError struct {
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// ResponseBody is the body of the response, which often explains why the
+	// request was rejected. It is truncated to the Client's maximum error
+	// body size, see WithMaxErrorBodySize.
+	ResponseBody string
+	// RetryAfter is the delay requested by the Retry-After header, if any.
+	RetryAfter time.Duration
+	// Retryable reports whether the failure is transient and the request can
+	// be retried.
+	Retryable bool
+}
+
+func (e HTTPError) Error() string {
+	msg := fmt.Sprintf("unexpected status code: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.ResponseBody != "" {
+		msg += ": " + e.ResponseBody
+	}
+	return msg
+}
+
+// Is reports whether the target is the sentinel for the class of the status
+// code: ErrThrottled, ErrClientError or ErrServerError.
+func (e HTTPError) Is(target error) bool {
+	switch target {
+	case ErrThrottled:
+		return e.IsThrottled()
+	case ErrClientError:
+		return e.IsClientError()
+	case ErrServerError:
+		return e.IsServerError()
+	}
+	return false
+}
+
+// IsThrottled reports whether the status code is 429 Too Many Requests.
+func (e HTTPError) IsThrottled() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// IsClientError reports whether the status code is a 4xx other than 429.
+func (e HTTPError) IsClientError() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 && !e.IsThrottled()
+}
+
+// IsServerError reports whether the status code is a 5xx.
+func (e HTTPError) IsServerError() bool {
+	return e.StatusCode >= 500 && e.StatusCode < 600
+}
+
+// IsRetryable reports whether err is a transient failure that is worth
+// retrying, either an HTTPError marked as retryable or a network timeout.
+func IsRetryable(err error) bool {
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Retryable
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// errorMessage returns the message, falling back to the wrapped error.
+func errorMessage(msg string, err error) string {
+	if msg == "" && err != nil {
+		return err.Error()
+	}
+	return msg
 }
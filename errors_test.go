@@ -0,0 +1,32 @@
+package gosumo_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/byitkc/gosumo"
+	"github.com/byitkc/gosumo/gosumotest"
+)
+
+func TestPostErrorBody(t *testing.T) {
+	col := newCollector(t)
+	col.Respond(gosumotest.Response{StatusCode: http.StatusBadRequest, Body: "bad payload"})
+	c, err := col.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.PostLogsString("line")
+	var httpErr gosumo.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("post error = %v, want an HTTPError", err)
+	}
+	if httpErr.StatusCode != http.StatusBadRequest || !strings.Contains(httpErr.ResponseBody, "bad payload") {
+		t.Errorf("HTTPError = %+v", httpErr)
+	}
+	var postErr gosumo.ErrPostingLogs
+	if !errors.As(err, &postErr) {
+		t.Errorf("post error = %T, want ErrPostingLogs", err)
+	}
+}
@@ -0,0 +1,118 @@
+package gosumo
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// HeaderFields is the header used to attach custom fields to a request.
+const HeaderFields = "X-Sumo-Fields"
+
+// Limits on the fields that can be sent with the X-Sumo-Fields header.
+const (
+	MaxFields           = 30
+	MaxFieldNameLength  = 255
+	MaxFieldValueLength = 200
+)
+
+var fieldNameRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// validateFields checks the fields against Sumo Logic's restrictions on field
+// names, values and the number of fields per request.
+func validateFields(fields map[string]string) error {
+	if len(fields) > MaxFields {
+		return fmt.Errorf("too many fields, maximum: %d, got: %d", MaxFields, len(fields))
+	}
+	for k, v := range fields {
+		if len(k) > MaxFieldNameLength {
+			return fmt.Errorf("field name '%s' is longer than %d characters", k, MaxFieldNameLength)
+		}
+		if !fieldNameRegexp.MatchString(k) {
+			return fmt.Errorf("field name '%s' must start with a letter and contain only letters, numbers and underscores", k)
+		}
+		if len(v) > MaxFieldValueLength {
+			return fmt.Errorf("value of field '%s' is longer than %d characters", k, MaxFieldValueLength)
+		}
+		if strings.ContainsAny(v, ",=\r\n") {
+			return fmt.Errorf("value of field '%s' cannot contain commas, equals signs or newlines", k)
+		}
+	}
+	return nil
+}
+
+// encodeFields serializes the fields into the key=value,key=value format used
+// by the X-Sumo-Fields header. Keys are sorted so the output is stable.
+func encodeFields(fields map[string]string) string {
+	pairs := make([]string, 0, len(fields))
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		pairs = append(pairs, k+"="+fields[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// mergeFields returns a new map containing base with the values from o
+// overriding it.
+func mergeFields(base, o map[string]string) map[string]string {
+	if len(o) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(o))
+	maps.Copy(merged, base)
+	maps.Copy(merged, o)
+	return merged
+}
+
+// applyFields sets the X-Sumo-Fields header if there are any fields.
+func applyFields(hdr http.Header, fields map[string]string) {
+	if len(fields) > 0 {
+		hdr.Set(HeaderFields, encodeFields(fields))
+	}
+}
+
+// ParseFields parses fields written as comma separated key=value pairs, the
+// same format used by the X-Sumo-Fields header, such as "env=prod,team=core".
+// Whitespace around keys and values is ignored.
+func ParseFields(s string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("field %q is not a key=value pair", pair)
+		}
+		fields[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	if err := validateFields(fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// WithFields attaches the provided fields to every request made by the Client
+// using the X-Sumo-Fields header. Calling it more than once adds to the
+// existing fields.
+func WithFields(fields map[string]string) Option {
+	return func(c *Client) error {
+		merged := mergeFields(c.fields, fields)
+		if err := validateFields(merged); err != nil {
+			return err
+		}
+		c.fields = merged
+		return nil
+	}
+}
+
+// PostWithFields attaches the provided fields to a single post. They are
+// combined with the Client's fields, overriding any with the same name.
+func PostWithFields(fields map[string]string) PostOption {
+	return func(cfg *postConfig) {
+		cfg.fields = mergeFields(cfg.fields, fields)
+	}
+}
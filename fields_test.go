@@ -0,0 +1,77 @@
+package gosumo_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/byitkc/gosumo"
+)
+
+func TestPostFields(t *testing.T) {
+	var headers []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get(gosumo.HeaderFields))
+	}))
+	defer srv.Close()
+	c, err := gosumo.NewClient(srv.URL, gosumo.WithInsecureURL(),
+		gosumo.WithFields(map[string]string{"team": "core", "env": "dev"}),
+		gosumo.WithFields(map[string]string{"env": "prod"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostLogsString("line"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostLogsString("line", gosumo.PostWithFields(map[string]string{"team": "web", "build": "42"})); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"env=prod,team=core", "build=42,env=prod,team=web"}
+	if len(headers) != len(want) || headers[0] != want[0] || headers[1] != want[1] {
+		t.Errorf("%s headers = %q, want %q", gosumo.HeaderFields, headers, want)
+	}
+}
+
+func TestFieldsValidation(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := range gosumo.MaxFields + 1 {
+		tooMany[fmt.Sprintf("f%d", i)] = "v"
+	}
+	tests := []struct {
+		name   string
+		fields map[string]string
+		want   string
+	}{
+		{"too many", tooMany, "too many fields"},
+		{"long name", map[string]string{"a" + strings.Repeat("b", gosumo.MaxFieldNameLength): "v"}, "longer than"},
+		{"invalid name", map[string]string{"1env": "prod"}, "must start with a letter"},
+		{"name with dash", map[string]string{"my-env": "prod"}, "must start with a letter"},
+		{"long value", map[string]string{"env": strings.Repeat("v", gosumo.MaxFieldValueLength+1)}, "longer than"},
+		{"comma", map[string]string{"env": "a,b"}, "cannot contain"},
+		{"equals sign", map[string]string{"env": "a=b"}, "cannot contain"},
+		{"newline", map[string]string{"env": "a\nb"}, "cannot contain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := gosumo.NewClient("https://collectors.sumologic.com/receiver/v1/http/x", gosumo.WithFields(tt.fields))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("WithFields error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+
+	col := newCollector(t)
+	c, err := col.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostLogsString("line", gosumo.PostWithFields(map[string]string{"env": "a,b"})); err == nil {
+		t.Error("post with an invalid field succeeded")
+	}
+	if n := len(col.Requests()); n != 0 {
+		t.Errorf("collector received %d requests, want none", n)
+	}
+}
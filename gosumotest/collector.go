@@ -0,0 +1,207 @@
+package gosumotest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/byitkc/gosumo"
+)
+
+// Request is a request received by a Collector. The body has been
+// decompressed and the X-Sumo-* headers parsed.
+type Request struct {
+	Header          http.Header
+	ContentEncoding string
+	ContentType     string
+	Body            []byte
+	SourceName      string
+	SourceHost      string
+	SourceCategory  string
+	Fields          map[string]string
+	Dimensions      map[string]string
+	Metadata        map[string]string
+}
+
+// Lines returns the non-empty newline delimited lines of the request body.
+func (r Request) Lines() []string {
+	var lines []string
+	for _, line := range strings.Split(string(r.Body), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Response is a response returned by a Collector.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+}
+
+// Collector is a mock Sumo Logic HTTP source backed by an httptest.Server. It
+// records every request it receives and responds with any queued responses
+// in order, followed by 200 OK, so retry and batching behavior can be tested
+// end to end.
+type Collector struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	requests  []Request
+	responses []Response
+}
+
+// NewCollector starts and returns a new Collector. Close must be called when
+// the test is finished.
+func NewCollector() *Collector {
+	c := &Collector{}
+	c.server = httptest.NewServer(http.HandlerFunc(c.handle))
+	return c
+}
+
+// URL returns the URL of the Collector, to be used as the endpoint URL.
+func (c *Collector) URL() string {
+	return c.server.URL
+}
+
+// Close shuts down the Collector.
+func (c *Collector) Close() {
+	c.server.Close()
+}
+
+// NewClient creates a gosumo.Client that posts to the Collector. The options
+// are applied after gosumo.WithInsecureURL, which is required because the
+// Collector does not use https.
+func (c *Collector) NewClient(opts ...gosumo.Option) (*gosumo.Client, error) {
+	return gosumo.NewClient(c.URL(), append([]gosumo.Option{gosumo.WithInsecureURL()}, opts...)...)
+}
+
+// Respond queues responses that are returned, in order, to the next requests.
+// Once the queue is empty the Collector responds with 200 OK.
+func (c *Collector) Respond(responses ...Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responses = append(c.responses, responses...)
+}
+
+// RespondWithStatus queues a response with each of the status codes, for
+// example 429, 503 to test that a client retries.
+func (c *Collector) RespondWithStatus(statusCodes ...int) {
+	for _, code := range statusCodes {
+		c.Respond(Response{StatusCode: code})
+	}
+}
+
+// Requests returns every request received so far, including those that were
+// answered with an error status.
+func (c *Collector) Requests() []Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Request(nil), c.requests...)
+}
+
+// Lines returns the lines of every request received so far, in order.
+func (c *Collector) Lines() []string {
+	var lines []string
+	for _, r := range c.Requests() {
+		lines = append(lines, r.Lines()...)
+	}
+	return lines
+}
+
+// Reset discards the recorded requests and any queued responses.
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = nil
+	c.responses = nil
+}
+
+// handle records the request and writes the next response.
+func (c *Collector) handle(w http.ResponseWriter, r *http.Request) {
+	req, err := parseRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	c.requests = append(c.requests, req)
+	resp := Response{StatusCode: http.StatusOK}
+	if len(c.responses) > 0 {
+		resp = c.responses[0]
+		c.responses = c.responses[1:]
+	}
+	c.mu.Unlock()
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusOK
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.WriteString(w, resp.Body)
+}
+
+// parseRequest decompresses the body and parses the headers of a request.
+func parseRequest(r *http.Request) (Request, error) {
+	var body io.Reader = r.Body
+	encoding := r.Header.Get("Content-Encoding")
+	switch encoding {
+	case "":
+	case "gzip":
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return Request{}, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		defer zr.Close()
+		body = zr
+	case "deflate":
+		zr, err := zlib.NewReader(r.Body)
+		if err != nil {
+			return Request{}, fmt.Errorf("invalid deflate body: %w", err)
+		}
+		defer zr.Close()
+		body = zr
+	default:
+		return Request{}, fmt.Errorf("unsupported content encoding: %s", encoding)
+	}
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, body); err != nil {
+		return Request{}, fmt.Errorf("error reading body: %w", err)
+	}
+	return Request{
+		Header:          r.Header.Clone(),
+		ContentEncoding: encoding,
+		ContentType:     r.Header.Get("Content-Type"),
+		Body:            buf.Bytes(),
+		SourceName:      r.Header.Get(gosumo.HeaderSourceName),
+		SourceHost:      r.Header.Get(gosumo.HeaderSourceHost),
+		SourceCategory:  r.Header.Get(gosumo.HeaderSourceCategory),
+		Fields:          parsePairs(r.Header.Get(gosumo.HeaderFields)),
+		Dimensions:      parsePairs(r.Header.Get(gosumo.HeaderDimensions)),
+		Metadata:        parsePairs(r.Header.Get(gosumo.HeaderMetadata)),
+	}, nil
+}
+
+// parsePairs parses a comma separated list of key=value pairs.
+func parsePairs(v string) map[string]string {
+	if v == "" {
+		return nil
+	}
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		k, val, _ := strings.Cut(strings.TrimSpace(pair), "=")
+		if k != "" {
+			pairs[k] = val
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,84 @@
+// Package gosumotest provides test doubles for code that ships logs with
+// gosumo, so it can be unit tested without posting to Sumo Logic.
+package gosumotest
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/byitkc/gosumo"
+)
+
+// FakeSender is an in-memory gosumo.LogSender that records every payload it
+// is sent. It is safe for concurrent use. The zero value is ready to use.
+type FakeSender struct {
+	mu       sync.Mutex
+	payloads [][]byte
+	err      error
+}
+
+var _ gosumo.LogSender = (*FakeSender)(nil)
+
+// NewFakeSender creates and returns a new FakeSender.
+func NewFakeSender() *FakeSender {
+	return &FakeSender{}
+}
+
+// Send records the payload. If an error has been set with SetError it is
+// returned instead and the payload is not recorded.
+func (f *FakeSender) Send(ctx context.Context, payload []byte, _ ...gosumo.PostOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.payloads = append(f.payloads, bytes.Clone(payload))
+	return nil
+}
+
+// SetError makes every following call to Send fail with err. Passing nil
+// makes Send succeed again.
+func (f *FakeSender) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// Payloads returns a copy of every payload that has been recorded, in the
+// order they were sent.
+func (f *FakeSender) Payloads() [][]byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([][]byte, len(f.payloads))
+	for i, p := range f.payloads {
+		out[i] = bytes.Clone(p)
+	}
+	return out
+}
+
+// Lines returns every non-empty log line that has been recorded, across all
+// payloads, in the order they were sent.
+func (f *FakeSender) Lines() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var lines []string
+	for _, p := range f.payloads {
+		for _, line := range bytes.Split(p, []byte("\n")) {
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+			}
+		}
+	}
+	return lines
+}
+
+// Reset discards every recorded payload.
+func (f *FakeSender) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.payloads = nil
+}
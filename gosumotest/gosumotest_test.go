@@ -0,0 +1,91 @@
+package gosumotest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/byitkc/gosumo"
+)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	defer c.Close()
+	c.RespondWithStatus(http.StatusServiceUnavailable)
+	client, err := c.NewClient(gosumo.WithSourceCategory("test"), gosumo.WithFields(map[string]string{"a": "1", "b": "2"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.PostLogsString("one"); !errors.Is(err, gosumo.ErrServerError) {
+		t.Fatalf("first post = %v, want the queued 503", err)
+	}
+	if err := client.PostLogsString("two\n\nthree"); err != nil {
+		t.Fatal(err)
+	}
+	reqs := c.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("recorded %d requests, want 2", len(reqs))
+	}
+	if r := reqs[1]; r.SourceCategory != "test" || r.Fields["a"] != "1" || r.Fields["b"] != "2" {
+		t.Errorf("request = %+v", r)
+	}
+	if got, want := c.Lines(), []string{"one", "two", "three"}; !slices.Equal(got, want) {
+		t.Errorf("Lines = %q, want %q", got, want)
+	}
+	c.Reset()
+	if n := len(c.Requests()); n != 0 {
+		t.Errorf("recorded %d requests after Reset", n)
+	}
+}
+
+func TestCollectorRejectsInvalidEncoding(t *testing.T) {
+	c := NewCollector()
+	defer c.Close()
+	req, _ := http.NewRequest(http.MethodPost, c.URL(), strings.NewReader("not gzip"))
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+	if n := len(c.Requests()); n != 0 {
+		t.Errorf("recorded %d invalid requests", n)
+	}
+}
+
+func TestFakeSender(t *testing.T) {
+	var f FakeSender
+	ctx := context.Background()
+	if err := f.Send(ctx, []byte("a\nb")); err != nil {
+		t.Fatal(err)
+	}
+	failure := errors.New("failed")
+	f.SetError(failure)
+	if err := f.Send(ctx, []byte("c")); !errors.Is(err, failure) {
+		t.Errorf("Send = %v, want the set error", err)
+	}
+	f.SetError(nil)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := f.Send(cancelled, []byte("d")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Send with a cancelled context = %v", err)
+	}
+	payloads := f.Payloads()
+	if len(payloads) != 1 || string(payloads[0]) != "a\nb" {
+		t.Errorf("Payloads = %q", payloads)
+	}
+	payloads[0][0] = 'x'
+	if got := f.Lines(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("Lines = %q, want them unaffected by changes to Payloads", got)
+	}
+	f.Reset()
+	if n := len(f.Payloads()); n != 0 {
+		t.Errorf("%d payloads after Reset", n)
+	}
+}
@@ -0,0 +1,173 @@
+package gosumo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Header names used to override the metadata of the HTTP source per request.
+const (
+	HeaderSourceName     = "X-Sumo-Name"
+	HeaderSourceHost     = "X-Sumo-Host"
+	HeaderSourceCategory = "X-Sumo-Category"
+)
+
+// sourceHeaders holds the source metadata that is sent with a request.
+// Empty values are not sent.
+type sourceHeaders struct {
+	name     string
+	host     string
+	category string
+}
+
+// merge returns the headers with any non-empty values from o overriding them.
+func (h sourceHeaders) merge(o sourceHeaders) sourceHeaders {
+	if o.name != "" {
+		h.name = o.name
+	}
+	if o.host != "" {
+		h.host = o.host
+	}
+	if o.category != "" {
+		h.category = o.category
+	}
+	return h
+}
+
+// apply sets the non-empty headers on the provided http.Header.
+func (h sourceHeaders) apply(hdr http.Header) {
+	if h.name != "" {
+		hdr.Set(HeaderSourceName, h.name)
+	}
+	if h.host != "" {
+		hdr.Set(HeaderSourceHost, h.host)
+	}
+	if h.category != "" {
+		hdr.Set(HeaderSourceCategory, h.category)
+	}
+}
+
+// validateHeaderValue checks that the value can be used in a header.
+func validateHeaderValue(name, v string) error {
+	if strings.ContainsAny(v, "\r\n") {
+		return fmt.Errorf("%s cannot contain newlines", name)
+	}
+	return nil
+}
+
+// WithSourceName overrides the source name for every request made by the
+// Client using the X-Sumo-Name header.
+func WithSourceName(name string) Option {
+	return func(c *Client) error {
+		if err := validateHeaderValue("source name", name); err != nil {
+			return err
+		}
+		c.headers.name = name
+		return nil
+	}
+}
+
+// WithSourceHost overrides the source host for every request made by the
+// Client using the X-Sumo-Host header.
+func WithSourceHost(host string) Option {
+	return func(c *Client) error {
+		if err := validateHeaderValue("source host", host); err != nil {
+			return err
+		}
+		c.headers.host = host
+		return nil
+	}
+}
+
+// WithSourceCategory overrides the source category for every request made by
+// the Client using the X-Sumo-Category header.
+func WithSourceCategory(category string) Option {
+	return func(c *Client) error {
+		if err := validateHeaderValue("source category", category); err != nil {
+			return err
+		}
+		c.headers.category = category
+		return nil
+	}
+}
+
+// PostOption configures a single post, overriding the Client's settings for
+// that call only.
+type PostOption func(*postConfig)
+
+// postConfig holds the settings for a single post.
+type postConfig struct {
+	headers sourceHeaders
+	fields  map[string]string
+	err     error
+
+	contentType string
+	dimensions  map[string]string
+	metadata    map[string]string
+
+	// logs is the number of logs in the payload, set by the batcher. It is
+	// zero for payloads that are not newline delimited logs, such as traces.
+	logs int
+}
+
+// newPostConfig applies the PostOptions on top of the Client's settings. It
+// returns an ErrPostingLogs if any of the options are invalid.
+func (c *Client) newPostConfig(opts []PostOption) (postConfig, error) {
+	cfg, err := c.buildPostConfig(opts)
+	if err != nil {
+		return postConfig{}, ErrPostingLogs{
+			Message: fmt.Sprintf("invalid post option: %v", err),
+			Err:     err,
+		}
+	}
+	return cfg, nil
+}
+
+// buildPostConfig implements newPostConfig, returning the underlying error.
+func (c *Client) buildPostConfig(opts []PostOption) (postConfig, error) {
+	var cfg postConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.err != nil {
+		return postConfig{}, cfg.err
+	}
+	cfg.headers = c.headers.merge(cfg.headers)
+	cfg.fields = mergeFields(c.fields, cfg.fields)
+	if err := validateFields(cfg.fields); err != nil {
+		return postConfig{}, err
+	}
+	return cfg, nil
+}
+
+// setHeader validates the value and sets it using set, recording an error on
+// the postConfig if it is invalid.
+func (cfg *postConfig) setHeader(name, v string, set func(string)) {
+	if err := validateHeaderValue(name, v); err != nil {
+		cfg.err = err
+		return
+	}
+	set(v)
+}
+
+// PostWithSourceName overrides the source name for a single post.
+func PostWithSourceName(name string) PostOption {
+	return func(cfg *postConfig) {
+		cfg.setHeader("source name", name, func(v string) { cfg.headers.name = v })
+	}
+}
+
+// PostWithSourceHost overrides the source host for a single post.
+func PostWithSourceHost(host string) PostOption {
+	return func(cfg *postConfig) {
+		cfg.setHeader("source host", host, func(v string) { cfg.headers.host = v })
+	}
+}
+
+// PostWithSourceCategory overrides the source category for a single post.
+func PostWithSourceCategory(category string) PostOption {
+	return func(cfg *postConfig) {
+		cfg.setHeader("source category", category, func(v string) { cfg.headers.category = v })
+	}
+}
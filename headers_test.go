@@ -0,0 +1,32 @@
+package gosumo_test
+
+import (
+	"testing"
+
+	"github.com/byitkc/gosumo"
+)
+
+func TestPostHeaders(t *testing.T) {
+	col := newCollector(t)
+	c, err := col.NewClient(
+		gosumo.WithSourceName("app"),
+		gosumo.WithSourceHost("host1"),
+		gosumo.WithSourceCategory("prod/app"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostLogsString("line"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostLogsString("line", gosumo.PostWithSourceCategory("prod/other")); err != nil {
+		t.Fatal(err)
+	}
+	reqs := col.Requests()
+	if r := reqs[0]; r.SourceName != "app" || r.SourceHost != "host1" || r.SourceCategory != "prod/app" {
+		t.Errorf("first request = %+v", r)
+	}
+	if r := reqs[1]; r.SourceCategory != "prod/other" || r.SourceName != "app" || r.SourceHost != "host1" {
+		t.Errorf("second request = %+v, want the category overridden", r)
+	}
+}
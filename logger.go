@@ -0,0 +1,88 @@
+package gosumo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// WithLogger makes the Client emit a debug line to the logger for every
+// request it makes, with the endpoint, payload size, status code, duration
+// and attempt number. Failed attempts are logged at warn level. The token in
+// the endpoint URL is redacted.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) error {
+		if logger == nil {
+			return fmt.Errorf("logger cannot be nil")
+		}
+		c.logger = logger
+		return nil
+	}
+}
+
+// logAttempt logs a single request made by the Client.
+func (c *Client) logAttempt(info AttemptInfo) {
+	level := slog.LevelDebug
+	if info.Err != nil {
+		level = slog.LevelWarn
+	}
+	ctx := context.Background()
+	if !c.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("url", redactURL(c.endpoint.URL)),
+		slog.Int("bytes", info.Bytes),
+		slog.Int("status", info.StatusCode),
+		slog.Duration("duration", info.Duration),
+		slog.Int("attempt", info.Attempt),
+	}
+	msg := "posted to sumo logic"
+	if info.Err != nil {
+		msg = "error posting to sumo logic"
+		attrs = append(attrs, slog.String("error", redactError(info.Err, c.endpoint.URL)))
+	}
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// redactError returns the message of the error with the endpoint URL
+// redacted. Transport errors are a *url.Error, whose message includes the
+// URL of the request and so the source token.
+func redactError(err error, endpointURL string) string {
+	msg := err.Error()
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && urlErr.URL != "" {
+		msg = strings.ReplaceAll(msg, urlErr.URL, redactURL(urlErr.URL))
+	}
+	if endpointURL != "" {
+		msg = strings.ReplaceAll(msg, endpointURL, redactURL(endpointURL))
+	}
+	return msg
+}
+
+// redactURL returns the URL with its path segments after the collector
+// prefix and any query string replaced, since they contain the source token.
+func redactURL(endpointURL string) string {
+	u, err := url.Parse(endpointURL)
+	if err != nil {
+		return "<invalid url>"
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := range segments {
+		// The first three segments are the /receiver/v1/http prefix.
+		if i >= 3 && segments[i] != "" {
+			segments[i] = "REDACTED"
+			break
+		}
+	}
+	u.Path = "/" + strings.Join(segments, "/")
+	u.RawPath = ""
+	if u.RawQuery != "" {
+		u.RawQuery = "REDACTED"
+	}
+	u.User = nil
+	return u.String()
+}
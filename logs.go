@@ -1,12 +1,14 @@
 package gosumo
 
 import (
+	"bytes"
+	"context"
+	"encoding"
 	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
 	"reflect"
-	"strings"
+	"sync"
+	"time"
 )
 
 type LogEndpoint struct {
@@ -14,93 +16,256 @@ type LogEndpoint struct {
 }
 
 // NewLogEndpoint creates and returns a new LogEndpoint using the provided URL.
-// It will check to ensure that the URL is valid and will return an error if it
-// is not.
+// It will check to ensure that the URL is valid, uses https and has a host,
+// and will return an error if it is not.
 func NewLogEndpoint(endpointURL string) (LogEndpoint, error) {
-	if _, err := url.Parse(endpointURL); err != nil {
-		return LogEndpoint{}, ErrBuildingClient{
-			Message: fmt.Sprintf("unable to build client using the URL '%s'", endpointURL),
-		}
-	}
-	return LogEndpoint{URL: endpointURL}, nil
+	return newLogEndpoint(endpointURL, urlPolicy{})
 }
 
-// PostLogs will post the logs provided as a slice of logs. All logs structs
-// must include Metadata for JSON encoding.
+// PostLogs will post the logs provided as a slice of logs using the provided
+// Client. Logs can be structs, which must include Metadata for JSON encoding,
+// maps such as map[string]any, pre-marshaled json.RawMessage values, or any
+// type implementing json.Marshaler or encoding.TextMarshaler. Logs are
+// encoded with the Client's Serializer, which is JSONLines by default.
 // It will return an error if there are problems parsing or posting the logs to
 // the Sumo Logic Endpoint.
-func PostLogs[T any](e LogEndpoint, logs []T) error {
-	sLogs, err := getJSONString(logs)
-	if err != nil {
+func PostLogs[T any](c *Client, logs []T, opts ...PostOption) error {
+	return PostLogsContext(context.Background(), c, logs, opts...)
+}
+
+// PostLogsContext is like PostLogs but uses the provided context for the
+// request, so the upload can be cancelled or bound by a deadline.
+//
+// Logs are encoded straight into the payload of the current batch, which is
+// posted as soon as it is full, so only one batch is held in memory at a
+// time. Every log is checked for JSON metadata before anything is posted, but
+// if a log fails to encode the batches before it will already have been
+// posted.
+func PostLogsContext[T any](ctx context.Context, c *Client, logs []T, opts ...PostOption) error {
+	if err := validateLogs(c, logs); err != nil {
 		return ErrParsingLogs{
 			Message: fmt.Sprintf("error parsing logs: %v", err),
+			Err:     err,
 		}
 	}
-	if err := PostLogsString(e, sLogs); err != nil {
-		return ErrPostingLogs{
-			Message: err.Error(),
+	cfg, err := c.newPostConfig(opts)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	b := c.newBatcher(ctx, cfg)
+	line := getBuffer()
+	defer putBuffer(line)
+	enc := c.newLogEncoder(line)
+	for _, v := range logs {
+		encoded, err := enc.encode(v)
+		if err != nil {
+			b.discard()
+			return ErrParsingLogs{
+				Message: fmt.Sprintf("error parsing logs: %v", err),
+				Err:     err,
+			}
+		}
+		if !b.add(c.prepareLine(encoded, now)) {
+			break
 		}
 	}
-	return nil
+	return b.close()
 }
 
 // PostLogsString will post the logs provided as a string (newline separated) to
-// the provided Sumo Logic Client Endpoint.
+// the Client's Sumo Logic Endpoint.
 // The provided logs can be in any format, and should be delimited with a \n
-// (newline character).
-func PostLogsString(e LogEndpoint, logs string) error {
-	logReader := strings.NewReader(logs)
-	req, err := http.NewRequest("POST", e.URL, logReader)
+// (newline character). Logs are split into multiple requests when they exceed
+// the Client's batch limits.
+func (c *Client) PostLogsString(logs string, opts ...PostOption) error {
+	return c.PostLogsStringContext(context.Background(), logs, opts...)
+}
+
+// PostLogsStringContext is like PostLogsString but uses the provided context
+// for the request, so the upload can be cancelled or bound by a deadline.
+func (c *Client) PostLogsStringContext(ctx context.Context, logs string, opts ...PostOption) error {
+	cfg, err := c.newPostConfig(opts)
 	if err != nil {
 		return err
 	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
+	return c.postLines(ctx, c.prepareLines(splitLines([]byte(logs)), time.Now()), cfg)
+}
+
+// validateLogs checks that every log has JSON metadata, unless the Client
+// has validation disabled or does not use JSONLines. When T is not an
+// interface every log has the same type, so only the first one needs to be
+// checked.
+func validateLogs[T any](c *Client, logs []T) error {
+	if !c.validatesJSON() || len(logs) == 0 {
+		return nil
+	}
+	if reflect.TypeFor[T]().Kind() != reflect.Interface {
+		logs = logs[:1]
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return ErrPostingLogs{
-			Message: fmt.Sprintf("unexpected status code when posting logs, expected: %d, got: %d", http.StatusOK, resp.StatusCode),
+	for _, v := range logs {
+		if err := checkJSONMetadata(v); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-// getJSONString takes a slice of structs that include JSON metadata. It returns
-// a string with all JSON objects as a string containing all logs delimited by a
-// newline character (\n).
-func getJSONString[T any](s []T) (string, error) {
-	var sLogs []string
-	for _, v := range s {
-		if !hasJSONMetadata(v) {
-			return "", ErrParsingLogs{
-				Message: "object is missing json metadata",
-			}
+// marshalLog returns the JSON encoding of a single log. Unless validate is
+// false, the log must pass checkJSONMetadata.
+func marshalLog(v any, validate bool) ([]byte, error) {
+	if validate {
+		if err := checkJSONMetadata(v); err != nil {
+			return nil, err
 		}
-		bLog, err := json.Marshal(v)
-		if err != nil {
-			return "", err
+	}
+	return json.Marshal(v)
+}
+
+// splitLines splits newline delimited logs into individual lines, dropping
+// empty lines.
+func splitLines(logs []byte) [][]byte {
+	var lines [][]byte
+	for _, line := range bytes.Split(logs, []byte("\n")) {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// prepareLine applies the Client's transformations to a single encoded log
+// before it is added to a batch: sampling, redaction, then timestamp
+// injection. It returns nil if the log was discarded by the Sampler.
+func (c *Client) prepareLine(line []byte, now time.Time) []byte {
+	if c.sampler != nil && !c.sampler.Sample(line) {
+		c.stats.sampled.Add(1)
+		return nil
+	}
+	if c.redactor != nil {
+		line = c.redactor.redact(line)
+	}
+	if c.timestamp != nil {
+		line = c.timestamp.stamp(line, now)
+	}
+	return line
+}
+
+// prepareLines applies prepareLine to every line.
+func (c *Client) prepareLines(lines [][]byte, now time.Time) [][]byte {
+	if c.sampler == nil && c.redactor == nil && c.timestamp == nil {
+		return lines
+	}
+	for i, line := range lines {
+		lines[i] = c.prepareLine(line, now)
+	}
+	return lines
+}
+
+// jsonMetadataCache holds the result of checkJSONMetadata for each type, so
+// the reflection is only done once per type.
+var jsonMetadataCache sync.Map
+
+// jsonMarshalerType and textMarshalerType are used to skip types that encode
+// themselves.
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// checkJSONMetadata takes a log and checks to confirm that all values
+// inside of the struct have JSON metadata for Marshalling before posting to
+// Sumo Logic. Nested and embedded structs are checked as well, while fields
+// that are not encoded, because they are unexported or tagged with "-", are
+// ignored. The returned error names the first field that is missing a tag.
+// The result is cached for each type.
+func checkJSONMetadata(a any) error {
+	t := reflect.TypeOf(a)
+	if t == nil {
+		return ErrMissingJSONMetadata
+	}
+	if err, found := jsonMetadataCache.Load(t); found {
+		if err == nil {
+			return nil
 		}
-		sLogs = append(sLogs, string(bLog))
+		return err.(error)
 	}
-	return strings.Join(sLogs, "\n"), nil
+	err := typeJSONMetadata(t)
+	if err == nil {
+		jsonMetadataCache.Store(t, nil)
+	} else {
+		jsonMetadataCache.Store(t, err)
+	}
+	return err
 }
 
-// hasJSONMetadata takes a struct and checks to confirm that all values inside
-// of the struct have JSON metadata for Marshalling before posting to Sumo Logic.
-func hasJSONMetadata(a any) bool {
-	val := reflect.ValueOf(a)
-	if val.Kind() != reflect.Struct {
-		return false
+// typeJSONMetadata implements checkJSONMetadata for a type. The top level
+// type must be a struct or a map, or a pointer to one, unless it encodes
+// itself by implementing json.Marshaler or encoding.TextMarshaler.
+func typeJSONMetadata(t reflect.Type) error {
+	if marshalsItself(t) {
+		return nil
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return walkJSONMetadata(t, t.Name(), make(map[reflect.Type]bool))
+	case reflect.Map:
+		return walkJSONMetadata(t.Elem(), t.String(), make(map[reflect.Type]bool))
 	}
-	t := val.Type()
+	return fmt.Errorf("%w: %s is not a struct or map", ErrMissingJSONMetadata, t)
+}
+
+// marshalsItself reports whether values of t, or pointers to them, provide
+// their own JSON encoding.
+func marshalsItself(t reflect.Type) bool {
+	if t.Kind() != reflect.Pointer {
+		t = reflect.PointerTo(t)
+	}
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)
+}
+
+// walkJSONMetadata checks the fields of t, and of any structs reachable
+// through them, for JSON tags. The path is used to name the offending field.
+func walkJSONMetadata(t reflect.Type, path string, seen map[reflect.Type]bool) error {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] || marshalsItself(t) {
+		return nil
+	}
+	seen[t] = true
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "" {
-			return false
+		jsonTag, hasTag := field.Tag.Lookup("json")
+		if jsonTag == "-" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		// An untagged embedded struct has its fields promoted into the
+		// parent, so its fields need the tags instead.
+		if field.Anonymous && !hasTag && fieldType.Kind() == reflect.Struct {
+			if err := walkJSONMetadata(fieldType, path, seen); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		fieldPath := path + "." + field.Name
+		if !hasTag || jsonTag == "" {
+			return fmt.Errorf("%w: field '%s' has no json tag", ErrMissingJSONMetadata, fieldPath)
+		}
+		if err := walkJSONMetadata(field.Type, fieldPath, seen); err != nil {
+			return err
 		}
 	}
-	return true
+	return nil
 }
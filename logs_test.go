@@ -0,0 +1,36 @@
+package gosumo_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/byitkc/gosumo"
+)
+
+func TestPostLogsValues(t *testing.T) {
+	type entry struct {
+		Msg string `json:"msg"`
+	}
+	col := newCollector(t)
+	c, err := col.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gosumo.PostLogs(c, []entry{{"a"}, {"b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gosumo.PostLogs(c, []map[string]any{{"msg": "c", "n": 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gosumo.PostLogs(c, []json.RawMessage{json.RawMessage(`{"msg":"d"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := col.Lines(), []string{`{"msg":"a"}`, `{"msg":"b"}`, `{"msg":"c","n":1}`, `{"msg":"d"}`}; !slices.Equal(got, want) {
+		t.Errorf("collector received %q, want %q", got, want)
+	}
+	type untagged struct{ Msg string }
+	if err := gosumo.PostLogs(c, []untagged{{"c"}}); err == nil {
+		t.Error("posting a struct without JSON metadata succeeded, want an error")
+	}
+}
@@ -0,0 +1,157 @@
+package gosumo
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxSeriesPerMetric is the number of distinct dimension combinations
+// for a single metric name above which a cardinality warning is raised.
+const DefaultMaxSeriesPerMetric = 1000
+
+// Metric is a single metric data point. It is shared by all of the metric
+// formats supported by MetricsEndpoint.
+//
+// Dimensions identify the time series the data point belongs to, while Meta
+// holds additional metadata that does not form part of the identity of the
+// series. Formats that cannot express metadata, such as Prometheus, include it
+// alongside the dimensions.
+type Metric struct {
+	Name       string
+	Dimensions map[string]string
+	Meta       map[string]string
+	Value      float64
+	Timestamp  time.Time
+}
+
+// Validate checks that the metric has a name and that its dimension and
+// metadata keys and values only contain characters that are safe to use in
+// every metric format.
+func (m Metric) Validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("metric name cannot be empty")
+	}
+	if strings.ContainsAny(m.Name, " \t\r\n=\"") {
+		return fmt.Errorf("metric name '%s' cannot contain whitespace, quotes or equals signs", m.Name)
+	}
+	if err := validateDimensions("dimension", m.Name, m.Dimensions); err != nil {
+		return err
+	}
+	return validateDimensions("meta tag", m.Name, m.Meta)
+}
+
+// validateDimensions validates the keys and values of a metric's dimensions or
+// metadata.
+func validateDimensions(kind, metric string, tags map[string]string) error {
+	for k, v := range tags {
+		if k == "" || strings.ContainsAny(k, " \t\r\n=,\"") {
+			return fmt.Errorf("invalid %s name '%s' on metric '%s'", kind, k, metric)
+		}
+		if v == "" || strings.ContainsAny(v, " \t\r\n=,\"") {
+			return fmt.Errorf("invalid value '%s' for %s '%s' on metric '%s'", v, kind, k, metric)
+		}
+	}
+	return nil
+}
+
+// seriesKey returns a string identifying the time series of the metric.
+func (m Metric) seriesKey() string {
+	var b strings.Builder
+	b.WriteString(m.Name)
+	for _, k := range slices.Sorted(maps.Keys(m.Dimensions)) {
+		b.WriteByte(0)
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(m.Dimensions[k])
+	}
+	return b.String()
+}
+
+// CardinalityWarning is raised when a metric name has more distinct
+// dimension combinations than the configured limit, which usually means a
+// high cardinality value such as a request ID is being used as a dimension.
+type CardinalityWarning struct {
+	Metric string
+	Series int
+	Limit  int
+}
+
+// String returns a description of the warning.
+func (w CardinalityWarning) String() string {
+	return fmt.Sprintf("metric '%s' has more than %d distinct series", w.Metric, w.Limit)
+}
+
+// WithCardinalityWarnings calls fn the first time a metric name posted by a
+// MetricsEndpoint exceeds maxSeries distinct dimension combinations. A
+// maxSeries of 0 uses DefaultMaxSeriesPerMetric.
+func WithCardinalityWarnings(maxSeries int, fn func(CardinalityWarning)) Option {
+	return func(c *Client) error {
+		if maxSeries < 0 {
+			return fmt.Errorf("max series cannot be negative, got: %d", maxSeries)
+		}
+		if maxSeries == 0 {
+			maxSeries = DefaultMaxSeriesPerMetric
+		}
+		c.maxSeries = maxSeries
+		c.onCardinality = fn
+		return nil
+	}
+}
+
+// seriesTracker counts the distinct series seen for each metric name so
+// cardinality warnings can be raised.
+type seriesTracker struct {
+	mu     sync.Mutex
+	series map[string]map[string]struct{}
+	warned map[string]bool
+}
+
+// track records the metrics and returns a warning for every metric name that
+// has exceeded the limit for the first time. Once a metric has exceeded the
+// limit its series are no longer recorded.
+func (t *seriesTracker) track(metrics []Metric, limit int) []CardinalityWarning {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.series == nil {
+		t.series = make(map[string]map[string]struct{})
+		t.warned = make(map[string]bool)
+	}
+	var warnings []CardinalityWarning
+	for _, m := range metrics {
+		if t.warned[m.Name] {
+			continue
+		}
+		s, ok := t.series[m.Name]
+		if !ok {
+			s = make(map[string]struct{})
+			t.series[m.Name] = s
+		}
+		s[m.seriesKey()] = struct{}{}
+		if len(s) > limit {
+			t.warned[m.Name] = true
+			delete(t.series, m.Name)
+			warnings = append(warnings, CardinalityWarning{Metric: m.Name, Series: limit + 1, Limit: limit})
+		}
+	}
+	return warnings
+}
+
+// formatMetricValue formats a metric value, including the special values
+// NaN and +/-Inf.
+func formatMetricValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,137 @@
+package gosumo_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/byitkc/gosumo"
+)
+
+func TestMetricValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		metric  gosumo.Metric
+		wantErr string
+	}{
+		{"valid", gosumo.Metric{Name: "cpu.idle", Dimensions: map[string]string{"host": "web-1"}, Meta: map[string]string{"team": "ops"}}, ""},
+		{"name only", gosumo.Metric{Name: "up"}, ""},
+		{"empty name", gosumo.Metric{}, "name cannot be empty"},
+		{"space in name", gosumo.Metric{Name: "cpu idle"}, "cannot contain whitespace"},
+		{"tab in name", gosumo.Metric{Name: "cpu\tidle"}, "cannot contain whitespace"},
+		{"equals in name", gosumo.Metric{Name: "cpu=idle"}, "cannot contain whitespace"},
+		{"quote in name", gosumo.Metric{Name: `cpu"idle`}, "cannot contain whitespace"},
+		{"empty dimension name", gosumo.Metric{Name: "cpu", Dimensions: map[string]string{"": "web-1"}}, "invalid dimension name ''"},
+		{"comma in dimension name", gosumo.Metric{Name: "cpu", Dimensions: map[string]string{"a,b": "c"}}, "invalid dimension name 'a,b'"},
+		{"empty dimension value", gosumo.Metric{Name: "cpu", Dimensions: map[string]string{"host": ""}}, "invalid value '' for dimension 'host'"},
+		{"newline in dimension value", gosumo.Metric{Name: "cpu", Dimensions: map[string]string{"host": "web\n1"}}, "for dimension 'host'"},
+		{"quote in meta name", gosumo.Metric{Name: "cpu", Meta: map[string]string{`te"am`: "ops"}}, "invalid meta tag name"},
+		{"space in meta value", gosumo.Metric{Name: "cpu", Meta: map[string]string{"team": "site ops"}}, "for meta tag 'team' on metric 'cpu'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.metric.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// recordWarnings returns an option raising cardinality warnings above
+// maxSeries, and a function returning the warnings raised so far.
+func recordWarnings(maxSeries int) (gosumo.Option, func() []gosumo.CardinalityWarning) {
+	var mu sync.Mutex
+	var warnings []gosumo.CardinalityWarning
+	opt := gosumo.WithCardinalityWarnings(maxSeries, func(w gosumo.CardinalityWarning) {
+		mu.Lock()
+		defer mu.Unlock()
+		warnings = append(warnings, w)
+	})
+	return opt, func() []gosumo.CardinalityWarning {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]gosumo.CardinalityWarning(nil), warnings...)
+	}
+}
+
+// series returns n metrics with the name, each in a series of its own.
+func series(name string, n int) []gosumo.Metric {
+	metrics := make([]gosumo.Metric, n)
+	for i := range metrics {
+		metrics[i] = gosumo.Metric{Name: name, Dimensions: map[string]string{"id": fmt.Sprint(i)}, Value: 1, Timestamp: ts}
+	}
+	return metrics
+}
+
+func TestCardinalityWarnings(t *testing.T) {
+	col := newCollector(t)
+	opt, warnings := recordWarnings(2)
+	m := newMetricsEndpoint(t, col, opt)
+
+	// Meta is not part of the series, so these are two series.
+	metrics := []gosumo.Metric{
+		{Name: "requests", Dimensions: map[string]string{"host": "web-1"}, Meta: map[string]string{"id": "1"}, Value: 1, Timestamp: ts},
+		{Name: "requests", Dimensions: map[string]string{"host": "web-1"}, Meta: map[string]string{"id": "2"}, Value: 1, Timestamp: ts},
+		{Name: "requests", Dimensions: map[string]string{"host": "web-2"}, Value: 1, Timestamp: ts},
+	}
+	if err := m.PostCarbon2Metrics(metrics); err != nil {
+		t.Fatal(err)
+	}
+	if got := warnings(); len(got) != 0 {
+		t.Fatalf("warnings = %v, want none at the limit", got)
+	}
+
+	// The third series raises a warning, only once per metric name.
+	for range 2 {
+		if err := m.PostCarbon2Metrics(append(series("requests", 3), series("latency", 1)...)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := warnings()
+	want := gosumo.CardinalityWarning{Metric: "requests", Series: 3, Limit: 2}
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("warnings = %v, want [%v]", got, want)
+	}
+	if s, want := got[0].String(), "metric 'requests' has more than 2 distinct series"; s != want {
+		t.Errorf("String = %q, want %q", s, want)
+	}
+	// Warnings do not stop the metrics being posted.
+	if n := len(col.Requests()); n != 3 {
+		t.Errorf("collector received %d requests, want 3", n)
+	}
+}
+
+func TestCardinalityWarningsDefaultLimit(t *testing.T) {
+	col := newCollector(t)
+	opt, warnings := recordWarnings(0)
+	m := newMetricsEndpoint(t, col, opt)
+	if err := m.PostCarbon2Metrics(series("requests", gosumo.DefaultMaxSeriesPerMetric)); err != nil {
+		t.Fatal(err)
+	}
+	if got := warnings(); len(got) != 0 {
+		t.Fatalf("warnings = %v, want none at the limit", got)
+	}
+	if err := m.PostCarbon2Metrics([]gosumo.Metric{{Name: "requests", Value: 1, Timestamp: ts}}); err != nil {
+		t.Fatal(err)
+	}
+	want := gosumo.CardinalityWarning{Metric: "requests", Series: gosumo.DefaultMaxSeriesPerMetric + 1, Limit: gosumo.DefaultMaxSeriesPerMetric}
+	if got := warnings(); len(got) != 1 || got[0] != want {
+		t.Errorf("warnings = %v, want [%v]", got, want)
+	}
+}
+
+func TestCardinalityWarningsNegativeLimit(t *testing.T) {
+	col := newCollector(t)
+	_, err := gosumo.NewMetricsEndpoint(col.URL(), gosumo.WithInsecureURL(), gosumo.WithCardinalityWarnings(-1, func(gosumo.CardinalityWarning) {}))
+	if err == nil || !strings.Contains(err.Error(), "cannot be negative") {
+		t.Errorf("NewMetricsEndpoint = %v, want a negative max series error", err)
+	}
+}
@@ -0,0 +1,275 @@
+package gosumo
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Content types used to post metrics to a Sumo Logic HTTP source.
+const (
+	ContentTypePrometheus = "application/vnd.sumologic.prometheus"
+	ContentTypeCarbon2    = "application/vnd.sumologic.carbon2"
+	ContentTypeGraphite   = "application/vnd.sumologic.graphite"
+)
+
+// Headers used to attach dimensions and metadata to every metric in a
+// request.
+const (
+	HeaderDimensions = "X-Sumo-Dimensions"
+	HeaderMetadata   = "X-Sumo-Metadata"
+)
+
+// MetricsEndpoint posts metrics to a Sumo Logic HTTP source. It uses a Client
+// so metrics share the same transport, retry, compression and batching
+// settings as logs.
+type MetricsEndpoint struct {
+	client *Client
+	series seriesTracker
+}
+
+// NewMetricsEndpoint creates and returns a new MetricsEndpoint that posts to
+// the provided endpoint URL. The options are the same as for NewClient.
+func NewMetricsEndpoint(endpointURL string, opts ...Option) (*MetricsEndpoint, error) {
+	c, err := NewClient(endpointURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &MetricsEndpoint{client: c}, nil
+}
+
+// Client returns the Client used by the MetricsEndpoint.
+func (m *MetricsEndpoint) Client() *Client {
+	return m.client
+}
+
+// PostPrometheusMetrics will post the provided metrics in the Prometheus
+// exposition format. Dimensions and Meta are both written as labels. It will
+// return an error if any of the metrics are invalid, or if there are problems
+// posting them.
+func (m *MetricsEndpoint) PostPrometheusMetrics(metrics []Metric, opts ...PostOption) error {
+	return m.PostPrometheusMetricsContext(context.Background(), metrics, opts...)
+}
+
+// PostPrometheusMetricsContext is like PostPrometheusMetrics but uses the
+// provided context for the request.
+func (m *MetricsEndpoint) PostPrometheusMetricsContext(ctx context.Context, metrics []Metric, opts ...PostOption) error {
+	return m.postMetrics(ctx, metrics, ContentTypePrometheus, formatPrometheus, opts)
+}
+
+// PostCarbon2Metrics will post the provided metrics in the Carbon 2.0 format.
+// The metric name and Dimensions are written as intrinsic tags and Meta as
+// meta tags. Metrics without a timestamp use the current time. It will return
+// an error if any of the metrics are invalid, or if there are problems
+// posting them.
+func (m *MetricsEndpoint) PostCarbon2Metrics(metrics []Metric, opts ...PostOption) error {
+	return m.PostCarbon2MetricsContext(context.Background(), metrics, opts...)
+}
+
+// PostCarbon2MetricsContext is like PostCarbon2Metrics but uses the provided
+// context for the request.
+func (m *MetricsEndpoint) PostCarbon2MetricsContext(ctx context.Context, metrics []Metric, opts ...PostOption) error {
+	return m.postMetrics(ctx, metrics, ContentTypeCarbon2, formatCarbon2, opts)
+}
+
+// PostGraphiteMetrics will post the provided metrics in the Graphite
+// plaintext format, using the metric name as the path. Graphite cannot
+// express per-metric dimensions, so use PostWithDimensions and
+// PostWithMetadata to attach tags to the request instead. Metrics without a
+// timestamp use the current time. It will return an error if any of the
+// metrics are invalid, or if there are problems posting them.
+func (m *MetricsEndpoint) PostGraphiteMetrics(metrics []Metric, opts ...PostOption) error {
+	return m.PostGraphiteMetricsContext(context.Background(), metrics, opts...)
+}
+
+// PostGraphiteMetricsContext is like PostGraphiteMetrics but uses the
+// provided context for the request.
+func (m *MetricsEndpoint) PostGraphiteMetricsContext(ctx context.Context, metrics []Metric, opts ...PostOption) error {
+	return m.postMetrics(ctx, metrics, ContentTypeGraphite, formatGraphite, opts)
+}
+
+// metricFormatter formats a single metric as a line.
+type metricFormatter func(m Metric, now time.Time) (string, error)
+
+// postMetrics validates and formats the metrics and posts them with the
+// provided content type.
+func (m *MetricsEndpoint) postMetrics(ctx context.Context, metrics []Metric, contentType string, format metricFormatter, opts []PostOption) error {
+	now := time.Now()
+	lines := make([][]byte, 0, len(metrics))
+	for _, metric := range metrics {
+		err := metric.Validate()
+		var line string
+		if err == nil {
+			line, err = format(metric, now)
+		}
+		if err != nil {
+			return ErrParsingLogs{
+				Message: fmt.Sprintf("error parsing metrics: %v", err),
+				Err:     err,
+			}
+		}
+		lines = append(lines, []byte(line))
+	}
+	if m.client.onCardinality != nil {
+		for _, w := range m.series.track(metrics, m.client.maxSeries) {
+			m.client.onCardinality(w)
+		}
+	}
+	cfg, err := m.client.newPostConfig(opts)
+	if err != nil {
+		return err
+	}
+	cfg.contentType = contentType
+	cfg.dimensions = mergeFields(m.client.dimensions, cfg.dimensions)
+	cfg.metadata = mergeFields(m.client.metadata, cfg.metadata)
+	return m.client.postLines(ctx, lines, cfg)
+}
+
+var prometheusNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+var prometheusLabelRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// formatPrometheus returns the metric as a line in the Prometheus exposition
+// format.
+func formatPrometheus(m Metric, _ time.Time) (string, error) {
+	if !prometheusNameRegexp.MatchString(m.Name) {
+		return "", fmt.Errorf("invalid prometheus metric name '%s'", m.Name)
+	}
+	labels := mergeFields(m.Meta, m.Dimensions)
+	var b strings.Builder
+	b.WriteString(m.Name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i, k := range slices.Sorted(maps.Keys(labels)) {
+			if !prometheusLabelRegexp.MatchString(k) {
+				return "", fmt.Errorf("invalid prometheus label name '%s' on metric '%s'", k, m.Name)
+			}
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(k)
+			b.WriteString(`="`)
+			b.WriteString(prometheusLabelReplacer.Replace(labels[k]))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatMetricValue(m.Value))
+	if !m.Timestamp.IsZero() {
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatInt(m.Timestamp.UnixMilli(), 10))
+	}
+	return b.String(), nil
+}
+
+var prometheusLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatCarbon2 returns the metric as a line in the Carbon 2.0 format, where
+// the intrinsic and meta tags are separated by two spaces.
+func formatCarbon2(m Metric, now time.Time) (string, error) {
+	intrinsic := mergeFields(map[string]string{"metric": m.Name}, m.Dimensions)
+	var b strings.Builder
+	b.WriteString(joinCarbon2Tags(intrinsic))
+	b.WriteString("  ")
+	if len(m.Meta) > 0 {
+		b.WriteString(joinCarbon2Tags(m.Meta))
+		b.WriteByte(' ')
+	}
+	b.WriteString(formatMetricValue(m.Value))
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatInt(metricTime(m, now).Unix(), 10))
+	return b.String(), nil
+}
+
+// joinCarbon2Tags returns the tags as space separated key=value pairs, sorted
+// by key.
+func joinCarbon2Tags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		pairs = append(pairs, k+"="+tags[k])
+	}
+	return strings.Join(pairs, " ")
+}
+
+// formatGraphite returns the metric as a line in the Graphite plaintext
+// format.
+func formatGraphite(m Metric, now time.Time) (string, error) {
+	if len(m.Dimensions) > 0 || len(m.Meta) > 0 {
+		return "", fmt.Errorf("graphite metric '%s' cannot have dimensions or meta tags, use PostWithDimensions or PostWithMetadata instead", m.Name)
+	}
+	return m.Name + " " + formatMetricValue(m.Value) + " " + strconv.FormatInt(metricTime(m, now).Unix(), 10), nil
+}
+
+// metricTime returns the timestamp of the metric, or now if it has none.
+func metricTime(m Metric, now time.Time) time.Time {
+	if m.Timestamp.IsZero() {
+		return now
+	}
+	return m.Timestamp
+}
+
+// validateMetricTags checks dimensions or metadata sent in a header.
+func validateMetricTags(kind string, tags map[string]string) error {
+	for k, v := range tags {
+		if k == "" || strings.ContainsAny(k, ",= \r\n") {
+			return fmt.Errorf("invalid %s name '%s'", kind, k)
+		}
+		if strings.ContainsAny(v, ",=\r\n") {
+			return fmt.Errorf("value of %s '%s' cannot contain commas, equals signs or newlines", kind, k)
+		}
+	}
+	return nil
+}
+
+// WithDimensions attaches the provided dimensions to every metric posted by
+// the Client using the X-Sumo-Dimensions header.
+func WithDimensions(dimensions map[string]string) Option {
+	return func(c *Client) error {
+		if err := validateMetricTags("dimension", dimensions); err != nil {
+			return err
+		}
+		c.dimensions = mergeFields(c.dimensions, dimensions)
+		return nil
+	}
+}
+
+// WithMetadata attaches the provided metadata to every metric posted by the
+// Client using the X-Sumo-Metadata header.
+func WithMetadata(metadata map[string]string) Option {
+	return func(c *Client) error {
+		if err := validateMetricTags("metadata", metadata); err != nil {
+			return err
+		}
+		c.metadata = mergeFields(c.metadata, metadata)
+		return nil
+	}
+}
+
+// PostWithDimensions attaches the provided dimensions to the metrics of a
+// single post, overriding any Client dimensions with the same name.
+func PostWithDimensions(dimensions map[string]string) PostOption {
+	return func(cfg *postConfig) {
+		if err := validateMetricTags("dimension", dimensions); err != nil {
+			cfg.err = err
+			return
+		}
+		cfg.dimensions = mergeFields(cfg.dimensions, dimensions)
+	}
+}
+
+// PostWithMetadata attaches the provided metadata to the metrics of a single
+// post, overriding any Client metadata with the same name.
+func PostWithMetadata(metadata map[string]string) PostOption {
+	return func(cfg *postConfig) {
+		if err := validateMetricTags("metadata", metadata); err != nil {
+			cfg.err = err
+			return
+		}
+		cfg.metadata = mergeFields(cfg.metadata, metadata)
+	}
+}
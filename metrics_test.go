@@ -0,0 +1,300 @@
+package gosumo_test
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+	"github.com/byitkc/gosumo/gosumotest"
+)
+
+// newMetricsEndpoint returns a MetricsEndpoint that posts to the collector.
+func newMetricsEndpoint(t *testing.T, col *gosumotest.Collector, opts ...gosumo.Option) *gosumo.MetricsEndpoint {
+	t.Helper()
+	m, err := gosumo.NewMetricsEndpoint(col.URL(), append([]gosumo.Option{gosumo.WithInsecureURL()}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// ts is the timestamp of the test metrics, 1700000000 seconds since the
+// epoch.
+var ts = time.UnixMilli(1700000000000)
+
+func TestPostPrometheusMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric gosumo.Metric
+		want   string
+	}{
+		{"name only", gosumo.Metric{Name: "up", Value: 1}, "up 1"},
+		{"timestamp", gosumo.Metric{Name: "up", Value: 0.25, Timestamp: ts}, "up 0.25 1700000000000"},
+		{
+			"sorted labels",
+			gosumo.Metric{Name: "http_requests_total", Dimensions: map[string]string{"method": "GET", "code": "200"}, Value: 1027},
+			`http_requests_total{code="200",method="GET"} 1027`,
+		},
+		// Prometheus has no metadata, so it is written as labels, and a
+		// dimension with the same name wins.
+		{
+			"meta as labels",
+			gosumo.Metric{Name: "up", Dimensions: map[string]string{"host": "web-1"}, Meta: map[string]string{"host": "other", "team": "ops"}, Value: 1},
+			`up{host="web-1",team="ops"} 1`,
+		},
+		{
+			"escaped label value",
+			gosumo.Metric{Name: "files", Dimensions: map[string]string{"path": `C:\logs`}, Value: 3},
+			`files{path="C:\\logs"} 3`,
+		},
+		{"large value", gosumo.Metric{Name: "bytes", Value: 1.5e21}, "bytes 1.5e+21"},
+		{"NaN", gosumo.Metric{Name: "ratio", Value: math.NaN()}, "ratio NaN"},
+		{"Inf", gosumo.Metric{Name: "limit", Value: math.Inf(1)}, "limit +Inf"},
+		{"negative Inf", gosumo.Metric{Name: "limit", Value: math.Inf(-1)}, "limit -Inf"},
+		{"colon in name", gosumo.Metric{Name: "job:rate5m", Value: 2}, "job:rate5m 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := newCollector(t)
+			if err := newMetricsEndpoint(t, col).PostPrometheusMetrics([]gosumo.Metric{tt.metric}); err != nil {
+				t.Fatal(err)
+			}
+			r := col.Requests()[0]
+			if r.ContentType != gosumo.ContentTypePrometheus {
+				t.Errorf("Content-Type = %q", r.ContentType)
+			}
+			if got := r.Lines(); !slices.Equal(got, []string{tt.want}) {
+				t.Errorf("lines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostPrometheusMetricsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric gosumo.Metric
+	}{
+		{"empty name", gosumo.Metric{Value: 1}},
+		{"dash in name", gosumo.Metric{Name: "http-requests", Value: 1}},
+		{"leading digit", gosumo.Metric{Name: "2xx", Value: 1}},
+		{"colon in label", gosumo.Metric{Name: "up", Dimensions: map[string]string{"a:b": "c"}, Value: 1}},
+		{"quote in value", gosumo.Metric{Name: "up", Dimensions: map[string]string{"path": `a"b`}, Value: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := newCollector(t)
+			err := newMetricsEndpoint(t, col).PostPrometheusMetrics([]gosumo.Metric{{Name: "ok", Value: 1}, tt.metric})
+			var parseErr gosumo.ErrParsingLogs
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("PostPrometheusMetrics = %v, want ErrParsingLogs", err)
+			}
+			// Nothing is posted when any of the metrics is invalid.
+			if n := len(col.Requests()); n != 0 {
+				t.Errorf("collector received %d requests, want none", n)
+			}
+		})
+	}
+}
+
+func TestPostCarbon2Metrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric gosumo.Metric
+		want   string
+	}{
+		// The intrinsic and meta tags are separated by two spaces.
+		{"name only", gosumo.Metric{Name: "cpu.idle", Value: 98.5, Timestamp: ts}, "metric=cpu.idle  98.5 1700000000"},
+		{
+			"dimensions and meta",
+			gosumo.Metric{
+				Name:       "cpu.idle",
+				Dimensions: map[string]string{"host": "web-1", "cpu": "0"},
+				Meta:       map[string]string{"team": "ops", "env": "prod"},
+				Value:      98.5,
+				Timestamp:  ts,
+			},
+			"cpu=0 host=web-1 metric=cpu.idle  env=prod team=ops 98.5 1700000000",
+		},
+		// A dimension named metric replaces the name.
+		{"metric dimension", gosumo.Metric{Name: "cpu.idle", Dimensions: map[string]string{"metric": "cpu"}, Value: 1, Timestamp: ts}, "metric=cpu  1 1700000000"},
+		{"NaN", gosumo.Metric{Name: "ratio", Value: math.NaN(), Timestamp: ts}, "metric=ratio  NaN 1700000000"},
+		{"Inf", gosumo.Metric{Name: "limit", Value: math.Inf(-1), Timestamp: ts}, "metric=limit  -Inf 1700000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := newCollector(t)
+			if err := newMetricsEndpoint(t, col).PostCarbon2Metrics([]gosumo.Metric{tt.metric}); err != nil {
+				t.Fatal(err)
+			}
+			r := col.Requests()[0]
+			if r.ContentType != gosumo.ContentTypeCarbon2 {
+				t.Errorf("Content-Type = %q", r.ContentType)
+			}
+			if got := r.Lines(); !slices.Equal(got, []string{tt.want}) {
+				t.Errorf("lines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostCarbon2MetricsDefaultTimestamp(t *testing.T) {
+	col := newCollector(t)
+	before := time.Now().Unix()
+	if err := newMetricsEndpoint(t, col).PostCarbon2Metrics([]gosumo.Metric{{Name: "a", Value: 1}, {Name: "b", Value: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now().Unix()
+	lines := col.Lines()
+	var first, second int64
+	if _, err := fmt.Sscanf(lines[0], "metric=a  1 %d", &first); err != nil {
+		t.Fatalf("line %q: %v", lines[0], err)
+	}
+	if _, err := fmt.Sscanf(lines[1], "metric=b  2 %d", &second); err != nil {
+		t.Fatalf("line %q: %v", lines[1], err)
+	}
+	// Metrics without a timestamp get the time of the post, the same for
+	// every metric.
+	if first < before || first > after || second != first {
+		t.Errorf("timestamps = %d and %d, want the time of the post between %d and %d", first, second, before, after)
+	}
+}
+
+func TestPostCarbon2MetricsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric gosumo.Metric
+	}{
+		{"space in name", gosumo.Metric{Name: "cpu idle", Value: 1}},
+		{"equals in dimension", gosumo.Metric{Name: "cpu", Dimensions: map[string]string{"a=b": "c"}, Value: 1}},
+		{"space in meta value", gosumo.Metric{Name: "cpu", Meta: map[string]string{"team": "site ops"}, Value: 1}},
+		{"empty dimension value", gosumo.Metric{Name: "cpu", Dimensions: map[string]string{"host": ""}, Value: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := newCollector(t)
+			err := newMetricsEndpoint(t, col).PostCarbon2Metrics([]gosumo.Metric{tt.metric})
+			var parseErr gosumo.ErrParsingLogs
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("PostCarbon2Metrics = %v, want ErrParsingLogs", err)
+			}
+			if n := len(col.Requests()); n != 0 {
+				t.Errorf("collector received %d requests, want none", n)
+			}
+		})
+	}
+}
+
+func TestPostGraphiteMetrics(t *testing.T) {
+	col := newCollector(t)
+	metrics := []gosumo.Metric{
+		{Name: "servers.web-1.cpu.idle", Value: 98.5, Timestamp: ts},
+		{Name: "servers.web-1.load", Value: math.Inf(1), Timestamp: ts},
+	}
+	if err := newMetricsEndpoint(t, col).PostGraphiteMetrics(metrics); err != nil {
+		t.Fatal(err)
+	}
+	r := col.Requests()[0]
+	if r.ContentType != gosumo.ContentTypeGraphite {
+		t.Errorf("Content-Type = %q", r.ContentType)
+	}
+	want := []string{"servers.web-1.cpu.idle 98.5 1700000000", "servers.web-1.load +Inf 1700000000"}
+	if got := r.Lines(); !slices.Equal(got, want) {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+}
+
+func TestPostGraphiteMetricsRejectsTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric gosumo.Metric
+	}{
+		{"dimensions", gosumo.Metric{Name: "cpu", Dimensions: map[string]string{"host": "web-1"}, Value: 1}},
+		{"meta", gosumo.Metric{Name: "cpu", Meta: map[string]string{"team": "ops"}, Value: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := newCollector(t)
+			err := newMetricsEndpoint(t, col).PostGraphiteMetrics([]gosumo.Metric{tt.metric})
+			var parseErr gosumo.ErrParsingLogs
+			if !errors.As(err, &parseErr) || !strings.Contains(err.Error(), "PostWithDimensions") {
+				t.Fatalf("PostGraphiteMetrics = %v, want ErrParsingLogs pointing to PostWithDimensions", err)
+			}
+			if n := len(col.Requests()); n != 0 {
+				t.Errorf("collector received %d requests, want none", n)
+			}
+		})
+	}
+}
+
+func TestMetricHeaders(t *testing.T) {
+	col := newCollector(t)
+	m := newMetricsEndpoint(t, col,
+		gosumo.WithDimensions(map[string]string{"host": "web-1", "env": "prod"}),
+		gosumo.WithMetadata(map[string]string{"team": "ops"}),
+	)
+	metrics := []gosumo.Metric{{Name: "cpu.idle", Value: 1, Timestamp: ts}}
+	if err := m.PostGraphiteMetrics(metrics); err != nil {
+		t.Fatal(err)
+	}
+	// Tags of a post override those of the client with the same name.
+	err := m.PostGraphiteMetrics(metrics,
+		gosumo.PostWithDimensions(map[string]string{"host": "web-2"}),
+		gosumo.PostWithMetadata(map[string]string{"owner": "alice"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs := col.Requests()
+	tests := []struct {
+		dimensions, metadata map[string]string
+	}{
+		{map[string]string{"host": "web-1", "env": "prod"}, map[string]string{"team": "ops"}},
+		{map[string]string{"host": "web-2", "env": "prod"}, map[string]string{"team": "ops", "owner": "alice"}},
+	}
+	for i, tt := range tests {
+		if !maps.Equal(reqs[i].Dimensions, tt.dimensions) {
+			t.Errorf("post %d dimensions = %v, want %v", i, reqs[i].Dimensions, tt.dimensions)
+		}
+		if !maps.Equal(reqs[i].Metadata, tt.metadata) {
+			t.Errorf("post %d metadata = %v, want %v", i, reqs[i].Metadata, tt.metadata)
+		}
+	}
+}
+
+func TestInvalidMetricHeaders(t *testing.T) {
+	tags := []map[string]string{
+		{"": "web-1"},
+		{"host name": "web-1"},
+		{"host=": "web-1"},
+		{"host": "web-1,web-2"},
+		{"host": "web\n1"},
+	}
+	for _, tt := range tags {
+		col := newCollector(t)
+		if _, err := gosumo.NewMetricsEndpoint(col.URL(), gosumo.WithInsecureURL(), gosumo.WithDimensions(tt)); err == nil {
+			t.Errorf("WithDimensions(%q) succeeded", tt)
+		}
+		if _, err := gosumo.NewMetricsEndpoint(col.URL(), gosumo.WithInsecureURL(), gosumo.WithMetadata(tt)); err == nil {
+			t.Errorf("WithMetadata(%q) succeeded", tt)
+		}
+		m := newMetricsEndpoint(t, col)
+		metrics := []gosumo.Metric{{Name: "cpu", Value: 1}}
+		var postErr gosumo.ErrPostingLogs
+		if err := m.PostGraphiteMetrics(metrics, gosumo.PostWithDimensions(tt)); !errors.As(err, &postErr) {
+			t.Errorf("PostWithDimensions(%q) = %v, want ErrPostingLogs", tt, err)
+		}
+		if err := m.PostGraphiteMetrics(metrics, gosumo.PostWithMetadata(tt)); !errors.As(err, &postErr) {
+			t.Errorf("PostWithMetadata(%q) = %v, want ErrPostingLogs", tt, err)
+		}
+		if n := len(col.Requests()); n != 0 {
+			t.Errorf("collector received %d requests, want none", n)
+		}
+	}
+}
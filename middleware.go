@@ -0,0 +1,40 @@
+package gosumo
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RoundTripFunc sends a single request and returns its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTripFunc to run code around every request the
+// Client makes, for example to sign requests, mutate headers or audit
+// responses. A middleware must call next to send the request, unless it
+// wants to short-circuit it.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware adds middleware that wraps every request made by the Client,
+// including retries. Middleware runs in the order it is added, so the first
+// middleware sees the request first and the response last. It may be used
+// more than once.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) error {
+		for _, m := range mw {
+			if m == nil {
+				return fmt.Errorf("middleware cannot be nil")
+			}
+		}
+		c.middleware = append(c.middleware, mw...)
+		return nil
+	}
+}
+
+// buildRoundTrip chains the Client's middleware around its http.Client.
+func (c *Client) buildRoundTrip() RoundTripFunc {
+	rt := RoundTripFunc(c.httpClient.Do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		rt = c.middleware[i](rt)
+	}
+	return rt
+}
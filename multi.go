@@ -0,0 +1,270 @@
+package gosumo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MultiMode controls how a MultiSender uses its endpoints.
+type MultiMode int
+
+const (
+	// FanOut sends every payload to all of the endpoints.
+	FanOut MultiMode = iota
+	// Failover sends every payload to the first healthy endpoint, in the
+	// order they were provided, moving on to the next one when it fails.
+	Failover
+)
+
+// String returns the name of the mode.
+func (m MultiMode) String() string {
+	if m == Failover {
+		return "failover"
+	}
+	return "fan-out"
+}
+
+// Default health tracking used by a MultiSender.
+const (
+	DefaultUnhealthyAfter   = 3
+	DefaultEndpointCooldown = 30 * time.Second
+)
+
+// EndpointHealth is a snapshot of the health of one of a MultiSender's
+// endpoints.
+type EndpointHealth struct {
+	// Name identifies the endpoint. For a Client it is the endpoint URL with
+	// the token redacted.
+	Name string
+	// Healthy is false once the endpoint has failed UnhealthyAfter times in
+	// a row, until it next succeeds.
+	Healthy bool
+	// ConsecutiveFailures is the number of posts that have failed since the
+	// endpoint last succeeded.
+	ConsecutiveFailures int
+	// LastError is the error of the most recent failed post.
+	LastError error
+	// LastSuccess and LastFailure are the times of the most recent
+	// successful and failed posts.
+	LastSuccess time.Time
+	LastFailure time.Time
+}
+
+// MultiSender is a LogSender that sends to several endpoints, for example
+// collectors in more than one Sumo Logic deployment, either fanning every
+// payload out to all of them or failing over from a primary to secondaries.
+// The health of each endpoint is tracked so that, in Failover mode,
+// endpoints that keep failing are skipped until their cooldown has passed.
+type MultiSender struct {
+	mode           MultiMode
+	unhealthyAfter int
+	cooldown       time.Duration
+
+	mu        sync.Mutex
+	endpoints []*multiEndpoint
+}
+
+// multiEndpoint is one of the endpoints of a MultiSender.
+type multiEndpoint struct {
+	sender LogSender
+	health EndpointHealth
+}
+
+// MultiOption is used to configure a MultiSender.
+type MultiOption func(*MultiSender) error
+
+// WithEndpointHealth sets the number of consecutive failures after which an
+// endpoint is considered unhealthy, and how long an unhealthy endpoint is
+// skipped by Failover mode before it is tried again.
+func WithEndpointHealth(unhealthyAfter int, cooldown time.Duration) MultiOption {
+	return func(m *MultiSender) error {
+		if unhealthyAfter < 1 {
+			return fmt.Errorf("unhealthy after must be at least 1, got: %d", unhealthyAfter)
+		}
+		if cooldown < 0 {
+			return fmt.Errorf("cooldown cannot be negative, got: %s", cooldown)
+		}
+		m.unhealthyAfter = unhealthyAfter
+		m.cooldown = cooldown
+		return nil
+	}
+}
+
+// NewMultiSender creates and returns a new MultiSender that sends to the
+// provided senders using the selected mode. In Failover mode the first
+// sender is the primary.
+func NewMultiSender(mode MultiMode, senders []LogSender, opts ...MultiOption) (*MultiSender, error) {
+	if len(senders) == 0 {
+		return nil, ErrBuildingClient{Message: "unable to build multi sender: at least one sender is required"}
+	}
+	m := &MultiSender{
+		mode:           mode,
+		unhealthyAfter: DefaultUnhealthyAfter,
+		cooldown:       DefaultEndpointCooldown,
+	}
+	for _, opt := range opts {
+		if err := opt(m); err != nil {
+			return nil, ErrBuildingClient{
+				Message: fmt.Sprintf("unable to build multi sender: %v", err),
+				Err:     err,
+			}
+		}
+	}
+	for i, s := range senders {
+		if s == nil {
+			return nil, ErrBuildingClient{Message: fmt.Sprintf("unable to build multi sender: sender %d cannot be nil", i)}
+		}
+		name := fmt.Sprintf("sender %d", i)
+		if c, ok := s.(*Client); ok {
+			name = redactURL(c.endpoint.URL)
+		}
+		m.endpoints = append(m.endpoints, &multiEndpoint{
+			sender: s,
+			health: EndpointHealth{Name: name, Healthy: true},
+		})
+	}
+	return m, nil
+}
+
+// NewMultiSenderFromURLs creates a Client for each of the collector URLs,
+// using the same client options for all of them, and returns a MultiSender
+// that sends to them using the selected mode, configured with opts.
+func NewMultiSenderFromURLs(mode MultiMode, urls []string, clientOpts []Option, opts ...MultiOption) (*MultiSender, error) {
+	senders := make([]LogSender, 0, len(urls))
+	for _, u := range urls {
+		c, err := NewClient(u, clientOpts...)
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, c)
+	}
+	return NewMultiSender(mode, senders, opts...)
+}
+
+// Health returns a snapshot of the health of every endpoint, in the order
+// they were provided.
+func (m *MultiSender) Health() []EndpointHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	health := make([]EndpointHealth, len(m.endpoints))
+	for i, e := range m.endpoints {
+		health[i] = e.health
+	}
+	return health
+}
+
+// Send posts the payload according to the MultiSender's mode. In FanOut mode
+// it is sent to every endpoint concurrently and an error is returned if any of
+// them failed. In Failover mode an error is only returned if every endpoint
+// failed. It implements LogSender.
+func (m *MultiSender) Send(ctx context.Context, payload []byte, opts ...PostOption) error {
+	if m.mode == Failover {
+		return m.failover(ctx, payload, opts)
+	}
+	return m.fanOut(ctx, payload, opts)
+}
+
+// fanOut sends the payload to every endpoint.
+func (m *MultiSender) fanOut(ctx context.Context, payload []byte, opts []PostOption) error {
+	errs := make([]error, len(m.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range m.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = m.sendTo(ctx, e, payload, opts)
+		}()
+	}
+	wg.Wait()
+	return m.combine(errs)
+}
+
+// failover sends the payload to each available endpoint in turn until one of
+// them succeeds. If every endpoint is cooling down they are all tried anyway,
+// rather than failing without making a request.
+func (m *MultiSender) failover(ctx context.Context, payload []byte, opts []PostOption) error {
+	var errs []error
+	tried := 0
+	for _, e := range m.endpoints {
+		if !m.available(e) {
+			continue
+		}
+		tried++
+		err := m.sendTo(ctx, e, payload, opts)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			return m.combine(errs)
+		}
+	}
+	if tried == 0 {
+		for _, e := range m.endpoints {
+			err := m.sendTo(ctx, e, payload, opts)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+	}
+	return m.combine(errs)
+}
+
+// available reports whether Failover mode should try the endpoint.
+func (m *MultiSender) available(e *multiEndpoint) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return e.health.Healthy || time.Since(e.health.LastFailure) >= m.cooldown
+}
+
+// sendTo sends the payload to a single endpoint and records the result in
+// its health.
+func (m *MultiSender) sendTo(ctx context.Context, e *multiEndpoint, payload []byte, opts []PostOption) error {
+	err := e.sender.Send(ctx, payload, opts...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if err == nil {
+		e.health.Healthy = true
+		e.health.ConsecutiveFailures = 0
+		e.health.LastSuccess = now
+		return nil
+	}
+	e.health.ConsecutiveFailures++
+	e.health.LastError = err
+	e.health.LastFailure = now
+	if e.health.ConsecutiveFailures >= m.unhealthyAfter {
+		e.health.Healthy = false
+	}
+	return fmt.Errorf("%s: %w", e.health.Name, err)
+}
+
+// combine returns the errors of the endpoints that failed as a single
+// ErrPostingLogs.
+func (m *MultiSender) combine(errs []error) error {
+	err := errors.Join(errs...)
+	if err == nil {
+		return nil
+	}
+	failed := 0
+	var first error
+	for _, e := range errs {
+		if e != nil {
+			if first == nil {
+				first = e
+			}
+			failed++
+		}
+	}
+	return ErrPostingLogs{
+		Message: fmt.Sprintf("%d of %d endpoints failed: %v", failed, len(m.endpoints), first),
+		Err:     err,
+	}
+}
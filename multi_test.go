@@ -0,0 +1,49 @@
+package gosumo_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+)
+
+func TestNewMultiSenderFromURLs(t *testing.T) {
+	primary, secondary := newCollector(t), newCollector(t)
+	primary.RespondWithStatus(http.StatusBadRequest)
+	m, err := gosumo.NewMultiSenderFromURLs(gosumo.Failover,
+		[]string{primary.URL(), secondary.URL()},
+		[]gosumo.Option{gosumo.WithInsecureURL(), gosumo.WithSourceName("app")},
+		gosumo.WithEndpointHealth(1, time.Hour),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, payload := range []string{"one", "two"} {
+		if err := m.Send(context.Background(), []byte(payload)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The primary is unhealthy after a single failure, so only the first
+	// payload was tried on it.
+	if n := len(primary.Requests()); n != 1 {
+		t.Errorf("primary received %d requests, want 1", n)
+	}
+	reqs := secondary.Requests()
+	if len(reqs) != 2 || reqs[0].SourceName != "app" {
+		t.Errorf("secondary received %+v, want both payloads with the source name", reqs)
+	}
+	if h := m.Health(); h[0].Healthy || !h[1].Healthy {
+		t.Errorf("Health = %+v, want only the primary unhealthy", h)
+	}
+
+	_, err = gosumo.NewMultiSenderFromURLs(gosumo.FanOut, []string{secondary.URL()}, []gosumo.Option{gosumo.WithInsecureURL()}, gosumo.WithEndpointHealth(0, 0))
+	if err == nil || !strings.Contains(err.Error(), "unhealthy after") {
+		t.Errorf("invalid multi option error = %v", err)
+	}
+	if _, err := gosumo.NewMultiSenderFromURLs(gosumo.FanOut, []string{secondary.URL()}, nil); err == nil {
+		t.Error("an http URL was accepted without WithInsecureURL")
+	}
+}
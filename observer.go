@@ -0,0 +1,42 @@
+package gosumo
+
+import (
+	"fmt"
+	"time"
+)
+
+// AttemptInfo describes a single request made by the Client.
+type AttemptInfo struct {
+	// Attempt is the attempt number, starting at 1.
+	Attempt int
+	// Bytes is the size of the request body, after compression.
+	Bytes int
+	// StatusCode is the status code of the response, or 0 if no response
+	// was received.
+	StatusCode int
+	// Duration is how long the request took.
+	Duration time.Duration
+	// Err is the error from the attempt, if it failed.
+	Err error
+}
+
+// WithAttemptObserver registers a function that is called after every request
+// the Client makes, including retries. It can be used to record metrics such
+// as request latency. It may be used more than once to register several
+// observers, and the functions must be safe for concurrent use.
+func WithAttemptObserver(fn func(AttemptInfo)) Option {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("attempt observer cannot be nil")
+		}
+		c.observers = append(c.observers, fn)
+		return nil
+	}
+}
+
+// observe calls the registered observers with info.
+func (c *Client) observe(info AttemptInfo) {
+	for _, fn := range c.observers {
+		fn(info)
+	}
+}
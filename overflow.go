@@ -0,0 +1,144 @@
+package gosumo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OverflowPolicy decides what a BufferedSender does with a new log when its
+// buffer is full and the buffer cannot be spilled to a spool.
+type OverflowPolicy int
+
+const (
+	// OverflowError rejects the new log with ErrBufferFull.
+	OverflowError OverflowPolicy = iota
+	// OverflowBlock blocks the caller until a flush makes room in the
+	// buffer, for at most the flush interval unless the log is added with
+	// a context, see BufferedSender.EnqueueContext.
+	OverflowBlock
+	// OverflowDropOldest discards the oldest buffered log to make room for
+	// the new one.
+	OverflowDropOldest
+	// OverflowDropNewest discards the new log.
+	OverflowDropNewest
+)
+
+// String returns the name of the policy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropOldest:
+		return "drop-oldest"
+	case OverflowDropNewest:
+		return "drop-newest"
+	default:
+		return "error"
+	}
+}
+
+// WithOverflowPolicy sets what happens to new logs when the buffer is full,
+// see WithMaxBufferedLogs. The default is OverflowError. Dropped logs are
+// counted and reported by BufferedSender.Dropped.
+func WithOverflowPolicy(p OverflowPolicy) BufferOption {
+	return func(s *BufferedSender) error {
+		switch p {
+		case OverflowError, OverflowBlock, OverflowDropOldest, OverflowDropNewest:
+		default:
+			return fmt.Errorf("unsupported overflow policy: %d", p)
+		}
+		s.overflow = p
+		return nil
+	}
+}
+
+// Dropped returns the number of logs the BufferedSender has discarded
+// because its buffer was full.
+func (s *BufferedSender) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// makeRoom applies the overflow policy when the buffer is full, with no room
+// for a new log of size bytes, including its newline. It returns false if the
+// new log should not be added, along with any error for the caller. It must
+// be called with the lock held, and may release it while blocking until ctx
+// is done or, unless it is 0, maxWait has passed.
+func (s *BufferedSender) makeRoom(ctx context.Context, size int, maxWait time.Duration) (bool, error) {
+	for s.full(size) {
+		if s.spool != nil {
+			if s.dedupe != nil {
+				s.dedupe.apply(s.buf)
+			}
+			if err := s.spool.write(joinLines(s.buf)); err == nil {
+				s.buf = nil
+				s.bufBytes = 0
+				return true, nil
+			}
+		}
+		switch s.overflow {
+		case OverflowBlock:
+			if maxWait > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, maxWait)
+				defer cancel()
+				maxWait = 0
+			}
+			s.triggerFlush()
+			if err := s.waitForSpace(ctx); err != nil {
+				return false, err
+			}
+		case OverflowDropOldest:
+			s.bufBytes -= len(s.buf[0]) + 1
+			s.buf[0] = nil
+			s.buf = s.buf[1:]
+			s.dropped.Add(1)
+			if s.dedupe != nil {
+				s.dedupe.dropOldest()
+			}
+		case OverflowDropNewest:
+			s.dropped.Add(1)
+			return false, nil
+		default:
+			return false, ErrBufferFull
+		}
+	}
+	return true, nil
+}
+
+// full reports whether the buffer has no room for a new log of size bytes.
+// A log larger than the byte cap is let into an empty buffer, since dropping
+// every other log would not make room for it.
+func (s *BufferedSender) full(size int) bool {
+	if s.maxBuffered > 0 && len(s.buf) >= s.maxBuffered {
+		return true
+	}
+	return s.maxBufferedBytes > 0 && len(s.buf) > 0 && s.bufBytes+size > s.maxBufferedBytes
+}
+
+// waitForSpace waits for a flush to take the buffered logs or for the
+// context to be done, in which case it returns ErrBufferFull wrapping the
+// error of the context. It returns ErrSenderClosed if the BufferedSender was
+// closed. It must be called with the lock held, which is released while
+// waiting.
+func (s *BufferedSender) waitForSpace(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrBufferFull, err)
+	}
+	// The condition cannot wait on the context, so it is woken up when the
+	// context is done.
+	stop := context.AfterFunc(ctx, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.space.Broadcast()
+	})
+	defer stop()
+	s.space.Wait()
+	if s.closed {
+		return ErrSenderClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrBufferFull, err)
+	}
+	return nil
+}
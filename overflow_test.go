@@ -0,0 +1,150 @@
+package gosumo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+	"github.com/byitkc/gosumo/gosumotest"
+)
+
+func TestBufferedSenderOverflow(t *testing.T) {
+	tests := []struct {
+		policy  gosumo.OverflowPolicy
+		wantErr error
+		sent    []string
+		dropped uint64
+	}{
+		{gosumo.OverflowError, gosumo.ErrBufferFull, []string{"a", "b"}, 0},
+		{gosumo.OverflowDropOldest, nil, []string{"b", "c"}, 1},
+		{gosumo.OverflowDropNewest, nil, []string{"a", "b"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			fake := gosumotest.NewFakeSender()
+			s := newBufferedSender(t, fake, gosumo.WithMaxBufferedLogs(2), gosumo.WithOverflowPolicy(tt.policy))
+			var err error
+			for _, line := range []string{"a", "b", "c"} {
+				err = s.Enqueue([]byte(line))
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Enqueue into a full buffer = %v, want %v", err, tt.wantErr)
+			}
+			if got := s.Dropped(); got != tt.dropped {
+				t.Errorf("Dropped = %d, want %d", got, tt.dropped)
+			}
+			if err := s.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if got := fake.Lines(); !slices.Equal(got, tt.sent) {
+				t.Errorf("sent %q, want %q", got, tt.sent)
+			}
+		})
+	}
+}
+
+func TestBufferedSenderOverflowBlock(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake, gosumo.WithMaxBufferedLogs(1), gosumo.WithOverflowPolicy(gosumo.OverflowBlock))
+	if err := s.Enqueue([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	// The second log blocks until the background flush makes room.
+	if err := s.Enqueue([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.Lines(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("sent %q, want both logs", got)
+	}
+}
+
+// newOpenBreaker returns a BufferedSender posting with a Client whose
+// circuit breaker is open, so flushes cannot take the buffered logs.
+func newOpenBreaker(t *testing.T, opts ...gosumo.BufferOption) *gosumo.BufferedSender {
+	t.Helper()
+	col := newCollector(t)
+	col.RespondWithStatus(http.StatusServiceUnavailable)
+	c, err := col.NewClient(
+		gosumo.WithRetry(gosumo.RetryPolicy{MaxAttempts: 1}),
+		gosumo.WithCircuitBreaker(gosumo.BreakerConfig{FailureThreshold: 1, OpenDuration: time.Hour, HalfOpenProbes: 1}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostLogsString("x"); err == nil {
+		t.Fatal("post succeeded, want it to open the circuit")
+	}
+	if state := c.BreakerState(); state != gosumo.BreakerOpen {
+		t.Fatalf("BreakerState = %v, want open", state)
+	}
+	return newBufferedSender(t, c, opts...)
+}
+
+func TestBufferedSenderMaxBufferedBytes(t *testing.T) {
+	s := newOpenBreaker(t, gosumo.WithMaxBufferedBytes(10))
+	// Each log takes its length and a newline.
+	for _, line := range []string{"12345", "678"} {
+		if err := s.Enqueue([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Enqueue([]byte("9")); !errors.Is(err, gosumo.ErrBufferFull) {
+		t.Errorf("Enqueue over the byte cap = %v, want ErrBufferFull", err)
+	}
+	if err := s.Flush(); !errors.Is(err, gosumo.ErrCircuitOpen) {
+		t.Errorf("Flush = %v, want ErrCircuitOpen", err)
+	}
+
+	s = newOpenBreaker(t, gosumo.WithMaxBufferedBytes(4), gosumo.WithOverflowPolicy(gosumo.OverflowDropOldest))
+	for _, line := range []string{"a", "b", "c"} {
+		if err := s.Enqueue([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := s.Dropped(); got != 1 {
+		t.Errorf("Dropped = %d, want the oldest log", got)
+	}
+	// A log larger than the cap is kept once the others are dropped.
+	if err := s.Enqueue([]byte("too long")); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Dropped(); got != 3 {
+		t.Errorf("Dropped = %d, want every buffered log", got)
+	}
+}
+
+func TestBufferedSenderOverflowBlockWhileOpen(t *testing.T) {
+	s := newOpenBreaker(t, gosumo.WithMaxBufferedLogs(1), gosumo.WithOverflowPolicy(gosumo.OverflowBlock))
+	if err := s.Enqueue([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := s.EnqueueContext(ctx, []byte("b"))
+	if !errors.Is(err, gosumo.ErrBufferFull) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("EnqueueContext = %v, want ErrBufferFull once the context is done", err)
+	}
+	if err := s.SendContext(ctx, map[string]string{"msg": "c"}); !errors.Is(err, gosumo.ErrBufferFull) {
+		t.Errorf("SendContext with a done context = %v, want ErrBufferFull", err)
+	}
+
+	// Without a context the wait is bounded by the flush interval.
+	s = newOpenBreaker(t, gosumo.WithMaxBufferedLogs(1), gosumo.WithOverflowPolicy(gosumo.OverflowBlock), gosumo.WithFlushInterval(20*time.Millisecond))
+	if err := s.Enqueue([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := s.Enqueue([]byte("b")); !errors.Is(err, gosumo.ErrBufferFull) {
+		t.Errorf("Enqueue = %v, want ErrBufferFull after the flush interval", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Enqueue blocked for %s", d)
+	}
+}
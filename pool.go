@@ -0,0 +1,60 @@
+package gosumo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"sync"
+)
+
+// maxPooledBufferSize is the capacity above which buffers are not returned
+// to the pool, so one unusually large batch does not pin its memory.
+const maxPooledBufferSize = 4 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns the buffer to the pool. The buffer must not be used
+// afterwards.
+func putBuffer(buf *bytes.Buffer) {
+	if buf == nil || buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() any { return gzip.NewWriter(io.Discard) },
+}
+
+var zlibWriterPool = sync.Pool{
+	New: func() any { return zlib.NewWriter(io.Discard) },
+}
+
+// compressWriter is a compressor that can be reset to write to a new
+// destination, like gzip.Writer and zlib.Writer.
+type compressWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// getCompressWriter returns a pooled writer for the algorithm that writes to
+// w, along with the pool it must be returned to.
+func getCompressWriter(alg Compression, w io.Writer) (compressWriter, *sync.Pool) {
+	pool := &gzipWriterPool
+	if alg == Deflate {
+		pool = &zlibWriterPool
+	}
+	cw := pool.Get().(compressWriter)
+	cw.Reset(w)
+	return cw, pool
+}
@@ -0,0 +1,98 @@
+package gosumo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// benchmarkLogs returns n JSON logs of a typical size.
+func benchmarkLogs(n int) []json.RawMessage {
+	logs := make([]json.RawMessage, n)
+	for i := range logs {
+		logs[i] = json.RawMessage(fmt.Sprintf(`{"level":"info","msg":"request handled","method":"GET","path":"/api/items/%d","status":200,"duration_ms":%d}`, i, i%250))
+	}
+	return logs
+}
+
+// benchmarkPayload returns a newline delimited payload of n logs.
+func benchmarkPayload(n int) []byte {
+	var buf bytes.Buffer
+	for _, l := range benchmarkLogs(n) {
+		buf.Write(l)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// BenchmarkCompress compresses a payload with the pooled writers and
+// buffers, compared to allocating a buffer and writer for every payload as
+// was done before they were pooled.
+func BenchmarkCompress(b *testing.B) {
+	payload := benchmarkPayload(1000)
+	for _, alg := range []Compression{Gzip, Deflate} {
+		b.Run(alg.String()+"/pooled", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(payload)))
+			for i := 0; i < b.N; i++ {
+				buf, err := compress(alg, payload)
+				if err != nil {
+					b.Fatal(err)
+				}
+				putBuffer(buf)
+			}
+		})
+		b.Run(alg.String()+"/unpooled", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(payload)))
+			for i := 0; i < b.N; i++ {
+				var buf bytes.Buffer
+				var w io.WriteCloser
+				if alg == Gzip {
+					w = gzip.NewWriter(&buf)
+				} else {
+					w = zlib.NewWriter(&buf)
+				}
+				if _, err := w.Write(payload); err != nil {
+					b.Fatal(err)
+				}
+				if err := w.Close(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkPostLogs posts batches of logs to a server that discards them,
+// so the allocations are those of encoding, batching and compressing the
+// logs along with the request itself.
+func BenchmarkPostLogs(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer srv.Close()
+	logs := benchmarkLogs(1000)
+	for _, alg := range []Compression{NoCompression, Gzip} {
+		b.Run(alg.String(), func(b *testing.B) {
+			c, err := NewClient(srv.URL, WithInsecureURL(), WithCompression(alg))
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := PostLogsContext(context.Background(), c, logs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package gosumo
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit limits the number of requests the Client makes per second,
+// including retries, allowing bursts of up to burst requests. It is useful
+// for bulk backfills that would otherwise trip Sumo Logic's throttling.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) error {
+		if requestsPerSecond <= 0 {
+			return fmt.Errorf("requests per second must be greater than zero, got: %v", requestsPerSecond)
+		}
+		if burst < 1 {
+			return fmt.Errorf("burst must be at least 1, got: %d", burst)
+		}
+		c.requestLimiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+		return nil
+	}
+}
+
+// WithByteRateLimit limits the number of payload bytes the Client sends per
+// second, after compression, allowing bursts of up to burst bytes.
+func WithByteRateLimit(bytesPerSecond float64, burst int) Option {
+	return func(c *Client) error {
+		if bytesPerSecond <= 0 {
+			return fmt.Errorf("bytes per second must be greater than zero, got: %v", bytesPerSecond)
+		}
+		if burst < 1 {
+			return fmt.Errorf("burst must be at least 1, got: %d", burst)
+		}
+		c.byteLimiter = rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+		return nil
+	}
+}
+
+// waitRateLimit blocks until the Client's rate limits allow a request with a
+// payload of n bytes, or the context is done.
+func (c *Client) waitRateLimit(ctx context.Context, n int) error {
+	if c.requestLimiter != nil {
+		if err := c.requestLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if c.byteLimiter != nil {
+		// WaitN fails for more than burst bytes, so large payloads wait
+		// for their bytes in burst sized pieces.
+		burst := c.byteLimiter.Burst()
+		for n > 0 {
+			take := min(n, burst)
+			if err := c.byteLimiter.WaitN(ctx, take); err != nil {
+				return err
+			}
+			n -= take
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package gosumo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// PostLogsReader will stream newline delimited logs from the provided reader,
+// such as a file or a pipe, to the Client's Sumo Logic Endpoint until the
+// reader returns io.EOF. Logs are batched at line boundaries using the
+// Client's batch limits, and each batch is posted as soon as it is full, so
+// large files can be backfilled without loading them into memory. Empty lines
+// are dropped.
+//
+// If the reader fails, the logs read so far are still posted and the read
+// error is returned as an ErrParsingLogs.
+func (c *Client) PostLogsReader(ctx context.Context, r io.Reader, opts ...PostOption) error {
+	cfg, err := c.newPostConfig(opts)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	br := bufio.NewReaderSize(r, 64*1024)
+	b := c.newBatcher(ctx, cfg)
+	line := getBuffer()
+	defer putBuffer(line)
+	var readErr error
+	for ctx.Err() == nil {
+		line.Reset()
+		readErr = readLine(br, line)
+		if l := line.Bytes(); len(l) > 0 {
+			if !b.add(c.prepareLine(l, now)) {
+				break
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	postErr := b.close()
+	if readErr == nil || errors.Is(readErr, io.EOF) {
+		return postErr
+	}
+	return ErrParsingLogs{
+		Message: fmt.Sprintf("error reading logs: %v", readErr),
+		Err:     errors.Join(readErr, postErr),
+	}
+}
+
+// readLine reads a single line into buf, without the trailing newline or
+// carriage return. Lines longer than the reader's buffer are read in pieces.
+func readLine(r *bufio.Reader, buf *bytes.Buffer) error {
+	for {
+		chunk, err := r.ReadSlice('\n')
+		buf.Write(chunk)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		l := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+		buf.Truncate(len(bytes.TrimSuffix(l, []byte("\r"))))
+		return err
+	}
+}
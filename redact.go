@@ -0,0 +1,261 @@
+package gosumo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultRedactionReplacement replaces redacted values when a RedactionRule
+// does not set its own replacement.
+const DefaultRedactionReplacement = "[REDACTED]"
+
+// RedactionRule describes values that are scrubbed from logs before they
+// leave the process. A rule either matches the values of a JSON field by
+// name, or matches a pattern anywhere in the text of a log.
+type RedactionRule struct {
+	// Field is the name of a JSON field whose value is replaced, at any
+	// depth. Names are matched case-insensitively. Field rules only apply to
+	// logs that are JSON objects.
+	Field string
+	// Pattern matches text that is replaced. In JSON logs it is applied to
+	// string and number values, so the log stays valid JSON. A number that
+	// is redacted becomes a string.
+	Pattern *regexp.Regexp
+	// Replacement is written in place of redacted values. If it is empty,
+	// DefaultRedactionReplacement is used.
+	Replacement string
+
+	// check confirms a match of Pattern before it is replaced, to avoid
+	// false positives.
+	check func(match string) bool
+}
+
+// RedactField returns a rule that redacts the value of every JSON field with
+// the provided name.
+func RedactField(name string) RedactionRule {
+	return RedactionRule{Field: name}
+}
+
+// RedactPattern returns a rule that redacts all text matching the pattern.
+func RedactPattern(pattern *regexp.Regexp) RedactionRule {
+	return RedactionRule{Pattern: pattern}
+}
+
+// Built-in redaction rules for common secrets and personal information.
+var (
+	// RedactEmails redacts email addresses.
+	RedactEmails = RedactionRule{
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	}
+	// RedactCreditCards redacts card numbers of 13 to 19 digits, optionally
+	// separated by spaces or dashes, that pass the Luhn check.
+	RedactCreditCards = RedactionRule{
+		Pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		check:   luhnValid,
+	}
+	// RedactBearerTokens redacts the token of bearer authorization values,
+	// keeping the scheme so the log still shows which was used.
+	RedactBearerTokens = RedactionRule{
+		Pattern:     regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+		Replacement: "Bearer " + DefaultRedactionReplacement,
+	}
+)
+
+// WithRedaction makes the Client apply the redaction rules to every log
+// before it is added to a batch, so secrets and personal information never
+// leave the process. It applies to logs posted in any way, including
+// strings, lines from a reader and logs buffered by a BufferedSender. Calling
+// it more than once adds to the existing rules.
+func WithRedaction(rules ...RedactionRule) Option {
+	return func(c *Client) error {
+		if c.redactor == nil {
+			c.redactor = &redactor{fields: make(map[string]string)}
+		}
+		for i, rule := range rules {
+			if (rule.Field == "") == (rule.Pattern == nil) {
+				return fmt.Errorf("redaction rule %d must set exactly one of field or pattern", i)
+			}
+			if rule.Replacement == "" {
+				rule.Replacement = DefaultRedactionReplacement
+			}
+			if rule.Field != "" {
+				c.redactor.fields[strings.ToLower(rule.Field)] = rule.Replacement
+				continue
+			}
+			c.redactor.patterns = append(c.redactor.patterns, rule)
+		}
+		return nil
+	}
+}
+
+// redactor applies a Client's redaction rules.
+type redactor struct {
+	// fields maps lowercased field names to their replacement.
+	fields   map[string]string
+	patterns []RedactionRule
+}
+
+// redact returns the line with the rules applied. Lines that are JSON objects
+// are rewritten value by value, keeping the order of their fields, while any
+// other line only has the patterns applied to its text.
+func (r *redactor) redact(line []byte) []byte {
+	if !r.mightMatch(line) {
+		return line
+	}
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		if out, err := r.redactJSON(trimmed); err == nil {
+			return out
+		}
+	}
+	return []byte(r.redactText(string(line)))
+}
+
+// mightMatch is a quick check that skips lines no rule can apply to.
+func (r *redactor) mightMatch(line []byte) bool {
+	for _, p := range r.patterns {
+		if p.Pattern.Match(line) {
+			return true
+		}
+	}
+	if len(r.fields) == 0 {
+		return false
+	}
+	lower := bytes.ToLower(line)
+	for name := range r.fields {
+		if bytes.Contains(lower, []byte(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactText applies the patterns to text.
+func (r *redactor) redactText(s string) string {
+	for _, p := range r.patterns {
+		s = p.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if p.check != nil && !p.check(match) {
+				return match
+			}
+			return p.Replacement
+		})
+	}
+	return s
+}
+
+// redactJSON rewrites a JSON document, replacing the values of redacted
+// fields and applying the patterns to strings and numbers.
+func (r *redactor) redactJSON(doc []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := r.redactValue(dec, enc, &out); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after json value")
+	}
+	return out.Bytes(), nil
+}
+
+// redactValue copies the next value from dec to out.
+func (r *redactor) redactValue(dec *json.Decoder, enc *json.Encoder, out *bytes.Buffer) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '{':
+			out.WriteByte('{')
+			for i := 0; dec.More(); i++ {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				name, _ := key.(string)
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				writeJSONString(enc, out, name)
+				out.WriteByte(':')
+				if replacement, ok := r.fields[strings.ToLower(name)]; ok {
+					var skipped json.RawMessage
+					if err := dec.Decode(&skipped); err != nil {
+						return err
+					}
+					writeJSONString(enc, out, replacement)
+					continue
+				}
+				if err := r.redactValue(dec, enc, out); err != nil {
+					return err
+				}
+			}
+			out.WriteByte('}')
+		case '[':
+			out.WriteByte('[')
+			for i := 0; dec.More(); i++ {
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				if err := r.redactValue(dec, enc, out); err != nil {
+					return err
+				}
+			}
+			out.WriteByte(']')
+		}
+		// Consume the closing delimiter.
+		_, err := dec.Token()
+		return err
+	case string:
+		writeJSONString(enc, out, r.redactText(tok))
+	case json.Number:
+		// Numbers can hold sensitive values too, such as card numbers. One
+		// that a pattern matches is written as a string, since the
+		// replacement is not a number.
+		if redacted := r.redactText(tok.String()); redacted != tok.String() {
+			writeJSONString(enc, out, redacted)
+		} else {
+			out.WriteString(tok.String())
+		}
+	case bool:
+		out.WriteString(fmt.Sprint(tok))
+	case nil:
+		out.WriteString("null")
+	}
+	return nil
+}
+
+// writeJSONString writes s to out as a JSON string.
+func writeJSONString(enc *json.Encoder, out *bytes.Buffer, s string) {
+	enc.Encode(s)
+	out.Truncate(out.Len() - 1)
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum used by
+// card numbers.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
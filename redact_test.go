@@ -0,0 +1,89 @@
+package gosumo
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestRedaction(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []RedactionRule
+		line  string
+		want  string
+	}{
+		{
+			name:  "field",
+			rules: []RedactionRule{RedactField("password")},
+			line:  `{"user":"a","Password":"hunter2","n":1}`,
+			want:  `{"user":"a","Password":"[REDACTED]","n":1}`,
+		},
+		{
+			name:  "nested field",
+			rules: []RedactionRule{RedactField("token")},
+			line:  `{"auth":{"token":{"value":"x"}},"list":[{"token":1}]}`,
+			want:  `{"auth":{"token":"[REDACTED]"},"list":[{"token":"[REDACTED]"}]}`,
+		},
+		{
+			name:  "pattern in string",
+			rules: []RedactionRule{RedactEmails},
+			line:  `{"msg":"sent to a@example.com","ok":true,"x":null}`,
+			want:  `{"msg":"sent to [REDACTED]","ok":true,"x":null}`,
+		},
+		{
+			name:  "pattern in number",
+			rules: []RedactionRule{RedactCreditCards},
+			line:  `{"card":4111111111111111,"amount":12.5}`,
+			want:  `{"card":"[REDACTED]","amount":12.5}`,
+		},
+		{
+			name:  "number failing the check",
+			rules: []RedactionRule{RedactCreditCards},
+			line:  `{"id":4111111111111112}`,
+			want:  `{"id":4111111111111112}`,
+		},
+		{
+			name:  "custom pattern in number",
+			rules: []RedactionRule{{Pattern: regexp.MustCompile(`\b555\d{4}\b`), Replacement: "xxx"}},
+			line:  `{"phone":5551234,"other":1555}`,
+			want:  `{"phone":"xxx","other":1555}`,
+		},
+		{
+			name:  "plain text",
+			rules: []RedactionRule{RedactBearerTokens, RedactField("password")},
+			line:  `Authorization: Bearer abc.def password=x`,
+			want:  `Authorization: Bearer [REDACTED] password=x`,
+		},
+		{
+			name:  "invalid json",
+			rules: []RedactionRule{RedactEmails},
+			line:  `{"msg": "a@example.com"`,
+			want:  `{"msg": "[REDACTED]"`,
+		},
+		{
+			name:  "no match",
+			rules: []RedactionRule{RedactEmails, RedactField("secret")},
+			line:  `{"msg":"hello"}`,
+			want:  `{"msg":"hello"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient("https://collectors.sumologic.com/receiver/v1/http/token", WithRedaction(tt.rules...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(c.redactor.redact([]byte(tt.line))); got != tt.want {
+				t.Errorf("redact(%s)\n got: %s\nwant: %s", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRedactionRejectsInvalidRules(t *testing.T) {
+	for _, rule := range []RedactionRule{{}, {Field: "a", Pattern: regexp.MustCompile("a")}} {
+		if _, err := NewClient("https://collectors.sumologic.com/receiver/v1/http/token", WithRedaction(rule)); err == nil {
+			t.Errorf("WithRedaction(%+v) succeeded, want an error", rule)
+		}
+	}
+}
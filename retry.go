@@ -0,0 +1,160 @@
+package gosumo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the Client retries requests that fail with a
+// transient error.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request,
+	// including the first one. A value of 1 disables retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, including a longer delay
+	// requested by the Retry-After header of a response. Zero leaves the
+	// delay uncapped.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the backoff grows by after each attempt.
+	Multiplier float64
+	// Jitter is the fraction (0 to 1) of the backoff that is randomized to
+	// avoid many clients retrying at the same moment.
+	Jitter float64
+	// Retryable reports whether a response with the given status code should
+	// be retried. If nil, IsRetryableStatus is used.
+	Retryable func(statusCode int) bool
+}
+
+// DefaultRetryPolicy is a reasonable RetryPolicy for posting to Sumo Logic.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// noRetryPolicy is used by Clients that have not been configured with
+// WithRetry.
+var noRetryPolicy = RetryPolicy{MaxAttempts: 1}
+
+// WithRetry sets the RetryPolicy used by the Client. By default requests are
+// not retried.
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) error {
+		if p.MaxAttempts < 1 {
+			return fmt.Errorf("max attempts must be at least 1, got: %d", p.MaxAttempts)
+		}
+		if p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+			return fmt.Errorf("backoff cannot be negative")
+		}
+		if p.Multiplier < 1 {
+			p.Multiplier = 1
+		}
+		if p.Jitter < 0 || p.Jitter > 1 {
+			return fmt.Errorf("jitter must be between 0 and 1, got: %v", p.Jitter)
+		}
+		c.retry = p
+		return nil
+	}
+}
+
+// IsRetryableStatus reports whether the status code indicates a transient
+// failure that is worth retrying: 429, 500, 502, 503 and 504.
+func IsRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryable reports whether the status code should be retried under the
+// policy.
+func (p RetryPolicy) retryable(statusCode int) bool {
+	if p.Retryable != nil {
+		return p.Retryable(statusCode)
+	}
+	return IsRetryableStatus(statusCode)
+}
+
+// Backoff returns the delay before the provided retry, where retry 1 is the
+// first retry after the initial attempt.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	d := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(d)
+}
+
+// RetryDelay returns the delay before the provided retry when the response
+// to the previous attempt asked to wait for retryAfter: the longer of the
+// backoff and retryAfter, capped by MaxBackoff if it is set, so that a
+// server cannot stall a request indefinitely.
+func (p RetryPolicy) RetryDelay(retry int, retryAfter time.Duration) time.Duration {
+	d := max(p.Backoff(retry), retryAfter)
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date, into the delay it requests. It returns
+// false if the value is missing or invalid.
+func ParseRetryAfter(v string) (time.Duration, bool) {
+	return parseRetryAfter(v, time.Now())
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date. It returns false if the value is missing
+// or invalid.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleep waits for the provided duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+package gosumo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	for retry, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	} {
+		if got := p.Backoff(retry); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", retry, got, want)
+		}
+	}
+	p.Jitter = 0.5
+	for range 100 {
+		if got := p.Backoff(2); got < 100*time.Millisecond || got > 300*time.Millisecond {
+			t.Fatalf("Backoff(2) with jitter = %s, want within 50%% of 200ms", got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		v      string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.v, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %s, %v, want %s, %v", tt.v, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: 10 * time.Second, Multiplier: 2}
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		retry      int
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{"backoff", p, 2, 0, 2 * time.Second},
+		{"shorter retry after", p, 2, time.Second, 2 * time.Second},
+		{"longer retry after", p, 1, 5 * time.Second, 5 * time.Second},
+		{"retry after capped", p, 1, time.Hour, 10 * time.Second},
+		{"uncapped", RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, Multiplier: 1}, 1, time.Hour, time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.RetryDelay(tt.retry, tt.retryAfter); got != tt.want {
+				t.Errorf("RetryDelay(%d, %s) = %s, want %s", tt.retry, tt.retryAfter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterIsCappedByMaxBackoff(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.URL, WithInsecureURL(), WithRetry(RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.PostLogsStringContext(ctx, "line"); err != nil {
+		t.Fatalf("post error = %v, want the retry to succeed", err)
+	}
+	if n := attempts.Load(); n != 2 {
+		t.Errorf("made %d attempts, want 2", n)
+	}
+}
@@ -0,0 +1,88 @@
+package gosumo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// Sampler decides which logs are posted, so the ingest cost of chatty
+// services can be controlled. It is applied to every encoded log before it is
+// added to a batch, and must be safe for concurrent use.
+type Sampler interface {
+	// Sample reports whether the log should be kept.
+	Sample(line []byte) bool
+}
+
+// SamplerFunc adapts a function to a Sampler.
+type SamplerFunc func(line []byte) bool
+
+// Sample calls f.
+func (f SamplerFunc) Sample(line []byte) bool {
+	return f(line)
+}
+
+// WithSampler makes the Client discard the logs the Sampler does not keep.
+// Discarded logs are counted in Stats.Sampled.
+func WithSampler(s Sampler) Option {
+	return func(c *Client) error {
+		if s == nil {
+			return fmt.Errorf("sampler cannot be nil")
+		}
+		c.sampler = s
+		return nil
+	}
+}
+
+// RateSampler returns a Sampler that keeps a random fraction of logs, given
+// by rate. A rate of 0 or less discards every log and a rate of 1 or more
+// keeps every log.
+func RateSampler(rate float64) Sampler {
+	return SamplerFunc(func([]byte) bool {
+		return keep(rate)
+	})
+}
+
+// LevelSampler returns a Sampler that keeps a random fraction of logs based
+// on their level, read from the named field of JSON object logs. Levels are
+// matched case-insensitively, and logs with a level that is not in rates, no
+// level, or that are not JSON objects are always kept. For example, to keep
+// 10% of debug logs and every other log:
+//
+//	gosumo.LevelSampler("level", map[string]float64{"debug": 0.1})
+func LevelSampler(field string, rates map[string]float64) Sampler {
+	lower := make(map[string]float64, len(rates))
+	for level, rate := range rates {
+		lower[strings.ToLower(level)] = rate
+	}
+	return SamplerFunc(func(line []byte) bool {
+		rate, ok := lower[strings.ToLower(logLevel(line, field))]
+		return !ok || keep(rate)
+	})
+}
+
+// logLevel returns the string value of the field in a JSON object log, or an
+// empty string.
+func logLevel(line []byte, field string) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return ""
+	}
+	var level string
+	if err := json.Unmarshal(fields[field], &level); err != nil {
+		return ""
+	}
+	return level
+}
+
+// keep randomly returns true with the probability given by rate.
+func keep(rate float64) bool {
+	switch {
+	case rate <= 0:
+		return false
+	case rate >= 1:
+		return true
+	}
+	return rand.Float64() < rate
+}
@@ -0,0 +1,36 @@
+package gosumo
+
+import (
+	"context"
+	"time"
+)
+
+// LogSender posts newline delimited logs to Sumo Logic. It is implemented by
+// Client, and the types built on top of it, such as BufferedSender and
+// Writer, accept any LogSender so they can be tested with a fake, like the
+// one provided by the gosumotest package.
+type LogSender interface {
+	// Send posts the newline delimited logs in payload.
+	Send(ctx context.Context, payload []byte, opts ...PostOption) error
+}
+
+// Send posts the newline delimited logs in payload, splitting them into
+// batches according to the Client's batch limits. It implements LogSender.
+func (c *Client) Send(ctx context.Context, payload []byte, opts ...PostOption) error {
+	return c.sendPayload(ctx, payload, opts, true)
+}
+
+// sendPayload implements Send. The lines are only prepared, see prepareLine,
+// when prepare is set, since a BufferedSender prepares its logs as they are
+// enqueued and they must not be sampled twice.
+func (c *Client) sendPayload(ctx context.Context, payload []byte, opts []PostOption, prepare bool) error {
+	cfg, err := c.newPostConfig(opts)
+	if err != nil {
+		return err
+	}
+	lines := splitLines(payload)
+	if prepare {
+		lines = c.prepareLines(lines, time.Now())
+	}
+	return c.postLines(ctx, lines, cfg)
+}
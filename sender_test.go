@@ -0,0 +1,242 @@
+package gosumo_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+	"github.com/byitkc/gosumo/gosumotest"
+)
+
+func newBufferedSender(t *testing.T, sender gosumo.LogSender, opts ...gosumo.BufferOption) *gosumo.BufferedSender {
+	t.Helper()
+	s, err := gosumo.NewBufferedSender(sender, append([]gosumo.BufferOption{gosumo.WithFlushInterval(time.Hour)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// waitFor polls until cond is true, failing the test after a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func TestBufferedSenderFlush(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	for _, line := range []string{"one", "two"} {
+		if err := s.Enqueue([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Send(map[string]string{"msg": "three"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(fake.Payloads()); n != 0 {
+		t.Fatalf("sent %d payloads before a flush", n)
+	}
+	if got := s.Stats().QueueDepth; got != 3 {
+		t.Errorf("QueueDepth = %d, want 3", got)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fake.Lines(), []string{"one", "two", `{"msg":"three"}`}; !slices.Equal(got, want) {
+		t.Errorf("sent %q, want %q", got, want)
+	}
+	if n := len(fake.Payloads()); n != 1 {
+		t.Errorf("sent %d payloads, want 1", n)
+	}
+}
+
+func TestBufferedSenderFlushThresholds(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  gosumo.BufferOption
+	}{
+		{"logs", gosumo.WithFlushLogs(3)},
+		{"bytes", gosumo.WithFlushBytes(12)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := gosumotest.NewFakeSender()
+			s := newBufferedSender(t, fake, tt.opt)
+			for _, line := range []string{"one", "two", "six"} {
+				if err := s.Enqueue([]byte(line)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			waitFor(t, "the background flush", func() bool { return len(fake.Lines()) == 3 })
+		})
+	}
+}
+
+func TestBufferedSenderFlushInterval(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake, gosumo.WithFlushInterval(10*time.Millisecond))
+	if err := s.Enqueue([]byte("line")); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the interval flush", func() bool { return len(fake.Lines()) == 1 })
+}
+
+func TestBufferedSenderClose(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	if err := s.Enqueue([]byte("line")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.Lines(); !slices.Equal(got, []string{"line"}) {
+		t.Errorf("sent %q, want the buffered log", got)
+	}
+	if err := s.Enqueue([]byte("late")); !errors.Is(err, gosumo.ErrSenderClosed) {
+		t.Errorf("Enqueue after Close = %v, want ErrSenderClosed", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+}
+
+func TestBufferedSenderErrorHandler(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	failure := errors.New("unavailable")
+	fake.SetError(failure)
+	var mu sync.Mutex
+	var errs []error
+	s := newBufferedSender(t, fake, gosumo.WithFlushLogs(1), gosumo.WithErrorHandler(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}))
+	if err := s.Enqueue([]byte("line")); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the error handler", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs) == 1
+	})
+	if !errors.Is(errs[0], failure) {
+		t.Errorf("error handler got %v, want %v", errs[0], failure)
+	}
+	if err := s.Flush(); err != nil {
+		t.Errorf("Flush of an empty buffer = %v", err)
+	}
+}
+
+func TestWriterWithFakeSender(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	w, err := gosumo.NewWriter(fake, gosumo.WithFlushInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("first\nsecond\npart")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.Lines(); !slices.Equal(got, []string{"first", "second"}) {
+		t.Errorf("flushed %q, want the complete lines", got)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.Lines(); !slices.Equal(got, []string{"first", "second", "part"}) {
+		t.Errorf("sent %q, want the partial line on close", got)
+	}
+	if _, err := w.Write([]byte("late\n")); !errors.Is(err, gosumo.ErrSenderClosed) {
+		t.Errorf("Write after Close = %v, want ErrSenderClosed", err)
+	}
+}
+
+func TestClientSend(t *testing.T) {
+	col := newCollector(t)
+	c, err := col.NewClient(gosumo.WithMaxBatchLogs(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sender gosumo.LogSender = c
+	if err := sender.Send(context.Background(), []byte("one\ntwo\nthree\n"), gosumo.PostWithSourceName("app")); err != nil {
+		t.Fatal(err)
+	}
+	reqs := col.Requests()
+	if len(reqs) != 2 || reqs[0].SourceName != "app" {
+		t.Fatalf("collector received %+v, want 2 batches with the source name", reqs)
+	}
+	if got := col.Lines(); !slices.Equal(got, []string{"one", "two", "three"}) {
+		t.Errorf("collector received %q", got)
+	}
+}
+
+func TestBufferedSenderSpool(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	fake.SetError(errors.New("connection refused"))
+	dir := t.TempDir()
+	s := newBufferedSender(t, fake, gosumo.WithSpool(dir, 1<<20))
+	for _, line := range []string{"one", "two"} {
+		if err := s.Enqueue([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The logs are spooled instead of being lost.
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush with a spool = %v, want nil", err)
+	}
+	if err := s.Enqueue([]byte("three")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	fake.SetError(nil)
+	if err := s.Enqueue([]byte("four")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Close only posts the buffered log. The spooled payloads are kept for
+	// the background flushes to replay.
+	if got := fake.Lines(); !slices.Equal(got, []string{"four"}) {
+		t.Fatalf("sent %q on close, want the buffered log", got)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 2 {
+		t.Errorf("spool has %d files, %v, want the 2 failed payloads", len(entries), err)
+	}
+}
+
+func TestBufferedSenderSpoolReplay(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	fake.SetError(errors.New("connection refused"))
+	dir := t.TempDir()
+	s := newBufferedSender(t, fake, gosumo.WithSpool(dir, 1<<20), gosumo.WithFlushInterval(10*time.Millisecond))
+	for _, line := range []string{"one", "two"} {
+		if err := s.Enqueue([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	fake.SetError(nil)
+	waitFor(t, "the spool to be replayed", func() bool { return len(fake.Lines()) == 2 })
+	if got := fake.Lines(); !slices.Equal(got, []string{"one", "two"}) {
+		t.Errorf("replayed %q", got)
+	}
+}
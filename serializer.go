@@ -0,0 +1,265 @@
+package gosumo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Serializer encodes a single log as a line of the payload. It lets sources
+// that do not use JSON share the Client's batching and retry handling. A
+// Serializer must be safe for concurrent use.
+type Serializer interface {
+	// Serialize writes v to w as a single line. A trailing newline is
+	// removed, and the line must not contain any other newlines.
+	Serialize(w io.Writer, v any) error
+}
+
+// WithSerializer sets the Serializer used to encode logs passed to PostLogs,
+// PostLogsChan and BufferedSender.Send. The default is JSONLines. Only
+// JSONLines checks logs for JSON metadata.
+func WithSerializer(s Serializer) Option {
+	return func(c *Client) error {
+		if s == nil {
+			return fmt.Errorf("serializer cannot be nil")
+		}
+		c.serializer = s
+		return nil
+	}
+}
+
+// JSONLines is the default Serializer, which encodes every log as a JSON
+// object on its own line.
+type JSONLines struct{}
+
+// Serialize writes the JSON encoding of v.
+func (JSONLines) Serialize(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// TextLines is a Serializer for plain text logs. Strings and byte slices are
+// written as-is, values implementing fmt.Stringer or error use their String
+// or Error methods, and anything else is formatted with fmt.Sprint. Newlines
+// inside a log are escaped as \n so the log stays on one line.
+type TextLines struct{}
+
+// Serialize writes v as plain text.
+func (TextLines) Serialize(w io.Writer, v any) error {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case fmt.Stringer:
+		s = v.String()
+	case error:
+		s = v.Error()
+	default:
+		s = fmt.Sprint(v)
+	}
+	s = strings.TrimRight(s, "\r\n")
+	s = newlineEscaper.Replace(s)
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+// newlineEscaper escapes newlines as \n, so a log stays on one line.
+var newlineEscaper = strings.NewReplacer("\r\n", `\n`, "\n", `\n`)
+
+// CSV is a Serializer that writes every log as a CSV record. A log can be a
+// []string or []any holding the values of the record, or a struct or map,
+// in which case Columns picks the JSON fields written and their order.
+// Fields missing from a log are written as empty values. If Columns is
+// empty, every field is written in the order it is encoded. Newlines inside
+// a value are escaped as \n, since a quoted value spanning lines would be
+// split into separate logs.
+type CSV struct {
+	// Columns are the names of the JSON fields written for structs and maps.
+	Columns []string
+	// Comma is the field delimiter. If it is zero, a comma is used.
+	Comma rune
+}
+
+// Serialize writes v as a CSV record.
+func (s CSV) Serialize(w io.Writer, v any) error {
+	var record []string
+	switch v := v.(type) {
+	case []string:
+		record = v
+	case []any:
+		record = make([]string, len(v))
+		for i, f := range v {
+			record[i] = fmt.Sprint(f)
+		}
+	default:
+		fields, err := jsonFields(v)
+		if err != nil {
+			return err
+		}
+		if len(s.Columns) == 0 {
+			for _, f := range fields {
+				record = append(record, f.text())
+			}
+			break
+		}
+		byName := make(map[string]string, len(fields))
+		for _, f := range fields {
+			byName[f.name] = f.text()
+		}
+		record = make([]string, len(s.Columns))
+		for i, col := range s.Columns {
+			record[i] = byName[col]
+		}
+	}
+	cloned := false
+	for i, f := range record {
+		if !strings.Contains(f, "\n") {
+			continue
+		}
+		if !cloned {
+			// A []string log is the caller's, so it is not modified.
+			record = slices.Clone(record)
+			cloned = true
+		}
+		record[i] = newlineEscaper.Replace(f)
+	}
+	cw := csv.NewWriter(w)
+	if s.Comma != 0 {
+		cw.Comma = s.Comma
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Logfmt is a Serializer that writes every log as key=value pairs, such as
+// level=info msg="request served". A log must be a struct or map, and its
+// JSON fields are written in the order they are encoded. Nested objects and
+// arrays are written as JSON.
+type Logfmt struct{}
+
+// Serialize writes v as logfmt.
+func (Logfmt) Serialize(w io.Writer, v any) error {
+	fields, err := jsonFields(v)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(f.name)
+		b.WriteByte('=')
+		value := f.text()
+		if value == "" || strings.ContainsAny(value, " =\"\\\t\r\n") {
+			value = strconv.Quote(value)
+		}
+		b.WriteString(value)
+	}
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// jsonField is a top level field of a log's JSON encoding.
+type jsonField struct {
+	name  string
+	value json.RawMessage
+}
+
+// text returns the value of the field as text. Strings are unquoted, null is
+// empty, and anything else is the JSON encoding.
+func (f jsonField) text() string {
+	switch {
+	case len(f.value) == 0 || string(f.value) == "null":
+		return ""
+	case f.value[0] == '"':
+		var s string
+		if err := json.Unmarshal(f.value, &s); err == nil {
+			return s
+		}
+	}
+	return string(f.value)
+}
+
+// jsonFields returns the top level fields of the JSON encoding of v, in the
+// order they are encoded. It returns an error if v does not encode to a JSON
+// object.
+func jsonFields(v any) ([]jsonField, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("%T does not encode to a json object", v)
+	}
+	var fields []jsonField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		f := jsonField{name: tok.(string)}
+		if err := dec.Decode(&f.value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// logEncoder encodes logs into a reusable line buffer using the Client's
+// Serializer.
+type logEncoder struct {
+	s    Serializer
+	line *bytes.Buffer
+	enc  *json.Encoder
+}
+
+// newLogEncoder returns a logEncoder for the Client that writes into line.
+// For the default JSONLines a single json.Encoder is reused for every log.
+func (c *Client) newLogEncoder(line *bytes.Buffer) *logEncoder {
+	e := &logEncoder{s: c.serializer, line: line}
+	if e.s == nil {
+		e.enc = json.NewEncoder(line)
+	}
+	return e
+}
+
+// encode returns the line for v. The returned slice is only valid until the
+// next call.
+func (e *logEncoder) encode(v any) ([]byte, error) {
+	e.line.Reset()
+	var err error
+	if e.enc != nil {
+		err = e.enc.Encode(v)
+	} else {
+		err = e.s.Serialize(e.line, v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(e.line.Bytes(), "\r\n"), nil
+}
+
+// validatesJSON reports whether logs need to be checked for JSON metadata
+// before they are encoded.
+func (c *Client) validatesJSON() bool {
+	if c.skipJSONValidation {
+		return false
+	}
+	switch c.serializer.(type) {
+	case nil, JSONLines, *JSONLines:
+		return true
+	}
+	return false
+}
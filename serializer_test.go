@@ -0,0 +1,85 @@
+package gosumo
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+)
+
+type stringer struct{}
+
+func (stringer) String() string { return "from String" }
+
+func TestSerializers(t *testing.T) {
+	type entry struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+		N     int    `json:"n,omitempty"`
+	}
+	tests := []struct {
+		name string
+		s    Serializer
+		v    any
+		want string
+	}{
+		{"json", JSONLines{}, entry{Level: "info", Msg: "hi"}, `{"level":"info","msg":"hi"}` + "\n"},
+		{"text string", TextLines{}, "a\nb\r\nc\n", `a\nb\nc`},
+		{"text bytes", TextLines{}, []byte("raw"), "raw"},
+		{"text stringer", TextLines{}, stringer{}, "from String"},
+		{"text error", TextLines{}, errors.New("failed"), "failed"},
+		{"text other", TextLines{}, 42, "42"},
+		{"csv strings", CSV{}, []string{"a", "b c", `d"e`}, `a,b c,"d""e"` + "\n"},
+		{"csv values", CSV{Comma: ';'}, []any{1, "x;y", true}, `1;"x;y";true` + "\n"},
+		{"csv struct", CSV{}, entry{Level: "info", Msg: "hi", N: 2}, "info,hi,2\n"},
+		{"csv columns", CSV{Columns: []string{"msg", "missing", "level"}}, map[string]any{"level": "warn", "msg": "m"}, "m,,warn\n"},
+		{"csv newlines", CSV{}, []string{"first\nsecond", "a\r\nb"}, `first\nsecond,a\nb` + "\n"},
+		{"csv newlines in struct", CSV{}, entry{Level: "error", Msg: "panic:\n\tgoroutine 1"}, "error,panic:\\n\tgoroutine 1\n"},
+		{"logfmt", Logfmt{}, entry{Level: "info", Msg: "request served", N: 3}, `level=info msg="request served" n=3`},
+		{"logfmt quoting", Logfmt{}, map[string]any{"a": "", "b": "x=y\n", "c": map[string]int{"d": 1}}, `a="" b="x=y\n" c="{\"d\":1}"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.s.Serialize(&buf, tt.v); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Serialize = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSerializerErrors(t *testing.T) {
+	for _, s := range []Serializer{CSV{}, Logfmt{}} {
+		if err := s.Serialize(new(bytes.Buffer), []int{1}); err == nil {
+			t.Errorf("%T.Serialize of a slice succeeded, want an error", s)
+		}
+	}
+}
+
+func TestCSVDoesNotModifyRecord(t *testing.T) {
+	record := []string{"a\nb"}
+	if err := (CSV{}).Serialize(new(bytes.Buffer), record); err != nil {
+		t.Fatal(err)
+	}
+	if record[0] != "a\nb" {
+		t.Errorf("record = %q, want it unchanged", record)
+	}
+}
+
+func TestCSVMultilineFieldsArePostedAsOneLog(t *testing.T) {
+	srv := newLineServer(t)
+	c, err := NewClient(srv.URL, WithInsecureURL(), WithSerializer(CSV{}), WithMaxBatchLogs(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs := [][]string{{"1", "line one\nline two"}, {"2", "single"}}
+	if err := PostLogs(c, logs); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := srv.Lines(), []string{`1,line one\nline two`, "2,single"}; !slices.Equal(got, want) {
+		t.Errorf("posted %q, want %q", got, want)
+	}
+}
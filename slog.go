@@ -0,0 +1,67 @@
+package gosumo
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler that encodes records as JSON and queues them
+// on a BufferedSender, which posts them to Sumo Logic in batches.
+type SlogHandler struct {
+	inner  slog.Handler
+	sender *BufferedSender
+}
+
+// NewSlogHandler creates and returns a new SlogHandler that queues records on
+// the provided BufferedSender. The options control level filtering, source
+// information and attribute rewriting in the same way as for
+// slog.NewJSONHandler, and may be nil. The BufferedSender must be closed by
+// the caller when logging is finished.
+func NewSlogHandler(s *BufferedSender, opts *slog.HandlerOptions) *SlogHandler {
+	return &SlogHandler{
+		inner:  slog.NewJSONHandler(senderWriter{s}, opts),
+		sender: s,
+	}
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle encodes the record as JSON and queues it to be posted.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.inner.Handle(ctx, r)
+}
+
+// WithAttrs returns a new SlogHandler whose records include the provided
+// attributes.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SlogHandler{inner: h.inner.WithAttrs(attrs), sender: h.sender}
+}
+
+// WithGroup returns a new SlogHandler that nests the attributes of its
+// records under the provided group.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return &SlogHandler{inner: h.inner.WithGroup(name), sender: h.sender}
+}
+
+// senderWriter is an io.Writer that queues each write as a single log line
+// on a BufferedSender. It relies on the writer making exactly one write per
+// log, as the slog handlers do.
+type senderWriter struct {
+	sender *BufferedSender
+}
+
+// Write queues p, without its trailing newline, as a single log line.
+func (w senderWriter) Write(p []byte) (int, error) {
+	line := bytes.TrimRight(p, "\r\n")
+	if len(line) == 0 {
+		return len(p), nil
+	}
+	if err := w.sender.Enqueue(line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
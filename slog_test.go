@@ -0,0 +1,125 @@
+package gosumo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+	"github.com/byitkc/gosumo/gosumotest"
+)
+
+// decodeLines decodes each line as a JSON object.
+func decodeLines(t *testing.T, lines []string) []map[string]any {
+	t.Helper()
+	var logs []map[string]any
+	for _, line := range lines {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		logs = append(logs, m)
+	}
+	return logs
+}
+
+func TestSlogHandler(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	logger := slog.New(gosumo.NewSlogHandler(s, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	logger.Debug("dropped")
+	logger.Info("started", "port", 8080)
+	logger.With("request_id", "abc").WithGroup("http").Warn("slow request", "status", 200, slog.Group("client", "ip", "10.0.0.1"))
+	logger.Error("failed", "error", errors.New("connection refused"))
+	if len(fake.Payloads()) != 0 {
+		t.Fatal("records were posted before the flush")
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every record is posted in a single batch, one line per record.
+	if n := len(fake.Payloads()); n != 1 {
+		t.Fatalf("sent %d payloads, want 1", n)
+	}
+	logs := decodeLines(t, fake.Lines())
+	if len(logs) != 3 {
+		t.Fatalf("posted %d logs, want 3: %v", len(logs), logs)
+	}
+	tests := []struct {
+		level, msg string
+		check      func(m map[string]any) bool
+	}{
+		{"INFO", "started", func(m map[string]any) bool { return m["port"] == 8080.0 }},
+		{"WARN", "slow request", func(m map[string]any) bool {
+			http, _ := m["http"].(map[string]any)
+			client, _ := http["client"].(map[string]any)
+			return m["request_id"] == "abc" && http["status"] == 200.0 && client["ip"] == "10.0.0.1"
+		}},
+		{"ERROR", "failed", func(m map[string]any) bool { return m["error"] == "connection refused" }},
+	}
+	for i, tt := range tests {
+		m := logs[i]
+		if m["level"] != tt.level || m["msg"] != tt.msg || !tt.check(m) {
+			t.Errorf("log %d = %v, want %s %q with its attributes", i, m, tt.level, tt.msg)
+		}
+		if _, ok := m["time"]; !ok {
+			t.Errorf("log %d has no time", i)
+		}
+	}
+}
+
+func TestSlogHandlerOptions(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	h := gosumo.NewSlogHandler(s, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "password" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Enabled(Info) = true with a Warn level")
+	}
+	if !h.Enabled(context.Background(), slog.LevelWarn) {
+		t.Error("Enabled(Warn) = false with a Warn level")
+	}
+	slog.New(h).Warn("login", "user", "alice", "password", "secret")
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"level":"WARN","msg":"login","user":"alice"}`
+	if got := fake.Lines(); len(got) != 1 || got[0] != want {
+		t.Errorf("lines = %q, want [%s]", got, want)
+	}
+}
+
+func TestSlogHandlerNilOptions(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	h := gosumo.NewSlogHandler(s, nil)
+	// Without options the level is Info, as for slog.NewJSONHandler.
+	if h.Enabled(context.Background(), slog.LevelDebug) || !h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("nil options do not default to the Info level")
+	}
+}
+
+func TestSlogHandlerClosedSender(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	h := gosumo.NewSlogHandler(s, nil)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "late", 0)
+	if err := h.Handle(context.Background(), r); !errors.Is(err, gosumo.ErrSenderClosed) {
+		t.Errorf("Handle = %v, want ErrSenderClosed", err)
+	}
+}
@@ -0,0 +1,175 @@
+package gosumo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// spoolMagic starts every spool file, followed by the payload length and the
+// CRC-32 checksum of the payload. Files that do not match are discarded when
+// they are replayed, so a crash while writing never blocks the spool.
+var spoolMagic = [4]byte{'G', 'S', 'P', '1'}
+
+const (
+	spoolHeaderSize = 12
+	spoolExt        = ".spool"
+)
+
+// spool stores payloads that could not be posted in a directory on disk, one
+// file per payload, so they can be replayed later.
+type spool struct {
+	dir      string
+	maxBytes int64
+
+	mu  sync.Mutex
+	seq uint64
+}
+
+// WithSpool makes the BufferedSender spill payloads to files in dir when the
+// endpoint cannot be reached or the buffer is full, and replay them once
+// posting succeeds again. The spool is capped at maxBytes, and the oldest
+// files are removed to make room for new ones. Files left in dir by a
+// previous run are replayed as well. A replayed payload that the endpoint
+// rejects, rather than failing to reach it, is reported to the error handler
+// and removed, so it does not stop the files after it from being replayed.
+func WithSpool(dir string, maxBytes int64) BufferOption {
+	return func(s *BufferedSender) error {
+		if dir == "" {
+			return fmt.Errorf("spool directory cannot be empty")
+		}
+		if maxBytes <= 0 {
+			return fmt.Errorf("spool size must be greater than zero, got: %d", maxBytes)
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("error creating spool directory: %w", err)
+		}
+		s.spool = &spool{dir: dir, maxBytes: maxBytes}
+		return nil
+	}
+}
+
+// write stores the payload in a new spool file, removing the oldest files if
+// the spool would exceed its size cap. The file is written to a temporary
+// name and renamed so partially written files are never replayed.
+func (sp *spool) write(payload []byte) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	size := int64(len(payload) + spoolHeaderSize)
+	if size > sp.maxBytes {
+		return fmt.Errorf("payload of %d bytes is larger than the spool", len(payload))
+	}
+	files, total, err := sp.list()
+	if err != nil {
+		return err
+	}
+	for len(files) > 0 && total+size > sp.maxBytes {
+		info, err := os.Stat(files[0])
+		if err == nil {
+			total -= info.Size()
+		}
+		os.Remove(files[0])
+		files = files[1:]
+	}
+	sp.seq++
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), sp.seq%1000000, spoolExt)
+	buf := make([]byte, spoolHeaderSize, size)
+	copy(buf, spoolMagic[:])
+	binary.BigEndian.PutUint32(buf[4:8], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[8:12], crc32.ChecksumIEEE(payload))
+	buf = append(buf, payload...)
+	tmp := filepath.Join(sp.dir, "."+name+".tmp")
+	if err := os.WriteFile(tmp, buf, 0o600); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("error writing spool file: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(sp.dir, name)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("error writing spool file: %w", err)
+	}
+	return nil
+}
+
+// list returns the spool files, oldest first, and their total size. It must
+// be called with the lock held.
+func (sp *spool) list() ([]string, int64, error) {
+	entries, err := os.ReadDir(sp.dir)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading spool directory: %w", err)
+	}
+	var files []string
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), spoolExt) || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		if info, err := e.Info(); err == nil {
+			total += info.Size()
+		}
+		files = append(files, filepath.Join(sp.dir, e.Name()))
+	}
+	slices.Sort(files)
+	return files, total, nil
+}
+
+// replay calls send with each spooled payload, oldest first, removing the
+// file once it has been sent. It stops at the first error that means the
+// endpoint is unavailable, keeping the file to send it again later. Payloads
+// the endpoint rejects are removed instead, since they would be rejected
+// again and hold up the files after them, and their errors are returned
+// once every file has been tried. Corrupt files are removed without being
+// sent.
+func (sp *spool) replay(send func(payload []byte) error) error {
+	sp.mu.Lock()
+	files, _, err := sp.list()
+	sp.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	var rejected []error
+	for _, f := range files {
+		payload, err := readSpoolFile(f)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			os.Remove(f)
+			continue
+		}
+		if err := send(payload); err != nil {
+			if isEndpointFailure(err) {
+				return errors.Join(append(rejected, err)...)
+			}
+			rejected = append(rejected, err)
+		}
+		os.Remove(f)
+	}
+	return errors.Join(rejected...)
+}
+
+// readSpoolFile reads a spool file and verifies its header and checksum.
+func readSpoolFile(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < spoolHeaderSize || [4]byte(b[:4]) != spoolMagic {
+		return nil, fmt.Errorf("invalid spool file header")
+	}
+	n := binary.BigEndian.Uint32(b[4:8])
+	payload := b[spoolHeaderSize:]
+	if uint32(len(payload)) != n {
+		return nil, fmt.Errorf("spool file is truncated")
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(b[8:12]) {
+		return nil, fmt.Errorf("spool file checksum mismatch")
+	}
+	return payload, nil
+}
@@ -0,0 +1,121 @@
+package gosumo
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestSpoolReplay(t *testing.T) {
+	rejected := HTTPError{StatusCode: http.StatusBadRequest}
+	unavailable := HTTPError{StatusCode: http.StatusServiceUnavailable, Retryable: true}
+	tests := []struct {
+		name string
+		// errs holds the error send returns for each payload.
+		errs     map[string]error
+		wantSent []string
+		wantLeft int
+		wantErr  error
+	}{
+		{
+			name:     "all sent",
+			wantSent: []string{"a", "b", "c"},
+		},
+		{
+			name:     "rejected payload is dropped",
+			errs:     map[string]error{"b": rejected},
+			wantSent: []string{"a", "b", "c"},
+			wantErr:  ErrClientError,
+		},
+		{
+			name:     "unavailable endpoint stops the replay",
+			errs:     map[string]error{"b": unavailable},
+			wantSent: []string{"a", "b"},
+			wantLeft: 2,
+			wantErr:  ErrServerError,
+		},
+		{
+			name:     "transport error stops the replay",
+			errs:     map[string]error{"a": errors.New("connection refused")},
+			wantSent: []string{"a"},
+			wantLeft: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := &spool{dir: t.TempDir(), maxBytes: 1 << 20}
+			for _, p := range []string{"a", "b", "c"} {
+				if err := sp.write([]byte(p)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var sent []string
+			err := sp.replay(func(payload []byte) error {
+				sent = append(sent, string(payload))
+				return tt.errs[string(payload)]
+			})
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("replay error = %v, want %v", err, tt.wantErr)
+			}
+			if len(tt.errs) == 0 && err != nil {
+				t.Errorf("replay error = %v, want nil", err)
+			}
+			if !slices.Equal(sent, tt.wantSent) {
+				t.Errorf("sent %q, want %q", sent, tt.wantSent)
+			}
+			files, _, err := sp.list()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != tt.wantLeft {
+				t.Errorf("%d files left in the spool, want %d", len(files), tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestSpoolDiscardsCorruptFiles(t *testing.T) {
+	sp := &spool{dir: t.TempDir(), maxBytes: 1 << 20}
+	if err := sp.write([]byte("good")); err != nil {
+		t.Fatal(err)
+	}
+	files, _, _ := sp.list()
+	if err := os.WriteFile(files[0]+"x"+spoolExt, []byte("GSP1 garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var sent []string
+	if err := sp.replay(func(payload []byte) error {
+		sent = append(sent, string(payload))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(sent, []string{"good"}) {
+		t.Errorf("sent %q, want only the valid payload", sent)
+	}
+	if files, _, _ := sp.list(); len(files) != 0 {
+		t.Errorf("%d files left in the spool, want 0", len(files))
+	}
+}
+
+func TestSpoolEvictsOldestFiles(t *testing.T) {
+	sp := &spool{dir: t.TempDir(), maxBytes: 3 * (spoolHeaderSize + 1)}
+	for _, p := range []string{"a", "b", "c", "d"} {
+		if err := sp.write([]byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var sent []string
+	sp.replay(func(payload []byte) error {
+		sent = append(sent, string(payload))
+		return nil
+	})
+	if !slices.Equal(sent, []string{"b", "c", "d"}) {
+		t.Errorf("sent %q, want the three newest payloads", sent)
+	}
+	if err := sp.write(make([]byte, sp.maxBytes)); err == nil {
+		t.Error("writing a payload larger than the spool succeeded")
+	}
+}
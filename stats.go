@@ -0,0 +1,64 @@
+package gosumo
+
+import "sync/atomic"
+
+// Stats are counters describing the health of log shipping. They are
+// cumulative from when the Client or BufferedSender was created.
+type Stats struct {
+	// LogsSent is the number of logs in batches that were accepted.
+	LogsSent uint64
+	// BytesSent is the number of payload bytes accepted, after compression.
+	BytesSent uint64
+	// BatchesSent is the number of requests that were accepted.
+	BatchesSent uint64
+	// Retries is the number of attempts made after the first attempt of a
+	// request.
+	Retries uint64
+	// Failures is the number of requests that failed after all attempts.
+	Failures uint64
+	// Dropped is the number of logs discarded by a BufferedSender because
+	// its buffer was full.
+	Dropped uint64
+	// Sampled is the number of logs discarded by the Client's Sampler.
+	Sampled uint64
+	// QueueDepth is the number of logs currently buffered by a
+	// BufferedSender.
+	QueueDepth int
+}
+
+// clientStats holds the Client's counters.
+type clientStats struct {
+	logsSent    atomic.Uint64
+	bytesSent   atomic.Uint64
+	batchesSent atomic.Uint64
+	retries     atomic.Uint64
+	failures    atomic.Uint64
+	sampled     atomic.Uint64
+}
+
+// Stats returns the Client's counters. Dropped and QueueDepth are always zero
+// for a Client, see BufferedSender.Stats.
+func (c *Client) Stats() Stats {
+	return Stats{
+		LogsSent:    c.stats.logsSent.Load(),
+		BytesSent:   c.stats.bytesSent.Load(),
+		BatchesSent: c.stats.batchesSent.Load(),
+		Retries:     c.stats.retries.Load(),
+		Failures:    c.stats.failures.Load(),
+		Sampled:     c.stats.sampled.Load(),
+	}
+}
+
+// Stats returns the BufferedSender's counters. When it sends with a Client
+// the Client's counters are included.
+func (s *BufferedSender) Stats() Stats {
+	var st Stats
+	if c, ok := s.sender.(*Client); ok {
+		st = c.Stats()
+	}
+	st.Dropped = s.dropped.Load()
+	s.mu.Lock()
+	st.QueueDepth = len(s.buf)
+	s.mu.Unlock()
+	return st
+}
@@ -0,0 +1,143 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AccessKey is an access ID and access key pair used to authenticate with the
+// management API.
+type AccessKey struct {
+	// ID is the access ID.
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	// Key is the secret access key. It is only returned when the key is
+	// created, so it must be stored then.
+	Key string `json:"key,omitempty"`
+	// CORSHeaders lists the origins allowed to use the key from a browser.
+	// An empty list allows any origin.
+	CORSHeaders []string  `json:"corsHeaders,omitempty"`
+	Disabled    bool      `json:"disabled"`
+	LastUsed    time.Time `json:"lastUsed"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CreatedBy   string    `json:"createdBy,omitempty"`
+	ModifiedAt  time.Time `json:"modifiedAt"`
+
+	// Extra holds the properties of the access key that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the access key, keeping the properties that have no
+// field in Extra.
+func (k *AccessKey) UnmarshalJSON(b []byte) error {
+	type plain AccessKey
+	return unmarshalExtra(b, (*plain)(k), &k.Extra)
+}
+
+// MarshalJSON encodes the access key along with the properties in Extra.
+func (k AccessKey) MarshalJSON() ([]byte, error) {
+	type plain AccessKey
+	return marshalExtra(plain(k), k.Extra)
+}
+
+// NewClient returns a Client that authenticates with the access key, which
+// must have been returned by CreateAccessKey or RotateAccessKey since the
+// secret is not returned otherwise.
+func (k AccessKey) NewClient(opts ...Option) (*Client, error) {
+	return NewClient(k.ID, k.Key, opts...)
+}
+
+// ListAccessKeys returns every access key of the account, including those
+// of service accounts, following pagination. Listing the keys of other
+// users requires the CapabilityManageAccessKeys capability.
+func (c *Client) ListAccessKeys(ctx context.Context) ([]AccessKey, error) {
+	return Collect(c.IterateAccessKeys(ctx))
+}
+
+// IterateAccessKeys returns an Iterator over the access keys returned by
+// ListAccessKeys, requesting each page as it is read.
+func (c *Client) IterateAccessKeys(ctx context.Context) *Iterator[AccessKey] {
+	return iterateTokens[AccessKey](ctx, c, "listing access keys", "/v1/accessKeys", nil)
+}
+
+// ListPersonalAccessKeys returns the access keys of the user the Client
+// authenticates as.
+func (c *Client) ListPersonalAccessKeys(ctx context.Context) ([]AccessKey, error) {
+	var resp struct {
+		Data []AccessKey `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/accessKeys/personal", nil, &resp); err != nil {
+		return nil, fmt.Errorf("error listing personal access keys: %w", err)
+	}
+	return resp.Data, nil
+}
+
+// CreateAccessKey creates an access key for the user the Client
+// authenticates as and returns it, including its secret. The origins, if
+// any, are the CORS allowlist of the key.
+func (c *Client) CreateAccessKey(ctx context.Context, label string, origins ...string) (*AccessKey, error) {
+	if label == "" {
+		return nil, fmt.Errorf("error creating access key: label is required")
+	}
+	body := struct {
+		Label       string   `json:"label"`
+		CORSHeaders []string `json:"corsHeaders,omitempty"`
+	}{label, origins}
+	var created AccessKey
+	if err := c.post(ctx, "/v1/accessKeys", body, &created); err != nil {
+		return nil, fmt.Errorf("error creating access key: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateAccessKey sets whether the access key with the ID is disabled and
+// its CORS allowlist, and returns the updated key.
+func (c *Client) UpdateAccessKey(ctx context.Context, id string, disabled bool, origins ...string) (*AccessKey, error) {
+	body := struct {
+		Disabled    bool     `json:"disabled"`
+		CORSHeaders []string `json:"corsHeaders,omitempty"`
+	}{disabled, origins}
+	var updated AccessKey
+	if err := c.put(ctx, pathf("/v1/accessKeys/%s", id), body, &updated); err != nil {
+		return nil, fmt.Errorf("error updating access key: %w", err)
+	}
+	return &updated, nil
+}
+
+// EnableAccessKey enables the access key with the ID, keeping its CORS
+// allowlist.
+func (c *Client) EnableAccessKey(ctx context.Context, key AccessKey) (*AccessKey, error) {
+	return c.UpdateAccessKey(ctx, key.ID, false, key.CORSHeaders...)
+}
+
+// DisableAccessKey disables the access key, keeping its CORS allowlist, so
+// requests authenticated with it fail until it is enabled again.
+func (c *Client) DisableAccessKey(ctx context.Context, key AccessKey) (*AccessKey, error) {
+	return c.UpdateAccessKey(ctx, key.ID, true, key.CORSHeaders...)
+}
+
+// DeleteAccessKey deletes the access key with the ID.
+func (c *Client) DeleteAccessKey(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/accessKeys/%s", id)); err != nil {
+		return fmt.Errorf("error deleting access key: %w", err)
+	}
+	return nil
+}
+
+// RotateAccessKey replaces the access key with a new one with the same label
+// and CORS allowlist, deletes the old key, and returns the new key including
+// its secret. If the old key cannot be deleted the new key is still returned
+// along with the error, so its secret is not lost.
+func (c *Client) RotateAccessKey(ctx context.Context, key AccessKey) (*AccessKey, error) {
+	created, err := c.CreateAccessKey(ctx, key.Label, key.CORSHeaders...)
+	if err != nil {
+		return nil, fmt.Errorf("error rotating access key: %w", err)
+	}
+	if err := c.DeleteAccessKey(ctx, key.ID); err != nil {
+		return created, fmt.Errorf("error rotating access key: %w", err)
+	}
+	return created, nil
+}
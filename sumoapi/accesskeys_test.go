@@ -0,0 +1,119 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestAccessKeys(t *testing.T) {
+	ctx := context.Background()
+	key := `{"id": "k1", "label": "ci", "corsHeaders": ["https://example.com"], "disabled": false, "lastUsed": "2024-01-02T03:04:05Z", "scopes": ["viewCollectors"]}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				keys, err := c.ListAccessKeys(ctx)
+				if err != nil {
+					return err
+				}
+				if len(keys) != 1 {
+					return expect(len(keys), 1)
+				}
+				return expect(string(keys[0].Extra["scopes"]), `["viewCollectors"]`)
+			},
+			response: `{"data": [` + key + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/accessKeys",
+			query:    "limit=100",
+		},
+		{
+			name: "list personal",
+			call: func(c *Client) error {
+				keys, err := c.ListPersonalAccessKeys(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(len(keys), 1)
+			},
+			response: `{"data": [` + key + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/accessKeys/personal",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				k, err := c.CreateAccessKey(ctx, "ci", "https://example.com")
+				if err != nil {
+					return err
+				}
+				client, err := k.NewClient()
+				if err != nil {
+					return err
+				}
+				return expect(client != nil, true)
+			},
+			response: `{"id": "k1", "label": "ci", "key": "secret"}`,
+			method:   http.MethodPost,
+			path:     "/v1/accessKeys",
+			body:     `{"label": "ci", "corsHeaders": ["https://example.com"]}`,
+		},
+		{
+			name: "disable",
+			call: func(c *Client) error {
+				_, err := c.DisableAccessKey(ctx, AccessKey{ID: "k1", CORSHeaders: []string{"https://example.com"}})
+				return err
+			},
+			response: key,
+			method:   http.MethodPut,
+			path:     "/v1/accessKeys/k1",
+			// The origins are sent again, so they are not cleared.
+			body: `{"disabled": true, "corsHeaders": ["https://example.com"]}`,
+		},
+		{
+			name: "enable",
+			call: func(c *Client) error {
+				_, err := c.EnableAccessKey(ctx, AccessKey{ID: "k1", Disabled: true})
+				return err
+			},
+			response: key,
+			method:   http.MethodPut,
+			path:     "/v1/accessKeys/k1",
+			body:     `{"disabled": false}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteAccessKey(ctx, "k1") },
+			method: http.MethodDelete,
+			path:   "/v1/accessKeys/k1",
+		},
+	})
+}
+
+func TestRotateAccessKey(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/accessKeys", `{"id": "k2", "label": "ci", "key": "new"}`)
+	api.handle("DELETE /v1/accessKeys/k1", func(w http.ResponseWriter, r *http.Request) {})
+	c := api.client()
+	created, err := c.RotateAccessKey(context.Background(), AccessKey{ID: "k1", Label: "ci", CORSHeaders: []string{"https://example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "k2" || created.Key != "new" {
+		t.Errorf("RotateAccessKey = %+v, want the new key", created)
+	}
+	reqs := api.received()
+	if len(reqs) != 2 {
+		t.Fatalf("received %d requests, want a create and a delete", len(reqs))
+	}
+	// The new key is created before the old one is deleted.
+	checkRequest(t, reqs[0], http.MethodPost, "/v1/accessKeys", `{"label": "ci", "corsHeaders": ["https://example.com"]}`)
+	checkRequest(t, reqs[1], http.MethodDelete, "/v1/accessKeys/k1", "")
+
+	// The new key is returned when the old one cannot be deleted.
+	created, err = c.RotateAccessKey(context.Background(), AccessKey{ID: "k3", Label: "ci"})
+	if !errors.Is(err, ErrNotFound) || created == nil || created.ID != "k2" {
+		t.Errorf("RotateAccessKey with a failed delete = %+v, %v, want the new key and the error", created, err)
+	}
+}
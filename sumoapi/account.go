@@ -0,0 +1,278 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrUsageReportFailed is returned, wrapped with the reason, when a usage
+// report job fails.
+var ErrUsageReportFailed = errors.New("usage report failed")
+
+// Groupings of a usage report.
+const (
+	UsageGroupByDay   = "day"
+	UsageGroupByWeek  = "week"
+	UsageGroupByMonth = "month"
+)
+
+// Types of a usage report.
+const (
+	// UsageReportStandard reports the credits used by each product
+	// variable.
+	UsageReportStandard = "standard"
+	// UsageReportDetailed also breaks the data ingested down by data tier.
+	UsageReportDetailed = "detailed"
+	// UsageReportChildDetailed reports the usage of every child
+	// organization, for parent organizations.
+	UsageReportChildDetailed = "childDetailed"
+)
+
+// AccountStatus describes the plan of the account and its state.
+type AccountStatus struct {
+	// PricingModel is "credits" for accounts billed in credits.
+	PricingModel       string  `json:"pricingModel"`
+	PlanType           string  `json:"planType"`
+	PlanExpirationDays int     `json:"planExpirationDays"`
+	CanUpdatePlan      bool    `json:"canUpdatePlan"`
+	ApplicationUse     string  `json:"applicationUse,omitempty"`
+	AccountActivated   bool    `json:"accountActivated"`
+	TotalCredits       float64 `json:"totalCredits,omitempty"`
+
+	// Extra holds the properties of the account status that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the account status, keeping the properties that have
+// no field in Extra.
+func (s *AccountStatus) UnmarshalJSON(b []byte) error {
+	type plain AccountStatus
+	return unmarshalExtra(b, (*plain)(s), &s.Extra)
+}
+
+// MarshalJSON encodes the account status along with the properties in Extra.
+func (s AccountStatus) MarshalJSON() ([]byte, error) {
+	type plain AccountStatus
+	return marshalExtra(plain(s), s.Extra)
+}
+
+// AccountOwner is the user that owns the account.
+type AccountOwner struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+// Subdomain is the subdomain of the account, used in the URL users log in
+// with, such as https://acme.us2.sumologic.com.
+type Subdomain struct {
+	Subdomain string `json:"subdomain"`
+	// URL is the login URL of the subdomain.
+	URL string `json:"url,omitempty"`
+
+	// Extra holds the properties of the subdomain that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the subdomain, keeping the properties that have no
+// field in Extra.
+func (s *Subdomain) UnmarshalJSON(b []byte) error {
+	type plain Subdomain
+	return unmarshalExtra(b, (*plain)(s), &s.Extra)
+}
+
+// MarshalJSON encodes the subdomain along with the properties in Extra.
+func (s Subdomain) MarshalJSON() ([]byte, error) {
+	type plain Subdomain
+	return marshalExtra(plain(s), s.Extra)
+}
+
+// UsageReportRequest selects the usage reported by a usage report.
+type UsageReportRequest struct {
+	// GroupBy is one of the UsageGroupBy constants. It defaults to
+	// UsageGroupByDay.
+	GroupBy string
+	// ReportType is one of the UsageReport constants. It defaults to
+	// UsageReportStandard.
+	ReportType string
+	// IncludeDeploymentCharge includes the charge of the deployment of the
+	// account in the credits used.
+	IncludeDeploymentCharge bool
+	// StartDate and EndDate are the days to report the usage of. The
+	// contract period of the account is used when they are zero.
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// UsageForecast is the usage of the account forecast until the end of its
+// contract period, based on its recent usage.
+type UsageForecast struct {
+	AverageUsage              float64 `json:"averageUsage"`
+	UsagePercentage           float64 `json:"usagePercentage"`
+	ForecastedUsage           float64 `json:"forecastedUsage"`
+	ForecastedUsagePercentage float64 `json:"forecastedUsagePercentage"`
+	RemainingDays             int     `json:"remainingDays"`
+}
+
+// AccountStatus returns the plan and state of the account.
+func (c *Client) AccountStatus(ctx context.Context) (*AccountStatus, error) {
+	var status AccountStatus
+	if err := c.get(ctx, "/v1/account/status", nil, &status); err != nil {
+		return nil, fmt.Errorf("error getting account status: %w", err)
+	}
+	return &status, nil
+}
+
+// AccountOwner returns the user that owns the account.
+func (c *Client) AccountOwner(ctx context.Context) (*AccountOwner, error) {
+	var owner AccountOwner
+	if err := c.get(ctx, "/v1/account/accountOwner", nil, &owner); err != nil {
+		return nil, fmt.Errorf("error getting account owner: %w", err)
+	}
+	return &owner, nil
+}
+
+// Subdomain returns the subdomain of the account. An error matching
+// ErrNotFound is returned if it has none.
+func (c *Client) Subdomain(ctx context.Context) (*Subdomain, error) {
+	var sub Subdomain
+	if err := c.get(ctx, "/v1/account/subdomain", nil, &sub); err != nil {
+		return nil, fmt.Errorf("error getting subdomain: %w", err)
+	}
+	return &sub, nil
+}
+
+// CreateSubdomain sets the subdomain of an account that has none and returns
+// it.
+func (c *Client) CreateSubdomain(ctx context.Context, subdomain string) (*Subdomain, error) {
+	var sub Subdomain
+	if err := c.post(ctx, "/v1/account/subdomain", Subdomain{Subdomain: subdomain}, &sub); err != nil {
+		return nil, fmt.Errorf("error creating subdomain: %w", err)
+	}
+	return &sub, nil
+}
+
+// UpdateSubdomain changes the subdomain of the account and returns it. The
+// previous login URL stops working.
+func (c *Client) UpdateSubdomain(ctx context.Context, subdomain string) (*Subdomain, error) {
+	var sub Subdomain
+	if err := c.put(ctx, "/v1/account/subdomain", Subdomain{Subdomain: subdomain}, &sub); err != nil {
+		return nil, fmt.Errorf("error updating subdomain: %w", err)
+	}
+	return &sub, nil
+}
+
+// DeleteSubdomain removes the subdomain of the account.
+func (c *Client) DeleteSubdomain(ctx context.Context) error {
+	if err := c.delete(ctx, "/v1/account/subdomain"); err != nil {
+		return fmt.Errorf("error deleting subdomain: %w", err)
+	}
+	return nil
+}
+
+// UsageForecast returns the usage of the account forecast from its average
+// usage over the last days. The API picks the number of days when days is
+// zero.
+func (c *Client) UsageForecast(ctx context.Context, days int) (*UsageForecast, error) {
+	var query url.Values
+	if days > 0 {
+		query = url.Values{"numberOfDays": {strconv.Itoa(days)}}
+	}
+	var forecast UsageForecast
+	if err := c.get(ctx, "/v1/account/usageForecast", query, &forecast); err != nil {
+		return nil, fmt.Errorf("error getting usage forecast: %w", err)
+	}
+	return &forecast, nil
+}
+
+// StartUsageReport starts a job generating a CSV usage report and returns
+// its ID. Use WaitUsageReport to wait for it to finish.
+func (c *Client) StartUsageReport(ctx context.Context, req UsageReportRequest) (string, error) {
+	if req.GroupBy == "" {
+		req.GroupBy = UsageGroupByDay
+	}
+	if req.ReportType == "" {
+		req.ReportType = UsageReportStandard
+	}
+	body := struct {
+		GroupBy                 string `json:"groupBy"`
+		ReportType              string `json:"reportType"`
+		IncludeDeploymentCharge bool   `json:"includeDeploymentCharge"`
+		StartDate               string `json:"startDate,omitempty"`
+		EndDate                 string `json:"endDate,omitempty"`
+	}{req.GroupBy, req.ReportType, req.IncludeDeploymentCharge, usageDate(req.StartDate), usageDate(req.EndDate)}
+	var job struct {
+		JobID string `json:"jobId"`
+	}
+	if err := c.post(ctx, "/v1/account/usage/report", body, &job); err != nil {
+		return "", fmt.Errorf("error starting usage report: %w", err)
+	}
+	return job.JobID, nil
+}
+
+// WaitUsageReport polls the usage report job until it has finished and
+// returns the URL the report can be downloaded from, which expires after a
+// while. If the job failed the error wraps ErrUsageReportFailed.
+func (c *Client) WaitUsageReport(ctx context.Context, jobID string) (string, error) {
+	statusPath := pathf("/v1/account/usage/report/%s/status", jobID)
+	if err := c.waitJob(ctx, ErrUsageReportFailed, statusPath); err != nil {
+		return "", fmt.Errorf("error waiting for usage report: %w", err)
+	}
+	var status struct {
+		ReportDownloadURL string `json:"reportDownloadURL"`
+	}
+	if err := c.get(ctx, statusPath, nil, &status); err != nil {
+		return "", fmt.Errorf("error waiting for usage report: %w", err)
+	}
+	return status.ReportDownloadURL, nil
+}
+
+// ExportUsageCSV generates a usage report, waits for it, and writes the CSV
+// to w.
+func (c *Client) ExportUsageCSV(ctx context.Context, req UsageReportRequest, w io.Writer) error {
+	jobID, err := c.StartUsageReport(ctx, req)
+	if err != nil {
+		return err
+	}
+	reportURL, err := c.WaitUsageReport(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	// The report URL is presigned, so it is downloaded without the
+	// credentials of the client.
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, reportURL, nil)
+	if err != nil {
+		return fmt.Errorf("error downloading usage report: %w", err)
+	}
+	resp, err := c.httpClient.Do(r)
+	if err != nil {
+		return fmt.Errorf("error downloading usage report: %w", err)
+	}
+	defer resp.Body.Close()
+	if !(resp.StatusCode >= 200 && resp.StatusCode <= 299) {
+		return fmt.Errorf("error downloading usage report: %w", newError(resp))
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("error downloading usage report: %w", err)
+	}
+	return nil
+}
+
+// usageDate formats a day of a usage report, or returns "" for the zero
+// time.
+func usageDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
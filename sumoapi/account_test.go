@@ -0,0 +1,148 @@
+package sumoapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAccount(t *testing.T) {
+	ctx := context.Background()
+	testCalls(t, []apiCall{
+		{
+			name: "status",
+			call: func(c *Client) error {
+				s, err := c.AccountStatus(ctx)
+				if err != nil {
+					return err
+				}
+				if err := expect(s.PlanType, "Enterprise"); err != nil {
+					return err
+				}
+				return expect(string(s.Extra["logModel"]), `"Flex"`)
+			},
+			response: `{"pricingModel": "credits", "planType": "Enterprise", "accountActivated": true, "logModel": "Flex"}`,
+			method:   http.MethodGet,
+			path:     "/v1/account/status",
+		},
+		{
+			name: "owner",
+			call: func(c *Client) error {
+				o, err := c.AccountOwner(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(o.Email, "owner@example.com")
+			},
+			response: `{"id": "u1", "email": "owner@example.com"}`,
+			method:   http.MethodGet,
+			path:     "/v1/account/accountOwner",
+		},
+		{
+			name: "subdomain",
+			call: func(c *Client) error {
+				s, err := c.Subdomain(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(s.URL, "https://acme.sumologic.com")
+			},
+			response: `{"subdomain": "acme", "url": "https://acme.sumologic.com"}`,
+			method:   http.MethodGet,
+			path:     "/v1/account/subdomain",
+		},
+		{
+			name: "create subdomain",
+			call: func(c *Client) error {
+				_, err := c.CreateSubdomain(ctx, "acme")
+				return err
+			},
+			method: http.MethodPost,
+			path:   "/v1/account/subdomain",
+			body:   `{"subdomain": "acme"}`,
+		},
+		{
+			name: "update subdomain",
+			call: func(c *Client) error {
+				_, err := c.UpdateSubdomain(ctx, "acme-corp")
+				return err
+			},
+			method: http.MethodPut,
+			path:   "/v1/account/subdomain",
+			body:   `{"subdomain": "acme-corp"}`,
+		},
+		{
+			name:   "delete subdomain",
+			call:   func(c *Client) error { return c.DeleteSubdomain(ctx) },
+			method: http.MethodDelete,
+			path:   "/v1/account/subdomain",
+		},
+		{
+			name: "usage forecast",
+			call: func(c *Client) error {
+				f, err := c.UsageForecast(ctx, 30)
+				if err != nil {
+					return err
+				}
+				return expect(f.RemainingDays, 12)
+			},
+			response: `{"averageUsage": 1.5, "usagePercentage": 40, "remainingDays": 12}`,
+			method:   http.MethodGet,
+			path:     "/v1/account/usageForecast",
+			query:    "numberOfDays=30",
+		},
+		{
+			name: "usage report with defaults",
+			call: func(c *Client) error {
+				id, err := c.StartUsageReport(ctx, UsageReportRequest{StartDate: time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC)})
+				if err != nil {
+					return err
+				}
+				return expect(id, "j1")
+			},
+			response: `{"jobId": "j1"}`,
+			method:   http.MethodPost,
+			path:     "/v1/account/usage/report",
+			body:     `{"groupBy": "day", "reportType": "standard", "includeDeploymentCharge": false, "startDate": "2026-01-01"}`,
+		},
+	})
+}
+
+func TestExportUsageCSV(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/account/usage/report", `{"jobId": "j1"}`)
+	api.handle("GET /v1/account/usage/report/j1/status", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"status": "Success", "reportDownloadURL": "`+api.srv.URL+`/api/reports/j1.csv"}`)
+	})
+	api.handle("GET /reports/j1.csv", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "date,credits\n2026-01-01,1.5\n")
+	})
+	var buf bytes.Buffer
+	req := UsageReportRequest{GroupBy: UsageGroupByMonth, ReportType: UsageReportDetailed, IncludeDeploymentCharge: true}
+	if err := api.client().ExportUsageCSV(context.Background(), req, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "date,credits\n2026-01-01,1.5\n" {
+		t.Errorf("ExportUsageCSV wrote %q", buf.String())
+	}
+	checkPaths(t, api.received(),
+		"POST /v1/account/usage/report",
+		"GET /v1/account/usage/report/j1/status",
+		"GET /v1/account/usage/report/j1/status",
+		"GET /reports/j1.csv")
+	checkRequest(t, api.received()[0], http.MethodPost, "/v1/account/usage/report",
+		`{"groupBy": "month", "reportType": "detailed", "includeDeploymentCharge": true}`)
+}
+
+func TestWaitUsageReportFailed(t *testing.T) {
+	api := newTestAPI(t)
+	serveJob(api, "/v1/account/usage/report/j1/status", `{"status": "Failed", "statusMessage": "no usage"}`)
+	_, err := api.client().WaitUsageReport(context.Background(), "j1")
+	if !errors.Is(err, ErrUsageReportFailed) {
+		t.Errorf("WaitUsageReport = %v, want ErrUsageReportFailed", err)
+	}
+}
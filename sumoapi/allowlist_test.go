@@ -0,0 +1,86 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestAllowlist(t *testing.T) {
+	ctx := context.Background()
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				entries, err := c.ListAllowlistEntries(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(string(entries[0].Extra["createdBy"]), `"u1"`)
+			},
+			response: `{"data": [{"cidr": "10.0.0.0/8", "description": "office", "createdBy": "u1"}]}`,
+			method:   http.MethodGet,
+			path:     "/v1/serviceAllowlist/addresses",
+		},
+		{
+			name: "add",
+			call: func(c *Client) error {
+				_, err := c.AddAllowlistEntries(ctx, AllowlistEntry{CIDR: "10.0.0.0/8", Description: "office"}, AllowlistEntry{CIDR: "192.0.2.1"})
+				return err
+			},
+			method: http.MethodPost,
+			path:   "/v1/serviceAllowlist/addresses/add",
+			body:   `{"data": [{"cidr": "10.0.0.0/8", "description": "office"}, {"cidr": "192.0.2.1"}]}`,
+		},
+		{
+			name:   "remove",
+			call:   func(c *Client) error { return c.RemoveAllowlistEntries(ctx, AllowlistEntry{CIDR: "192.0.2.1"}) },
+			method: http.MethodPost,
+			path:   "/v1/serviceAllowlist/addresses/remove",
+			body:   `{"data": [{"cidr": "192.0.2.1"}]}`,
+		},
+		{
+			name: "status",
+			call: func(c *Client) error {
+				status, err := c.AllowlistStatus(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(*status, AllowlistStatus{LoginEnabled: true})
+			},
+			response: `{"loginEnabled": true, "contentEnabled": false}`,
+			method:   http.MethodGet,
+			path:     "/v1/serviceAllowlist/status",
+		},
+		{
+			name:   "enable",
+			call:   func(c *Client) error { return c.EnableAllowlist(ctx, AllowlistBoth) },
+			method: http.MethodPost,
+			path:   "/v1/serviceAllowlist/enable",
+			query:  "allowlistType=Both",
+		},
+		{
+			name:   "disable",
+			call:   func(c *Client) error { return c.DisableAllowlist(ctx, AllowlistLogin) },
+			method: http.MethodPost,
+			path:   "/v1/serviceAllowlist/disable",
+			query:  "allowlistType=Login",
+		},
+	})
+}
+
+func TestAllowlistValidation(t *testing.T) {
+	api := newTestAPI(t)
+	c := api.client()
+	for _, cidr := range []string{"10.0.0.0/33", "office", ""} {
+		if _, err := c.AddAllowlistEntries(context.Background(), AllowlistEntry{CIDR: cidr}); err == nil {
+			t.Errorf("AddAllowlistEntries(%q) succeeded", cidr)
+		}
+	}
+	if err := c.EnableAllowlist(context.Background(), "Everything"); err == nil {
+		t.Error("EnableAllowlist with an invalid type succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
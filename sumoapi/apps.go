@@ -0,0 +1,151 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrAppJobFailed is returned, wrapped with the reason, when an app install,
+// upgrade or uninstall fails.
+var ErrAppJobFailed = errors.New("app job failed")
+
+// App is an app from the app catalog.
+type App struct {
+	Definition AppDefinition `json:"appDefinition"`
+	Manifest   AppManifest   `json:"appManifest"`
+
+	// Extra holds the properties of the app that have no field, which are
+	// kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the app, keeping the properties that have no field
+// in Extra.
+func (a *App) UnmarshalJSON(b []byte) error {
+	type plain App
+	return unmarshalExtra(b, (*plain)(a), &a.Extra)
+}
+
+// MarshalJSON encodes the app along with the properties in Extra.
+func (a App) MarshalJSON() ([]byte, error) {
+	type plain App
+	return marshalExtra(plain(a), a.Extra)
+}
+
+// AppDefinition identifies an app and its version.
+type AppDefinition struct {
+	// UUID identifies the app when installing it.
+	UUID       string `json:"uuid"`
+	Name       string `json:"name"`
+	AppVersion string `json:"appVersion"`
+	ContentID  string `json:"contentId,omitempty"`
+	Preview    bool   `json:"preview,omitempty"`
+}
+
+// AppManifest describes an app.
+type AppManifest struct {
+	Family            string   `json:"family,omitempty"`
+	Description       string   `json:"description"`
+	Categories        []string `json:"categories,omitempty"`
+	HoverText         string   `json:"hoverText,omitempty"`
+	IconURL           string   `json:"iconURL,omitempty"`
+	ScreenshotURLs    []string `json:"screenshotURLs,omitempty"`
+	HelpURL           string   `json:"helpURL,omitempty"`
+	Installable       bool     `json:"installable"`
+	ShowOnMarketplace bool     `json:"showOnMarketplace"`
+	Author            string   `json:"author,omitempty"`
+}
+
+// AppInstallRequest configures the installation or upgrade of an app.
+type AppInstallRequest struct {
+	// Parameters are the values of the parameters of the app, such as its
+	// data source.
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// ListApps returns the apps in the app catalog.
+func (c *Client) ListApps(ctx context.Context) ([]App, error) {
+	var resp struct {
+		Apps []App `json:"apps"`
+	}
+	if err := c.get(ctx, "/v2/apps", nil, &resp); err != nil {
+		return nil, fmt.Errorf("error listing apps: %w", err)
+	}
+	return resp.Apps, nil
+}
+
+// GetApp returns the app with the UUID.
+func (c *Client) GetApp(ctx context.Context, uuid string) (*App, error) {
+	var app App
+	if err := c.get(ctx, pathf("/v2/apps/%s", uuid), nil, &app); err != nil {
+		return nil, fmt.Errorf("error getting app: %w", err)
+	}
+	return &app, nil
+}
+
+// InstallApp installs the app with the UUID and returns the ID of the job
+// doing so. Use WaitAppJob to wait for it to finish.
+func (c *Client) InstallApp(ctx context.Context, uuid string, req AppInstallRequest) (string, error) {
+	id, err := c.startAppJob(ctx, uuid, "install", req)
+	if err != nil {
+		return "", fmt.Errorf("error installing app: %w", err)
+	}
+	return id, nil
+}
+
+// UpgradeApp upgrades the installed app with the UUID to its latest version
+// and returns the ID of the job doing so. Use WaitAppJob to wait for it to
+// finish.
+func (c *Client) UpgradeApp(ctx context.Context, uuid string, req AppInstallRequest) (string, error) {
+	id, err := c.startAppJob(ctx, uuid, "upgrade", req)
+	if err != nil {
+		return "", fmt.Errorf("error upgrading app: %w", err)
+	}
+	return id, nil
+}
+
+// UninstallApp uninstalls the app with the UUID and returns the ID of the
+// job doing so. Use WaitAppJob to wait for it to finish.
+func (c *Client) UninstallApp(ctx context.Context, uuid string) (string, error) {
+	id, err := c.startAppJob(ctx, uuid, "uninstall", nil)
+	if err != nil {
+		return "", fmt.Errorf("error uninstalling app: %w", err)
+	}
+	return id, nil
+}
+
+// AppJobStatus returns the progress of an install, upgrade or uninstall
+// job. The action is "install", "upgrade" or "uninstall" and must match the
+// call that started the job.
+func (c *Client) AppJobStatus(ctx context.Context, action, jobID string) (*ContentJobStatus, error) {
+	var status ContentJobStatus
+	if err := c.get(ctx, pathf("/v2/apps/%s/%s/status", action, jobID), nil, &status); err != nil {
+		return nil, fmt.Errorf("error getting app job status: %w", err)
+	}
+	return &status, nil
+}
+
+// WaitAppJob polls the status of an install, upgrade or uninstall job until
+// it has finished. The action is "install", "upgrade" or "uninstall" and
+// must match the call that started the job. If the job failed the error
+// wraps ErrAppJobFailed.
+func (c *Client) WaitAppJob(ctx context.Context, action, jobID string) error {
+	if err := c.waitJob(ctx, ErrAppJobFailed, pathf("/v2/apps/%s/%s/status", action, jobID)); err != nil {
+		return fmt.Errorf("error waiting for app %s: %w", action, err)
+	}
+	return nil
+}
+
+// startAppJob starts an install, upgrade or uninstall job and returns its
+// ID.
+func (c *Client) startAppJob(ctx context.Context, uuid, action string, body any) (string, error) {
+	var job struct {
+		JobID string `json:"jobId"`
+	}
+	if err := c.post(ctx, pathf("/v2/apps/%s/%s", uuid, action), body, &job); err != nil {
+		return "", err
+	}
+	return job.JobID, nil
+}
@@ -0,0 +1,110 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestApps(t *testing.T) {
+	ctx := context.Background()
+	app := `{"appDefinition": {"uuid": "a1", "name": "Nginx", "appVersion": "1.2"}, "appManifest": {"description": "Nginx logs", "installable": true, "showOnMarketplace": true}, "appType": "Logs"}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				apps, err := c.ListApps(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(apps[0].Definition.UUID, "a1")
+			},
+			response: `{"apps": [` + app + `]}`,
+			method:   http.MethodGet,
+			path:     "/v2/apps",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				a, err := c.GetApp(ctx, "a1")
+				if err != nil {
+					return err
+				}
+				if err := expect(a.Manifest.Description, "Nginx logs"); err != nil {
+					return err
+				}
+				return expect(string(a.Extra["appType"]), `"Logs"`)
+			},
+			response: app,
+			method:   http.MethodGet,
+			path:     "/v2/apps/a1",
+		},
+		{
+			name: "install",
+			call: func(c *Client) error {
+				id, err := c.InstallApp(ctx, "a1", AppInstallRequest{Parameters: map[string]string{"source": "_sourceCategory=nginx"}})
+				if err != nil {
+					return err
+				}
+				return expect(id, "j1")
+			},
+			response: `{"jobId": "j1"}`,
+			method:   http.MethodPost,
+			path:     "/v2/apps/a1/install",
+			body:     `{"parameters": {"source": "_sourceCategory=nginx"}}`,
+		},
+		{
+			name: "upgrade",
+			call: func(c *Client) error {
+				_, err := c.UpgradeApp(ctx, "a1", AppInstallRequest{})
+				return err
+			},
+			response: `{"jobId": "j1"}`,
+			method:   http.MethodPost,
+			path:     "/v2/apps/a1/upgrade",
+			body:     `{}`,
+		},
+		{
+			name: "uninstall",
+			call: func(c *Client) error {
+				_, err := c.UninstallApp(ctx, "a1")
+				return err
+			},
+			response: `{"jobId": "j1"}`,
+			method:   http.MethodPost,
+			path:     "/v2/apps/a1/uninstall",
+		},
+		{
+			name: "status",
+			call: func(c *Client) error {
+				s, err := c.AppJobStatus(ctx, "install", "j1")
+				if err != nil {
+					return err
+				}
+				return expect(s.Status, ContentJobSuccess)
+			},
+			response: `{"status": "Success"}`,
+			method:   http.MethodGet,
+			path:     "/v2/apps/install/j1/status",
+		},
+	})
+}
+
+func TestWaitAppJob(t *testing.T) {
+	api := newTestAPI(t)
+	serveJob(api, "/v2/apps/install/j1/status", `{"status": "Success"}`)
+	serveJob(api, "/v2/apps/upgrade/j2/status", `{"status": "Failed", "error": {"code": "app:invalid", "message": "missing parameter"}}`)
+	c := api.client()
+
+	if err := c.WaitAppJob(context.Background(), "install", "j1"); err != nil {
+		t.Fatal(err)
+	}
+	checkPaths(t, api.received(), "GET /v2/apps/install/j1/status", "GET /v2/apps/install/j1/status")
+
+	err := c.WaitAppJob(context.Background(), "upgrade", "j2")
+	if !errors.Is(err, ErrAppJobFailed) || !strings.HasSuffix(err.Error(), "missing parameter") {
+		t.Errorf("WaitAppJob of a failed job = %v, want ErrAppJobFailed with the reason", err)
+	}
+}
@@ -0,0 +1,151 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Statuses of an archive ingestion job.
+const (
+	ArchiveJobPending   = "Pending"
+	ArchiveJobScanning  = "Scanning"
+	ArchiveJobIngesting = "Ingesting"
+	ArchiveJobFailed    = "Failed"
+	ArchiveJobSucceeded = "Succeeded"
+)
+
+// ArchiveJob is a job ingesting the logs an AWS S3 archive source archived
+// over a time range, so they can be searched again.
+type ArchiveJob struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	// StartTime and EndTime are the time range of the logs to ingest.
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	// Status is one of the ArchiveJob constants.
+	Status               string    `json:"status,omitempty"`
+	TotalObjectsScanned  int64     `json:"totalObjectsScanned,omitempty"`
+	TotalObjectsIngested int64     `json:"totalObjectsIngested,omitempty"`
+	TotalBytesIngested   int64     `json:"totalBytesIngested,omitempty"`
+	CreatedAt            time.Time `json:"createdAt"`
+	CreatedBy            string    `json:"createdBy,omitempty"`
+
+	// Extra holds the properties of the archive job that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the archive job, keeping the properties that have no
+// field in Extra.
+func (j *ArchiveJob) UnmarshalJSON(b []byte) error {
+	type plain ArchiveJob
+	return unmarshalExtra(b, (*plain)(j), &j.Extra)
+}
+
+// MarshalJSON encodes the archive job along with the properties in Extra.
+func (j ArchiveJob) MarshalJSON() ([]byte, error) {
+	type plain ArchiveJob
+	return marshalExtra(plain(j), j.Extra)
+}
+
+// Done reports whether the job has finished, successfully or not.
+func (j ArchiveJob) Done() bool {
+	return j.Status == ArchiveJobSucceeded || j.Status == ArchiveJobFailed
+}
+
+// ArchiveJobCount is the number of archive ingestion jobs of a source.
+type ArchiveJobCount struct {
+	SourceID string `json:"sourceId"`
+	Count    int    `json:"count"`
+}
+
+// ListArchiveJobs returns the archive ingestion jobs of the AWS S3 archive
+// source, following pagination.
+func (c *Client) ListArchiveJobs(ctx context.Context, sourceID int64) ([]ArchiveJob, error) {
+	return Collect(c.IterateArchiveJobs(ctx, sourceID))
+}
+
+// IterateArchiveJobs returns an Iterator over the archive jobs returned by
+// ListArchiveJobs, requesting each page as it is read.
+func (c *Client) IterateArchiveJobs(ctx context.Context, sourceID int64) *Iterator[ArchiveJob] {
+	return iterateTokens[ArchiveJob](ctx, c, "listing archive jobs", pathf("/v1/archive/%s/jobs", sourceID), nil)
+}
+
+// ListArchiveJobCounts returns the number of archive ingestion jobs of every
+// source that has any.
+func (c *Client) ListArchiveJobCounts(ctx context.Context) ([]ArchiveJobCount, error) {
+	var resp struct {
+		Data []ArchiveJobCount `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/archive/jobs/count", nil, &resp); err != nil {
+		return nil, fmt.Errorf("error listing archive job counts: %w", err)
+	}
+	return resp.Data, nil
+}
+
+// CreateArchiveJob starts a job ingesting the logs the AWS S3 archive source
+// archived between start and end, and returns it. Use WaitArchiveJob to wait
+// for it to finish.
+func (c *Client) CreateArchiveJob(ctx context.Context, sourceID int64, name string, start, end time.Time) (*ArchiveJob, error) {
+	if name == "" {
+		return nil, fmt.Errorf("error creating archive job: name is required")
+	}
+	if !start.Before(end) {
+		return nil, fmt.Errorf("error creating archive job: start must be before end")
+	}
+	body := struct {
+		Name      string    `json:"name"`
+		StartTime time.Time `json:"startTime"`
+		EndTime   time.Time `json:"endTime"`
+	}{name, start.UTC(), end.UTC()}
+	var job ArchiveJob
+	if err := c.post(ctx, pathf("/v1/archive/%s/jobs", sourceID), body, &job); err != nil {
+		return nil, fmt.Errorf("error creating archive job: %w", err)
+	}
+	return &job, nil
+}
+
+// GetArchiveJob returns the archive ingestion job of the source with the ID.
+// The API has no endpoint for a single job, so it is looked up in the jobs of
+// the source. An error matching ErrNotFound is returned if there is none.
+func (c *Client) GetArchiveJob(ctx context.Context, sourceID int64, id string) (*ArchiveJob, error) {
+	jobs, err := c.ListArchiveJobs(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		if job.ID == id {
+			return &job, nil
+		}
+	}
+	return nil, fmt.Errorf("error getting archive job: %q: %w", id, ErrNotFound)
+}
+
+// WaitArchiveJob polls the archive ingestion job until it has finished and
+// returns it. A failed job is returned without an error, so check its
+// Status.
+func (c *Client) WaitArchiveJob(ctx context.Context, sourceID int64, id string) (*ArchiveJob, error) {
+	for {
+		job, err := c.GetArchiveJob(ctx, sourceID, id)
+		if err != nil {
+			return nil, fmt.Errorf("error waiting for archive job: %w", err)
+		}
+		if job.Done() {
+			return job, nil
+		}
+		if err := c.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// DeleteArchiveJob deletes the archive ingestion job of the source with the
+// ID. Logs that were already ingested are kept.
+func (c *Client) DeleteArchiveJob(ctx context.Context, sourceID int64, id string) error {
+	if err := c.delete(ctx, pathf("/v1/archive/%s/jobs/%s", sourceID, id)); err != nil {
+		return fmt.Errorf("error deleting archive job: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestArchiveJobs(t *testing.T) {
+	ctx := context.Background()
+	job := `{"id": "j1", "name": "replay", "startTime": "2026-01-01T00:00:00Z", "endTime": "2026-01-02T00:00:00Z", "status": "Succeeded", "totalBytesIngested": 2048, "createdAt": "2026-01-03T00:00:00Z", "totalObjectsFailed": 0}`
+	start := time.Date(2026, 1, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600))
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				jobs, err := c.ListArchiveJobs(ctx, 42)
+				if err != nil {
+					return err
+				}
+				if err := expect(jobs[0].TotalBytesIngested, int64(2048)); err != nil {
+					return err
+				}
+				return expect(string(jobs[0].Extra["totalObjectsFailed"]), "0")
+			},
+			response: `{"data": [` + job + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/archive/42/jobs",
+			query:    "limit=100",
+		},
+		{
+			name: "counts",
+			call: func(c *Client) error {
+				counts, err := c.ListArchiveJobCounts(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(counts, []ArchiveJobCount{{SourceID: "42", Count: 3}})
+			},
+			response: `{"data": [{"sourceId": "42", "count": 3}]}`,
+			method:   http.MethodGet,
+			path:     "/v1/archive/jobs/count",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateArchiveJob(ctx, 42, "replay", start, start.Add(24*time.Hour))
+				return err
+			},
+			response: job,
+			method:   http.MethodPost,
+			path:     "/v1/archive/42/jobs",
+			// The times are sent in UTC.
+			body: `{"name": "replay", "startTime": "2026-01-01T00:00:00Z", "endTime": "2026-01-02T00:00:00Z"}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteArchiveJob(ctx, 42, "j1") },
+			method: http.MethodDelete,
+			path:   "/v1/archive/42/jobs/j1",
+		},
+	})
+}
+
+func TestCreateArchiveJobValidation(t *testing.T) {
+	api := newTestAPI(t)
+	c := api.client()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := c.CreateArchiveJob(context.Background(), 42, "", start, start.Add(time.Hour)); err == nil {
+		t.Error("CreateArchiveJob without a name succeeded")
+	}
+	if _, err := c.CreateArchiveJob(context.Background(), 42, "replay", start, start); err == nil {
+		t.Error("CreateArchiveJob with an empty time range succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
+
+func TestWaitArchiveJob(t *testing.T) {
+	api := newTestAPI(t)
+	var n atomic.Int32
+	api.handle("GET /v1/archive/42/jobs", func(w http.ResponseWriter, r *http.Request) {
+		status := "Ingesting"
+		if n.Add(1) > 1 {
+			status = "Failed"
+		}
+		io.WriteString(w, `{"data": [{"id": "j0", "status": "Succeeded"}, {"id": "j1", "status": "`+status+`"}]}`)
+	})
+	c := api.client()
+
+	// A failed job is returned without an error.
+	job, err := c.WaitArchiveJob(context.Background(), 42, "j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "j1" || job.Status != ArchiveJobFailed || n.Load() != 2 {
+		t.Errorf("WaitArchiveJob = %+v after %d polls", job, n.Load())
+	}
+
+	if _, err := c.GetArchiveJob(context.Background(), 42, "j2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetArchiveJob of a missing job = %v, want ErrNotFound", err)
+	}
+}
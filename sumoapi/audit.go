@@ -0,0 +1,200 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditEventIndex is the partition holding the audit events of the account.
+const AuditEventIndex = "sumologic_audit_events"
+
+// Subsystems of audit events.
+const (
+	AuditSubsystemUsers      = "users"
+	AuditSubsystemRoles      = "roles"
+	AuditSubsystemAccessKeys = "accessKeys"
+	AuditSubsystemCollection = "collection"
+	AuditSubsystemContent    = "content"
+	AuditSubsystemSAML       = "saml"
+)
+
+// AuditEventQuery selects audit events from the audit event index.
+type AuditEventQuery struct {
+	// From and To are the time range to search.
+	From time.Time
+	To   time.Time
+	// Subsystems and EventNames, such as "UserCreated", restrict the events
+	// to those matching any of them, when not empty.
+	Subsystems []string
+	EventNames []string
+	// OperatorEmail restricts the events to those made by the user with the
+	// email.
+	OperatorEmail string
+	// Filter is appended to the query, such as | where
+	// resourceIdentity.name = "prod".
+	Filter string
+}
+
+// String returns the search query of the audit events.
+func (q AuditEventQuery) String() string {
+	var sb strings.Builder
+	sb.WriteString("_index=" + AuditEventIndex)
+	sb.WriteString(` | json field=_raw "eventName", "subsystem", "operator.email" as eventName, subsystem, operatorEmail nodrop`)
+	if w := anyOf("subsystem", q.Subsystems); w != "" {
+		sb.WriteString(" | where " + w)
+	}
+	if w := anyOf("eventName", q.EventNames); w != "" {
+		sb.WriteString(" | where " + w)
+	}
+	if q.OperatorEmail != "" {
+		sb.WriteString(" | where operatorEmail = " + strconv.Quote(q.OperatorEmail))
+	}
+	if q.Filter != "" {
+		sb.WriteString(" " + q.Filter)
+	}
+	return sb.String()
+}
+
+// anyOf returns a condition matching the field against any of the values.
+func anyOf(field string, values []string) string {
+	conds := make([]string, len(values))
+	for i, v := range values {
+		conds[i] = field + " = " + strconv.Quote(v)
+	}
+	return strings.Join(conds, " or ")
+}
+
+// AuditEvent holds the fields common to every audit event.
+type AuditEvent struct {
+	EventID            string    `json:"eventId"`
+	EventName          string    `json:"eventName"`
+	EventTime          time.Time `json:"eventTime"`
+	EventFormatVersion string    `json:"eventFormatVersion,omitempty"`
+	// Subsystem is one of the AuditSubsystem constants.
+	Subsystem        string           `json:"subsystem"`
+	Operator         AuditOperator    `json:"operator"`
+	ResourceIdentity ResourceIdentity `json:"resourceIdentity"`
+}
+
+// AuditOperator is the user or access key that made the change of an audit
+// event.
+type AuditOperator struct {
+	Email       string `json:"email,omitempty"`
+	AccessKeyID string `json:"accessKeyId,omitempty"`
+	// Interface is how the change was made, such as "UI" or "API".
+	Interface string `json:"interface,omitempty"`
+	SourceIP  string `json:"sourceIp,omitempty"`
+	SessionID string `json:"sessionId,omitempty"`
+}
+
+// UserAuditEvent is an audit event of the users subsystem, such as
+// UserCreated or UserUpdated.
+type UserAuditEvent struct {
+	AuditEvent
+	User struct {
+		ID        string   `json:"id"`
+		Email     string   `json:"email"`
+		FirstName string   `json:"firstName,omitempty"`
+		LastName  string   `json:"lastName,omitempty"`
+		RoleIDs   []string `json:"roleIds,omitempty"`
+	} `json:"user"`
+}
+
+// RoleAuditEvent is an audit event of the roles subsystem, such as
+// RoleCreated.
+type RoleAuditEvent struct {
+	AuditEvent
+	Role struct {
+		ID              string   `json:"id"`
+		Name            string   `json:"name"`
+		Capabilities    []string `json:"capabilities,omitempty"`
+		FilterPredicate string   `json:"filterPredicate,omitempty"`
+	} `json:"role"`
+}
+
+// AccessKeyAuditEvent is an audit event of the accessKeys subsystem, such as
+// AccessKeyCreated.
+type AccessKeyAuditEvent struct {
+	AuditEvent
+	AccessKey struct {
+		ID    string `json:"id"`
+		Label string `json:"label,omitempty"`
+	} `json:"accessKey"`
+}
+
+// CollectionAuditEvent is an audit event of the collection subsystem, such
+// as CollectorCreated or SourceUpdated.
+type CollectionAuditEvent struct {
+	AuditEvent
+	Collector struct {
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		CollectorType string `json:"collectorType,omitempty"`
+	} `json:"collector"`
+	Source struct {
+		ID         string `json:"id,omitempty"`
+		Name       string `json:"name,omitempty"`
+		SourceType string `json:"sourceType,omitempty"`
+	} `json:"source"`
+}
+
+// AuditEvents returns the audit events matching the query.
+func (c *Client) AuditEvents(ctx context.Context, q AuditEventQuery) ([]AuditEvent, error) {
+	return AuditEventsInto[AuditEvent](ctx, c, q)
+}
+
+// UserAuditEvents returns the audit events of the users subsystem matching
+// the query.
+func (c *Client) UserAuditEvents(ctx context.Context, q AuditEventQuery) ([]UserAuditEvent, error) {
+	q.Subsystems = []string{AuditSubsystemUsers}
+	return AuditEventsInto[UserAuditEvent](ctx, c, q)
+}
+
+// RoleAuditEvents returns the audit events of the roles subsystem matching
+// the query.
+func (c *Client) RoleAuditEvents(ctx context.Context, q AuditEventQuery) ([]RoleAuditEvent, error) {
+	q.Subsystems = []string{AuditSubsystemRoles}
+	return AuditEventsInto[RoleAuditEvent](ctx, c, q)
+}
+
+// AccessKeyAuditEvents returns the audit events of the accessKeys subsystem
+// matching the query.
+func (c *Client) AccessKeyAuditEvents(ctx context.Context, q AuditEventQuery) ([]AccessKeyAuditEvent, error) {
+	q.Subsystems = []string{AuditSubsystemAccessKeys}
+	return AuditEventsInto[AccessKeyAuditEvent](ctx, c, q)
+}
+
+// CollectionAuditEvents returns the audit events of the collection
+// subsystem, for collectors and sources, matching the query.
+func (c *Client) CollectionAuditEvents(ctx context.Context, q AuditEventQuery) ([]CollectionAuditEvent, error) {
+	q.Subsystems = []string{AuditSubsystemCollection}
+	return AuditEventsInto[CollectionAuditEvent](ctx, c, q)
+}
+
+// AuditEventsInto runs a search for the audit events matching the query and
+// decodes the JSON of every event into a T, which usually embeds AuditEvent
+// along with the fields of the event type. Use json.RawMessage as T to get
+// the events as they are.
+func AuditEventsInto[T any](ctx context.Context, c *Client, q AuditEventQuery) ([]T, error) {
+	if q.From.IsZero() || q.To.IsZero() {
+		return nil, fmt.Errorf("error searching audit events: from and to are required")
+	}
+	it := c.SearchMessages(ctx, SearchJobRequest{Query: q.String(), From: q.From, To: q.To})
+	defer it.Close()
+	var events []T
+	for it.Next() {
+		var e T
+		if err := json.Unmarshal([]byte(it.Message().Raw()), &e); err != nil {
+			return events, fmt.Errorf("error decoding audit event %d: %w", len(events), err)
+		}
+		events = append(events, e)
+	}
+	if err := it.Err(); err != nil {
+		return events, fmt.Errorf("error searching audit events: %w", err)
+	}
+	return events, nil
+}
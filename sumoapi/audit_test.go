@@ -0,0 +1,91 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditEventQueryString(t *testing.T) {
+	const base = `_index=sumologic_audit_events | json field=_raw "eventName", "subsystem", "operator.email" as eventName, subsystem, operatorEmail nodrop`
+	tests := []struct {
+		name string
+		q    AuditEventQuery
+		want string
+	}{
+		{"all", AuditEventQuery{}, base},
+		{
+			"subsystems and events",
+			AuditEventQuery{Subsystems: []string{"users", "roles"}, EventNames: []string{"UserCreated"}},
+			base + ` | where subsystem = "users" or subsystem = "roles" | where eventName = "UserCreated"`,
+		},
+		{
+			"operator and filter",
+			AuditEventQuery{OperatorEmail: `ada"@example.com`, Filter: "| count by eventName"},
+			base + ` | where operatorEmail = "ada\"@example.com" | count by eventName`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.String(); got != tt.want {
+				t.Errorf("String() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserAuditEvents(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/search/jobs", `{"id": "j1"}`)
+	serveStates(api, 1, 0, SearchDone)
+	event := `{"eventId": "e1", "eventName": "UserCreated", "eventTime": "2026-01-01T00:00:00Z", "subsystem": "users", "operator": {"email": "admin@example.com", "interface": "API"}, "user": {"id": "u1", "email": "ada@example.com", "roleIds": ["r1"]}}`
+	raw, _ := json.Marshal(event)
+	api.respond("GET /v1/search/jobs/j1/messages", `{"messages": [{"map": {"_raw": `+string(raw)+`}}]}`)
+	api.respond("DELETE /v1/search/jobs/j1", ``)
+	from := time.UnixMilli(1700000000000)
+
+	events, err := api.client().UserAuditEvents(context.Background(), AuditEventQuery{From: from, To: from.Add(time.Hour), Subsystems: []string{"roles"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].EventName != "UserCreated" || events[0].Operator.Interface != "API" || events[0].User.RoleIDs[0] != "r1" {
+		t.Errorf("UserAuditEvents = %+v", events)
+	}
+	// The subsystem of the query is replaced with users.
+	var job struct {
+		Query string `json:"query"`
+		From  int64  `json:"from"`
+	}
+	if err := json.Unmarshal([]byte(api.received()[0].body), &job); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(job.Query, `| where subsystem = "users"`) || job.From != 1700000000000 {
+		t.Errorf("search job = %+v", job)
+	}
+}
+
+func TestAuditEventsErrors(t *testing.T) {
+	api := newTestAPI(t)
+	if _, err := api.client().AuditEvents(context.Background(), AuditEventQuery{From: time.Now()}); err == nil {
+		t.Error("AuditEvents without a time range succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+
+	api.respond("POST /v1/search/jobs", `{"id": "j1"}`)
+	serveStates(api, 2, 0, SearchDone)
+	api.respond("GET /v1/search/jobs/j1/messages", `{"messages": [{"map": {"_raw": "{\"eventId\": \"e1\"}"}}, {"map": {"_raw": "not json"}}]}`)
+	api.respond("DELETE /v1/search/jobs/j1", ``)
+	from := time.UnixMilli(1700000000000)
+	events, err := api.client().AuditEvents(context.Background(), AuditEventQuery{From: from, To: from.Add(time.Hour)})
+	if err == nil || !strings.Contains(err.Error(), "audit event 1") {
+		t.Errorf("AuditEvents with a message that is not JSON = %v", err)
+	}
+	// The events decoded before the error are returned.
+	if len(events) != 1 || events[0].EventID != "e1" {
+		t.Errorf("events = %+v", events)
+	}
+}
@@ -0,0 +1,169 @@
+package sumoapi
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/byitkc/gosumo"
+)
+
+// Deployment is a Sumo Logic deployment, the region an account is hosted in.
+// Each deployment has its own management API base URL.
+type Deployment string
+
+// The Sumo Logic deployments.
+const (
+	DeploymentAU  Deployment = "au"
+	DeploymentCA  Deployment = "ca"
+	DeploymentCH  Deployment = "ch"
+	DeploymentDE  Deployment = "de"
+	DeploymentEU  Deployment = "eu"
+	DeploymentFED Deployment = "fed"
+	DeploymentIN  Deployment = "in"
+	DeploymentJP  Deployment = "jp"
+	DeploymentKR  Deployment = "kr"
+	DeploymentUS1 Deployment = "us1"
+	DeploymentUS2 Deployment = "us2"
+)
+
+// Deployments lists every known Deployment.
+var Deployments = []Deployment{
+	DeploymentAU, DeploymentCA, DeploymentCH, DeploymentDE, DeploymentEU, DeploymentFED,
+	DeploymentIN, DeploymentJP, DeploymentKR, DeploymentUS1, DeploymentUS2,
+}
+
+// BaseURL returns the management API base URL of the deployment.
+func (d Deployment) BaseURL() string {
+	if d == DeploymentUS1 || d == "" {
+		return DefaultBaseURL
+	}
+	return fmt.Sprintf("https://api.%s.sumologic.com/api", strings.ToLower(string(d)))
+}
+
+// WithDeployment sets the base URL to the one of the deployment the account
+// is hosted in. Requests sent to the wrong deployment are redirected by Sumo
+// Logic, which the Client follows, but setting the deployment avoids the
+// extra round trip. NewDiscoveredClient finds the deployment instead.
+func WithDeployment(d Deployment) Option {
+	return func(c *Client) error {
+		for _, known := range Deployments {
+			if strings.EqualFold(string(d), string(known)) {
+				return WithBaseURL(known.BaseURL())(c)
+			}
+		}
+		return fmt.Errorf("unknown deployment: %q", d)
+	}
+}
+
+// WithRetry sets the policy used to retry requests that are rate limited or
+// fail with a server error. Rate limited requests are retried for every
+// method, waiting as long as any Retry-After header asks for up to
+// MaxBackoff, while server errors are only retried for requests that are
+// safe to repeat. When a response reports that no requests remain in the
+// rate limit window, the next request waits for the window to reset, also
+// for up to MaxBackoff. The default is gosumo.DefaultRetryPolicy, and a
+// policy with MaxAttempts of 1 disables retries and waiting.
+func WithRetry(p gosumo.RetryPolicy) Option {
+	return func(c *Client) error {
+		if p.MaxAttempts < 1 {
+			return fmt.Errorf("max attempts must be at least 1, got: %d", p.MaxAttempts)
+		}
+		if p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+			return fmt.Errorf("backoff cannot be negative")
+		}
+		if p.Multiplier < 1 {
+			p.Multiplier = 1
+		}
+		c.retry = p
+		return nil
+	}
+}
+
+// maxRedirects is the number of deployment redirects followed for a single
+// request.
+const maxRedirects = 3
+
+// isRedirect reports whether the status code redirects to another
+// deployment.
+func isRedirect(statusCode int) bool {
+	switch statusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// redirectBaseURL returns the base URL of the deployment a request for path
+// was redirected to. The Location header holds the full URL of the request
+// on the right deployment, so the base URL is what precedes the path. Since
+// the request is sent again with the credentials, only redirects to a Sumo
+// Logic host, or to the host the request was sent to, are followed.
+func redirectBaseURL(resp *http.Response, path string) (*url.URL, error) {
+	loc, err := resp.Location()
+	if err != nil {
+		return nil, fmt.Errorf("redirect without a location: %w", err)
+	}
+	base := *loc
+	base.RawQuery = ""
+	base.Fragment = ""
+	p := strings.TrimSuffix(base.Path, "/")
+	if i := strings.LastIndex(p, strings.TrimSuffix(path, "/")); i >= 0 {
+		p = p[:i]
+	} else if i := strings.Index(p, "/api"); i >= 0 {
+		p = p[:i+len("/api")]
+	}
+	base.Path = p
+	base.RawPath = ""
+	if base.Scheme != "https" {
+		return nil, fmt.Errorf("refusing to follow redirect to %s", base.Redacted())
+	}
+	sameHost := resp.Request != nil && strings.EqualFold(base.Host, resp.Request.URL.Host)
+	if !sameHost && !isSumoLogicHost(base.Host) {
+		return nil, fmt.Errorf("refusing to follow redirect to %s: not a Sumo Logic host", base.Redacted())
+	}
+	return &base, nil
+}
+
+// isSumoLogicHost reports whether the host, which may include a port, is the
+// one of a deployment or another host under sumologic.com on the default
+// port.
+func isSumoLogicHost(host string) bool {
+	name, port, err := net.SplitHostPort(host)
+	if err != nil {
+		name, port = host, ""
+	}
+	if port != "" && port != "443" {
+		return false
+	}
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for _, d := range Deployments {
+		if u, err := url.Parse(d.BaseURL()); err == nil && name == u.Hostname() {
+			return true
+		}
+	}
+	return strings.HasSuffix(name, ".sumologic.com")
+}
+
+// retryable reports whether a failed request should be retried under the
+// Client's policy. Server errors are only retried for idempotent methods, so
+// a create that may have succeeded is not repeated.
+func (c *Client) retryable(method string, statusCode int) bool {
+	retryable := gosumo.IsRetryableStatus
+	if c.retry.Retryable != nil {
+		retryable = c.retry.Retryable
+	}
+	if !retryable(statusCode) {
+		return false
+	}
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIsSumoLogicHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"api.sumologic.com", true},
+		{"api.us2.sumologic.com", true},
+		{"API.EU.SUMOLOGIC.COM", true},
+		{"api.fed.sumologic.com:443", true},
+		{"api.sumologic.com.", true},
+		{"api.sumologic.com:8443", false},
+		{"sumologic.com.example.com", false},
+		{"evilsumologic.com", false},
+		{"example.com", false},
+		{"127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := isSumoLogicHost(tt.host); got != tt.want {
+			t.Errorf("isSumoLogicHost(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestRedirectToOtherHostIsRefused(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://collector.example.com/api/v1/users", http.StatusMovedPermanently)
+	}))
+	defer srv.Close()
+	c, err := NewClient("redirect-other-host", "key", WithBaseURL(srv.URL+"/api"), WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.get(context.Background(), "/v1/users", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "not a Sumo Logic host") {
+		t.Fatalf("get error = %v, want a refused redirect", err)
+	}
+	if got := c.BaseURL(); got != srv.URL+"/api" {
+		t.Errorf("BaseURL = %s, want it unchanged", got)
+	}
+	if _, ok := discoveredBaseURLs.Load(discoveryKey{"redirect-other-host", srv.URL + "/api"}); ok {
+		t.Error("the refused base URL was cached")
+	}
+}
+
+func TestRedirectToSameHostIsFollowed(t *testing.T) {
+	var auth []string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, key, _ := r.BasicAuth()
+		auth = append(auth, key)
+		if strings.HasPrefix(r.URL.Path, "/old/") {
+			http.Redirect(w, r, "/new/api/v1/users", http.StatusMovedPermanently)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	c, err := NewClient("redirect-same-host", "key", WithBaseURL(srv.URL+"/old/api"), WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.get(context.Background(), "/v1/users", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.BaseURL(), srv.URL+"/new/api"; got != want {
+		t.Errorf("BaseURL = %s, want %s", got, want)
+	}
+	if len(auth) != 2 || auth[1] != "key" {
+		t.Errorf("requests authenticated with %q, want the key on both", auth)
+	}
+}
+
+func TestWithDeployment(t *testing.T) {
+	tests := []struct {
+		deployment Deployment
+		want       string
+	}{
+		{DeploymentUS1, "https://api.sumologic.com/api"},
+		{DeploymentUS2, "https://api.us2.sumologic.com/api"},
+		{"EU", "https://api.eu.sumologic.com/api"},
+	}
+	for _, tt := range tests {
+		c, err := NewClient("id", "key", WithDeployment(tt.deployment))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.BaseURL(); got != tt.want {
+			t.Errorf("BaseURL with deployment %q = %s, want %s", tt.deployment, got, tt.want)
+		}
+	}
+	if _, err := NewClient("id", "key", WithDeployment("mars")); err == nil {
+		t.Error("NewClient with an unknown deployment succeeded")
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+		want   int
+	}{
+		{"server error on get", http.MethodGet, http.StatusServiceUnavailable, 3},
+		{"server error on put", http.MethodPut, http.StatusInternalServerError, 3},
+		// A create that may have succeeded is not repeated.
+		{"server error on post", http.MethodPost, http.StatusServiceUnavailable, 1},
+		{"rate limited post", http.MethodPost, http.StatusTooManyRequests, 3},
+		{"client error", http.MethodGet, http.StatusBadRequest, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI(t)
+			api.handle(tt.method+" /v1/users", func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, `{"errors": [{"code": "error"}]}`, tt.status)
+			})
+			_, err := api.client().do(context.Background(), request{method: tt.method, path: "/v1/users"})
+			var apiErr Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("do error = %v, want a %d Error", err, tt.status)
+			}
+			if got := len(api.received()); got != tt.want {
+				t.Errorf("received %d requests, want %d", got, tt.want)
+			}
+		})
+	}
+}
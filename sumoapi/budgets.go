@@ -0,0 +1,284 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Actions taken when an ingest budget reaches its capacity.
+const (
+	BudgetStopCollecting = "stopCollecting"
+	BudgetKeepCollecting = "keepCollecting"
+)
+
+// Usage statuses of an ingest budget.
+const (
+	BudgetNormal      = "Normal"
+	BudgetApproaching = "Approaching"
+	BudgetExceeded    = "Exceeded"
+	BudgetFailed      = "Failed"
+)
+
+// IngestBudget is a v1 ingest budget, which limits the daily volume of the
+// collectors assigned to it, either directly or through their
+// _budget field.
+type IngestBudget struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// FieldValue is the value of the _budget field that assigns collectors
+	// to the budget.
+	FieldValue    string `json:"fieldValue"`
+	CapacityBytes int64  `json:"capacityBytes"`
+	// Timezone and ResetTime, such as "America/Chicago" and "00:00", are
+	// when the daily usage is reset.
+	Timezone  string `json:"timezone"`
+	ResetTime string `json:"resetTime"`
+	// Action is BudgetStopCollecting or BudgetKeepCollecting.
+	Action string `json:"action"`
+	// AuditThreshold is the percentage of the capacity at which an audit
+	// event is raised.
+	AuditThreshold     int       `json:"auditThreshold,omitempty"`
+	UsageBytes         int64     `json:"usageBytes,omitempty"`
+	UsageStatus        string    `json:"usageStatus,omitempty"`
+	NumberOfCollectors int       `json:"numberOfCollectors,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+	CreatedBy          string    `json:"createdBy,omitempty"`
+	ModifiedAt         time.Time `json:"modifiedAt"`
+	ModifiedBy         string    `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the ingest budget that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the ingest budget, keeping the properties that have
+// no field in Extra.
+func (b *IngestBudget) UnmarshalJSON(data []byte) error {
+	type plain IngestBudget
+	return unmarshalExtra(data, (*plain)(b), &b.Extra)
+}
+
+// MarshalJSON encodes the ingest budget along with the properties in Extra.
+func (b IngestBudget) MarshalJSON() ([]byte, error) {
+	type plain IngestBudget
+	return marshalExtra(plain(b), b.Extra)
+}
+
+// IngestBudgetV2 is a v2 ingest budget, which limits the volume of logs
+// matching its scope rather than of assigned collectors.
+type IngestBudgetV2 struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Scope selects the logs counted against the budget, such as
+	// _sourceCategory=prod/*.
+	Scope         string `json:"scope"`
+	CapacityBytes int64  `json:"capacityBytes"`
+	// Timezone and ResetTime, such as "America/Chicago" and "00:00", are
+	// when the usage is reset.
+	Timezone  string `json:"timezone"`
+	ResetTime string `json:"resetTime"`
+	// Action is BudgetStopCollecting or BudgetKeepCollecting.
+	Action string `json:"action"`
+	// AuditThreshold is the percentage of the capacity at which an audit
+	// event is raised.
+	AuditThreshold int `json:"auditThreshold,omitempty"`
+	// BudgetType is "dailyVolume" or "minuteVolume".
+	BudgetType  string    `json:"budgetType,omitempty"`
+	UsageBytes  int64     `json:"usageBytes,omitempty"`
+	UsageStatus string    `json:"usageStatus,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CreatedBy   string    `json:"createdBy,omitempty"`
+	ModifiedAt  time.Time `json:"modifiedAt"`
+	ModifiedBy  string    `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the ingest budget that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the ingest budget, keeping the properties that have
+// no field in Extra.
+func (b *IngestBudgetV2) UnmarshalJSON(data []byte) error {
+	type plain IngestBudgetV2
+	return unmarshalExtra(data, (*plain)(b), &b.Extra)
+}
+
+// MarshalJSON encodes the ingest budget along with the properties in Extra.
+func (b IngestBudgetV2) MarshalJSON() ([]byte, error) {
+	type plain IngestBudgetV2
+	return marshalExtra(plain(b), b.Extra)
+}
+
+// ListIngestBudgets returns every v1 ingest budget, following pagination.
+func (c *Client) ListIngestBudgets(ctx context.Context) ([]IngestBudget, error) {
+	return Collect(c.IterateIngestBudgets(ctx))
+}
+
+// IterateIngestBudgets returns an Iterator over the ingest budgets returned
+// by ListIngestBudgets, requesting each page as it is read.
+func (c *Client) IterateIngestBudgets(ctx context.Context) *Iterator[IngestBudget] {
+	return iterateTokens[IngestBudget](ctx, c, "listing ingest budgets", "/v1/ingestBudgets", nil)
+}
+
+// GetIngestBudget returns the v1 ingest budget with the ID.
+func (c *Client) GetIngestBudget(ctx context.Context, id string) (*IngestBudget, error) {
+	var b IngestBudget
+	if err := c.get(ctx, pathf("/v1/ingestBudgets/%s", id), nil, &b); err != nil {
+		return nil, fmt.Errorf("error getting ingest budget: %w", err)
+	}
+	return &b, nil
+}
+
+// CreateIngestBudget creates a v1 ingest budget and returns it.
+func (c *Client) CreateIngestBudget(ctx context.Context, budget IngestBudget) (*IngestBudget, error) {
+	var created IngestBudget
+	if err := c.post(ctx, "/v1/ingestBudgets", ingestBudgetBody(budget), &created); err != nil {
+		return nil, fmt.Errorf("error creating ingest budget: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateIngestBudget replaces the v1 ingest budget with the same ID and
+// returns the updated budget.
+func (c *Client) UpdateIngestBudget(ctx context.Context, budget IngestBudget) (*IngestBudget, error) {
+	var updated IngestBudget
+	if err := c.put(ctx, pathf("/v1/ingestBudgets/%s", budget.ID), ingestBudgetBody(budget), &updated); err != nil {
+		return nil, fmt.Errorf("error updating ingest budget: %w", err)
+	}
+	return &updated, nil
+}
+
+// DeleteIngestBudget deletes the v1 ingest budget with the ID.
+func (c *Client) DeleteIngestBudget(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/ingestBudgets/%s", id)); err != nil {
+		return fmt.Errorf("error deleting ingest budget: %w", err)
+	}
+	return nil
+}
+
+// ResetIngestBudgetUsage resets the usage of the v1 ingest budget with the
+// ID to zero, so collection resumes if it was stopped.
+func (c *Client) ResetIngestBudgetUsage(ctx context.Context, id string) error {
+	if err := c.post(ctx, pathf("/v1/ingestBudgets/%s/usage/reset", id), nil, nil); err != nil {
+		return fmt.Errorf("error resetting ingest budget usage: %w", err)
+	}
+	return nil
+}
+
+// ListIngestBudgetCollectors returns the IDs and names of the collectors
+// assigned to the v1 ingest budget, following pagination.
+func (c *Client) ListIngestBudgetCollectors(ctx context.Context, id string) ([]ResourceIdentity, error) {
+	return Collect(c.IterateIngestBudgetCollectors(ctx, id))
+}
+
+// IterateIngestBudgetCollectors returns an Iterator over the ingest budget
+// collectors returned by ListIngestBudgetCollectors, requesting each page as
+// it is read.
+func (c *Client) IterateIngestBudgetCollectors(ctx context.Context, id string) *Iterator[ResourceIdentity] {
+	return iterateTokens[ResourceIdentity](ctx, c, "listing ingest budget collectors", pathf("/v1/ingestBudgets/%s/collectors", id), nil)
+}
+
+// AssignCollectorToBudget assigns the collector to the v1 ingest budget.
+func (c *Client) AssignCollectorToBudget(ctx context.Context, budgetID string, collectorID int64) error {
+	if err := c.put(ctx, pathf("/v1/ingestBudgets/%s/collectors/%s", budgetID, collectorID), nil, nil); err != nil {
+		return fmt.Errorf("error assigning collector to ingest budget: %w", err)
+	}
+	return nil
+}
+
+// RemoveCollectorFromBudget removes the collector from the v1 ingest budget.
+func (c *Client) RemoveCollectorFromBudget(ctx context.Context, budgetID string, collectorID int64) error {
+	if err := c.delete(ctx, pathf("/v1/ingestBudgets/%s/collectors/%s", budgetID, collectorID)); err != nil {
+		return fmt.Errorf("error removing collector from ingest budget: %w", err)
+	}
+	return nil
+}
+
+// ListIngestBudgetsV2 returns every v2 ingest budget, following pagination.
+func (c *Client) ListIngestBudgetsV2(ctx context.Context) ([]IngestBudgetV2, error) {
+	return Collect(c.IterateIngestBudgetsV2(ctx))
+}
+
+// IterateIngestBudgetsV2 returns an Iterator over the ingest budgets
+// returned by ListIngestBudgetsV2, requesting each page as it is read.
+func (c *Client) IterateIngestBudgetsV2(ctx context.Context) *Iterator[IngestBudgetV2] {
+	return iterateTokens[IngestBudgetV2](ctx, c, "listing ingest budgets", "/v2/ingestBudgets", nil)
+}
+
+// GetIngestBudgetV2 returns the v2 ingest budget with the ID.
+func (c *Client) GetIngestBudgetV2(ctx context.Context, id string) (*IngestBudgetV2, error) {
+	var b IngestBudgetV2
+	if err := c.get(ctx, pathf("/v2/ingestBudgets/%s", id), nil, &b); err != nil {
+		return nil, fmt.Errorf("error getting ingest budget: %w", err)
+	}
+	return &b, nil
+}
+
+// CreateIngestBudgetV2 creates a v2 ingest budget and returns it.
+func (c *Client) CreateIngestBudgetV2(ctx context.Context, budget IngestBudgetV2) (*IngestBudgetV2, error) {
+	var created IngestBudgetV2
+	if err := c.post(ctx, "/v2/ingestBudgets", ingestBudgetV2Body(budget), &created); err != nil {
+		return nil, fmt.Errorf("error creating ingest budget: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateIngestBudgetV2 replaces the v2 ingest budget with the same ID and
+// returns the updated budget.
+func (c *Client) UpdateIngestBudgetV2(ctx context.Context, budget IngestBudgetV2) (*IngestBudgetV2, error) {
+	var updated IngestBudgetV2
+	if err := c.put(ctx, pathf("/v2/ingestBudgets/%s", budget.ID), ingestBudgetV2Body(budget), &updated); err != nil {
+		return nil, fmt.Errorf("error updating ingest budget: %w", err)
+	}
+	return &updated, nil
+}
+
+// DeleteIngestBudgetV2 deletes the v2 ingest budget with the ID.
+func (c *Client) DeleteIngestBudgetV2(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v2/ingestBudgets/%s", id)); err != nil {
+		return fmt.Errorf("error deleting ingest budget: %w", err)
+	}
+	return nil
+}
+
+// ResetIngestBudgetV2Usage resets the usage of the v2 ingest budget with the
+// ID to zero.
+func (c *Client) ResetIngestBudgetV2Usage(ctx context.Context, id string) error {
+	if err := c.post(ctx, pathf("/v2/ingestBudgets/%s/usage/reset", id), nil, nil); err != nil {
+		return fmt.Errorf("error resetting ingest budget usage: %w", err)
+	}
+	return nil
+}
+
+// ingestBudgetBody returns the fields of a v1 budget that can be set.
+func ingestBudgetBody(b IngestBudget) any {
+	return struct {
+		Name           string `json:"name"`
+		Description    string `json:"description,omitempty"`
+		FieldValue     string `json:"fieldValue"`
+		CapacityBytes  int64  `json:"capacityBytes"`
+		Timezone       string `json:"timezone"`
+		ResetTime      string `json:"resetTime"`
+		Action         string `json:"action"`
+		AuditThreshold int    `json:"auditThreshold,omitempty"`
+	}{b.Name, b.Description, b.FieldValue, b.CapacityBytes, b.Timezone, b.ResetTime, b.Action, b.AuditThreshold}
+}
+
+// ingestBudgetV2Body returns the fields of a v2 budget that can be set.
+func ingestBudgetV2Body(b IngestBudgetV2) any {
+	return struct {
+		Name           string `json:"name"`
+		Description    string `json:"description,omitempty"`
+		Scope          string `json:"scope"`
+		CapacityBytes  int64  `json:"capacityBytes"`
+		Timezone       string `json:"timezone"`
+		ResetTime      string `json:"resetTime"`
+		Action         string `json:"action"`
+		AuditThreshold int    `json:"auditThreshold,omitempty"`
+		BudgetType     string `json:"budgetType,omitempty"`
+	}{b.Name, b.Description, b.Scope, b.CapacityBytes, b.Timezone, b.ResetTime, b.Action, b.AuditThreshold, b.BudgetType}
+}
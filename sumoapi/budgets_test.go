@@ -0,0 +1,180 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestIngestBudgets(t *testing.T) {
+	ctx := context.Background()
+	budget := IngestBudget{
+		ID: "b1", Name: "web", FieldValue: "web", CapacityBytes: 1 << 30, Timezone: "UTC",
+		ResetTime: "00:00", Action: BudgetStopCollecting, UsageBytes: 10, UsageStatus: BudgetNormal,
+	}
+	body := `{"name": "web", "fieldValue": "web", "capacityBytes": 1073741824, "timezone": "UTC", "resetTime": "00:00", "action": "stopCollecting"}`
+	response := `{"id": "b1", "name": "web", "fieldValue": "web", "usageStatus": "Exceeded", "usageBytes": 2048}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				budgets, err := c.ListIngestBudgets(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(len(budgets), 1)
+			},
+			response: `{"data": [` + response + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/ingestBudgets",
+			query:    "limit=100",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				b, err := c.GetIngestBudget(ctx, "b1")
+				if err != nil {
+					return err
+				}
+				return expect(b.UsageStatus, BudgetExceeded)
+			},
+			response: response,
+			method:   http.MethodGet,
+			path:     "/v1/ingestBudgets/b1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateIngestBudget(ctx, budget)
+				return err
+			},
+			response: response,
+			method:   http.MethodPost,
+			path:     "/v1/ingestBudgets",
+			// Only the settings are sent, not the usage.
+			body: body,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateIngestBudget(ctx, budget)
+				return err
+			},
+			response: response,
+			method:   http.MethodPut,
+			path:     "/v1/ingestBudgets/b1",
+			body:     body,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteIngestBudget(ctx, "b1") },
+			method: http.MethodDelete,
+			path:   "/v1/ingestBudgets/b1",
+		},
+		{
+			name:   "reset usage",
+			call:   func(c *Client) error { return c.ResetIngestBudgetUsage(ctx, "b1") },
+			method: http.MethodPost,
+			path:   "/v1/ingestBudgets/b1/usage/reset",
+		},
+		{
+			name: "list collectors",
+			call: func(c *Client) error {
+				collectors, err := c.ListIngestBudgetCollectors(ctx, "b1")
+				if err != nil {
+					return err
+				}
+				return expect(collectors, []ResourceIdentity{{ID: "1", Name: "web"}})
+			},
+			response: `{"data": [{"id": "1", "name": "web"}]}`,
+			method:   http.MethodGet,
+			path:     "/v1/ingestBudgets/b1/collectors",
+			query:    "limit=100",
+		},
+		{
+			name:   "assign collector",
+			call:   func(c *Client) error { return c.AssignCollectorToBudget(ctx, "b1", 1) },
+			method: http.MethodPut,
+			path:   "/v1/ingestBudgets/b1/collectors/1",
+		},
+		{
+			name:   "remove collector",
+			call:   func(c *Client) error { return c.RemoveCollectorFromBudget(ctx, "b1", 1) },
+			method: http.MethodDelete,
+			path:   "/v1/ingestBudgets/b1/collectors/1",
+		},
+	})
+}
+
+func TestIngestBudgetsV2(t *testing.T) {
+	ctx := context.Background()
+	budget := IngestBudgetV2{
+		ID: "b2", Name: "web", Scope: "_sourceCategory=web", CapacityBytes: 1 << 30, Timezone: "UTC",
+		ResetTime: "00:00", Action: BudgetKeepCollecting, BudgetType: "dailyVolume",
+	}
+	body := `{"name": "web", "scope": "_sourceCategory=web", "capacityBytes": 1073741824, "timezone": "UTC", "resetTime": "00:00", "action": "keepCollecting", "budgetType": "dailyVolume"}`
+	response := `{"id": "b2", "name": "web", "scope": "_sourceCategory=web", "usageStatus": "Normal"}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				budgets, err := c.ListIngestBudgetsV2(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(len(budgets), 1)
+			},
+			response: `{"data": [` + response + `]}`,
+			method:   http.MethodGet,
+			path:     "/v2/ingestBudgets",
+			query:    "limit=100",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				b, err := c.GetIngestBudgetV2(ctx, "b2")
+				if err != nil {
+					return err
+				}
+				return expect(b.Scope, "_sourceCategory=web")
+			},
+			response: response,
+			method:   http.MethodGet,
+			path:     "/v2/ingestBudgets/b2",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateIngestBudgetV2(ctx, budget)
+				return err
+			},
+			response: response,
+			method:   http.MethodPost,
+			path:     "/v2/ingestBudgets",
+			body:     body,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateIngestBudgetV2(ctx, budget)
+				return err
+			},
+			response: response,
+			method:   http.MethodPut,
+			path:     "/v2/ingestBudgets/b2",
+			body:     body,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteIngestBudgetV2(ctx, "b2") },
+			method: http.MethodDelete,
+			path:   "/v2/ingestBudgets/b2",
+		},
+		{
+			name:   "reset usage",
+			call:   func(c *Client) error { return c.ResetIngestBudgetV2Usage(ctx, "b2") },
+			method: http.MethodPost,
+			path:   "/v2/ingestBudgets/b2/usage/reset",
+		},
+	})
+}
@@ -0,0 +1,347 @@
+// Package sumoapi provides a client for the Sumo Logic management API, which
+// is used to run searches and to manage collectors, sources and other
+// account resources. Requests are authenticated with an access ID and access
+// key, see https://help.sumologic.com/docs/manage/security/access-keys/.
+//
+// The Client follows the redirects Sumo Logic sends when a request reaches
+// the wrong deployment, and retries rate limited requests, so the same
+// handling applies to every API.
+//
+// Every method takes a context, which bounds the requests it makes, including
+// any polling done while waiting for a job to finish.
+package sumoapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/byitkc/gosumo"
+)
+
+// DefaultBaseURL is the base URL of the management API for the us1
+// deployment.
+const DefaultBaseURL = "https://api.sumologic.com/api"
+
+// DefaultPollInterval is how often the status of a job is checked while
+// waiting for it to finish.
+const DefaultPollInterval = 2 * time.Second
+
+// Client makes requests to the Sumo Logic management API. It is safe for
+// concurrent use.
+type Client struct {
+	accessID     string
+	accessKey    string
+	httpClient   *http.Client
+	retry        gosumo.RetryPolicy
+	pollInterval time.Duration
+	// configuredBaseURL is the base URL set by the options, before any
+	// redirect is followed, and baseURLSet reports whether the options set
+	// it rather than leaving DefaultBaseURL.
+	configuredBaseURL string
+	baseURLSet        bool
+
+	// mu protects baseURL, which changes when a request is redirected to
+	// another deployment, and rateLimit, the state reported by the most
+	// recent response with rate limit headers.
+	mu        sync.RWMutex
+	baseURL   *url.URL
+	rateLimit *RateLimit
+}
+
+// Option configures a Client when it is created with NewClient.
+type Option func(*Client) error
+
+// WithBaseURL sets the base URL of the management API, including the /api
+// path, such as https://api.eu.sumologic.com/api. The default is
+// DefaultBaseURL. An http URL is accepted so that the Client can be pointed
+// at a local server in tests, but the credentials are then sent in the clear.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+		if err != nil {
+			return fmt.Errorf("invalid base url: %w", err)
+		}
+		if u.Scheme != "https" && u.Scheme != "http" {
+			return fmt.Errorf("base url must use https or http, got: %q", baseURL)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("base url must have a host, got: %q", baseURL)
+		}
+		c.baseURL = u
+		c.baseURLSet = true
+		return nil
+	}
+}
+
+// WithHTTPClient sets the http.Client used to make requests. The Client uses
+// a copy of it, so it can follow deployment redirects itself. The search job
+// API relies on cookies to route requests for a job to the same node, so if
+// the client has no cookie jar the copy is given its own jar.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) error {
+		if hc == nil {
+			return fmt.Errorf("http client cannot be nil")
+		}
+		c.httpClient = hc
+		return nil
+	}
+}
+
+// WithPollInterval sets how often the status of a job is checked while
+// waiting for it to finish. The default is DefaultPollInterval.
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return fmt.Errorf("poll interval must be greater than zero, got: %s", d)
+		}
+		c.pollInterval = d
+		return nil
+	}
+}
+
+// NewClient creates and returns a new Client that authenticates with the
+// provided access ID and access key. Options are applied in order and an
+// error is returned if the credentials or any of the options are invalid.
+func NewClient(accessID, accessKey string, opts ...Option) (*Client, error) {
+	if accessID == "" || accessKey == "" {
+		return nil, gosumo.ErrBuildingClient{Message: "unable to build api client: access id and access key are required"}
+	}
+	c := &Client{
+		accessID:     accessID,
+		accessKey:    accessKey,
+		httpClient:   &http.Client{},
+		retry:        gosumo.DefaultRetryPolicy,
+		pollInterval: DefaultPollInterval,
+	}
+	c.baseURL, _ = url.Parse(DefaultBaseURL)
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, gosumo.ErrBuildingClient{
+				Message: fmt.Sprintf("unable to build api client: %v", err),
+				Err:     err,
+			}
+		}
+	}
+	c.configuredBaseURL = c.baseURL.String()
+	hc := *c.httpClient
+	if hc.Jar == nil {
+		hc.Jar, _ = cookiejar.New(nil)
+	}
+	// Redirects are followed by do, since the http.Client would drop the
+	// credentials and the body of the request.
+	hc.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	c.httpClient = &hc
+	return c, nil
+}
+
+// BaseURL returns the base URL of the management API the Client uses. It
+// changes if a request is redirected to another deployment.
+func (c *Client) BaseURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.baseURL.String()
+}
+
+// base returns a copy of the base URL.
+func (c *Client) base() *url.URL {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	u := *c.baseURL
+	return &u
+}
+
+// request describes a single call to the management API.
+type request struct {
+	method string
+	// path is relative to the base URL, such as /v1/collectors.
+	path   string
+	query  url.Values
+	header http.Header
+	// body is encoded as JSON, unless it is an io.Reader, which is sent
+	// as-is.
+	body any
+	// out, if set, is decoded from the JSON response body.
+	out any
+}
+
+// do makes the request and returns the response, with its body already
+// decoded into r.out and closed. Requests redirected to another deployment
+// are sent again to it, and rate limited requests and server errors are
+// retried according to the Client's RetryPolicy, waiting for the rate limit
+// to reset when the previous response reported none remaining. Responses
+// with a status code outside the 2xx range are returned as an Error, unless
+// the context is done while waiting to retry, when the context error is
+// returned wrapping the Error.
+func (c *Client) do(ctx context.Context, r request) (*http.Response, error) {
+	body, contentType, err := encodeBody(r.body)
+	if err != nil {
+		return nil, err
+	}
+	redirects := 0
+	for attempt := 1; ; attempt++ {
+		if err := c.throttle(ctx); err != nil {
+			return nil, err
+		}
+		resp, err := c.attempt(ctx, r, body, contentType)
+		if err != nil {
+			return nil, err
+		}
+		c.observeRateLimit(resp)
+		if isRedirect(resp.StatusCode) && redirects < maxRedirects {
+			resp.Body.Close()
+			base, err := redirectBaseURL(resp, r.path)
+			if err != nil {
+				return nil, err
+			}
+			c.mu.Lock()
+			c.baseURL = base
+			c.mu.Unlock()
+			discoveredBaseURLs.Store(discoveryKey{c.accessID, c.configuredBaseURL}, base.String())
+			redirects++
+			attempt--
+			continue
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+			defer resp.Body.Close()
+			return resp, decodeBody(resp, r.out)
+		}
+		apiErr := newError(resp)
+		resp.Body.Close()
+		if attempt >= c.retry.MaxAttempts || !c.retryable(r.method, resp.StatusCode) {
+			return resp, apiErr
+		}
+		var retryAfter time.Duration
+		if apiErr.RateLimit != nil {
+			retryAfter = apiErr.RateLimit.wait(time.Now())
+		}
+		if err := sleep(ctx, c.retry.RetryDelay(attempt, retryAfter)); err != nil {
+			// The context error comes first so that a request cancelled
+			// while waiting to retry is not mistaken for a failed one.
+			return resp, fmt.Errorf("%w, last response: %w", err, apiErr)
+		}
+	}
+}
+
+// attempt sends the request once to the current base URL.
+func (c *Client) attempt(ctx context.Context, r request, body []byte, contentType string) (*http.Response, error) {
+	u := c.base().JoinPath(r.path)
+	if len(r.query) > 0 {
+		u.RawQuery = r.query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, u.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.SetBasicAuth(c.accessID, c.accessKey)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	return c.httpClient.Do(req)
+}
+
+// encodeBody returns the body of a request and its content type. Bodies that
+// are an io.Reader are read in full, so the request can be sent again after a
+// redirect or a retry.
+func encodeBody(b any) ([]byte, string, error) {
+	switch b := b.(type) {
+	case nil:
+		return nil, "", nil
+	case io.Reader:
+		data, err := io.ReadAll(b)
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading request body: %w", err)
+		}
+		return data, "", nil
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding request: %w", err)
+	}
+	return data, "application/json", nil
+}
+
+// decodeBody decodes a successful response into out. If out is an
+// io.Writer the body is copied to it as-is.
+func decodeBody(resp *http.Response, out any) error {
+	switch out := out.(type) {
+	case nil:
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	case io.Writer:
+		if _, err := io.Copy(out, resp.Body); err != nil {
+			return fmt.Errorf("error reading response: %w", err)
+		}
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
+
+// sleep waits for the provided duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// get makes a GET request and decodes the response into out.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	_, err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, out: out})
+	return err
+}
+
+// post makes a POST request with the body and decodes the response into out.
+func (c *Client) post(ctx context.Context, path string, body, out any) error {
+	_, err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, out: out})
+	return err
+}
+
+// put makes a PUT request with the body and decodes the response into out.
+func (c *Client) put(ctx context.Context, path string, body, out any) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: path, body: body, out: out})
+	return err
+}
+
+// delete makes a DELETE request.
+func (c *Client) delete(ctx context.Context, path string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path})
+	return err
+}
+
+// pathf formats a request path, escaping every argument as a path segment.
+func pathf(format string, args ...any) string {
+	escaped := make([]any, len(args))
+	for i, a := range args {
+		escaped[i] = url.PathEscape(fmt.Sprint(a))
+	}
+	return fmt.Sprintf(format, escaped...)
+}
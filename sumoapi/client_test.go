@@ -0,0 +1,224 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+)
+
+// testAPI is a fake management API. Handlers are registered with patterns
+// relative to the base URL, such as "GET /v1/collectors", and every request
+// it receives is recorded.
+type testAPI struct {
+	t   *testing.T
+	mux *http.ServeMux
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	requests []apiRequest
+}
+
+// apiRequest is a request received by a testAPI.
+type apiRequest struct {
+	method string
+	// path is relative to the base URL.
+	path  string
+	query url.Values
+	body  string
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	a := &testAPI{t: t, mux: http.NewServeMux()}
+	a.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		a.mu.Lock()
+		a.requests = append(a.requests, apiRequest{r.Method, strings.TrimPrefix(r.URL.Path, "/api"), r.URL.Query(), string(body)})
+		a.mu.Unlock()
+		a.mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(a.srv.Close)
+	return a
+}
+
+// client returns a Client of the API that polls and retries without waiting.
+func (a *testAPI) client(opts ...Option) *Client {
+	a.t.Helper()
+	opts = append([]Option{
+		WithBaseURL(a.srv.URL + "/api"),
+		WithPollInterval(time.Millisecond),
+		WithRetry(gosumo.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+	}, opts...)
+	c, err := NewClient("id", "key", opts...)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return c
+}
+
+// handle registers the handler for the pattern.
+func (a *testAPI) handle(pattern string, h http.HandlerFunc) {
+	method, path, _ := strings.Cut(pattern, " ")
+	a.mux.HandleFunc(method+" /api"+path, h)
+}
+
+// respond registers a handler for the pattern that writes body as JSON.
+func (a *testAPI) respond(pattern, body string) {
+	a.handle(pattern, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	})
+}
+
+// received returns the requests received so far.
+func (a *testAPI) received() []apiRequest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]apiRequest(nil), a.requests...)
+}
+
+// last returns the last request received.
+func (a *testAPI) last() apiRequest {
+	a.t.Helper()
+	reqs := a.received()
+	if len(reqs) == 0 {
+		a.t.Fatal("no requests received")
+	}
+	return reqs[len(reqs)-1]
+}
+
+// checkRequest checks the method and path of the request, and that its body
+// is the JSON in body, unless body is empty.
+func checkRequest(t *testing.T, r apiRequest, method, path, body string) {
+	t.Helper()
+	if r.method != method || r.path != path {
+		t.Errorf("request = %s %s, want %s %s", r.method, r.path, method, path)
+	}
+	if body != "" {
+		checkJSON(t, r.body, body)
+	}
+}
+
+// checkJSON checks that got and want are the same JSON value.
+func checkJSON(t *testing.T, got, want string) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal([]byte(got), &g); err != nil {
+		t.Fatalf("invalid JSON %q: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("JSON = %s, want %s", got, compact(t, want))
+	}
+}
+
+// apiCall is a call of a Client method and the request it should send.
+type apiCall struct {
+	name string
+	call func(c *Client) error
+	// response is the JSON the request is answered with, {} if it is empty.
+	response string
+	method   string
+	path     string
+	// query is the encoded query of the request, and body its JSON body.
+	// They are not checked when empty.
+	query string
+	body  string
+}
+
+// testCalls makes each call against its own testAPI and checks the request
+// it sent.
+func testCalls(t *testing.T, calls []apiCall) {
+	t.Helper()
+	for _, tt := range calls {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI(t)
+			response := tt.response
+			if response == "" {
+				response = "{}"
+			}
+			api.respond(tt.method+" "+tt.path, response)
+			if err := tt.call(api.client()); err != nil {
+				t.Fatal(err)
+			}
+			r := api.last()
+			checkRequest(t, r, tt.method, tt.path, tt.body)
+			if got := r.query.Encode(); tt.query != "" && got != tt.query {
+				t.Errorf("query = %s, want %s", got, tt.query)
+			}
+		})
+	}
+}
+
+// expect returns an error describing the difference if got is not want, for
+// an apiCall to check the result of its call.
+func expect(got, want any) error {
+	if !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("got %+v, want %+v", got, want)
+	}
+	return nil
+}
+
+func TestCancelledWhileRetrying(t *testing.T) {
+	api := newTestAPI(t)
+	api.handle("GET /v1/users", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"errors": [{"code": "unavailable"}]}`, http.StatusServiceUnavailable)
+	})
+	c := api.client(WithRetry(gosumo.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Minute}))
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	err := c.get(ctx, "/v1/users", nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("get error = %v, want context.Canceled", err)
+	}
+	var apiErr Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("get error = %v, want it to wrap the last response", err)
+	}
+	if n := len(api.received()); n != 1 {
+		t.Errorf("received %d requests, want 1", n)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{"https://api.eu.sumologic.com/api", ""},
+		{"https://api.eu.sumologic.com/api/", ""},
+		{"http://127.0.0.1:8080/api", ""},
+		{"ftp://api.sumologic.com/api", "must use https or http"},
+		{"api.sumologic.com/api", "must use https or http"},
+		{"https:///api", "must have a host"},
+		{"https://api.sumologic.com/%zz", "invalid base url"},
+	}
+	for _, tt := range tests {
+		c, err := NewClient("id", "key", WithBaseURL(tt.url))
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("WithBaseURL(%q) error = %v", tt.url, err)
+			} else if got, want := c.BaseURL(), strings.TrimSuffix(tt.url, "/"); got != want {
+				t.Errorf("BaseURL = %q, want %q", got, want)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("WithBaseURL(%q) error = %v, want it to contain %q", tt.url, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Collector types.
+const (
+	CollectorHosted      = "Hosted"
+	CollectorInstallable = "Installable"
+)
+
+// Collector is a Sumo Logic collector.
+type Collector struct {
+	ID               int64             `json:"id,omitempty"`
+	Name             string            `json:"name"`
+	Description      string            `json:"description,omitempty"`
+	Category         string            `json:"category,omitempty"`
+	HostName         string            `json:"hostName,omitempty"`
+	TimeZone         string            `json:"timeZone,omitempty"`
+	Fields           map[string]string `json:"fields,omitempty"`
+	CollectorType    string            `json:"collectorType,omitempty"`
+	CollectorVersion string            `json:"collectorVersion,omitempty"`
+	Alive            bool              `json:"alive,omitempty"`
+	LastSeenAlive    int64             `json:"lastSeenAlive,omitempty"`
+	Ephemeral        bool              `json:"ephemeral,omitempty"`
+	SourceSyncMode   string            `json:"sourceSyncMode,omitempty"`
+	CutoffTimestamp  int64             `json:"cutoffTimestamp,omitempty"`
+	TargetCPU        int               `json:"targetCpu,omitempty"`
+
+	// ETag is the version of the collector returned by GetCollector. When it
+	// is set, UpdateCollector only succeeds if the collector has not been
+	// changed since, and otherwise fails with ErrPreconditionFailed.
+	ETag string `json:"-"`
+
+	// Extra holds the properties of the collector that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the collector, keeping the properties that have no
+// field in Extra.
+func (c *Collector) UnmarshalJSON(b []byte) error {
+	type plain Collector
+	return unmarshalExtra(b, (*plain)(c), &c.Extra)
+}
+
+// MarshalJSON encodes the collector along with the properties in Extra.
+func (c Collector) MarshalJSON() ([]byte, error) {
+	type plain Collector
+	return marshalExtra(plain(c), c.Extra)
+}
+
+// CollectorListOptions filters and pages the collectors returned by
+// ListCollectors.
+type CollectorListOptions struct {
+	// Filter is one of "installed", "hosted", "dead" or "alive". All
+	// collectors are returned when it is empty.
+	Filter string
+	// Limit is the maximum number of collectors returned, and Offset the
+	// number skipped.
+	Limit  int
+	Offset int
+}
+
+// collectorBody is the envelope used for a single collector.
+type collectorBody struct {
+	Collector Collector `json:"collector"`
+}
+
+// ListCollectors returns a page of collectors. IterateCollectors follows
+// pagination instead.
+func (c *Client) ListCollectors(ctx context.Context, opts CollectorListOptions) ([]Collector, error) {
+	query := url.Values{}
+	if opts.Filter != "" {
+		query.Set("filter", opts.Filter)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	var resp struct {
+		Collectors []Collector `json:"collectors"`
+	}
+	if err := c.get(ctx, "/v1/collectors", query, &resp); err != nil {
+		return nil, fmt.Errorf("error listing collectors: %w", err)
+	}
+	return resp.Collectors, nil
+}
+
+// IterateCollectors returns an Iterator over every collector matching the
+// filter, which is one of the values of CollectorListOptions.Filter,
+// requesting each page as it is read.
+func (c *Client) IterateCollectors(ctx context.Context, filter string) *Iterator[Collector] {
+	return iterateOffsets(ctx, "listing collectors", func(ctx context.Context, offset, limit int) ([]Collector, error) {
+		query := url.Values{"limit": {strconv.Itoa(limit)}, "offset": {strconv.Itoa(offset)}}
+		if filter != "" {
+			query.Set("filter", filter)
+		}
+		var resp struct {
+			Collectors []Collector `json:"collectors"`
+		}
+		if err := c.get(ctx, "/v1/collectors", query, &resp); err != nil {
+			return nil, err
+		}
+		return resp.Collectors, nil
+	})
+}
+
+// GetCollector returns the collector with the ID, including its ETag.
+func (c *Client) GetCollector(ctx context.Context, id int64) (*Collector, error) {
+	var body collectorBody
+	resp, err := c.do(ctx, request{method: http.MethodGet, path: pathf("/v1/collectors/%s", id), out: &body})
+	if err != nil {
+		return nil, fmt.Errorf("error getting collector: %w", err)
+	}
+	body.Collector.ETag = resp.Header.Get("ETag")
+	return &body.Collector, nil
+}
+
+// GetCollectorByName returns the collector with the name.
+func (c *Client) GetCollectorByName(ctx context.Context, name string) (*Collector, error) {
+	var body collectorBody
+	resp, err := c.do(ctx, request{method: http.MethodGet, path: pathf("/v1/collectors/name/%s", name), out: &body})
+	if err != nil {
+		return nil, fmt.Errorf("error getting collector: %w", err)
+	}
+	body.Collector.ETag = resp.Header.Get("ETag")
+	return &body.Collector, nil
+}
+
+// CreateCollector creates a collector and returns it. Only hosted
+// collectors can be created through the API, so CollectorType defaults to
+// CollectorHosted.
+func (c *Client) CreateCollector(ctx context.Context, collector Collector) (*Collector, error) {
+	if collector.CollectorType == "" {
+		collector.CollectorType = CollectorHosted
+	}
+	var body collectorBody
+	resp, err := c.do(ctx, request{method: http.MethodPost, path: "/v1/collectors", body: collectorBody{collector}, out: &body})
+	if err != nil {
+		return nil, fmt.Errorf("error creating collector: %w", err)
+	}
+	body.Collector.ETag = resp.Header.Get("ETag")
+	return &body.Collector, nil
+}
+
+// UpdateCollector replaces the collector with the same ID and returns the
+// updated collector. If the collector has an ETag the update is conditional
+// on it, see Collector.ETag.
+func (c *Client) UpdateCollector(ctx context.Context, collector Collector) (*Collector, error) {
+	var body collectorBody
+	r := request{
+		method: http.MethodPut,
+		path:   pathf("/v1/collectors/%s", collector.ID),
+		header: ifMatch(collector.ETag),
+		body:   collectorBody{collector},
+		out:    &body,
+	}
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("error updating collector: %w", err)
+	}
+	body.Collector.ETag = resp.Header.Get("ETag")
+	return &body.Collector, nil
+}
+
+// DeleteCollector deletes the collector with the ID, along with its sources.
+func (c *Client) DeleteCollector(ctx context.Context, id int64) error {
+	if err := c.delete(ctx, pathf("/v1/collectors/%s", id)); err != nil {
+		return fmt.Errorf("error deleting collector: %w", err)
+	}
+	return nil
+}
+
+// ifMatch returns the If-Match header for a conditional update, or nil if
+// there is no ETag.
+func ifMatch(etag string) http.Header {
+	if etag == "" {
+		return nil
+	}
+	return http.Header{"If-Match": {etag}}
+}
@@ -0,0 +1,115 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestListCollectors(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("GET /v1/collectors", `{"collectors": [{"id": 1, "name": "web", "collectorType": "Hosted", "alive": true}]}`)
+	collectors, err := api.client().ListCollectors(context.Background(), CollectorListOptions{Filter: "hosted", Limit: 10, Offset: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collectors) != 1 || collectors[0].ID != 1 || collectors[0].Name != "web" || !collectors[0].Alive {
+		t.Errorf("ListCollectors = %+v", collectors)
+	}
+	r := api.last()
+	checkRequest(t, r, http.MethodGet, "/v1/collectors", "")
+	if got := r.query.Encode(); got != "filter=hosted&limit=10&offset=20" {
+		t.Errorf("query = %s", got)
+	}
+}
+
+func TestGetCollector(t *testing.T) {
+	api := newTestAPI(t)
+	collector := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, `{"collector": {"id": 1, "name": "web pool", "targetCpu": 50, "cutoffRelativeTime": "-1d"}}`)
+	}
+	api.handle("GET /v1/collectors/1", collector)
+	api.handle("GET /v1/collectors/name/{name}", collector)
+	c := api.client()
+
+	got, err := c.GetCollector(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.last(), http.MethodGet, "/v1/collectors/1", "")
+	if got.ETag != `"v1"` || got.TargetCPU != 50 {
+		t.Errorf("GetCollector = %+v", got)
+	}
+	// Properties without a field are kept when the collector is updated.
+	if string(got.Extra["cutoffRelativeTime"]) != `"-1d"` {
+		t.Errorf("Extra = %s", got.Extra)
+	}
+
+	got, err = c.GetCollectorByName(context.Background(), "web pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.last(), http.MethodGet, "/v1/collectors/name/web pool", "")
+	if got.ETag != `"v1"` {
+		t.Errorf("ETag = %q", got.ETag)
+	}
+}
+
+func TestCreateCollector(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/collectors", `{"collector": {"id": 1, "name": "web", "collectorType": "Hosted"}}`)
+	got, err := api.client().CreateCollector(context.Background(), Collector{Name: "web", Fields: map[string]string{"team": "ops"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != 1 {
+		t.Errorf("CreateCollector = %+v", got)
+	}
+	checkRequest(t, api.last(), http.MethodPost, "/v1/collectors",
+		`{"collector": {"name": "web", "collectorType": "Hosted", "fields": {"team": "ops"}}}`)
+}
+
+func TestUpdateCollector(t *testing.T) {
+	api := newTestAPI(t)
+	api.handle("PUT /v1/collectors/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Match") != `"v1"` {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			io.WriteString(w, `{"errors": [{"code": "collectors.version.mismatch"}]}`)
+			return
+		}
+		w.Header().Set("ETag", `"v2"`)
+		io.WriteString(w, `{"collector": {"id": 1, "name": "web", "cutoffRelativeTime": "-1d"}}`)
+	})
+	c := api.client()
+	collector := Collector{ID: 1, Name: "web", ETag: `"v1"`, Extra: map[string]json.RawMessage{"cutoffRelativeTime": json.RawMessage(`"-1d"`)}}
+	got, err := c.UpdateCollector(context.Background(), collector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ETag != `"v2"` {
+		t.Errorf("ETag = %q, want the new version", got.ETag)
+	}
+	checkRequest(t, api.last(), http.MethodPut, "/v1/collectors/1",
+		`{"collector": {"id": 1, "name": "web", "cutoffRelativeTime": "-1d"}}`)
+
+	collector.ETag = `"v0"`
+	if _, err := c.UpdateCollector(context.Background(), collector); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("UpdateCollector with a stale ETag = %v, want ErrPreconditionFailed", err)
+	}
+}
+
+func TestDeleteCollector(t *testing.T) {
+	api := newTestAPI(t)
+	api.handle("DELETE /v1/collectors/1", func(w http.ResponseWriter, r *http.Request) {})
+	if err := api.client().DeleteCollector(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.last(), http.MethodDelete, "/v1/collectors/1", "")
+	if err := api.client().DeleteCollector(context.Background(), 2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteCollector of a missing collector = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,206 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Types of a connection.
+const (
+	ConnectionWebhook    = "WebhookConnection"
+	ConnectionServiceNow = "ServiceNowConnection"
+)
+
+// Webhook types of a webhook connection.
+const (
+	WebhookGeneric        = "Webhook"
+	WebhookSlack          = "Slack"
+	WebhookPagerDuty      = "PagerDuty"
+	WebhookMicrosoftTeams = "MicrosoftTeams"
+	WebhookOpsgenie       = "Opsgenie"
+	WebhookJira           = "Jira"
+	WebhookAWSLambda      = "AWSLambda"
+	WebhookAzureFunctions = "AzureFunctions"
+	WebhookDatadog        = "Datadog"
+	WebhookNewRelic       = "NewRelic"
+)
+
+// Connection is a connection monitors send notifications to, such as a
+// Slack or PagerDuty webhook.
+type Connection struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Type is ConnectionWebhook or ConnectionServiceNow. It defaults to
+	// ConnectionWebhook.
+	Type string `json:"type"`
+	// WebhookType is one of the Webhook constants. It defaults to
+	// WebhookGeneric.
+	WebhookType string `json:"webhookType,omitempty"`
+	URL         string `json:"url"`
+	// Headers are sent with every request, such as an Authorization
+	// header. CustomHeaders are sent in addition to them.
+	Headers       []ConnectionHeader `json:"headers,omitempty"`
+	CustomHeaders []ConnectionHeader `json:"customHeaders,omitempty"`
+	// DefaultPayload is the JSON template sent when a monitor triggers,
+	// which may use variables such as {{TriggerType}}. ResolutionPayload is
+	// sent when the alert is resolved.
+	DefaultPayload    string    `json:"defaultPayload"`
+	ResolutionPayload string    `json:"resolutionPayload,omitempty"`
+	ConnectionSubtype string    `json:"connectionSubtype,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+	CreatedBy         string    `json:"createdBy,omitempty"`
+	ModifiedAt        time.Time `json:"modifiedAt"`
+	ModifiedBy        string    `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the connection that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the connection, keeping the properties that have no
+// field in Extra.
+func (c *Connection) UnmarshalJSON(b []byte) error {
+	type plain Connection
+	return unmarshalExtra(b, (*plain)(c), &c.Extra)
+}
+
+// MarshalJSON encodes the connection along with the properties in Extra.
+func (c Connection) MarshalJSON() ([]byte, error) {
+	type plain Connection
+	return marshalExtra(plain(c), c.Extra)
+}
+
+// ConnectionHeader is a header sent by a connection.
+type ConnectionHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+
+	// Extra holds the properties of the header that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the header, keeping the properties that have no
+// field in Extra.
+func (h *ConnectionHeader) UnmarshalJSON(b []byte) error {
+	type plain ConnectionHeader
+	return unmarshalExtra(b, (*plain)(h), &h.Extra)
+}
+
+// MarshalJSON encodes the header along with the properties in Extra.
+func (h ConnectionHeader) MarshalJSON() ([]byte, error) {
+	type plain ConnectionHeader
+	return marshalExtra(plain(h), h.Extra)
+}
+
+// ConnectionTestResult is the response of the endpoint of a connection to a
+// test notification.
+type ConnectionTestResult struct {
+	StatusCode      int    `json:"statusCode"`
+	ResponseContent string `json:"responseContent"`
+}
+
+// OK reports whether the endpoint responded with a 2xx status code.
+func (r ConnectionTestResult) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode <= 299
+}
+
+// ListConnections returns every connection, following pagination.
+func (c *Client) ListConnections(ctx context.Context) ([]Connection, error) {
+	return Collect(c.IterateConnections(ctx))
+}
+
+// IterateConnections returns an Iterator over the connections returned by
+// ListConnections, requesting each page as it is read.
+func (c *Client) IterateConnections(ctx context.Context) *Iterator[Connection] {
+	return iterateTokens[Connection](ctx, c, "listing connections", "/v1/connections", nil)
+}
+
+// GetConnection returns the webhook connection with the ID.
+func (c *Client) GetConnection(ctx context.Context, id string) (*Connection, error) {
+	var conn Connection
+	query := url.Values{"type": {ConnectionWebhook}}
+	if err := c.get(ctx, pathf("/v1/connections/%s", id), query, &conn); err != nil {
+		return nil, fmt.Errorf("error getting connection: %w", err)
+	}
+	return &conn, nil
+}
+
+// CreateConnection creates a connection and returns it.
+func (c *Client) CreateConnection(ctx context.Context, conn Connection) (*Connection, error) {
+	if conn.Name == "" || conn.URL == "" {
+		return nil, fmt.Errorf("error creating connection: name and url are required")
+	}
+	var created Connection
+	if err := c.post(ctx, "/v1/connections", connectionBody(conn), &created); err != nil {
+		return nil, fmt.Errorf("error creating connection: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateConnection replaces the connection with the same ID and returns the
+// updated connection.
+func (c *Client) UpdateConnection(ctx context.Context, conn Connection) (*Connection, error) {
+	var updated Connection
+	if err := c.put(ctx, pathf("/v1/connections/%s", conn.ID), connectionBody(conn), &updated); err != nil {
+		return nil, fmt.Errorf("error updating connection: %w", err)
+	}
+	return &updated, nil
+}
+
+// DeleteConnection deletes the webhook connection with the ID. Connections
+// used by monitors cannot be deleted.
+func (c *Client) DeleteConnection(ctx context.Context, id string) error {
+	r := request{
+		method: http.MethodDelete,
+		path:   pathf("/v1/connections/%s", id),
+		query:  url.Values{"type": {ConnectionWebhook}},
+	}
+	if _, err := c.do(ctx, r); err != nil {
+		return fmt.Errorf("error deleting connection: %w", err)
+	}
+	return nil
+}
+
+// TestConnection sends a test notification with the connection, which need
+// not have been created yet, and returns how its endpoint responded. An
+// endpoint responding with an error is not an error of TestConnection, so
+// check ConnectionTestResult.OK.
+func (c *Client) TestConnection(ctx context.Context, conn Connection) (*ConnectionTestResult, error) {
+	var result ConnectionTestResult
+	if err := c.post(ctx, "/v1/connections/test", connectionBody(conn), &result); err != nil {
+		return nil, fmt.Errorf("error testing connection: %w", err)
+	}
+	return &result, nil
+}
+
+// connectionBody returns the fields of the connection that can be set, and
+// the properties in Extra, filling in the default types.
+func connectionBody(conn Connection) any {
+	if conn.Type == "" {
+		conn.Type = ConnectionWebhook
+	}
+	if conn.WebhookType == "" && conn.Type == ConnectionWebhook {
+		conn.WebhookType = WebhookGeneric
+	}
+	return withExtra(struct {
+		Name              string             `json:"name"`
+		Description       string             `json:"description,omitempty"`
+		Type              string             `json:"type"`
+		WebhookType       string             `json:"webhookType,omitempty"`
+		URL               string             `json:"url"`
+		Headers           []ConnectionHeader `json:"headers,omitempty"`
+		CustomHeaders     []ConnectionHeader `json:"customHeaders,omitempty"`
+		DefaultPayload    string             `json:"defaultPayload"`
+		ResolutionPayload string             `json:"resolutionPayload,omitempty"`
+		ConnectionSubtype string             `json:"connectionSubtype,omitempty"`
+	}{
+		conn.Name, conn.Description, conn.Type, conn.WebhookType, conn.URL, conn.Headers,
+		conn.CustomHeaders, conn.DefaultPayload, conn.ResolutionPayload, conn.ConnectionSubtype,
+	}, conn.Extra)
+}
@@ -0,0 +1,116 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestConnections(t *testing.T) {
+	ctx := context.Background()
+	conn := `{"id": "c1", "name": "ops", "type": "WebhookConnection", "webhookType": "Slack", "url": "https://hooks.example.com/1", "defaultPayload": "{}", "headers": [{"name": "Authorization", "value": "Bearer x", "secret": true}], "connectionSubtype": "Incident", "isDeprecated": false}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				conns, err := c.ListConnections(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(len(conns), 1)
+			},
+			response: `{"data": [` + conn + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/connections",
+			query:    "limit=100",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				got, err := c.GetConnection(ctx, "c1")
+				if err != nil {
+					return err
+				}
+				if err := expect(string(got.Extra["isDeprecated"]), "false"); err != nil {
+					return err
+				}
+				return expect(string(got.Headers[0].Extra["secret"]), "true")
+			},
+			response: conn,
+			method:   http.MethodGet,
+			path:     "/v1/connections/c1",
+			query:    "type=WebhookConnection",
+		},
+		{
+			name: "create with default types",
+			call: func(c *Client) error {
+				_, err := c.CreateConnection(ctx, Connection{Name: "ops", URL: "https://hooks.example.com/1", DefaultPayload: "{}"})
+				return err
+			},
+			response: conn,
+			method:   http.MethodPost,
+			path:     "/v1/connections",
+			body:     `{"name": "ops", "type": "WebhookConnection", "webhookType": "Webhook", "url": "https://hooks.example.com/1", "defaultPayload": "{}"}`,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateConnection(ctx, Connection{
+					ID:             "c1",
+					Name:           "ops",
+					Type:           ConnectionServiceNow,
+					URL:            "https://example.service-now.com",
+					DefaultPayload: "{}",
+					Extra:          map[string]json.RawMessage{"isDeprecated": json.RawMessage("false")},
+				})
+				return err
+			},
+			response: conn,
+			method:   http.MethodPut,
+			path:     "/v1/connections/c1",
+			// ServiceNow connections have no webhook type.
+			body: `{"name": "ops", "type": "ServiceNowConnection", "url": "https://example.service-now.com", "defaultPayload": "{}", "isDeprecated": false}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteConnection(ctx, "c1") },
+			method: http.MethodDelete,
+			path:   "/v1/connections/c1",
+			query:  "type=WebhookConnection",
+		},
+	})
+}
+
+func TestCreateConnectionRequiresURL(t *testing.T) {
+	api := newTestAPI(t)
+	if _, err := api.client().CreateConnection(context.Background(), Connection{Name: "ops"}); err == nil {
+		t.Error("CreateConnection without a url succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
+
+func TestTestConnection(t *testing.T) {
+	tests := []struct {
+		response string
+		wantOK   bool
+	}{
+		{`{"statusCode": 200, "responseContent": "ok"}`, true},
+		{`{"statusCode": 403, "responseContent": "forbidden"}`, false},
+	}
+	for _, tt := range tests {
+		api := newTestAPI(t)
+		api.respond("POST /v1/connections/test", tt.response)
+		result, err := api.client().TestConnection(context.Background(), Connection{Name: "ops", WebhookType: WebhookSlack, URL: "https://hooks.example.com/1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.OK() != tt.wantOK {
+			t.Errorf("OK of %+v = %v, want %v", result, result.OK(), tt.wantOK)
+		}
+		checkRequest(t, api.last(), http.MethodPost, "/v1/connections/test",
+			`{"name": "ops", "type": "WebhookConnection", "webhookType": "Slack", "url": "https://hooks.example.com/1", "defaultPayload": ""}`)
+	}
+}
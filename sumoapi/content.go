@@ -0,0 +1,296 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrContentJobFailed is returned, wrapped with the reason, when an
+// asynchronous content job such as an export or import fails.
+var ErrContentJobFailed = errors.New("content job failed")
+
+// Types of the items in the content library.
+const (
+	ContentFolder    = "Folder"
+	ContentSearch    = "Search"
+	ContentDashboard = "Dashboard"
+	ContentReport    = "Report"
+	ContentLookup    = "Lookups"
+)
+
+// ContentItem is an item in the content library, such as a folder, saved
+// search or dashboard.
+type ContentItem struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// ItemType is one of the Content constants.
+	ItemType string `json:"itemType"`
+	ParentID string `json:"parentId,omitempty"`
+	// Permissions lists what the user the Client authenticates as can do
+	// with the item, such as "View" or "Edit".
+	Permissions []string  `json:"permissions,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CreatedBy   string    `json:"createdBy,omitempty"`
+	ModifiedAt  time.Time `json:"modifiedAt"`
+	ModifiedBy  string    `json:"modifiedBy,omitempty"`
+
+	// Children are the items in a folder.
+	Children []ContentItem `json:"children,omitempty"`
+
+	// Extra holds the properties of the content item that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the content item, keeping the properties that have
+// no field in Extra.
+func (i *ContentItem) UnmarshalJSON(b []byte) error {
+	type plain ContentItem
+	return unmarshalExtra(b, (*plain)(i), &i.Extra)
+}
+
+// MarshalJSON encodes the content item along with the properties in Extra.
+func (i ContentItem) MarshalJSON() ([]byte, error) {
+	type plain ContentItem
+	return marshalExtra(plain(i), i.Extra)
+}
+
+// IsFolder reports whether the item is a folder.
+func (i ContentItem) IsFolder() bool {
+	return i.ItemType == ContentFolder
+}
+
+// Statuses of an asynchronous content job.
+const (
+	ContentJobInProgress = "InProgress"
+	ContentJobSuccess    = "Success"
+	ContentJobFailed     = "Failed"
+)
+
+// ContentJobStatus is the progress of an asynchronous content job. App
+// install, upgrade and uninstall jobs report their progress the same way.
+type ContentJobStatus struct {
+	Status        string       `json:"status"`
+	StatusMessage string       `json:"statusMessage,omitempty"`
+	Error         *ErrorDetail `json:"error,omitempty"`
+}
+
+// contentJob is the response that starts an asynchronous content job.
+type contentJob struct {
+	ID string `json:"id"`
+}
+
+// PersonalFolder returns the personal folder of the user the Client
+// authenticates as, including its children.
+func (c *Client) PersonalFolder(ctx context.Context) (*ContentItem, error) {
+	var f ContentItem
+	if err := c.get(ctx, "/v2/content/folders/personal", nil, &f); err != nil {
+		return nil, fmt.Errorf("error getting personal folder: %w", err)
+	}
+	return &f, nil
+}
+
+// AdminRecommendedFolder returns the Admin Recommended folder, including its
+// children. The folder is read with an asynchronous job, which this waits
+// for.
+func (c *Client) AdminRecommendedFolder(ctx context.Context) (*ContentItem, error) {
+	var job contentJob
+	if err := c.get(ctx, "/v2/content/folders/adminRecommended", nil, &job); err != nil {
+		return nil, fmt.Errorf("error getting admin recommended folder: %w", err)
+	}
+	base := pathf("/v2/content/folders/adminRecommended/%s", job.ID)
+	if err := c.waitJob(ctx, ErrContentJobFailed, base+"/status"); err != nil {
+		return nil, fmt.Errorf("error getting admin recommended folder: %w", err)
+	}
+	var f ContentItem
+	if err := c.get(ctx, base+"/result", nil, &f); err != nil {
+		return nil, fmt.Errorf("error getting admin recommended folder: %w", err)
+	}
+	return &f, nil
+}
+
+// GetFolder returns the folder with the ID, including its children.
+func (c *Client) GetFolder(ctx context.Context, id string) (*ContentItem, error) {
+	var f ContentItem
+	if err := c.get(ctx, pathf("/v2/content/folders/%s", id), nil, &f); err != nil {
+		return nil, fmt.Errorf("error getting folder: %w", err)
+	}
+	return &f, nil
+}
+
+// CreateFolder creates a folder in the folder with the parent ID and returns
+// it.
+func (c *Client) CreateFolder(ctx context.Context, parentID, name, description string) (*ContentItem, error) {
+	body := struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		ParentID    string `json:"parentId"`
+	}{name, description, parentID}
+	var f ContentItem
+	if err := c.post(ctx, "/v2/content/folders", body, &f); err != nil {
+		return nil, fmt.Errorf("error creating folder: %w", err)
+	}
+	return &f, nil
+}
+
+// UpdateFolder renames the folder with the ID and returns the updated
+// folder.
+func (c *Client) UpdateFolder(ctx context.Context, id, name, description string) (*ContentItem, error) {
+	body := struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+	}{name, description}
+	var f ContentItem
+	if err := c.put(ctx, pathf("/v2/content/folders/%s", id), body, &f); err != nil {
+		return nil, fmt.Errorf("error updating folder: %w", err)
+	}
+	return &f, nil
+}
+
+// GetContentByPath returns the item at the path in the content library,
+// such as /Library/Users/user@example.com/Dashboards.
+func (c *Client) GetContentByPath(ctx context.Context, path string) (*ContentItem, error) {
+	var item ContentItem
+	if err := c.get(ctx, "/v2/content/path", url.Values{"path": {path}}, &item); err != nil {
+		return nil, fmt.Errorf("error getting content: %w", err)
+	}
+	return &item, nil
+}
+
+// ContentPath returns the path of the item with the ID in the content
+// library.
+func (c *Client) ContentPath(ctx context.Context, id string) (string, error) {
+	var resp struct {
+		Path string `json:"path"`
+	}
+	if err := c.get(ctx, pathf("/v2/content/%s/path", id), nil, &resp); err != nil {
+		return "", fmt.Errorf("error getting content path: %w", err)
+	}
+	return resp.Path, nil
+}
+
+// ExportContent exports the item with the ID, including everything in it if
+// it is a folder, and returns its JSON definition, which can be kept in
+// version control and passed to ImportContent. The export runs as an
+// asynchronous job, which this waits for.
+func (c *Client) ExportContent(ctx context.Context, id string) (json.RawMessage, error) {
+	var job contentJob
+	if err := c.post(ctx, pathf("/v2/content/%s/export", id), nil, &job); err != nil {
+		return nil, fmt.Errorf("error exporting content: %w", err)
+	}
+	base := pathf("/v2/content/%s/export/%s", id, job.ID)
+	if err := c.waitJob(ctx, ErrContentJobFailed, base+"/status"); err != nil {
+		return nil, fmt.Errorf("error exporting content: %w", err)
+	}
+	var def json.RawMessage
+	if err := c.get(ctx, base+"/result", nil, &def); err != nil {
+		return nil, fmt.Errorf("error exporting content: %w", err)
+	}
+	return def, nil
+}
+
+// ImportContent imports a definition returned by ExportContent into the
+// folder with the ID. If overwrite is true an item with the same name in the
+// folder is replaced, and otherwise the import fails. The import runs as an
+// asynchronous job, which this waits for.
+func (c *Client) ImportContent(ctx context.Context, folderID string, def json.RawMessage, overwrite bool) error {
+	var job contentJob
+	r := request{
+		method: http.MethodPost,
+		path:   pathf("/v2/content/folders/%s/import", folderID),
+		query:  url.Values{"overwrite": {strconv.FormatBool(overwrite)}},
+		body:   def,
+		out:    &job,
+	}
+	if _, err := c.do(ctx, r); err != nil {
+		return fmt.Errorf("error importing content: %w", err)
+	}
+	if err := c.waitJob(ctx, ErrContentJobFailed, pathf("/v2/content/folders/%s/import/%s/status", folderID, job.ID)); err != nil {
+		return fmt.Errorf("error importing content: %w", err)
+	}
+	return nil
+}
+
+// MoveContent moves the item with the ID into the destination folder.
+func (c *Client) MoveContent(ctx context.Context, id, destinationFolderID string) error {
+	r := request{
+		method: http.MethodPost,
+		path:   pathf("/v2/content/%s/move", id),
+		query:  url.Values{"destinationFolderId": {destinationFolderID}},
+	}
+	if _, err := c.do(ctx, r); err != nil {
+		return fmt.Errorf("error moving content: %w", err)
+	}
+	return nil
+}
+
+// CopyContent copies the item with the ID into the destination folder and
+// returns the copy. The copy runs as an asynchronous job, which this waits
+// for.
+func (c *Client) CopyContent(ctx context.Context, id, destinationFolderID string) (*ContentItem, error) {
+	var job contentJob
+	r := request{
+		method: http.MethodPost,
+		path:   pathf("/v2/content/%s/copy", id),
+		query:  url.Values{"destinationFolder": {destinationFolderID}},
+		out:    &job,
+	}
+	if _, err := c.do(ctx, r); err != nil {
+		return nil, fmt.Errorf("error copying content: %w", err)
+	}
+	base := pathf("/v2/content/%s/copy/%s", id, job.ID)
+	if err := c.waitJob(ctx, ErrContentJobFailed, base+"/status"); err != nil {
+		return nil, fmt.Errorf("error copying content: %w", err)
+	}
+	var item ContentItem
+	if err := c.get(ctx, base+"/result", nil, &item); err != nil {
+		return nil, fmt.Errorf("error copying content: %w", err)
+	}
+	return &item, nil
+}
+
+// DeleteContent deletes the item with the ID, including everything in it if
+// it is a folder. The deletion runs as an asynchronous job, which this waits
+// for.
+func (c *Client) DeleteContent(ctx context.Context, id string) error {
+	var job contentJob
+	if _, err := c.do(ctx, request{method: http.MethodDelete, path: pathf("/v2/content/%s/delete", id), out: &job}); err != nil {
+		return fmt.Errorf("error deleting content: %w", err)
+	}
+	if err := c.waitJob(ctx, ErrContentJobFailed, pathf("/v2/content/%s/delete/%s/status", id, job.ID)); err != nil {
+		return fmt.Errorf("error deleting content: %w", err)
+	}
+	return nil
+}
+
+// waitJob polls the status of an asynchronous job, such as a content export
+// or an app install, until it is no longer in progress, and returns an error
+// wrapping failed if it failed.
+func (c *Client) waitJob(ctx context.Context, failed error, statusPath string) error {
+	for {
+		var status ContentJobStatus
+		if err := c.get(ctx, statusPath, nil, &status); err != nil {
+			return err
+		}
+		switch status.Status {
+		case ContentJobSuccess:
+			return nil
+		case ContentJobFailed:
+			reason := status.StatusMessage
+			if status.Error != nil && status.Error.Message != "" {
+				reason = status.Error.Message
+			}
+			return fmt.Errorf("%w: %s", failed, reason)
+		}
+		if err := c.wait(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+// serveJob serves the status of an asynchronous job at the path, which is in
+// progress on the first poll and then has the final status.
+func serveJob(api *testAPI, path, final string) {
+	var n atomic.Int32
+	api.handle("GET "+path, func(w http.ResponseWriter, r *http.Request) {
+		if n.Add(1) == 1 {
+			io.WriteString(w, `{"status": "InProgress"}`)
+			return
+		}
+		io.WriteString(w, final)
+	})
+}
+
+// checkPaths checks the methods and paths of the requests.
+func checkPaths(t *testing.T, reqs []apiRequest, want ...string) {
+	t.Helper()
+	got := make([]string, len(reqs))
+	for i, r := range reqs {
+		got[i] = r.method + " " + r.path
+	}
+	if err := expect(got, want); err != nil {
+		t.Errorf("requests: %v", err)
+	}
+}
+
+func TestContentFolders(t *testing.T) {
+	ctx := context.Background()
+	folder := `{"id": "f1", "name": "Web", "itemType": "Folder", "parentId": "p1", "children": [{"id": "d1", "name": "Latency", "itemType": "Dashboard"}], "isPublic": false}`
+	testCalls(t, []apiCall{
+		{
+			name: "personal folder",
+			call: func(c *Client) error {
+				f, err := c.PersonalFolder(ctx)
+				if err != nil {
+					return err
+				}
+				if err := expect(f.IsFolder() && len(f.Children) == 1, true); err != nil {
+					return err
+				}
+				return expect(string(f.Extra["isPublic"]), "false")
+			},
+			response: folder,
+			method:   http.MethodGet,
+			path:     "/v2/content/folders/personal",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				_, err := c.GetFolder(ctx, "f1")
+				return err
+			},
+			response: folder,
+			method:   http.MethodGet,
+			path:     "/v2/content/folders/f1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateFolder(ctx, "p1", "Web", "web content")
+				return err
+			},
+			response: folder,
+			method:   http.MethodPost,
+			path:     "/v2/content/folders",
+			body:     `{"name": "Web", "description": "web content", "parentId": "p1"}`,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateFolder(ctx, "f1", "Web", "")
+				return err
+			},
+			response: folder,
+			method:   http.MethodPut,
+			path:     "/v2/content/folders/f1",
+			body:     `{"name": "Web"}`,
+		},
+		{
+			name: "by path",
+			call: func(c *Client) error {
+				_, err := c.GetContentByPath(ctx, "/Library/Users/ada@example.com/Web")
+				return err
+			},
+			response: folder,
+			method:   http.MethodGet,
+			path:     "/v2/content/path",
+			query:    "path=%2FLibrary%2FUsers%2Fada%40example.com%2FWeb",
+		},
+		{
+			name: "path",
+			call: func(c *Client) error {
+				path, err := c.ContentPath(ctx, "f1")
+				if err != nil {
+					return err
+				}
+				return expect(path, "/Library/Web")
+			},
+			response: `{"path": "/Library/Web"}`,
+			method:   http.MethodGet,
+			path:     "/v2/content/f1/path",
+		},
+		{
+			name:   "move",
+			call:   func(c *Client) error { return c.MoveContent(ctx, "d1", "f2") },
+			method: http.MethodPost,
+			path:   "/v2/content/d1/move",
+			query:  "destinationFolderId=f2",
+		},
+	})
+}
+
+func TestAdminRecommendedFolder(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("GET /v2/content/folders/adminRecommended", `{"id": "j1"}`)
+	serveJob(api, "/v2/content/folders/adminRecommended/j1/status", `{"status": "Success"}`)
+	api.respond("GET /v2/content/folders/adminRecommended/j1/result", `{"id": "f1", "name": "Admin Recommended", "itemType": "Folder"}`)
+	f, err := api.client().AdminRecommendedFolder(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.ID != "f1" {
+		t.Errorf("AdminRecommendedFolder = %+v", f)
+	}
+	checkPaths(t, api.received(),
+		"GET /v2/content/folders/adminRecommended",
+		"GET /v2/content/folders/adminRecommended/j1/status",
+		"GET /v2/content/folders/adminRecommended/j1/status",
+		"GET /v2/content/folders/adminRecommended/j1/result",
+	)
+}
+
+func TestExportContent(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v2/content/d1/export", `{"id": "j1"}`)
+	serveJob(api, "/v2/content/d1/export/j1/status", `{"status": "Success"}`)
+	api.respond("GET /v2/content/d1/export/j1/result", `{"type": "DashboardV2SyncDefinition", "name": "Latency"}`)
+	def, err := api.client().ExportContent(context.Background(), "d1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkJSON(t, string(def), `{"type": "DashboardV2SyncDefinition", "name": "Latency"}`)
+	checkPaths(t, api.received(),
+		"POST /v2/content/d1/export",
+		"GET /v2/content/d1/export/j1/status",
+		"GET /v2/content/d1/export/j1/status",
+		"GET /v2/content/d1/export/j1/result",
+	)
+}
+
+func TestImportContent(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v2/content/folders/f1/import", `{"id": "j1"}`)
+	serveJob(api, "/v2/content/folders/f1/import/j1/status", `{"status": "Failed", "error": {"code": "content:duplicate", "message": "content already exists"}}`)
+	def := json.RawMessage(`{"type": "DashboardV2SyncDefinition", "name": "Latency"}`)
+	err := api.client().ImportContent(context.Background(), "f1", def, false)
+	if !errors.Is(err, ErrContentJobFailed) {
+		t.Fatalf("ImportContent of a failed job = %v, want ErrContentJobFailed", err)
+	}
+	if want := "error importing content: content job failed: content already exists"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+	r := api.received()[0]
+	checkRequest(t, r, http.MethodPost, "/v2/content/folders/f1/import", string(def))
+	if got := r.query.Encode(); got != "overwrite=false" {
+		t.Errorf("query = %s", got)
+	}
+}
+
+func TestCopyContent(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v2/content/d1/copy", `{"id": "j1"}`)
+	serveJob(api, "/v2/content/d1/copy/j1/status", `{"status": "Success"}`)
+	api.respond("GET /v2/content/d1/copy/j1/result", `{"id": "d2", "name": "Latency", "itemType": "Dashboard"}`)
+	item, err := api.client().CopyContent(context.Background(), "d1", "f2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != "d2" {
+		t.Errorf("CopyContent = %+v, want the copy", item)
+	}
+	if got := api.received()[0].query.Encode(); got != "destinationFolder=f2" {
+		t.Errorf("query = %s", got)
+	}
+}
+
+func TestDeleteContent(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("DELETE /v2/content/d1/delete", `{"id": "j1"}`)
+	serveJob(api, "/v2/content/d1/delete/j1/status", `{"status": "Success"}`)
+	if err := api.client().DeleteContent(context.Background(), "d1"); err != nil {
+		t.Fatal(err)
+	}
+	checkPaths(t, api.received(),
+		"DELETE /v2/content/d1/delete",
+		"GET /v2/content/d1/delete/j1/status",
+		"GET /v2/content/d1/delete/j1/status",
+	)
+}
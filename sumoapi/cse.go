@@ -0,0 +1,101 @@
+package sumoapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// The Cloud SIEM (CSE) API is served under /sec/v1 by the same deployment
+// and credentials as the rest of the management API. Its responses wrap
+// their content in a data field, and its lists are paginated by offset.
+
+// cseMaxPageSize is the largest page the CSE API returns.
+const cseMaxPageSize = 100
+
+// CSETime is a time returned by the CSE API, which omits the time zone of
+// times in UTC.
+type CSETime struct {
+	time.Time
+}
+
+// cseTimeLayout is the layout of times without a time zone.
+const cseTimeLayout = "2006-01-02T15:04:05.999999999"
+
+// UnmarshalJSON parses the time as RFC 3339, or as UTC if it has no time
+// zone.
+func (t *CSETime) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		t.Time = time.Time{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+	if parsed, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		t.Time = parsed
+		return nil
+	}
+	parsed, err := time.Parse(cseTimeLayout, s)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// MarshalJSON formats the time as RFC 3339, or null for the zero time.
+func (t CSETime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.UTC().Format(time.RFC3339Nano))
+}
+
+// cseResponse is the envelope of a CSE API response.
+type cseResponse[T any] struct {
+	Data T `json:"data"`
+}
+
+// csePage is a page of a CSE API list.
+type csePage[T any] struct {
+	Objects     []T  `json:"objects"`
+	Total       int  `json:"total"`
+	HasNextPage bool `json:"hasNextPage"`
+}
+
+// cseIterate returns an Iterator over a CSE API list that is paginated by
+// offset, requesting pages until one has no next page.
+func cseIterate[T any](ctx context.Context, c *Client, op, path string, query url.Values) *Iterator[T] {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("limit", strconv.Itoa(cseMaxPageSize))
+	return newIterator(ctx, op, func(ctx context.Context, cursor string) ([]T, string, error) {
+		offset := 0
+		if cursor != "" {
+			var err error
+			if offset, err = strconv.Atoi(cursor); err != nil {
+				return nil, "", err
+			}
+		}
+		q.Set("offset", strconv.Itoa(offset))
+		var resp cseResponse[csePage[T]]
+		if err := c.get(ctx, path, q, &resp); err != nil {
+			return nil, "", err
+		}
+		if !resp.Data.HasNextPage || len(resp.Data.Objects) == 0 {
+			return resp.Data.Objects, "", nil
+		}
+		return resp.Data.Objects, strconv.Itoa(offset + len(resp.Data.Objects)), nil
+	})
+}
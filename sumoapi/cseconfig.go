@@ -0,0 +1,372 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+)
+
+// CustomInsight raises an insight when an entity has signals from the rules
+// or with the names it lists, instead of waiting for its activity score to
+// exceed the threshold.
+type CustomInsight struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	// Ordered requires the signals to occur in the order of RuleIDs or
+	// SignalNames.
+	Ordered     bool     `json:"ordered"`
+	RuleIDs     []string `json:"ruleIds"`
+	SignalNames []string `json:"signalNames"`
+	// Severity is one of the InsightSeverity constants.
+	Severity string   `json:"severity"`
+	Tags     []string `json:"tags"`
+
+	// Extra holds the properties of the custom insight that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the custom insight, keeping the properties that have
+// no field in Extra.
+func (i *CustomInsight) UnmarshalJSON(b []byte) error {
+	type plain CustomInsight
+	return unmarshalExtra(b, (*plain)(i), &i.Extra)
+}
+
+// MarshalJSON encodes the custom insight along with the properties in Extra.
+func (i CustomInsight) MarshalJSON() ([]byte, error) {
+	type plain CustomInsight
+	return marshalExtra(plain(i), i.Extra)
+}
+
+// Entity is an entity signals are raised for, such as a host, an IP address
+// or a user.
+type Entity struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	EntityType string `json:"entityType"`
+	Value      string `json:"value"`
+	// Criticality is the name of the criticality of the entity, which
+	// adjusts the severity of its signals.
+	Criticality string   `json:"criticality,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// IsSuppressed entities have their signals suppressed.
+	IsSuppressed  bool    `json:"isSuppressed"`
+	ActivityScore float64 `json:"activityScore"`
+	FirstSeen     CSETime `json:"firstSeen"`
+	LastSeen      CSETime `json:"lastSeen"`
+
+	// Extra holds the properties of the entity that have no field, which are
+	// kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the entity, keeping the properties that have no
+// field in Extra.
+func (e *Entity) UnmarshalJSON(b []byte) error {
+	type plain Entity
+	return unmarshalExtra(b, (*plain)(e), &e.Extra)
+}
+
+// MarshalJSON encodes the entity along with the properties in Extra.
+func (e Entity) MarshalJSON() ([]byte, error) {
+	type plain Entity
+	return marshalExtra(plain(e), e.Extra)
+}
+
+// EntityCriticality is a criticality that can be given to entities, which
+// adjusts the severity of their signals.
+type EntityCriticality struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	// SeverityExpression computes the adjusted severity from the severity
+	// of a signal, such as severity + 2.
+	SeverityExpression string `json:"severityExpression"`
+
+	// Extra holds the properties of the entity criticality that have no
+	// field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the entity criticality, keeping the properties that
+// have no field in Extra.
+func (c *EntityCriticality) UnmarshalJSON(b []byte) error {
+	type plain EntityCriticality
+	return unmarshalExtra(b, (*plain)(c), &c.Extra)
+}
+
+// MarshalJSON encodes the entity criticality along with the properties in
+// Extra.
+func (c EntityCriticality) MarshalJSON() ([]byte, error) {
+	type plain EntityCriticality
+	return marshalExtra(plain(c), c.Extra)
+}
+
+// NetworkBlock is a range of IP addresses, labeled to give context to the
+// addresses in records.
+type NetworkBlock struct {
+	ID string `json:"id,omitempty"`
+	// AddressBlock is the CIDR range, such as 10.0.0.0/8.
+	AddressBlock string `json:"addressBlock"`
+	Label        string `json:"label"`
+	// Internal marks the addresses as those of the organization.
+	Internal bool `json:"internal"`
+	// SuppressesSignals suppresses the signals of the addresses.
+	SuppressesSignals bool `json:"suppressesSignals"`
+
+	// Extra holds the properties of the network block that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the network block, keeping the properties that have
+// no field in Extra.
+func (b *NetworkBlock) UnmarshalJSON(data []byte) error {
+	type plain NetworkBlock
+	return unmarshalExtra(data, (*plain)(b), &b.Extra)
+}
+
+// MarshalJSON encodes the network block along with the properties in Extra.
+func (b NetworkBlock) MarshalJSON() ([]byte, error) {
+	type plain NetworkBlock
+	return marshalExtra(plain(b), b.Extra)
+}
+
+// ListCustomInsights returns every custom insight.
+func (c *Client) ListCustomInsights(ctx context.Context) ([]CustomInsight, error) {
+	var resp cseResponse[[]CustomInsight]
+	if err := c.get(ctx, "/sec/v1/custom-insights", nil, &resp); err != nil {
+		return nil, fmt.Errorf("error listing custom insights: %w", err)
+	}
+	return resp.Data, nil
+}
+
+// GetCustomInsight returns the custom insight with the ID.
+func (c *Client) GetCustomInsight(ctx context.Context, id string) (*CustomInsight, error) {
+	var resp cseResponse[CustomInsight]
+	if err := c.get(ctx, pathf("/sec/v1/custom-insights/%s", id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("error getting custom insight: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// CreateCustomInsight creates a custom insight and returns it.
+func (c *Client) CreateCustomInsight(ctx context.Context, ci CustomInsight) (*CustomInsight, error) {
+	if ci.Name == "" || ci.Severity == "" {
+		return nil, fmt.Errorf("error creating custom insight: name and severity are required")
+	}
+	var resp cseResponse[CustomInsight]
+	if err := c.post(ctx, "/sec/v1/custom-insights", customInsightBody(ci), &resp); err != nil {
+		return nil, fmt.Errorf("error creating custom insight: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// UpdateCustomInsight replaces the custom insight with the same ID and
+// returns the updated custom insight.
+func (c *Client) UpdateCustomInsight(ctx context.Context, ci CustomInsight) (*CustomInsight, error) {
+	var resp cseResponse[CustomInsight]
+	if err := c.put(ctx, pathf("/sec/v1/custom-insights/%s", ci.ID), customInsightBody(ci), &resp); err != nil {
+		return nil, fmt.Errorf("error updating custom insight: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// DeleteCustomInsight deletes the custom insight with the ID.
+func (c *Client) DeleteCustomInsight(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/sec/v1/custom-insights/%s", id)); err != nil {
+		return fmt.Errorf("error deleting custom insight: %w", err)
+	}
+	return nil
+}
+
+// ListEntities returns the entities matching the query, such as
+// entityType:"_hostname", or every entity if it is empty, following
+// pagination.
+func (c *Client) ListEntities(ctx context.Context, query string) ([]Entity, error) {
+	return Collect(c.IterateEntities(ctx, query))
+}
+
+// IterateEntities returns an Iterator over the entities returned by
+// ListEntities, requesting each page as it is read.
+func (c *Client) IterateEntities(ctx context.Context, query string) *Iterator[Entity] {
+	var q url.Values
+	if query != "" {
+		q = url.Values{"q": {query}}
+	}
+	return cseIterate[Entity](ctx, c, "listing entities", "/sec/v1/entities", q)
+}
+
+// GetEntity returns the entity with the ID.
+func (c *Client) GetEntity(ctx context.Context, id string) (*Entity, error) {
+	var resp cseResponse[Entity]
+	if err := c.get(ctx, pathf("/sec/v1/entities/%s", id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("error getting entity: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// LookupEntity returns the entity of the type with the value, such as the
+// _ip entity of 10.0.0.1. An error matching ErrNotFound is returned if there
+// is none.
+func (c *Client) LookupEntity(ctx context.Context, entityType, value string) (*Entity, error) {
+	query := fmt.Sprintf("entityType:%q value:%q", entityType, value)
+	entities, err := c.ListEntities(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entities {
+		if e.EntityType == entityType && e.Value == value {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("error looking up entity: %s %q: %w", entityType, value, ErrNotFound)
+}
+
+// SetEntityCriticality gives the entity with the ID the criticality with the
+// name, or removes its criticality if the name is empty, and returns the
+// updated entity.
+func (c *Client) SetEntityCriticality(ctx context.Context, id, criticality string) (*Entity, error) {
+	body := struct {
+		Criticality *string `json:"criticality"`
+	}{}
+	if criticality != "" {
+		body.Criticality = &criticality
+	}
+	var resp cseResponse[Entity]
+	if err := c.put(ctx, pathf("/sec/v1/entities/%s/criticality", id), body, &resp); err != nil {
+		return nil, fmt.Errorf("error setting entity criticality: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// ListEntityCriticalities returns every criticality that can be given to
+// entities.
+func (c *Client) ListEntityCriticalities(ctx context.Context) ([]EntityCriticality, error) {
+	var resp cseResponse[[]EntityCriticality]
+	if err := c.get(ctx, "/sec/v1/entity-criticality-configs", nil, &resp); err != nil {
+		return nil, fmt.Errorf("error listing entity criticalities: %w", err)
+	}
+	return resp.Data, nil
+}
+
+// CreateEntityCriticality creates a criticality that can be given to
+// entities and returns it.
+func (c *Client) CreateEntityCriticality(ctx context.Context, name, severityExpression string) (*EntityCriticality, error) {
+	if name == "" || severityExpression == "" {
+		return nil, fmt.Errorf("error creating entity criticality: name and severity expression are required")
+	}
+	body := struct {
+		Fields EntityCriticality `json:"fields"`
+	}{EntityCriticality{Name: name, SeverityExpression: severityExpression}}
+	var resp cseResponse[EntityCriticality]
+	if err := c.post(ctx, "/sec/v1/entity-criticality-configs", body, &resp); err != nil {
+		return nil, fmt.Errorf("error creating entity criticality: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// UpdateEntityCriticality changes the severity expression of the
+// criticality with the ID and returns the updated criticality.
+func (c *Client) UpdateEntityCriticality(ctx context.Context, id, severityExpression string) (*EntityCriticality, error) {
+	body := struct {
+		Fields struct {
+			SeverityExpression string `json:"severityExpression"`
+		} `json:"fields"`
+	}{}
+	body.Fields.SeverityExpression = severityExpression
+	var resp cseResponse[EntityCriticality]
+	if err := c.put(ctx, pathf("/sec/v1/entity-criticality-configs/%s", id), body, &resp); err != nil {
+		return nil, fmt.Errorf("error updating entity criticality: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// DeleteEntityCriticality deletes the criticality with the ID.
+func (c *Client) DeleteEntityCriticality(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/sec/v1/entity-criticality-configs/%s", id)); err != nil {
+		return fmt.Errorf("error deleting entity criticality: %w", err)
+	}
+	return nil
+}
+
+// ListNetworkBlocks returns every network block, following pagination.
+func (c *Client) ListNetworkBlocks(ctx context.Context) ([]NetworkBlock, error) {
+	return Collect(c.IterateNetworkBlocks(ctx))
+}
+
+// IterateNetworkBlocks returns an Iterator over the network blocks returned
+// by ListNetworkBlocks, requesting each page as it is read.
+func (c *Client) IterateNetworkBlocks(ctx context.Context) *Iterator[NetworkBlock] {
+	return cseIterate[NetworkBlock](ctx, c, "listing network blocks", "/sec/v1/network-blocks", nil)
+}
+
+// GetNetworkBlock returns the network block with the ID.
+func (c *Client) GetNetworkBlock(ctx context.Context, id string) (*NetworkBlock, error) {
+	var resp cseResponse[NetworkBlock]
+	if err := c.get(ctx, pathf("/sec/v1/network-blocks/%s", id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("error getting network block: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// CreateNetworkBlock creates a network block and returns it.
+func (c *Client) CreateNetworkBlock(ctx context.Context, block NetworkBlock) (*NetworkBlock, error) {
+	if _, _, err := net.ParseCIDR(block.AddressBlock); err != nil {
+		return nil, fmt.Errorf("error creating network block: invalid address block %q", block.AddressBlock)
+	}
+	var resp cseResponse[NetworkBlock]
+	if err := c.post(ctx, "/sec/v1/network-blocks", networkBlockBody(block), &resp); err != nil {
+		return nil, fmt.Errorf("error creating network block: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// UpdateNetworkBlock replaces the network block with the same ID and returns
+// the updated network block.
+func (c *Client) UpdateNetworkBlock(ctx context.Context, block NetworkBlock) (*NetworkBlock, error) {
+	var resp cseResponse[NetworkBlock]
+	if err := c.put(ctx, pathf("/sec/v1/network-blocks/%s", block.ID), networkBlockBody(block), &resp); err != nil {
+		return nil, fmt.Errorf("error updating network block: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// DeleteNetworkBlock deletes the network block with the ID.
+func (c *Client) DeleteNetworkBlock(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/sec/v1/network-blocks/%s", id)); err != nil {
+		return fmt.Errorf("error deleting network block: %w", err)
+	}
+	return nil
+}
+
+// customInsightBody returns the custom insight without its ID, wrapped as
+// the CSE API expects.
+func customInsightBody(ci CustomInsight) any {
+	ci.ID = ""
+	if ci.RuleIDs == nil {
+		ci.RuleIDs = []string{}
+	}
+	if ci.SignalNames == nil {
+		ci.SignalNames = []string{}
+	}
+	if ci.Tags == nil {
+		ci.Tags = []string{}
+	}
+	return struct {
+		Fields CustomInsight `json:"fields"`
+	}{ci}
+}
+
+// networkBlockBody returns the network block without its ID, wrapped as the
+// CSE API expects.
+func networkBlockBody(block NetworkBlock) any {
+	block.ID = ""
+	return struct {
+		Fields NetworkBlock `json:"fields"`
+	}{block}
+}
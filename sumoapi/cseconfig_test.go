@@ -0,0 +1,260 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestCustomInsights(t *testing.T) {
+	ctx := context.Background()
+	insight := `{"data": {"id": "ci1", "name": "Lateral movement", "description": "", "enabled": true, "ordered": true, "ruleIds": ["USER-1"], "signalNames": [], "severity": "HIGH", "tags": [], "dynamicSeverity": []}}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				insights, err := c.ListCustomInsights(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(insights[0].Severity, InsightSeverityHigh)
+			},
+			response: `{"data": [{"id": "ci1", "severity": "HIGH"}]}`,
+			method:   http.MethodGet,
+			path:     "/sec/v1/custom-insights",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				ci, err := c.GetCustomInsight(ctx, "ci1")
+				if err != nil {
+					return err
+				}
+				return expect(string(ci.Extra["dynamicSeverity"]), "[]")
+			},
+			response: insight,
+			method:   http.MethodGet,
+			path:     "/sec/v1/custom-insights/ci1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateCustomInsight(ctx, CustomInsight{Name: "Lateral movement", Enabled: true, Ordered: true, RuleIDs: []string{"USER-1"}, Severity: InsightSeverityHigh})
+				return err
+			},
+			response: insight,
+			method:   http.MethodPost,
+			path:     "/sec/v1/custom-insights",
+			// Empty lists are sent rather than null.
+			body: `{"fields": {"name": "Lateral movement", "description": "", "enabled": true, "ordered": true, "ruleIds": ["USER-1"], "signalNames": [], "severity": "HIGH", "tags": []}}`,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateCustomInsight(ctx, CustomInsight{ID: "ci1", Name: "Lateral movement", Severity: InsightSeverityLow, Extra: map[string]json.RawMessage{"dynamicSeverity": json.RawMessage("[]")}})
+				return err
+			},
+			response: insight,
+			method:   http.MethodPut,
+			path:     "/sec/v1/custom-insights/ci1",
+			body:     `{"fields": {"name": "Lateral movement", "description": "", "enabled": false, "ordered": false, "ruleIds": [], "signalNames": [], "severity": "LOW", "tags": [], "dynamicSeverity": []}}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteCustomInsight(ctx, "ci1") },
+			method: http.MethodDelete,
+			path:   "/sec/v1/custom-insights/ci1",
+		},
+	})
+}
+
+func TestEntities(t *testing.T) {
+	ctx := context.Background()
+	entity := `{"data": {"id": "e1", "name": "10.0.0.1", "entityType": "_ip", "value": "10.0.0.1", "criticality": "high", "isSuppressed": false, "activityScore": 3, "firstSeen": "2026-01-02T03:04:05"}}`
+	testCalls(t, []apiCall{
+		{
+			name: "get",
+			call: func(c *Client) error {
+				e, err := c.GetEntity(ctx, "e1")
+				if err != nil {
+					return err
+				}
+				return expect(e.FirstSeen.Year(), 2026)
+			},
+			response: entity,
+			method:   http.MethodGet,
+			path:     "/sec/v1/entities/e1",
+		},
+		{
+			name: "lookup",
+			call: func(c *Client) error {
+				e, err := c.LookupEntity(ctx, "_ip", "10.0.0.1")
+				if err != nil {
+					return err
+				}
+				// Entities whose value only contains the value are skipped.
+				return expect(e.ID, "e2")
+			},
+			response: `{"data": {"objects": [{"id": "e1", "entityType": "_ip", "value": "10.0.0.10"}, {"id": "e2", "entityType": "_ip", "value": "10.0.0.1"}]}}`,
+			method:   http.MethodGet,
+			path:     "/sec/v1/entities",
+			query:    "limit=100&offset=0&q=entityType%3A%22_ip%22+value%3A%2210.0.0.1%22",
+		},
+		{
+			name: "lookup missing",
+			call: func(c *Client) error {
+				_, err := c.LookupEntity(ctx, "_ip", "10.0.0.2")
+				return expect(errors.Is(err, ErrNotFound), true)
+			},
+			response: `{"data": {"objects": []}}`,
+			method:   http.MethodGet,
+			path:     "/sec/v1/entities",
+		},
+		{
+			name: "set criticality",
+			call: func(c *Client) error {
+				_, err := c.SetEntityCriticality(ctx, "e1", "high")
+				return err
+			},
+			response: entity,
+			method:   http.MethodPut,
+			path:     "/sec/v1/entities/e1/criticality",
+			body:     `{"criticality": "high"}`,
+		},
+		{
+			name: "remove criticality",
+			call: func(c *Client) error {
+				_, err := c.SetEntityCriticality(ctx, "e1", "")
+				return err
+			},
+			response: entity,
+			method:   http.MethodPut,
+			path:     "/sec/v1/entities/e1/criticality",
+			body:     `{"criticality": null}`,
+		},
+	})
+}
+
+func TestEntityCriticalities(t *testing.T) {
+	ctx := context.Background()
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				crits, err := c.ListEntityCriticalities(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(crits[0].SeverityExpression, "severity + 2")
+			},
+			response: `{"data": [{"id": "c1", "name": "high", "severityExpression": "severity + 2"}]}`,
+			method:   http.MethodGet,
+			path:     "/sec/v1/entity-criticality-configs",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateEntityCriticality(ctx, "high", "severity + 2")
+				return err
+			},
+			method: http.MethodPost,
+			path:   "/sec/v1/entity-criticality-configs",
+			body:   `{"fields": {"name": "high", "severityExpression": "severity + 2"}}`,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateEntityCriticality(ctx, "c1", "severity + 3")
+				return err
+			},
+			method: http.MethodPut,
+			path:   "/sec/v1/entity-criticality-configs/c1",
+			body:   `{"fields": {"severityExpression": "severity + 3"}}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteEntityCriticality(ctx, "c1") },
+			method: http.MethodDelete,
+			path:   "/sec/v1/entity-criticality-configs/c1",
+		},
+	})
+}
+
+func TestNetworkBlocks(t *testing.T) {
+	ctx := context.Background()
+	block := `{"data": {"id": "n1", "addressBlock": "10.0.0.0/8", "label": "office", "internal": true, "suppressesSignals": false}}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				blocks, err := c.ListNetworkBlocks(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(blocks[0].Internal, true)
+			},
+			response: `{"data": {"objects": [{"id": "n1", "internal": true}]}}`,
+			method:   http.MethodGet,
+			path:     "/sec/v1/network-blocks",
+			query:    "limit=100&offset=0",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				_, err := c.GetNetworkBlock(ctx, "n1")
+				return err
+			},
+			response: block,
+			method:   http.MethodGet,
+			path:     "/sec/v1/network-blocks/n1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateNetworkBlock(ctx, NetworkBlock{AddressBlock: "10.0.0.0/8", Label: "office", Internal: true})
+				return err
+			},
+			response: block,
+			method:   http.MethodPost,
+			path:     "/sec/v1/network-blocks",
+			body:     `{"fields": {"addressBlock": "10.0.0.0/8", "label": "office", "internal": true, "suppressesSignals": false}}`,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateNetworkBlock(ctx, NetworkBlock{ID: "n1", AddressBlock: "10.0.0.0/8", Label: "office", SuppressesSignals: true})
+				return err
+			},
+			response: block,
+			method:   http.MethodPut,
+			path:     "/sec/v1/network-blocks/n1",
+			// The ID is in the path and not the body.
+			body: `{"fields": {"addressBlock": "10.0.0.0/8", "label": "office", "internal": false, "suppressesSignals": true}}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteNetworkBlock(ctx, "n1") },
+			method: http.MethodDelete,
+			path:   "/sec/v1/network-blocks/n1",
+		},
+	})
+}
+
+func TestCSEConfigValidation(t *testing.T) {
+	api := newTestAPI(t)
+	c := api.client()
+	if _, err := c.CreateCustomInsight(context.Background(), CustomInsight{Name: "ci"}); err == nil {
+		t.Error("CreateCustomInsight without a severity succeeded")
+	}
+	if _, err := c.CreateEntityCriticality(context.Background(), "high", ""); err == nil {
+		t.Error("CreateEntityCriticality without a severity expression succeeded")
+	}
+	if _, err := c.CreateNetworkBlock(context.Background(), NetworkBlock{AddressBlock: "10.0.0.1"}); err == nil {
+		t.Error("CreateNetworkBlock with an address succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
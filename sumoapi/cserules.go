@@ -0,0 +1,410 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Types of a CSE rule.
+const (
+	// CSERuleMatch rules raise a signal for every record matching their
+	// expression.
+	CSERuleMatch = "templated"
+	// CSERuleThreshold rules raise a signal when more than a number of
+	// records match their expression within a window.
+	CSERuleThreshold = "threshold"
+	// CSERuleChain rules raise a signal when the records of an entity match
+	// each of several expressions within a window.
+	CSERuleChain = "chain"
+	// CSERuleAggregation rules raise a signal when aggregates of the
+	// records of an entity within a window match their trigger expression.
+	CSERuleAggregation = "aggregation"
+)
+
+// CSERule is a Cloud SIEM rule, which raises signals from records. The
+// fields used depend on its RuleType.
+type CSERule struct {
+	ID string `json:"id,omitempty"`
+	// RuleType is one of the CSERule constants.
+	RuleType    string `json:"ruleType"`
+	Name        string `json:"name"`
+	Enabled     bool   `json:"enabled"`
+	Description string `json:"description,omitempty"`
+	// DescriptionExpression, NameExpression and SummaryExpression are the
+	// templates of the signals raised by the rule, such as {{user_username}}
+	// logged in from {{srcDevice_ip}}.
+	DescriptionExpression string `json:"descriptionExpression"`
+	NameExpression        string `json:"nameExpression,omitempty"`
+	SummaryExpression     string `json:"summaryExpression,omitempty"`
+	// EntitySelectors select the entities signals are raised for.
+	EntitySelectors []CSEEntitySelector `json:"entitySelectors"`
+	// IsPrototype rules raise signals that do not contribute to insights,
+	// for testing.
+	IsPrototype bool     `json:"isPrototype"`
+	Tags        []string `json:"tags,omitempty"`
+	// Score is the severity of the signals, from 0 to 10, for threshold,
+	// chain and aggregation rules. Match rules use ScoreMapping.
+	Score        int              `json:"score,omitempty"`
+	ScoreMapping *CSEScoreMapping `json:"scoreMapping,omitempty"`
+	// Expression selects the records of match and threshold rules.
+	Expression string `json:"expression,omitempty"`
+	// WindowSize is the window of threshold, chain and aggregation rules,
+	// such as "T05M" or "T24H".
+	WindowSize string `json:"windowSize,omitempty"`
+	// GroupByFields split the records of threshold, chain and aggregation
+	// rules, so each group is counted on its own.
+	GroupByFields []string `json:"groupByFields,omitempty"`
+	// Limit is the number of records a threshold rule must match.
+	// CountDistinct and CountField count distinct values of the field
+	// instead.
+	Limit         int    `json:"limit,omitempty"`
+	CountDistinct bool   `json:"countDistinct,omitempty"`
+	CountField    string `json:"countField,omitempty"`
+	// ExpressionsAndLimits are the expressions of a chain rule, each of
+	// which must match a number of records, in order if Ordered is set.
+	ExpressionsAndLimits []CSEExpressionLimit `json:"expressionsAndLimits,omitempty"`
+	Ordered              bool                 `json:"ordered,omitempty"`
+	// MatchExpression selects the records of an aggregation rule, and
+	// TriggerExpression is evaluated against its AggregationFunctions, such
+	// as logins > 10.
+	MatchExpression      string                   `json:"matchExpression,omitempty"`
+	AggregationFunctions []CSEAggregationFunction `json:"aggregationFunctions,omitempty"`
+	TriggerExpression    string                   `json:"triggerExpression,omitempty"`
+	Created              CSETime                  `json:"created"`
+	CreatedBy            string                   `json:"createdBy,omitempty"`
+	LastUpdated          CSETime                  `json:"lastUpdated"`
+	LastUpdatedBy        string                   `json:"lastUpdatedBy,omitempty"`
+
+	// Extra holds the properties of the rule that have no field, which are
+	// kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the rule, keeping the properties that have no field
+// in Extra.
+func (r *CSERule) UnmarshalJSON(b []byte) error {
+	type plain CSERule
+	return unmarshalExtra(b, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes the rule along with the properties in Extra.
+func (r CSERule) MarshalJSON() ([]byte, error) {
+	type plain CSERule
+	return marshalExtra(plain(r), r.Extra)
+}
+
+// CSEEntitySelector selects the entity of a signal from a field of the
+// record, such as the srcDevice_ip for an _ip entity.
+type CSEEntitySelector struct {
+	EntityType string `json:"entityType"`
+	Expression string `json:"expression"`
+
+	// Extra holds the properties of the entity selector that have no
+	// field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the entity selector, keeping the properties that
+// have no field in Extra.
+func (s *CSEEntitySelector) UnmarshalJSON(b []byte) error {
+	type plain CSEEntitySelector
+	return unmarshalExtra(b, (*plain)(s), &s.Extra)
+}
+
+// MarshalJSON encodes the entity selector along with the properties in
+// Extra.
+func (s CSEEntitySelector) MarshalJSON() ([]byte, error) {
+	type plain CSEEntitySelector
+	return marshalExtra(plain(s), s.Extra)
+}
+
+// CSEScoreMapping sets the severity of the signals of a match rule, as a
+// constant or from a field of the record.
+type CSEScoreMapping struct {
+	// Type is "constant" or "fieldValueMapping".
+	Type    string `json:"type"`
+	Default int    `json:"default"`
+	// Field and Mapping map the values of the field to a severity, for
+	// fieldValueMapping.
+	Field   string            `json:"field,omitempty"`
+	Mapping []CSEScoreMapping `json:"mapping,omitempty"`
+	From    string            `json:"from,omitempty"`
+	To      int               `json:"to,omitempty"`
+
+	// Extra holds the properties of the score mapping that have no
+	// field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the score mapping, keeping the properties that have
+// no field in Extra.
+func (m *CSEScoreMapping) UnmarshalJSON(b []byte) error {
+	type plain CSEScoreMapping
+	return unmarshalExtra(b, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes the score mapping along with the properties in Extra.
+func (m CSEScoreMapping) MarshalJSON() ([]byte, error) {
+	type plain CSEScoreMapping
+	return marshalExtra(plain(m), m.Extra)
+}
+
+// CSEExpressionLimit is an expression of a chain rule, with the number of
+// records that must match it.
+type CSEExpressionLimit struct {
+	Expression string `json:"expression"`
+	Limit      int    `json:"limit"`
+
+	// Extra holds the properties of the expression that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the expression, keeping the properties that have no
+// field in Extra.
+func (e *CSEExpressionLimit) UnmarshalJSON(b []byte) error {
+	type plain CSEExpressionLimit
+	return unmarshalExtra(b, (*plain)(e), &e.Extra)
+}
+
+// MarshalJSON encodes the expression along with the properties in Extra.
+func (e CSEExpressionLimit) MarshalJSON() ([]byte, error) {
+	type plain CSEExpressionLimit
+	return marshalExtra(plain(e), e.Extra)
+}
+
+// CSEAggregationFunction is an aggregate of an aggregation rule, such as
+// count_distinct of the device_hostname field, named for use in its trigger
+// expression.
+type CSEAggregationFunction struct {
+	Name      string   `json:"name"`
+	Function  string   `json:"function"`
+	Arguments []string `json:"arguments"`
+
+	// Extra holds the properties of the aggregation function that have
+	// no field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the aggregation function, keeping the properties
+// that have no field in Extra.
+func (f *CSEAggregationFunction) UnmarshalJSON(b []byte) error {
+	type plain CSEAggregationFunction
+	return unmarshalExtra(b, (*plain)(f), &f.Extra)
+}
+
+// MarshalJSON encodes the aggregation function along with the properties in
+// Extra.
+func (f CSEAggregationFunction) MarshalJSON() ([]byte, error) {
+	type plain CSEAggregationFunction
+	return marshalExtra(plain(f), f.Extra)
+}
+
+// TuningExpression is a rule tuning expression, which includes or excludes
+// records from the rules it applies to.
+type TuningExpression struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Expression selects the records, such as srcDevice_ip = '10.0.0.1'.
+	Expression string `json:"expression"`
+	Enabled    bool   `json:"enabled"`
+	// Exclude excludes the matching records from the rules, instead of
+	// only including them.
+	Exclude bool `json:"exclude"`
+	// IsGlobal applies the expression to every rule, instead of RuleIDs.
+	IsGlobal      bool     `json:"isGlobal"`
+	RuleIDs       []string `json:"ruleIds"`
+	Created       CSETime  `json:"created"`
+	CreatedBy     string   `json:"createdBy,omitempty"`
+	LastUpdated   CSETime  `json:"lastUpdated"`
+	LastUpdatedBy string   `json:"lastUpdatedBy,omitempty"`
+
+	// Extra holds the properties of the tuning expression that have no
+	// field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the tuning expression, keeping the properties that
+// have no field in Extra.
+func (e *TuningExpression) UnmarshalJSON(b []byte) error {
+	type plain TuningExpression
+	return unmarshalExtra(b, (*plain)(e), &e.Extra)
+}
+
+// MarshalJSON encodes the tuning expression along with the properties in
+// Extra.
+func (e TuningExpression) MarshalJSON() ([]byte, error) {
+	type plain TuningExpression
+	return marshalExtra(plain(e), e.Extra)
+}
+
+// ListCSERules returns the rules matching the query, such as
+// ruleSource:"user", or every rule if it is empty, following pagination.
+func (c *Client) ListCSERules(ctx context.Context, query string) ([]CSERule, error) {
+	return Collect(c.IterateCSERules(ctx, query))
+}
+
+// IterateCSERules returns an Iterator over the CSE rules returned by
+// ListCSERules, requesting each page as it is read.
+func (c *Client) IterateCSERules(ctx context.Context, query string) *Iterator[CSERule] {
+	var q url.Values
+	if query != "" {
+		q = url.Values{"q": {query}}
+	}
+	return cseIterate[CSERule](ctx, c, "listing cse rules", "/sec/v1/rules", q)
+}
+
+// GetCSERule returns the rule with the ID.
+func (c *Client) GetCSERule(ctx context.Context, id string) (*CSERule, error) {
+	var resp cseResponse[CSERule]
+	if err := c.get(ctx, pathf("/sec/v1/rules/%s", id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("error getting cse rule: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// CreateCSERule creates a rule of its RuleType and returns it.
+func (c *Client) CreateCSERule(ctx context.Context, rule CSERule) (*CSERule, error) {
+	if err := validateCSERuleType(rule.RuleType); err != nil {
+		return nil, fmt.Errorf("error creating cse rule: %w", err)
+	}
+	if rule.Name == "" {
+		return nil, fmt.Errorf("error creating cse rule: name is required")
+	}
+	var resp cseResponse[CSERule]
+	if err := c.post(ctx, pathf("/sec/v1/rules/%s", rule.RuleType), cseRuleBody(rule), &resp); err != nil {
+		return nil, fmt.Errorf("error creating cse rule: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// UpdateCSERule replaces the rule with the same ID and returns the updated
+// rule. Its RuleType cannot be changed.
+func (c *Client) UpdateCSERule(ctx context.Context, rule CSERule) (*CSERule, error) {
+	if err := validateCSERuleType(rule.RuleType); err != nil {
+		return nil, fmt.Errorf("error updating cse rule: %w", err)
+	}
+	var resp cseResponse[CSERule]
+	if err := c.put(ctx, pathf("/sec/v1/rules/%s/%s", rule.RuleType, rule.ID), cseRuleBody(rule), &resp); err != nil {
+		return nil, fmt.Errorf("error updating cse rule: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// SetCSERuleEnabled enables or disables the rule with the ID and returns the
+// updated rule.
+func (c *Client) SetCSERuleEnabled(ctx context.Context, id string, enabled bool) (*CSERule, error) {
+	body := struct {
+		Enabled bool `json:"enabled"`
+	}{enabled}
+	var resp cseResponse[CSERule]
+	if err := c.put(ctx, pathf("/sec/v1/rules/%s/enabled", id), body, &resp); err != nil {
+		return nil, fmt.Errorf("error setting cse rule enabled: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// DeleteCSERule deletes the rule with the ID. Only rules created by users
+// can be deleted.
+func (c *Client) DeleteCSERule(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/sec/v1/rules/%s", id)); err != nil {
+		return fmt.Errorf("error deleting cse rule: %w", err)
+	}
+	return nil
+}
+
+// ListTuningExpressions returns every rule tuning expression, following
+// pagination.
+func (c *Client) ListTuningExpressions(ctx context.Context) ([]TuningExpression, error) {
+	return Collect(c.IterateTuningExpressions(ctx))
+}
+
+// IterateTuningExpressions returns an Iterator over the tuning expressions
+// returned by ListTuningExpressions, requesting each page as it is read.
+func (c *Client) IterateTuningExpressions(ctx context.Context) *Iterator[TuningExpression] {
+	return cseIterate[TuningExpression](ctx, c, "listing tuning expressions", "/sec/v1/rule-tuning-expressions", nil)
+}
+
+// GetTuningExpression returns the rule tuning expression with the ID.
+func (c *Client) GetTuningExpression(ctx context.Context, id string) (*TuningExpression, error) {
+	var resp cseResponse[TuningExpression]
+	if err := c.get(ctx, pathf("/sec/v1/rule-tuning-expressions/%s", id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("error getting tuning expression: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// CreateTuningExpression creates a rule tuning expression and returns it.
+func (c *Client) CreateTuningExpression(ctx context.Context, expr TuningExpression) (*TuningExpression, error) {
+	if expr.Name == "" || expr.Expression == "" {
+		return nil, fmt.Errorf("error creating tuning expression: name and expression are required")
+	}
+	var resp cseResponse[TuningExpression]
+	if err := c.post(ctx, "/sec/v1/rule-tuning-expressions", tuningExpressionBody(expr), &resp); err != nil {
+		return nil, fmt.Errorf("error creating tuning expression: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// UpdateTuningExpression replaces the rule tuning expression with the same
+// ID and returns the updated expression.
+func (c *Client) UpdateTuningExpression(ctx context.Context, expr TuningExpression) (*TuningExpression, error) {
+	var resp cseResponse[TuningExpression]
+	if err := c.put(ctx, pathf("/sec/v1/rule-tuning-expressions/%s", expr.ID), tuningExpressionBody(expr), &resp); err != nil {
+		return nil, fmt.Errorf("error updating tuning expression: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// DeleteTuningExpression deletes the rule tuning expression with the ID.
+func (c *Client) DeleteTuningExpression(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/sec/v1/rule-tuning-expressions/%s", id)); err != nil {
+		return fmt.Errorf("error deleting tuning expression: %w", err)
+	}
+	return nil
+}
+
+// validateCSERuleType checks that the rule type is one of the CSERule
+// constants, since it is part of the request path.
+func validateCSERuleType(ruleType string) error {
+	switch ruleType {
+	case CSERuleMatch, CSERuleThreshold, CSERuleChain, CSERuleAggregation:
+		return nil
+	}
+	return fmt.Errorf("invalid rule type: %q", ruleType)
+}
+
+// cseRuleBody returns the rule without the fields that are only set by the
+// API, wrapped as the CSE API expects.
+func cseRuleBody(rule CSERule) any {
+	type body CSERule
+	return struct {
+		Fields any `json:"fields"`
+	}{withExtra(struct {
+		body
+		ID          string   `json:"id,omitempty"`
+		RuleType    string   `json:"ruleType,omitempty"`
+		Created     *CSETime `json:"created,omitempty"`
+		LastUpdated *CSETime `json:"lastUpdated,omitempty"`
+	}{body: body(rule)}, rule.Extra)}
+}
+
+// tuningExpressionBody returns the expression without the fields that are
+// only set by the API, wrapped as the CSE API expects.
+func tuningExpressionBody(expr TuningExpression) any {
+	if expr.RuleIDs == nil {
+		expr.RuleIDs = []string{}
+	}
+	type body TuningExpression
+	return struct {
+		Fields any `json:"fields"`
+	}{withExtra(struct {
+		body
+		ID          string   `json:"id,omitempty"`
+		Created     *CSETime `json:"created,omitempty"`
+		LastUpdated *CSETime `json:"lastUpdated,omitempty"`
+	}{body: body(expr)}, expr.Extra)}
+}
@@ -0,0 +1,179 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestCSERules(t *testing.T) {
+	ctx := context.Background()
+	rule := `{"data": {"id": "USER-1", "ruleType": "threshold", "name": "Brute force", "enabled": true, "descriptionExpression": "Logins", "entitySelectors": [{"entityType": "_ip", "expression": "srcDevice_ip"}], "isPrototype": false, "score": 5, "expression": "action = 'login'", "windowSize": "T05M", "limit": 10, "created": "2026-01-02T03:04:05.123", "ruleSource": "user"}}`
+	threshold := CSERule{
+		ID:                    "USER-1",
+		RuleType:              CSERuleThreshold,
+		Name:                  "Brute force",
+		Enabled:               true,
+		DescriptionExpression: "Logins",
+		EntitySelectors:       []CSEEntitySelector{{EntityType: "_ip", Expression: "srcDevice_ip"}},
+		Score:                 5,
+		Expression:            "action = 'login'",
+		WindowSize:            "T05M",
+		Limit:                 10,
+		Extra:                 map[string]json.RawMessage{"ruleSource": json.RawMessage(`"user"`)},
+	}
+	// The ID, type and times are set by the API and are not sent.
+	body := `{"fields": {"name": "Brute force", "enabled": true, "descriptionExpression": "Logins", "entitySelectors": [{"entityType": "_ip", "expression": "srcDevice_ip"}], "isPrototype": false, "score": 5, "expression": "action = 'login'", "windowSize": "T05M", "limit": 10, "ruleSource": "user"}}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				rules, err := c.ListCSERules(ctx, `ruleSource:"user"`)
+				if err != nil {
+					return err
+				}
+				return expect(len(rules), 1)
+			},
+			response: `{"data": {"objects": [{"id": "USER-1", "ruleType": "threshold"}], "hasNextPage": false}}`,
+			method:   http.MethodGet,
+			path:     "/sec/v1/rules",
+			query:    "limit=100&offset=0&q=ruleSource%3A%22user%22",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				r, err := c.GetCSERule(ctx, "USER-1")
+				if err != nil {
+					return err
+				}
+				if err := expect(r.EntitySelectors[0].Expression, "srcDevice_ip"); err != nil {
+					return err
+				}
+				return expect(string(r.Extra["ruleSource"]), `"user"`)
+			},
+			response: rule,
+			method:   http.MethodGet,
+			path:     "/sec/v1/rules/USER-1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateCSERule(ctx, threshold)
+				return err
+			},
+			response: rule,
+			method:   http.MethodPost,
+			path:     "/sec/v1/rules/threshold",
+			body:     body,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateCSERule(ctx, threshold)
+				return err
+			},
+			response: rule,
+			method:   http.MethodPut,
+			path:     "/sec/v1/rules/threshold/USER-1",
+			body:     body,
+		},
+		{
+			name: "disable",
+			call: func(c *Client) error {
+				_, err := c.SetCSERuleEnabled(ctx, "USER-1", false)
+				return err
+			},
+			response: rule,
+			method:   http.MethodPut,
+			path:     "/sec/v1/rules/USER-1/enabled",
+			body:     `{"enabled": false}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteCSERule(ctx, "USER-1") },
+			method: http.MethodDelete,
+			path:   "/sec/v1/rules/USER-1",
+		},
+	})
+}
+
+func TestCSERuleValidation(t *testing.T) {
+	api := newTestAPI(t)
+	c := api.client()
+	if _, err := c.CreateCSERule(context.Background(), CSERule{RuleType: "../match", Name: "r"}); err == nil {
+		t.Error("CreateCSERule with an invalid type succeeded")
+	}
+	if _, err := c.CreateCSERule(context.Background(), CSERule{RuleType: CSERuleMatch}); err == nil {
+		t.Error("CreateCSERule without a name succeeded")
+	}
+	if _, err := c.UpdateCSERule(context.Background(), CSERule{ID: "USER-1"}); err == nil {
+		t.Error("UpdateCSERule without a type succeeded")
+	}
+	if _, err := c.CreateTuningExpression(context.Background(), TuningExpression{Name: "t"}); err == nil {
+		t.Error("CreateTuningExpression without an expression succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
+
+func TestTuningExpressions(t *testing.T) {
+	ctx := context.Background()
+	expr := `{"data": {"id": "t1", "name": "Scanner", "expression": "srcDevice_ip = '10.0.0.1'", "enabled": true, "exclude": true, "isGlobal": true, "ruleIds": []}}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				exprs, err := c.ListTuningExpressions(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(exprs[0].Exclude, true)
+			},
+			response: `{"data": {"objects": [{"id": "t1", "exclude": true}], "hasNextPage": false}}`,
+			method:   http.MethodGet,
+			path:     "/sec/v1/rule-tuning-expressions",
+			query:    "limit=100&offset=0",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				_, err := c.GetTuningExpression(ctx, "t1")
+				return err
+			},
+			response: expr,
+			method:   http.MethodGet,
+			path:     "/sec/v1/rule-tuning-expressions/t1",
+		},
+		{
+			name: "create global",
+			call: func(c *Client) error {
+				_, err := c.CreateTuningExpression(ctx, TuningExpression{Name: "Scanner", Expression: "srcDevice_ip = '10.0.0.1'", Enabled: true, Exclude: true, IsGlobal: true})
+				return err
+			},
+			response: expr,
+			method:   http.MethodPost,
+			path:     "/sec/v1/rule-tuning-expressions",
+			// A global expression is sent with an empty list of rules.
+			body: `{"fields": {"name": "Scanner", "expression": "srcDevice_ip = '10.0.0.1'", "enabled": true, "exclude": true, "isGlobal": true, "ruleIds": []}}`,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateTuningExpression(ctx, TuningExpression{ID: "t1", Name: "Scanner", Expression: "true", RuleIDs: []string{"USER-1"}})
+				return err
+			},
+			response: expr,
+			method:   http.MethodPut,
+			path:     "/sec/v1/rule-tuning-expressions/t1",
+			body:     `{"fields": {"name": "Scanner", "expression": "true", "enabled": false, "exclude": false, "isGlobal": false, "ruleIds": ["USER-1"]}}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteTuningExpression(ctx, "t1") },
+			method: http.MethodDelete,
+			path:   "/sec/v1/rule-tuning-expressions/t1",
+		},
+	})
+}
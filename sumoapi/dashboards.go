@@ -0,0 +1,326 @@
+package sumoapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Types of a dashboard panel.
+const (
+	PanelSearch = "SumoSearchPanel"
+	PanelText   = "TextPanel"
+)
+
+// Types of the source of a dashboard variable's values.
+const (
+	VariableSourceCSV      = "CsvVariableSourceDefinition"
+	VariableSourceLogQuery = "LogQueryVariableSourceDefinition"
+	VariableSourceMetadata = "MetadataVariableSourceDefinition"
+)
+
+// Dashboard is a dashboard of the Dashboards (New) API.
+type Dashboard struct {
+	ID          string `json:"id,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// FolderID is the content folder the dashboard is in. The personal
+	// folder is used when it is empty.
+	FolderID string `json:"folderId,omitempty"`
+	// RefreshInterval is how often panels are refreshed, in seconds. Zero
+	// turns refreshing off.
+	RefreshInterval int `json:"refreshInterval,omitempty"`
+	// TimeRange is the default time range of the panels, such as the one
+	// returned by RelativeTimeRange.
+	TimeRange json.RawMessage `json:"timeRange,omitempty"`
+	Panels    []Panel         `json:"panels,omitempty"`
+	Layout    *Layout         `json:"layout,omitempty"`
+	Variables []Variable      `json:"variables,omitempty"`
+	// Theme is "Light" or "Dark".
+	Theme string `json:"theme,omitempty"`
+
+	// Extra holds the properties of the dashboard that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the dashboard, keeping the properties that have no
+// field in Extra.
+func (d *Dashboard) UnmarshalJSON(b []byte) error {
+	type plain Dashboard
+	return unmarshalExtra(b, (*plain)(d), &d.Extra)
+}
+
+// MarshalJSON encodes the dashboard along with the properties in Extra.
+func (d Dashboard) MarshalJSON() ([]byte, error) {
+	type plain Dashboard
+	return marshalExtra(plain(d), d.Extra)
+}
+
+// Panel is a panel of a dashboard.
+type Panel struct {
+	ID string `json:"id,omitempty"`
+	// Key identifies the panel in the layout of the dashboard.
+	Key   string `json:"key"`
+	Title string `json:"title"`
+	// PanelType is PanelSearch or PanelText.
+	PanelType   string `json:"panelType"`
+	Description string `json:"description,omitempty"`
+	// VisualSettings is a JSON encoded string describing how the panel is
+	// drawn, such as its chart type.
+	VisualSettings                         string `json:"visualSettings,omitempty"`
+	KeepVisualSettingsConsistentWithParent bool   `json:"keepVisualSettingsConsistentWithParent"`
+	// Queries are the queries of a search panel.
+	Queries []PanelQuery `json:"queries,omitempty"`
+	// Text is the markdown shown by a text panel.
+	Text string `json:"text,omitempty"`
+	// TimeRange overrides the time range of the dashboard, when set.
+	TimeRange json.RawMessage `json:"timeRange,omitempty"`
+
+	// Extra holds the properties of the panel that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the panel, keeping the properties that have no field
+// in Extra.
+func (p *Panel) UnmarshalJSON(b []byte) error {
+	type plain Panel
+	return unmarshalExtra(b, (*plain)(p), &p.Extra)
+}
+
+// MarshalJSON encodes the panel along with the properties in Extra.
+func (p Panel) MarshalJSON() ([]byte, error) {
+	type plain Panel
+	return marshalExtra(plain(p), p.Extra)
+}
+
+// PanelQuery is a query of a search panel.
+type PanelQuery struct {
+	QueryString string `json:"queryString"`
+	// QueryType is "Logs" or "Metrics".
+	QueryType string `json:"queryType"`
+	// QueryKey identifies the query within the panel, such as "A".
+	QueryKey         string `json:"queryKey"`
+	MetricsQueryMode string `json:"metricsQueryMode,omitempty"`
+	ParseMode        string `json:"parseMode,omitempty"`
+	TimeSource       string `json:"timeSource,omitempty"`
+
+	// Extra holds the properties of the panel query that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the panel query, keeping the properties that have no
+// field in Extra.
+func (q *PanelQuery) UnmarshalJSON(b []byte) error {
+	type plain PanelQuery
+	return unmarshalExtra(b, (*plain)(q), &q.Extra)
+}
+
+// MarshalJSON encodes the panel query along with the properties in Extra.
+func (q PanelQuery) MarshalJSON() ([]byte, error) {
+	type plain PanelQuery
+	return marshalExtra(plain(q), q.Extra)
+}
+
+// Layout positions the panels of a dashboard.
+type Layout struct {
+	// LayoutType is "Grid".
+	LayoutType       string            `json:"layoutType"`
+	LayoutStructures []LayoutStructure `json:"layoutStructures"`
+
+	// Extra holds the properties of the layout that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the layout, keeping the properties that have no
+// field in Extra.
+func (l *Layout) UnmarshalJSON(b []byte) error {
+	type plain Layout
+	return unmarshalExtra(b, (*plain)(l), &l.Extra)
+}
+
+// MarshalJSON encodes the layout along with the properties in Extra.
+func (l Layout) MarshalJSON() ([]byte, error) {
+	type plain Layout
+	return marshalExtra(plain(l), l.Extra)
+}
+
+// LayoutStructure positions a single panel.
+type LayoutStructure struct {
+	// Key is the Key of the panel.
+	Key string `json:"key"`
+	// Structure is a JSON encoded string holding the position and size of
+	// the panel, such as {"height":6,"width":12,"x":0,"y":0}.
+	Structure string `json:"structure"`
+
+	// Extra holds the properties of the layout structure that have no
+	// field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the layout structure, keeping the properties that
+// have no field in Extra.
+func (s *LayoutStructure) UnmarshalJSON(b []byte) error {
+	type plain LayoutStructure
+	return unmarshalExtra(b, (*plain)(s), &s.Extra)
+}
+
+// MarshalJSON encodes the layout structure along with the properties in
+// Extra.
+func (s LayoutStructure) MarshalJSON() ([]byte, error) {
+	type plain LayoutStructure
+	return marshalExtra(plain(s), s.Extra)
+}
+
+// Variable is a variable of a dashboard, which filters its panels.
+type Variable struct {
+	ID               string                   `json:"id,omitempty"`
+	Name             string                   `json:"name"`
+	DisplayName      string                   `json:"displayName,omitempty"`
+	DefaultValue     string                   `json:"defaultValue,omitempty"`
+	SourceDefinition VariableSourceDefinition `json:"sourceDefinition"`
+	AllowMultiSelect bool                     `json:"allowMultiSelect"`
+	IncludeAllOption bool                     `json:"includeAllOption"`
+	HideFromUI       bool                     `json:"hideFromUI"`
+
+	// Extra holds the properties of the variable that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the variable, keeping the properties that have no
+// field in Extra.
+func (v *Variable) UnmarshalJSON(b []byte) error {
+	type plain Variable
+	return unmarshalExtra(b, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON encodes the variable along with the properties in Extra.
+func (v Variable) MarshalJSON() ([]byte, error) {
+	type plain Variable
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// VariableSourceDefinition is where the values of a variable come from:
+// a comma separated list of Values, or the values of Field in the results of
+// Query.
+type VariableSourceDefinition struct {
+	// VariableSourceType is one of the VariableSource constants.
+	VariableSourceType string `json:"variableSourceType"`
+	Values             string `json:"values,omitempty"`
+	Query              string `json:"query,omitempty"`
+	Field              string `json:"field,omitempty"`
+	Key                string `json:"key,omitempty"`
+	Filter             string `json:"filter,omitempty"`
+
+	// Extra holds the properties of the source definition that have no
+	// field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the source definition, keeping the properties that
+// have no field in Extra.
+func (d *VariableSourceDefinition) UnmarshalJSON(b []byte) error {
+	type plain VariableSourceDefinition
+	return unmarshalExtra(b, (*plain)(d), &d.Extra)
+}
+
+// MarshalJSON encodes the source definition along with the properties in
+// Extra.
+func (d VariableSourceDefinition) MarshalJSON() ([]byte, error) {
+	type plain VariableSourceDefinition
+	return marshalExtra(plain(d), d.Extra)
+}
+
+// RelativeTimeRange returns a time range starting at the relative time, such
+// as "-15m" or "-1d", and ending now, for use as the TimeRange of a
+// Dashboard or Panel.
+func RelativeTimeRange(from string) json.RawMessage {
+	tr, _ := json.Marshal(map[string]any{
+		"type": "BeginBoundedTimeRange",
+		"from": map[string]string{"type": "RelativeTimeRangeBoundary", "relativeTime": from},
+	})
+	return tr
+}
+
+// ListDashboards returns every dashboard, following pagination.
+func (c *Client) ListDashboards(ctx context.Context) ([]Dashboard, error) {
+	return Collect(c.IterateDashboards(ctx))
+}
+
+// IterateDashboards returns an Iterator over the dashboards returned by
+// ListDashboards, requesting each page as it is read.
+func (c *Client) IterateDashboards(ctx context.Context) *Iterator[Dashboard] {
+	return iterateTokens[Dashboard](ctx, c, "listing dashboards", "/v2/dashboards", nil)
+}
+
+// GetDashboard returns the dashboard with the ID.
+func (c *Client) GetDashboard(ctx context.Context, id string) (*Dashboard, error) {
+	var d Dashboard
+	if err := c.get(ctx, pathf("/v2/dashboards/%s", id), nil, &d); err != nil {
+		return nil, fmt.Errorf("error getting dashboard: %w", err)
+	}
+	return &d, nil
+}
+
+// CreateDashboard creates a dashboard and returns it.
+func (c *Client) CreateDashboard(ctx context.Context, dashboard Dashboard) (*Dashboard, error) {
+	if dashboard.Title == "" {
+		return nil, fmt.Errorf("error creating dashboard: title is required")
+	}
+	dashboard.ID = ""
+	var created Dashboard
+	if err := c.post(ctx, "/v2/dashboards", dashboard, &created); err != nil {
+		return nil, fmt.Errorf("error creating dashboard: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateDashboard replaces the dashboard with the same ID and returns the
+// updated dashboard.
+func (c *Client) UpdateDashboard(ctx context.Context, dashboard Dashboard) (*Dashboard, error) {
+	var updated Dashboard
+	if err := c.put(ctx, pathf("/v2/dashboards/%s", dashboard.ID), dashboard, &updated); err != nil {
+		return nil, fmt.Errorf("error updating dashboard: %w", err)
+	}
+	return &updated, nil
+}
+
+// DeleteDashboard deletes the dashboard with the ID.
+func (c *Client) DeleteDashboard(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v2/dashboards/%s", id)); err != nil {
+		return fmt.Errorf("error deleting dashboard: %w", err)
+	}
+	return nil
+}
+
+// ExportDashboard returns the JSON definition of the dashboard with the ID,
+// for versioning in git. Every field returned by the API is kept, including
+// those Dashboard does not model, except the ID and folder, which differ
+// between accounts. The JSON is indented with its object keys sorted, so
+// exporting an unchanged dashboard produces the same bytes. The definition
+// can be decoded into a Dashboard and passed to CreateDashboard.
+func (c *Client) ExportDashboard(ctx context.Context, id string) ([]byte, error) {
+	var raw bytes.Buffer
+	if err := c.get(ctx, pathf("/v2/dashboards/%s", id), nil, &raw); err != nil {
+		return nil, fmt.Errorf("error exporting dashboard: %w", err)
+	}
+	// Numbers are kept as written so large values are not rounded.
+	dec := json.NewDecoder(&raw)
+	dec.UseNumber()
+	var def map[string]any
+	if err := dec.Decode(&def); err != nil {
+		return nil, fmt.Errorf("error exporting dashboard: %w", err)
+	}
+	delete(def, "id")
+	delete(def, "folderId")
+	out, err := json.MarshalIndent(def, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error exporting dashboard: %w", err)
+	}
+	return append(out, '\n'), nil
+}
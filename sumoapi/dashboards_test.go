@@ -0,0 +1,106 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestDashboards(t *testing.T) {
+	ctx := context.Background()
+	dashboard := `{"id": "d1", "title": "Latency", "folderId": "f1", "refreshInterval": 60, "panels": [{"key": "p1", "title": "p99", "panelType": "SumoSearchPanel", "keepVisualSettingsConsistentWithParent": false, "coloringRules": []}], "topologyLabelMap": {"data": {}}}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				dashboards, err := c.ListDashboards(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(len(dashboards), 1)
+			},
+			response: `{"dashboards": [], "data": [` + dashboard + `]}`,
+			method:   http.MethodGet,
+			path:     "/v2/dashboards",
+			query:    "limit=100",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				d, err := c.GetDashboard(ctx, "d1")
+				if err != nil {
+					return err
+				}
+				if len(d.Panels) != 1 {
+					return expect(len(d.Panels), 1)
+				}
+				return expect(string(d.Panels[0].Extra["coloringRules"]), "[]")
+			},
+			response: dashboard,
+			method:   http.MethodGet,
+			path:     "/v2/dashboards/d1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateDashboard(ctx, Dashboard{ID: "old", Title: "Latency", TimeRange: RelativeTimeRange("-15m")})
+				return err
+			},
+			response: dashboard,
+			method:   http.MethodPost,
+			path:     "/v2/dashboards",
+			// The ID of a dashboard exported from another account is not sent.
+			body: `{
+				"title": "Latency",
+				"timeRange": {"type": "BeginBoundedTimeRange", "from": {"type": "RelativeTimeRangeBoundary", "relativeTime": "-15m"}}
+			}`,
+		},
+		{
+			name: "update keeps extra",
+			call: func(c *Client) error {
+				var d Dashboard
+				if err := json.Unmarshal([]byte(dashboard), &d); err != nil {
+					return err
+				}
+				_, err := c.UpdateDashboard(ctx, d)
+				return err
+			},
+			response: dashboard,
+			method:   http.MethodPut,
+			path:     "/v2/dashboards/d1",
+			body:     dashboard,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteDashboard(ctx, "d1") },
+			method: http.MethodDelete,
+			path:   "/v2/dashboards/d1",
+		},
+	})
+}
+
+func TestExportDashboard(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("GET /v2/dashboards/d1", `{"title": "Latency", "id": "d1", "folderId": "f1", "refreshInterval": 12345678901234567890, "theme": "Dark"}`)
+	got, err := api.client().ExportDashboard(context.Background(), "d1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The keys are sorted, large numbers are kept as written and the ID and
+	// folder are removed.
+	want := "{\n  \"refreshInterval\": 12345678901234567890,\n  \"theme\": \"Dark\",\n  \"title\": \"Latency\"\n}\n"
+	if string(got) != want {
+		t.Errorf("ExportDashboard = %q, want %q", got, want)
+	}
+}
+
+func TestCreateDashboardRequiresTitle(t *testing.T) {
+	api := newTestAPI(t)
+	if _, err := api.client().CreateDashboard(context.Background(), Dashboard{}); err == nil {
+		t.Error("CreateDashboard without a title succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
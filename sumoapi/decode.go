@@ -0,0 +1,208 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decode stores the fields of the result in the struct pointed to by v. Each
+// struct field is matched to a result field by its json tag, or its name if
+// it has no tag, ignoring case since Sumo Logic lowercases field names.
+// Since every value is returned as a string, values are parsed according to
+// the type of the struct field: strings, booleans, numbers, time.Time (from
+// milliseconds since the epoch or RFC 3339), and types implementing
+// encoding.TextUnmarshaler or json.Unmarshaler are supported. Result fields
+// without a struct field are ignored. A map[string]string is also accepted,
+// and receives a copy of every field.
+func (r SearchResult) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got: %T", v)
+	}
+	rv = rv.Elem()
+	if m, ok := rv.Addr().Interface().(*map[string]string); ok {
+		*m = make(map[string]string, len(r.Map))
+		for k, val := range r.Map {
+			(*m)[k] = val
+		}
+		return nil
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("decode target must point to a struct, got: %T", v)
+	}
+	byName := make(map[string]string, len(r.Map))
+	for k, val := range r.Map {
+		byName[strings.ToLower(k)] = val
+	}
+	return decodeStruct(rv, byName)
+}
+
+// decodeStruct sets the fields of the struct value from the lowercased
+// result fields.
+func decodeStruct(rv reflect.Value, fields map[string]string) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && !hasTag && f.Type.Kind() == reflect.Struct {
+			if err := decodeStruct(rv.Field(i), fields); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s, ok := fields[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+		if err := decodeValue(rv.Field(i), s); err != nil {
+			return fmt.Errorf("field %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+// decodeValue parses s into the value.
+func decodeValue(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		if s == "" {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(v.Elem(), s)
+	}
+	if v.Type() == timeType {
+		t, err := parseTime(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch pt := reflect.PointerTo(v.Type()); {
+	case pt.Implements(textUnmarshalerType):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	case pt.Implements(jsonUnmarshalerType):
+		return unmarshalJSONValue(v.Addr().Interface(), s)
+	}
+	if s == "" && v.Kind() != reflect.String {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			// Aggregates such as avg return decimals even for counts.
+			f, ferr := strconv.ParseFloat(s, 64)
+			if ferr != nil || f != float64(int64(f)) {
+				return err
+			}
+			n = int64(f)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return unmarshalJSONValue(v.Addr().Interface(), s)
+	}
+	return nil
+}
+
+// unmarshalJSONValue decodes s as JSON, falling back to decoding it as a
+// JSON string.
+func unmarshalJSONValue(v any, s string) error {
+	if err := json.Unmarshal([]byte(s), v); err == nil {
+		return nil
+	}
+	quoted, _ := json.Marshal(s)
+	return json.Unmarshal(quoted, v)
+}
+
+// parseTime parses a time given in milliseconds since the epoch, as Sumo
+// Logic returns _messagetime and _receipttime, or as RFC 3339.
+func parseTime(s string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", s)
+	}
+	return t, nil
+}
+
+// SearchInto runs the search, waits for it to finish, and decodes every
+// aggregate record into a T using SearchResult.Decode, following pagination
+// until every record has been read. The search job is deleted afterwards. T
+// is usually a struct with json tags matching the fields of the query,
+// such as:
+//
+//	type count struct {
+//		Host  string `json:"_sourcehost"`
+//		Count int    `json:"_count"`
+//	}
+//	counts, err := sumoapi.SearchInto[count](ctx, client, req)
+func SearchInto[T any](ctx context.Context, c *Client, req SearchJobRequest) ([]T, error) {
+	return collectInto[T](c.SearchRecords(ctx, req))
+}
+
+// SearchMessagesInto is like SearchInto but decodes the raw messages matched
+// by the search instead of aggregate records.
+func SearchMessagesInto[T any](ctx context.Context, c *Client, req SearchJobRequest) ([]T, error) {
+	return collectInto[T](c.SearchMessages(ctx, req))
+}
+
+// collectInto decodes every result of the iterator.
+func collectInto[T any](it *SearchIterator) ([]T, error) {
+	defer it.Close()
+	var out []T
+	for it.Next() {
+		var v T
+		if err := it.Result().Decode(&v); err != nil {
+			return out, fmt.Errorf("error decoding search result %d: %w", len(out), err)
+		}
+		out = append(out, v)
+	}
+	return out, it.Err()
+}
@@ -0,0 +1,99 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// discoveredBaseURLs holds the base URL of the deployment of each access ID,
+// as found by DiscoverBaseURL or by following a deployment redirect, keyed by
+// discoveryKey.
+var discoveredBaseURLs sync.Map
+
+// discoveryKey identifies a discovered base URL by the access ID and the base
+// URL the Client was configured with, since a base URL found from one
+// configured base URL does not apply to another, such as a proxy.
+type discoveryKey struct {
+	accessID string
+	baseURL  string
+}
+
+// DiscoverBaseURL returns the base URL of the management API of the
+// deployment the access ID and access key belong to, so it does not have to
+// be configured. A request is made to the us1 deployment, following the
+// redirect Sumo Logic sends if it is the wrong deployment. Deployments that
+// reject the credentials instead are skipped, trying each of Deployments in
+// turn. The base URL is cached for the access ID, so only the first call for
+// it makes requests. If the options set the base URL, with WithBaseURL or
+// WithDeployment, it is returned without making any requests, so the
+// credentials are only sent where the caller asked.
+func DiscoverBaseURL(ctx context.Context, accessID, accessKey string, opts ...Option) (string, error) {
+	c, err := NewClient(accessID, accessKey, opts...)
+	if err != nil {
+		return "", err
+	}
+	if c.baseURLSet {
+		return c.BaseURL(), nil
+	}
+	key := discoveryKey{accessID, c.configuredBaseURL}
+	if base, ok := discoveredBaseURLs.Load(key); ok {
+		return base.(string), nil
+	}
+	candidates := []string{c.BaseURL()}
+	for _, d := range Deployments {
+		if base := d.BaseURL(); base != candidates[0] {
+			candidates = append(candidates, base)
+		}
+	}
+	for _, base := range candidates {
+		u, _ := url.Parse(base)
+		c.mu.Lock()
+		c.baseURL = u
+		c.mu.Unlock()
+		// The account owner is readable by any key, and a key lacking the
+		// capability is still rejected as forbidden rather than
+		// unauthorized by the right deployment.
+		err := c.get(ctx, "/v1/account/accountOwner", nil, nil)
+		if err == nil || errors.Is(err, ErrForbidden) {
+			base := c.BaseURL()
+			discoveredBaseURLs.Store(key, base)
+			return base, nil
+		}
+		if !errors.Is(err, ErrUnauthorized) {
+			return "", fmt.Errorf("error discovering deployment: %w", err)
+		}
+	}
+	return "", fmt.Errorf("error discovering deployment: no deployment accepts the credentials: %w", ErrUnauthorized)
+}
+
+// NewDiscoveredClient is like NewClient but sets the base URL to the one
+// found by DiscoverBaseURL.
+func NewDiscoveredClient(ctx context.Context, accessID, accessKey string, opts ...Option) (*Client, error) {
+	base, err := DiscoverBaseURL(ctx, accessID, accessKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(accessID, accessKey, append(opts, WithBaseURL(base))...)
+}
+
+// DeploymentOf returns the Deployment with the base URL. It returns false if
+// the base URL is not the one of a known deployment.
+func DeploymentOf(baseURL string) (Deployment, bool) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	for _, d := range Deployments {
+		if strings.EqualFold(baseURL, d.BaseURL()) {
+			return d, true
+		}
+	}
+	return "", false
+}
+
+// Deployment returns the Deployment of the base URL the Client uses. It
+// returns false if it is not the base URL of a known deployment.
+func (c *Client) Deployment() (Deployment, bool) {
+	return DeploymentOf(c.BaseURL())
+}
@@ -0,0 +1,120 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// roundTripFunc is an http.RoundTripper that calls a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// deploymentTransport answers requests to every deployment, accepting the
+// credentials at the one with the host and rejecting them elsewhere. It
+// records the hosts requests were sent to.
+type deploymentTransport struct {
+	accept string
+	mu     sync.Mutex
+	hosts  []string
+}
+
+func (t *deploymentTransport) client() *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		t.mu.Lock()
+		t.hosts = append(t.hosts, r.URL.Host)
+		t.mu.Unlock()
+		status := http.StatusUnauthorized
+		if r.URL.Host == t.accept {
+			status = http.StatusOK
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Request:    r,
+		}, nil
+	})}
+}
+
+func TestDiscoverBaseURL(t *testing.T) {
+	tr := &deploymentTransport{accept: "api.eu.sumologic.com"}
+	base, err := DiscoverBaseURL(context.Background(), "discover", "key", WithHTTPClient(tr.client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := DeploymentEU.BaseURL(); base != want {
+		t.Errorf("DiscoverBaseURL = %s, want %s", base, want)
+	}
+	n := len(tr.hosts)
+	if n < 2 || tr.hosts[0] != "api.sumologic.com" || tr.hosts[n-1] != "api.eu.sumologic.com" {
+		t.Errorf("requests sent to %q, want us1 first and eu last", tr.hosts)
+	}
+	// The base URL is cached.
+	if _, err := DiscoverBaseURL(context.Background(), "discover", "key", WithHTTPClient(tr.client())); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.hosts) != n {
+		t.Errorf("the second call made %d requests, want none", len(tr.hosts)-n)
+	}
+}
+
+func TestDiscoverBaseURLNoDeployment(t *testing.T) {
+	tr := &deploymentTransport{}
+	_, err := DiscoverBaseURL(context.Background(), "discover-none", "key", WithHTTPClient(tr.client()))
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("DiscoverBaseURL error = %v, want ErrUnauthorized", err)
+	}
+	if len(tr.hosts) != len(Deployments) {
+		t.Errorf("requests sent to %q, want one to each deployment", tr.hosts)
+	}
+}
+
+func TestDiscoverBaseURLWithBaseURL(t *testing.T) {
+	var requests int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	for _, opt := range []Option{WithBaseURL(srv.URL + "/api"), WithDeployment(DeploymentAU)} {
+		tr := &deploymentTransport{}
+		base, err := DiscoverBaseURL(context.Background(), "discover-configured", "key", opt, WithHTTPClient(tr.client()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, _ := NewClient("discover-configured", "key", opt)
+		if base != c.BaseURL() {
+			t.Errorf("DiscoverBaseURL = %s, want the configured %s", base, c.BaseURL())
+		}
+		if len(tr.hosts) != 0 {
+			t.Errorf("requests sent to %q, want none", tr.hosts)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("server received %d requests, want none", requests)
+	}
+}
+
+// TestDiscoverBaseURLIgnoresOtherBaseURLs checks that a redirect followed
+// from a custom base URL is not used for a Client with the default base
+// URL.
+func TestDiscoverBaseURLIgnoresOtherBaseURLs(t *testing.T) {
+	discoveredBaseURLs.Store(discoveryKey{"discover-other", "https://proxy.example.com/api"}, "https://proxy.example.com/eu/api")
+	tr := &deploymentTransport{accept: "api.sumologic.com"}
+	base, err := DiscoverBaseURL(context.Background(), "discover-other", "key", WithHTTPClient(tr.client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base != DefaultBaseURL {
+		t.Errorf("DiscoverBaseURL = %s, want %s", base, DefaultBaseURL)
+	}
+}
@@ -0,0 +1,137 @@
+package sumoapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for common failures. They are matched by Error and can be
+// checked with errors.Is.
+var (
+	// ErrUnauthorized matches an Error with a 401 status code, returned
+	// when the access ID or access key is wrong.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches an Error with a 403 status code, returned when
+	// the access key lacks the capabilities for the request.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound matches an Error with a 404 status code.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict matches an Error with a 409 status code.
+	ErrConflict = errors.New("conflict")
+	// ErrPreconditionFailed matches an Error with a 412 status code,
+	// returned when an update is made with an outdated ETag.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrRateLimited matches an Error with a 429 status code.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// maxErrorBodySize is the number of bytes of an error response that are
+// read.
+const maxErrorBodySize = 64 * 1024
+
+// Error is returned when the management API responds with a status code
+// outside the 2xx range. Use errors.As to retrieve it from the errors
+// returned by the Client.
+type Error struct {
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// ID identifies the failed request, and is useful when contacting Sumo
+	// Logic support.
+	ID string
+	// Errors are the errors reported in the response body.
+	Errors []ErrorDetail
+	// Body is the response body when it is not in the documented error
+	// format.
+	Body string
+	// RateLimit is the rate limit state reported by the response, such as
+	// the Retry-After delay of a rate limited request. It is nil if the
+	// response had no rate limit headers.
+	RateLimit *RateLimit
+}
+
+// ErrorDetail is a single error reported by the management API.
+type ErrorDetail struct {
+	// Code is a machine readable code, such as "collectors:invalid_name".
+	Code string `json:"code"`
+	// Message explains the error.
+	Message string `json:"message"`
+	// Detail holds further information, when there is any.
+	Detail string `json:"detail,omitempty"`
+	// Meta holds data specific to the error.
+	Meta map[string]any `json:"meta,omitempty"`
+}
+
+func (e Error) Error() string {
+	msg := fmt.Sprintf("sumo logic api: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	var details []string
+	for _, d := range e.Errors {
+		switch {
+		case d.Code != "" && d.Message != "":
+			details = append(details, d.Code+": "+d.Message)
+		case d.Message != "":
+			details = append(details, d.Message)
+		default:
+			details = append(details, d.Code)
+		}
+	}
+	if len(details) > 0 {
+		msg += ": " + strings.Join(details, "; ")
+	} else if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+// Is reports whether the target is the sentinel for the status code.
+func (e Error) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrPreconditionFailed:
+		return e.StatusCode == http.StatusPreconditionFailed
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// HasCode reports whether any of the errors in the response has the code.
+func (e Error) HasCode(code string) bool {
+	for _, d := range e.Errors {
+		if d.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// newError reads an error response.
+func newError(resp *http.Response) Error {
+	e := Error{StatusCode: resp.StatusCode}
+	if l, ok := parseRateLimit(resp.Header, time.Now()); ok {
+		e.RateLimit = &l
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	var parsed struct {
+		ID     string        `json:"id"`
+		Errors []ErrorDetail `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil && len(parsed.Errors) > 0 {
+		e.ID = parsed.ID
+		e.Errors = parsed.Errors
+		return e
+	}
+	e.Body = strings.TrimSpace(string(body))
+	return e
+}
@@ -0,0 +1,55 @@
+package sumoapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// EstimatedUsage is the amount of data a search would scan.
+type EstimatedUsage struct {
+	// Tier is the data tier the data is in, such as "Infrequent". It is
+	// empty for the total returned by EstimateSearchUsage.
+	Tier               string `json:"tier,omitempty"`
+	DataScannedInBytes int64  `json:"dataScannedInBytes"`
+}
+
+// EstimateSearchUsage returns how much data the search would scan, without
+// running it, so an expensive search can be caught before it is run, such as
+// on accounts billed by the data scanned.
+func (c *Client) EstimateSearchUsage(ctx context.Context, req SearchJobRequest) (*EstimatedUsage, error) {
+	var resp struct {
+		Details EstimatedUsage `json:"estimatedUsageDetails"`
+	}
+	if err := c.post(ctx, "/v1/logSearches/estimatedUsage", estimateBody(req), &resp); err != nil {
+		return nil, fmt.Errorf("error estimating search usage: %w", err)
+	}
+	return &resp.Details, nil
+}
+
+// EstimateSearchUsageByTier is like EstimateSearchUsage but breaks the
+// estimate down by data tier.
+func (c *Client) EstimateSearchUsageByTier(ctx context.Context, req SearchJobRequest) ([]EstimatedUsage, error) {
+	var resp struct {
+		Details []EstimatedUsage `json:"estimatedUsageDetails"`
+	}
+	if err := c.post(ctx, "/v1/logSearches/estimatedUsageByTier", estimateBody(req), &resp); err != nil {
+		return nil, fmt.Errorf("error estimating search usage: %w", err)
+	}
+	return resp.Details, nil
+}
+
+// estimateBody returns the body of an estimated usage request for the
+// search.
+func estimateBody(req SearchJobRequest) any {
+	tz := req.TimeZone
+	if tz == "" {
+		tz = "UTC"
+	}
+	return struct {
+		QueryString     string      `json:"queryString"`
+		TimeRange       any         `json:"timeRange"`
+		Timezone        string      `json:"timezone"`
+		ByReceiptTime   bool        `json:"byReceiptTime"`
+		AutoParsingMode ParsingMode `json:"autoParsingMode,omitempty"`
+	}{req.Query, epochTimeRange(req.From, req.To), tz, req.ByReceiptTime, req.ParsingMode}
+}
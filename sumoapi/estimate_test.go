@@ -0,0 +1,77 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestEstimateSearchUsage(t *testing.T) {
+	ctx := context.Background()
+	req := SearchJobRequest{Query: "_sourceCategory=web", From: time.UnixMilli(1000), To: time.UnixMilli(2000)}
+	body := `{
+		"queryString": "_sourceCategory=web",
+		"timeRange": {
+			"type": "BeginBoundedTimeRange",
+			"from": {"type": "EpochTimeRangeBoundary", "epochMillis": 1000},
+			"to": {"type": "EpochTimeRangeBoundary", "epochMillis": 2000}
+		},
+		"timezone": "UTC",
+		"byReceiptTime": false
+	}`
+	testCalls(t, []apiCall{
+		{
+			name: "total",
+			call: func(c *Client) error {
+				usage, err := c.EstimateSearchUsage(ctx, req)
+				if err != nil {
+					return err
+				}
+				return expect(*usage, EstimatedUsage{DataScannedInBytes: 4096})
+			},
+			response: `{"estimatedUsageDetails": {"dataScannedInBytes": 4096}}`,
+			method:   http.MethodPost,
+			path:     "/v1/logSearches/estimatedUsage",
+			body:     body,
+		},
+		{
+			name: "by tier",
+			call: func(c *Client) error {
+				usage, err := c.EstimateSearchUsageByTier(ctx, req)
+				if err != nil {
+					return err
+				}
+				return expect(usage, []EstimatedUsage{{Tier: "Continuous", DataScannedInBytes: 1024}, {Tier: "Infrequent", DataScannedInBytes: 3072}})
+			},
+			response: `{"estimatedUsageDetails": [{"tier": "Continuous", "dataScannedInBytes": 1024}, {"tier": "Infrequent", "dataScannedInBytes": 3072}]}`,
+			method:   http.MethodPost,
+			path:     "/v1/logSearches/estimatedUsageByTier",
+			body:     body,
+		},
+		{
+			name: "time zone, receipt time and parsing mode",
+			call: func(c *Client) error {
+				r := req
+				r.TimeZone = "Europe/Paris"
+				r.ByReceiptTime = true
+				r.ParsingMode = ParsingModeManual
+				_, err := c.EstimateSearchUsage(ctx, r)
+				return err
+			},
+			method: http.MethodPost,
+			path:   "/v1/logSearches/estimatedUsage",
+			body: `{
+				"queryString": "_sourceCategory=web",
+				"timeRange": {
+					"type": "BeginBoundedTimeRange",
+					"from": {"type": "EpochTimeRangeBoundary", "epochMillis": 1000},
+					"to": {"type": "EpochTimeRangeBoundary", "epochMillis": 2000}
+				},
+				"timezone": "Europe/Paris",
+				"byReceiptTime": true,
+				"autoParsingMode": "Manual"
+			}`,
+		},
+	})
+}
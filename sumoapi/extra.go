@@ -0,0 +1,147 @@
+package sumoapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The models of the management API keep the properties they have no field
+// for in an Extra map, so attributes added to the API are neither lost when
+// a model is read nor when it is sent back, such as by an update. Each model
+// implements json.Unmarshaler and json.Marshaler with unmarshalExtra and
+// marshalExtra, which it calls with a copy of its type without methods:
+//
+//	func (u *User) UnmarshalJSON(b []byte) error {
+//		type user User
+//		return unmarshalExtra(b, (*user)(u), &u.Extra)
+//	}
+
+// unmarshalExtra decodes b into v, and the properties of b that no field of
+// v is decoded from into extra. extra is set to nil if there are none.
+func unmarshalExtra[T any](b []byte, v *T, extra *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	*extra = nil
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		return nil
+	}
+	var props map[string]json.RawMessage
+	if err := json.Unmarshal(b, &props); err != nil {
+		return err
+	}
+	known := jsonNames(reflect.TypeFor[T]())
+	for k, raw := range props {
+		if known[strings.ToLower(k)] {
+			continue
+		}
+		if *extra == nil {
+			*extra = make(map[string]json.RawMessage)
+		}
+		(*extra)[k] = raw
+	}
+	return nil
+}
+
+// marshalExtra encodes v along with the properties in extra that v has no
+// field for, which are added in the order of their names.
+func marshalExtra[T any](v T, extra map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+	known := jsonNames(reflect.TypeFor[T]())
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		if !known[strings.ToLower(k)] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.Write(b[:len(b)-1])
+	sep := len(b) > 2
+	for _, k := range keys {
+		name, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		if sep {
+			buf.WriteByte(',')
+		}
+		sep = true
+		buf.Write(name)
+		buf.WriteByte(':')
+		if err := json.Compact(&buf, extra[k]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// extraBody is a request body encoded along with the properties in extra,
+// for models that are sent as a copy without the fields only set by the API,
+// which does not have their MarshalJSON method.
+type extraBody[T any] struct {
+	body  T
+	extra map[string]json.RawMessage
+}
+
+// withExtra returns a request body encoding body along with the properties
+// in extra.
+func withExtra[T any](body T, extra map[string]json.RawMessage) extraBody[T] {
+	return extraBody[T]{body, extra}
+}
+
+// MarshalJSON encodes the body along with the properties in extra.
+func (b extraBody[T]) MarshalJSON() ([]byte, error) {
+	return marshalExtra(b.body, b.extra)
+}
+
+// jsonNameCache holds the names returned by jsonNames by type.
+var jsonNameCache sync.Map
+
+// jsonNames returns the lowercased names of the JSON properties the fields
+// of the struct type are encoded as, including those of embedded structs,
+// since encoding/json matches properties to fields ignoring case.
+func jsonNames(t reflect.Type) map[string]bool {
+	if names, ok := jsonNameCache.Load(t); ok {
+		return names.(map[string]bool)
+	}
+	names := make(map[string]bool)
+	addJSONNames(t, names)
+	jsonNameCache.Store(t, names)
+	return names
+}
+
+// addJSONNames adds the names of the fields of the struct type to names.
+func addJSONNames(t reflect.Type, names map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && !hasTag && ft.Kind() == reflect.Struct {
+			addJSONNames(ft, names)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+}
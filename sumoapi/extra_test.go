@@ -0,0 +1,317 @@
+package sumoapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestExtraRoundTrip decodes payloads with properties the models have no
+// field for, at the top level and in nested models, and checks that encoding
+// the model again returns the same payload.
+func TestExtraRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   func() any
+		payload string
+	}{
+		{
+			name:  "dashboard",
+			model: func() any { return new(Dashboard) },
+			payload: `{
+				"title": "Latency",
+				"panels": [{
+					"key": "p1",
+					"title": "p99",
+					"panelType": "SumoSearchPanel",
+					"keepVisualSettingsConsistentWithParent": true,
+					"queries": [{"queryString": "*", "queryType": "Logs", "queryKey": "A", "outputCardinalityLimit": 1000}],
+					"linkedDashboards": [{"id": "d2"}]
+				}],
+				"layout": {
+					"layoutType": "Grid",
+					"layoutStructures": [{"key": "p1", "structure": "{}", "pinned": true}],
+					"columns": 24
+				},
+				"variables": [{
+					"name": "env",
+					"sourceDefinition": {"variableSourceType": "CsvVariableSourceDefinition", "values": "prod,dev", "delimiter": ","},
+					"allowMultiSelect": false,
+					"includeAllOption": true,
+					"hideFromUI": false,
+					"valueType": "String"
+				}],
+				"topologyLabelMap": {"data": {}}
+			}`,
+		},
+		{
+			name:  "monitor",
+			model: func() any { return new(Monitor) },
+			payload: `{
+				"name": "Errors",
+				"type": "MonitorsLibraryMonitor",
+				"createdAt": "2024-01-02T03:04:05Z",
+				"modifiedAt": "2024-01-02T03:04:05Z",
+				"queries": [{"rowId": "A", "query": "error", "queryHint": "logs"}],
+				"triggers": [{
+					"triggerType": "Critical",
+					"threshold": 10,
+					"timeRange": "-15m",
+					"minDataPoints": 2
+				}],
+				"notifications": [{
+					"notification": {"connectionType": "Email", "recipients": ["ops@example.com"], "resolutionPayloadOverride": "{}"},
+					"runForTriggerTypes": ["Critical"],
+					"notificationGroupFields": ["host"]
+				}],
+				"alertName": "{{Name}}"
+			}`,
+		},
+		{
+			name:  "slo",
+			model: func() any { return new(SLO) },
+			payload: `{
+				"name": "Availability",
+				"type": "SlosLibrarySlo",
+				"createdAt": "2024-01-02T03:04:05Z",
+				"modifiedAt": "2024-01-02T03:04:05Z",
+				"compliance": {"complianceType": "Rolling", "target": 99.9, "timezone": "UTC", "size": "7d", "complianceName": "weekly"},
+				"indicator": {
+					"evaluationType": "Window",
+					"queryType": "Logs",
+					"queries": [{
+						"queryGroupType": "Successful",
+						"queryGroup": [{"rowId": "A", "query": "*", "useRowCount": true, "weight": 1}],
+						"label": "ok"
+					}],
+					"threshold": 200,
+					"trackBy": ["service"]
+				},
+				"tags": {"team": "web"}
+			}`,
+		},
+		{
+			name:  "cse rule",
+			model: func() any { return new(CSERule) },
+			payload: `{
+				"ruleType": "match",
+				"name": "Login failures",
+				"enabled": true,
+				"descriptionExpression": "failure",
+				"entitySelectors": [{"entityType": "_username", "expression": "user", "entityTypeName": "Username"}],
+				"isPrototype": false,
+				"scoreMapping": {
+					"type": "fieldValueMapping",
+					"default": 3,
+					"mapping": [{"type": "eq", "default": 0, "from": "high", "to": 8, "caseSensitive": false}],
+					"priority": 1
+				},
+				"expressionsAndLimits": [{"expression": "a", "limit": 1, "weight": 2}],
+				"aggregationFunctions": [{"name": "n", "function": "count", "arguments": ["x"], "distinct": true}],
+				"created": "2024-01-02T03:04:05Z",
+				"lastUpdated": null,
+				"suppressionWindowSize": 3600
+			}`,
+		},
+		{
+			name:  "source",
+			model: func() any { return new(Source) },
+			payload: `{
+				"name": "app",
+				"sourceType": "HTTP",
+				"automaticDateParsing": true,
+				"multilineProcessingEnabled": false,
+				"useAutolineMatching": false,
+				"forceTimeZone": false,
+				"defaultDateFormats": [{"format": "yyyy", "locator": "t=", "priority": 1}],
+				"filters": [{"name": "mask", "filterType": "Mask", "regexp": "x", "mask": "#", "enabled": true}],
+				"path": {
+					"type": "RumSourcePath",
+					"serviceName": "web",
+					"selectedCountry": "US"
+				},
+				"hashAlgorithm": "SHA-256"
+			}`,
+		},
+		{
+			name:  "connection",
+			model: func() any { return new(Connection) },
+			payload: `{
+				"name": "hook",
+				"type": "WebhookDefinition",
+				"url": "https://example.com",
+				"headers": [{"name": "X-Key", "value": "1", "secret": true}],
+				"defaultPayload": "{}",
+				"createdAt": "2024-01-02T03:04:05Z",
+				"modifiedAt": "2024-01-02T03:04:05Z",
+				"region": "us-east-1"
+			}`,
+		},
+		{
+			name:  "lookup table",
+			model: func() any { return new(LookupTable) },
+			payload: `{
+				"name": "hosts",
+				"description": "",
+				"fields": [{"fieldName": "host", "fieldType": "string", "nullable": false}],
+				"primaryKeys": ["host"],
+				"parentFolderId": "f1",
+				"createdAt": "2024-01-02T03:04:05Z",
+				"modifiedAt": "2024-01-02T03:04:05Z",
+				"contentPath": "/Library/hosts"
+			}`,
+		},
+		{
+			name:  "saml configuration",
+			model: func() any { return new(SAMLConfiguration) },
+			payload: `{
+				"configurationName": "okta",
+				"issuer": "okta",
+				"x509cert1": "cert",
+				"spInitiatedLoginEnabled": false,
+				"onDemandProvisioningEnabled": {"onDemandProvisioningRoles": ["admin"], "defaultRole": "viewer"},
+				"logoutEnabled": false,
+				"debugMode": false,
+				"signAuthnRequest": false,
+				"disableRequestedAuthnContext": false,
+				"isRedirectBinding": false,
+				"createdAt": "2024-01-02T03:04:05Z",
+				"modifiedAt": "2024-01-02T03:04:05Z",
+				"sessionTimeout": 3600
+			}`,
+		},
+		{
+			name:  "organization",
+			model: func() any { return new(Organization) },
+			payload: `{
+				"orgName": "child",
+				"email": "a@example.com",
+				"firstName": "A",
+				"lastName": "B",
+				"baselines": {"continuousIngest": 10, "logsIngest": 5},
+				"createdAt": "2024-01-02T03:04:05Z",
+				"parentDeploymentId": "us2"
+			}`,
+		},
+		{
+			name:    "allowlist entry",
+			model:   func() any { return new(AllowlistEntry) },
+			payload: `{"cidr": "192.0.2.0/24", "description": "office", "createdBy": "u1"}`,
+		},
+		{
+			name:  "content permissions",
+			model: func() any { return new(ContentPermissions) },
+			payload: `{
+				"explicitPermissions": [{"permissionName": "View", "sourceType": "user", "sourceId": "u1", "contentId": "c1", "inherited": false}],
+				"implicitPermissions": []
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.model()
+			if err := json.Unmarshal([]byte(tt.payload), v); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var want, got any
+			if err := json.Unmarshal([]byte(tt.payload), &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(encoded, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip changed the payload\n got: %s\nwant: %s", encoded, compact(t, tt.payload))
+			}
+		})
+	}
+}
+
+// TestExtraDoesNotOverrideFields checks that a property in Extra that has
+// a field, such as one set by hand, does not replace the field when the
+// model is encoded.
+func TestExtraDoesNotOverrideFields(t *testing.T) {
+	e := AllowlistEntry{
+		CIDR: "192.0.2.0/24",
+		Extra: map[string]json.RawMessage{
+			"CIDR": json.RawMessage(`"198.51.100.0/24"`),
+			"b":    json.RawMessage(`2`),
+			"a":    json.RawMessage(`{"x": 1}`),
+		},
+	}
+	got, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"cidr":"192.0.2.0/24","a":{"x":1},"b":2}`; string(got) != want {
+		t.Errorf("Marshal = %s, want %s", got, want)
+	}
+}
+
+// TestExtraReset checks that decoding into a model that already has Extra
+// replaces it.
+func TestExtraReset(t *testing.T) {
+	e := AllowlistEntry{Extra: map[string]json.RawMessage{"old": json.RawMessage(`1`)}}
+	if err := json.Unmarshal([]byte(`{"cidr": "192.0.2.0/24"}`), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Extra != nil {
+		t.Errorf("Extra = %v, want nil", e.Extra)
+	}
+}
+
+func compact(t *testing.T, s string) string {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// TestExtraInRequestBodies checks that the bodies of models sent without the
+// fields only set by the API keep their Extra properties.
+func TestExtraInRequestBodies(t *testing.T) {
+	extra := map[string]json.RawMessage{"newSetting": json.RawMessage(`true`)}
+	tests := []struct {
+		name string
+		body any
+		// path is the properties leading to the object holding the model.
+		path []string
+	}{
+		{"monitor", monitorBody(Monitor{Name: "m", Extra: extra}), nil},
+		{"slo", sloBody(SLO{Name: "s", Extra: extra}), nil},
+		{"saml configuration", samlBody(SAMLConfiguration{ConfigurationName: "c", Extra: extra}), nil},
+		{"cse rule", cseRuleBody(CSERule{Name: "r", Extra: extra}), []string{"fields"}},
+		{"tuning expression", tuningExpressionBody(TuningExpression{Name: "e", Extra: extra}), []string{"fields"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var obj map[string]any
+			if err := json.Unmarshal(b, &obj); err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range tt.path {
+				obj, _ = obj[p].(map[string]any)
+			}
+			if obj["newSetting"] != true {
+				t.Errorf("body = %s, want the Extra properties", b)
+			}
+			if _, ok := obj["createdAt"]; ok {
+				t.Errorf("body = %s, want no fields set by the API", b)
+			}
+		})
+	}
+}
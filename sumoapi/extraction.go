@@ -0,0 +1,161 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrInvalidParseExpression matches a ParseExpressionError and can be
+// checked with errors.Is.
+var ErrInvalidParseExpression = errors.New("invalid parse expression")
+
+// ParseExpressionError is returned when a field extraction rule is created
+// or updated with a scope or parse expression Sumo Logic fails to parse. Use
+// errors.As to retrieve it, and the underlying Error, from the errors
+// returned by the Client.
+type ParseExpressionError struct {
+	// Rule is the name of the rule.
+	Rule string
+	// Expression is the parse expression that was rejected.
+	Expression string
+	// Message explains why the expression was rejected.
+	Message string
+	// Err is the Error the management API responded with.
+	Err error
+}
+
+func (e ParseExpressionError) Error() string {
+	return fmt.Sprintf("invalid parse expression for rule '%s': %s", e.Rule, e.Message)
+}
+
+func (e ParseExpressionError) Unwrap() error {
+	return e.Err
+}
+
+func (e ParseExpressionError) Is(target error) bool {
+	return target == ErrInvalidParseExpression
+}
+
+// ExtractionRule is a field extraction rule, which parses fields out of logs
+// at ingest.
+type ExtractionRule struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	// Scope is the query selecting the logs the rule applies to, such as
+	// _sourceCategory=prod/web.
+	Scope string `json:"scope"`
+	// ParseExpression extracts the fields, such as
+	// parse "user=*," as user.
+	ParseExpression string    `json:"parseExpression"`
+	Enabled         bool      `json:"enabled"`
+	CreatedAt       time.Time `json:"createdAt"`
+	CreatedBy       string    `json:"createdBy,omitempty"`
+	ModifiedAt      time.Time `json:"modifiedAt"`
+	ModifiedBy      string    `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the extraction rule that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the extraction rule, keeping the properties that
+// have no field in Extra.
+func (r *ExtractionRule) UnmarshalJSON(b []byte) error {
+	type plain ExtractionRule
+	return unmarshalExtra(b, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes the extraction rule along with the properties in
+// Extra.
+func (r ExtractionRule) MarshalJSON() ([]byte, error) {
+	type plain ExtractionRule
+	return marshalExtra(plain(r), r.Extra)
+}
+
+// ListExtractionRules returns every field extraction rule, following
+// pagination.
+func (c *Client) ListExtractionRules(ctx context.Context) ([]ExtractionRule, error) {
+	return Collect(c.IterateExtractionRules(ctx))
+}
+
+// IterateExtractionRules returns an Iterator over the extraction rules
+// returned by ListExtractionRules, requesting each page as it is read.
+func (c *Client) IterateExtractionRules(ctx context.Context) *Iterator[ExtractionRule] {
+	return iterateTokens[ExtractionRule](ctx, c, "listing extraction rules", "/v1/extractionRules", nil)
+}
+
+// GetExtractionRule returns the field extraction rule with the ID.
+func (c *Client) GetExtractionRule(ctx context.Context, id string) (*ExtractionRule, error) {
+	var rule ExtractionRule
+	if err := c.get(ctx, pathf("/v1/extractionRules/%s", id), nil, &rule); err != nil {
+		return nil, fmt.Errorf("error getting extraction rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// CreateExtractionRule creates a field extraction rule and returns it. If
+// the parse expression is rejected the error is a ParseExpressionError.
+func (c *Client) CreateExtractionRule(ctx context.Context, rule ExtractionRule) (*ExtractionRule, error) {
+	var created ExtractionRule
+	if err := c.post(ctx, "/v1/extractionRules", extractionRuleBody(rule), &created); err != nil {
+		return nil, fmt.Errorf("error creating extraction rule: %w", parseExpressionError(rule, err))
+	}
+	return &created, nil
+}
+
+// UpdateExtractionRule replaces the field extraction rule with the same ID
+// and returns the updated rule. If the parse expression is rejected the
+// error is a ParseExpressionError.
+func (c *Client) UpdateExtractionRule(ctx context.Context, rule ExtractionRule) (*ExtractionRule, error) {
+	var updated ExtractionRule
+	if err := c.put(ctx, pathf("/v1/extractionRules/%s", rule.ID), extractionRuleBody(rule), &updated); err != nil {
+		return nil, fmt.Errorf("error updating extraction rule: %w", parseExpressionError(rule, err))
+	}
+	return &updated, nil
+}
+
+// DeleteExtractionRule deletes the field extraction rule with the ID.
+func (c *Client) DeleteExtractionRule(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/extractionRules/%s", id)); err != nil {
+		return fmt.Errorf("error deleting extraction rule: %w", err)
+	}
+	return nil
+}
+
+// extractionRuleBody returns the fields of the rule that can be set.
+func extractionRuleBody(rule ExtractionRule) any {
+	return struct {
+		Name            string `json:"name"`
+		Scope           string `json:"scope"`
+		ParseExpression string `json:"parseExpression"`
+		Enabled         bool   `json:"enabled"`
+	}{rule.Name, rule.Scope, rule.ParseExpression, rule.Enabled}
+}
+
+// parseExpressionError returns a ParseExpressionError if err is an Error
+// rejecting the scope or parse expression of the rule, and err otherwise.
+func parseExpressionError(rule ExtractionRule, err error) error {
+	var apiErr Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return err
+	}
+	for _, d := range apiErr.Errors {
+		code := strings.ToLower(d.Code)
+		if !strings.HasPrefix(code, "fer:") {
+			continue
+		}
+		if strings.Contains(code, "parse") || strings.Contains(code, "expression") || strings.Contains(code, "scope") {
+			msg := d.Message
+			if d.Detail != "" {
+				msg += ": " + d.Detail
+			}
+			return ParseExpressionError{Rule: rule.Name, Expression: rule.ParseExpression, Message: msg, Err: apiErr}
+		}
+	}
+	return err
+}
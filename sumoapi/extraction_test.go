@@ -0,0 +1,127 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestListExtractionRulesFollowsTokens(t *testing.T) {
+	api := newTestAPI(t)
+	api.handle("GET /v1/extractionRules", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token") == "" {
+			io.WriteString(w, `{"data": [{"id": "1", "name": "a"}], "next": "t2"}`)
+			return
+		}
+		io.WriteString(w, `{"data": [{"id": "2", "name": "b", "fieldNames": ["user"]}]}`)
+	})
+	rules, err := api.client().ListExtractionRules(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 || rules[0].ID != "1" || rules[1].ID != "2" {
+		t.Fatalf("ListExtractionRules = %+v, want both pages", rules)
+	}
+	if got := string(rules[1].Extra["fieldNames"]); got != `["user"]` {
+		t.Errorf("Extra = %s", got)
+	}
+	reqs := api.received()
+	if len(reqs) != 2 {
+		t.Fatalf("received %d requests, want 2", len(reqs))
+	}
+	for i, want := range []string{"limit=100", "limit=100&token=t2"} {
+		checkRequest(t, reqs[i], http.MethodGet, "/v1/extractionRules", "")
+		if got := reqs[i].query.Encode(); got != want {
+			t.Errorf("page %d query = %s, want %s", i+1, got, want)
+		}
+	}
+}
+
+func TestExtractionRules(t *testing.T) {
+	ctx := context.Background()
+	rule := ExtractionRule{ID: "1", Name: "users", Scope: "_sourceCategory=web", ParseExpression: `parse "user=*," as user`, Enabled: true, CreatedBy: "u1"}
+	body := `{"name": "users", "scope": "_sourceCategory=web", "parseExpression": "parse \"user=*,\" as user", "enabled": true}`
+	response := `{"id": "1", "name": "users", "scope": "_sourceCategory=web", "enabled": true}`
+	testCalls(t, []apiCall{
+		{
+			name: "get",
+			call: func(c *Client) error {
+				r, err := c.GetExtractionRule(ctx, "1")
+				if err != nil {
+					return err
+				}
+				return expect(r.Name, "users")
+			},
+			response: response,
+			method:   http.MethodGet,
+			path:     "/v1/extractionRules/1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				r, err := c.CreateExtractionRule(ctx, rule)
+				if err != nil {
+					return err
+				}
+				return expect(r.ID, "1")
+			},
+			response: response,
+			method:   http.MethodPost,
+			path:     "/v1/extractionRules",
+			body:     body,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateExtractionRule(ctx, rule)
+				return err
+			},
+			response: response,
+			method:   http.MethodPut,
+			path:     "/v1/extractionRules/1",
+			body:     body,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteExtractionRule(ctx, "1") },
+			method: http.MethodDelete,
+			path:   "/v1/extractionRules/1",
+		},
+	})
+}
+
+func TestParseExpressionError(t *testing.T) {
+	tests := []struct {
+		name  string
+		code  string
+		isFER bool
+	}{
+		{"parse expression", "fer:invalid_parse_expression", true},
+		{"scope", "fer:invalid_scope", true},
+		{"other rule error", "fer:name_already_exists", false},
+		{"other error", "bad_request", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI(t)
+			api.handle("POST /v1/extractionRules", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, `{"errors": [{"code": "`+tt.code+`", "message": "rejected", "detail": "at 1"}]}`)
+			})
+			_, err := api.client().CreateExtractionRule(context.Background(), ExtractionRule{Name: "users", ParseExpression: "parse"})
+			if got := errors.Is(err, ErrInvalidParseExpression); got != tt.isFER {
+				t.Fatalf("errors.Is(%v, ErrInvalidParseExpression) = %v, want %v", err, got, tt.isFER)
+			}
+			var pe ParseExpressionError
+			if tt.isFER && (!errors.As(err, &pe) || pe.Rule != "users" || pe.Expression != "parse" || pe.Message != "rejected: at 1") {
+				t.Errorf("ParseExpressionError = %+v", pe)
+			}
+			var apiErr Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+				t.Errorf("error = %v, want it to wrap the Error", err)
+			}
+		})
+	}
+}
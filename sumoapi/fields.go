@@ -0,0 +1,172 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Field states.
+const (
+	FieldEnabled  = "Enabled"
+	FieldDisabled = "Disabled"
+)
+
+// Field is a field in the field schema of the account. Custom fields are the
+// ones that can be sent with logs, such as with the X-Sumo-Fields header or
+// gosumo.WithFields, and only fields in the schema are kept at ingest.
+type Field struct {
+	FieldID   string `json:"fieldId,omitempty"`
+	FieldName string `json:"fieldName"`
+	// DataType is the type of the field's values, such as "String".
+	DataType string `json:"dataType,omitempty"`
+	// State is FieldEnabled or FieldDisabled. Disabled fields are dropped at
+	// ingest but still count against the quota.
+	State string `json:"state,omitempty"`
+
+	// Extra holds the properties of the field that have no field, which are
+	// kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the field, keeping the properties that have no field
+// in Extra.
+func (f *Field) UnmarshalJSON(b []byte) error {
+	type plain Field
+	return unmarshalExtra(b, (*plain)(f), &f.Extra)
+}
+
+// MarshalJSON encodes the field along with the properties in Extra.
+func (f Field) MarshalJSON() ([]byte, error) {
+	type plain Field
+	return marshalExtra(plain(f), f.Extra)
+}
+
+// Enabled reports whether the field is kept at ingest.
+func (f Field) Enabled() bool {
+	return f.State != FieldDisabled
+}
+
+// FieldQuota is the number of custom fields the account can have.
+type FieldQuota struct {
+	Quota     int `json:"quota"`
+	Remaining int `json:"remaining"`
+}
+
+// DroppedField is a field that was sent with logs but dropped at ingest
+// because it is not in the field schema.
+type DroppedField struct {
+	FieldName string `json:"fieldName"`
+}
+
+// ListFields returns the custom fields of the account.
+func (c *Client) ListFields(ctx context.Context) ([]Field, error) {
+	var resp struct {
+		Data []Field `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/fields", nil, &resp); err != nil {
+		return nil, fmt.Errorf("error listing fields: %w", err)
+	}
+	return resp.Data, nil
+}
+
+// ListBuiltinFields returns the built-in fields, such as _sourceCategory,
+// which cannot be changed.
+func (c *Client) ListBuiltinFields(ctx context.Context) ([]Field, error) {
+	var resp struct {
+		Data []Field `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/fields/builtin", nil, &resp); err != nil {
+		return nil, fmt.Errorf("error listing builtin fields: %w", err)
+	}
+	return resp.Data, nil
+}
+
+// GetField returns the custom field with the ID.
+func (c *Client) GetField(ctx context.Context, id string) (*Field, error) {
+	var f Field
+	if err := c.get(ctx, pathf("/v1/fields/%s", id), nil, &f); err != nil {
+		return nil, fmt.Errorf("error getting field: %w", err)
+	}
+	return &f, nil
+}
+
+// GetFieldByName returns the custom field with the name, matched ignoring
+// case as Sumo Logic does. An error matching ErrNotFound is returned if
+// there is no such field.
+func (c *Client) GetFieldByName(ctx context.Context, name string) (*Field, error) {
+	fields, err := c.ListFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.FieldName, name) {
+			return &f, nil
+		}
+	}
+	return nil, fmt.Errorf("error getting field: %q: %w", name, ErrNotFound)
+}
+
+// CreateField adds a custom field with the name to the field schema and
+// returns it. New fields are enabled.
+func (c *Client) CreateField(ctx context.Context, name string) (*Field, error) {
+	if name == "" {
+		return nil, fmt.Errorf("error creating field: field name is required")
+	}
+	var f Field
+	if err := c.post(ctx, "/v1/fields", Field{FieldName: name}, &f); err != nil {
+		return nil, fmt.Errorf("error creating field: %w", err)
+	}
+	return &f, nil
+}
+
+// DeleteField removes the custom field with the ID from the field schema.
+// Only disabled fields can be deleted.
+func (c *Client) DeleteField(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/fields/%s", id)); err != nil {
+		return fmt.Errorf("error deleting field: %w", err)
+	}
+	return nil
+}
+
+// EnableField enables the custom field with the ID, so it is kept at
+// ingest.
+func (c *Client) EnableField(ctx context.Context, id string) error {
+	if err := c.put(ctx, pathf("/v1/fields/%s/enable", id), nil, nil); err != nil {
+		return fmt.Errorf("error enabling field: %w", err)
+	}
+	return nil
+}
+
+// DisableField disables the custom field with the ID, so it is dropped at
+// ingest.
+func (c *Client) DisableField(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/fields/%s/disable", id)); err != nil {
+		return fmt.Errorf("error disabling field: %w", err)
+	}
+	return nil
+}
+
+// FieldQuota returns how many custom fields the account can have and how
+// many more can be created.
+func (c *Client) FieldQuota(ctx context.Context) (*FieldQuota, error) {
+	var q FieldQuota
+	if err := c.get(ctx, "/v1/fields/quota", nil, &q); err != nil {
+		return nil, fmt.Errorf("error getting field quota: %w", err)
+	}
+	return &q, nil
+}
+
+// ListDroppedFields returns the fields that were recently sent with logs but
+// dropped because they are not in the field schema, which usually means a
+// field passed to gosumo.WithFields needs to be created.
+func (c *Client) ListDroppedFields(ctx context.Context) ([]DroppedField, error) {
+	var resp struct {
+		Data []DroppedField `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/fields/dropped", nil, &resp); err != nil {
+		return nil, fmt.Errorf("error listing dropped fields: %w", err)
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,127 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	ctx := context.Background()
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				fields, err := c.ListFields(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(fields, []Field{{FieldID: "1", FieldName: "team", DataType: "String", State: FieldEnabled}})
+			},
+			response: `{"data": [{"fieldId": "1", "fieldName": "team", "dataType": "String", "state": "Enabled"}]}`,
+			method:   http.MethodGet,
+			path:     "/v1/fields",
+		},
+		{
+			name: "list builtin",
+			call: func(c *Client) error {
+				fields, err := c.ListBuiltinFields(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(len(fields), 1)
+			},
+			response: `{"data": [{"fieldId": "0", "fieldName": "_sourceCategory"}]}`,
+			method:   http.MethodGet,
+			path:     "/v1/fields/builtin",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				f, err := c.GetField(ctx, "1")
+				if err != nil {
+					return err
+				}
+				return expect(f.Enabled(), false)
+			},
+			response: `{"fieldId": "1", "fieldName": "team", "state": "Disabled"}`,
+			method:   http.MethodGet,
+			path:     "/v1/fields/1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				f, err := c.CreateField(ctx, "team")
+				if err != nil {
+					return err
+				}
+				return expect(f.FieldID, "1")
+			},
+			response: `{"fieldId": "1", "fieldName": "team", "state": "Enabled"}`,
+			method:   http.MethodPost,
+			path:     "/v1/fields",
+			body:     `{"fieldName": "team"}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteField(ctx, "1") },
+			method: http.MethodDelete,
+			path:   "/v1/fields/1",
+		},
+		{
+			name:   "enable",
+			call:   func(c *Client) error { return c.EnableField(ctx, "1") },
+			method: http.MethodPut,
+			path:   "/v1/fields/1/enable",
+		},
+		{
+			name:   "disable",
+			call:   func(c *Client) error { return c.DisableField(ctx, "1") },
+			method: http.MethodDelete,
+			path:   "/v1/fields/1/disable",
+		},
+		{
+			name: "quota",
+			call: func(c *Client) error {
+				q, err := c.FieldQuota(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(*q, FieldQuota{Quota: 200, Remaining: 150})
+			},
+			response: `{"quota": 200, "remaining": 150}`,
+			method:   http.MethodGet,
+			path:     "/v1/fields/quota",
+		},
+		{
+			name: "dropped",
+			call: func(c *Client) error {
+				dropped, err := c.ListDroppedFields(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(dropped, []DroppedField{{FieldName: "env"}})
+			},
+			response: `{"data": [{"fieldName": "env"}]}`,
+			method:   http.MethodGet,
+			path:     "/v1/fields/dropped",
+		},
+	})
+}
+
+func TestGetFieldByName(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("GET /v1/fields", `{"data": [{"fieldId": "1", "fieldName": "Team", "retention": 30}]}`)
+	c := api.client()
+	f, err := c.GetFieldByName(context.Background(), "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.FieldID != "1" || string(f.Extra["retention"]) != "30" {
+		t.Errorf("GetFieldByName = %+v", f)
+	}
+	if _, err := c.GetFieldByName(context.Background(), "env"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetFieldByName of a missing field = %v, want ErrNotFound", err)
+	}
+}
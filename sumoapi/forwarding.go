@@ -0,0 +1,303 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Authentication modes of a data forwarding destination.
+const (
+	ForwardingAuthRoleBased = "RoleBased"
+	ForwardingAuthAccessKey = "AccessKey"
+)
+
+// File formats of a data forwarding rule.
+const (
+	ForwardingFormatCSV  = "Csv"
+	ForwardingFormatRaw  = "Raw"
+	ForwardingFormatText = "Text"
+	ForwardingFormatJSON = "Json"
+)
+
+// Payload schemas of a data forwarding rule.
+const (
+	ForwardingSchemaBuiltInFields = "builtInFields"
+	ForwardingSchemaAllFields     = "allFields"
+	ForwardingSchemaRaw           = "raw"
+)
+
+// ForwardingDestination is an AWS S3 bucket logs are forwarded to.
+type ForwardingDestination struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"destinationName"`
+	Description string `json:"description,omitempty"`
+	BucketName  string `json:"bucketName"`
+	S3Region    string `json:"S3Region,omitempty"`
+	// AuthenticationMode is ForwardingAuthRoleBased, which uses RoleARN, or
+	// ForwardingAuthAccessKey, which uses AccessKeyID and SecretAccessKey.
+	AuthenticationMode string `json:"authenticationMode"`
+	RoleARN            string `json:"roleArn,omitempty"`
+	AccessKeyID        string `json:"accessKeyId,omitempty"`
+	// SecretAccessKey is only sent, it is never returned.
+	SecretAccessKey        string `json:"secretAccessKey,omitempty"`
+	S3ServerSideEncryption bool   `json:"s3ServerSideEncryption,omitempty"`
+	Enabled                bool   `json:"enabled"`
+	// InvalidatedBySystem is set when Sumo Logic disabled the destination
+	// because it could not write to the bucket.
+	InvalidatedBySystem bool      `json:"invalidatedBySystem,omitempty"`
+	CreatedAt           time.Time `json:"createdAt"`
+	CreatedBy           string    `json:"createdBy,omitempty"`
+	ModifiedAt          time.Time `json:"modifiedAt"`
+	ModifiedBy          string    `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the forwarding destination that have no
+	// field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the forwarding destination, keeping the properties
+// that have no field in Extra.
+func (d *ForwardingDestination) UnmarshalJSON(b []byte) error {
+	type plain ForwardingDestination
+	return unmarshalExtra(b, (*plain)(d), &d.Extra)
+}
+
+// MarshalJSON encodes the forwarding destination along with the properties
+// in Extra.
+func (d ForwardingDestination) MarshalJSON() ([]byte, error) {
+	type plain ForwardingDestination
+	return marshalExtra(plain(d), d.Extra)
+}
+
+// ForwardingRule forwards the logs of a partition or scheduled view to a
+// ForwardingDestination.
+type ForwardingRule struct {
+	// IndexID is the ID of the partition or scheduled view, which also
+	// identifies the rule.
+	IndexID       string `json:"indexId"`
+	DestinationID string `json:"destinationId"`
+	Enabled       bool   `json:"enabled"`
+	// FileFormat is the format of the names of the forwarded files, such as
+	// {index}_{day}_{hour}_{minute}_{second}.
+	FileFormat string `json:"fileFormat,omitempty"`
+	// Format is one of the ForwardingFormat constants.
+	Format string `json:"format,omitempty"`
+	// PayloadSchema is one of the ForwardingSchema constants.
+	PayloadSchema string    `json:"payloadSchema,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	CreatedBy     string    `json:"createdBy,omitempty"`
+	ModifiedAt    time.Time `json:"modifiedAt"`
+	ModifiedBy    string    `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the forwarding rule that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the forwarding rule, keeping the properties that
+// have no field in Extra.
+func (r *ForwardingRule) UnmarshalJSON(b []byte) error {
+	type plain ForwardingRule
+	return unmarshalExtra(b, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes the forwarding rule along with the properties in
+// Extra.
+func (r ForwardingRule) MarshalJSON() ([]byte, error) {
+	type plain ForwardingRule
+	return marshalExtra(plain(r), r.Extra)
+}
+
+// ListForwardingDestinations returns every data forwarding destination,
+// following pagination.
+func (c *Client) ListForwardingDestinations(ctx context.Context) ([]ForwardingDestination, error) {
+	return Collect(c.IterateForwardingDestinations(ctx))
+}
+
+// IterateForwardingDestinations returns an Iterator over the forwarding
+// destinations returned by ListForwardingDestinations, requesting each page
+// as it is read.
+func (c *Client) IterateForwardingDestinations(ctx context.Context) *Iterator[ForwardingDestination] {
+	return iterateTokens[ForwardingDestination](ctx, c, "listing forwarding destinations", "/v1/logsDataForwarding/destinations", nil)
+}
+
+// GetForwardingDestination returns the data forwarding destination with the
+// ID.
+func (c *Client) GetForwardingDestination(ctx context.Context, id string) (*ForwardingDestination, error) {
+	var dest ForwardingDestination
+	if err := c.get(ctx, pathf("/v1/logsDataForwarding/destinations/%s", id), nil, &dest); err != nil {
+		return nil, fmt.Errorf("error getting forwarding destination: %w", err)
+	}
+	return &dest, nil
+}
+
+// CreateForwardingDestination creates a data forwarding destination and
+// returns it. Sumo Logic checks that it can write to the bucket.
+func (c *Client) CreateForwardingDestination(ctx context.Context, dest ForwardingDestination) (*ForwardingDestination, error) {
+	if dest.Name == "" || dest.BucketName == "" {
+		return nil, fmt.Errorf("error creating forwarding destination: name and bucket name are required")
+	}
+	var created ForwardingDestination
+	if err := c.post(ctx, "/v1/logsDataForwarding/destinations", forwardingDestinationBody(dest), &created); err != nil {
+		return nil, fmt.Errorf("error creating forwarding destination: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateForwardingDestination replaces the data forwarding destination with
+// the same ID and returns the updated destination.
+func (c *Client) UpdateForwardingDestination(ctx context.Context, dest ForwardingDestination) (*ForwardingDestination, error) {
+	var updated ForwardingDestination
+	if err := c.put(ctx, pathf("/v1/logsDataForwarding/destinations/%s", dest.ID), forwardingDestinationBody(dest), &updated); err != nil {
+		return nil, fmt.Errorf("error updating forwarding destination: %w", err)
+	}
+	return &updated, nil
+}
+
+// EnableForwardingDestination enables the data forwarding destination with
+// the ID and returns the updated destination.
+func (c *Client) EnableForwardingDestination(ctx context.Context, id string) (*ForwardingDestination, error) {
+	return c.setForwardingDestinationEnabled(ctx, id, true)
+}
+
+// DisableForwardingDestination disables the data forwarding destination
+// with the ID, which stops every rule forwarding to it, and returns the
+// updated destination.
+func (c *Client) DisableForwardingDestination(ctx context.Context, id string) (*ForwardingDestination, error) {
+	return c.setForwardingDestinationEnabled(ctx, id, false)
+}
+
+// setForwardingDestinationEnabled reads the destination and updates it with
+// the enabled state.
+func (c *Client) setForwardingDestinationEnabled(ctx context.Context, id string, enabled bool) (*ForwardingDestination, error) {
+	dest, err := c.GetForwardingDestination(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	dest.Enabled = enabled
+	return c.UpdateForwardingDestination(ctx, *dest)
+}
+
+// DeleteForwardingDestination deletes the data forwarding destination with
+// the ID. Destinations used by rules cannot be deleted.
+func (c *Client) DeleteForwardingDestination(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/logsDataForwarding/destinations/%s", id)); err != nil {
+		return fmt.Errorf("error deleting forwarding destination: %w", err)
+	}
+	return nil
+}
+
+// ListForwardingRules returns every data forwarding rule, following
+// pagination.
+func (c *Client) ListForwardingRules(ctx context.Context) ([]ForwardingRule, error) {
+	return Collect(c.IterateForwardingRules(ctx))
+}
+
+// IterateForwardingRules returns an Iterator over the forwarding rules
+// returned by ListForwardingRules, requesting each page as it is read.
+func (c *Client) IterateForwardingRules(ctx context.Context) *Iterator[ForwardingRule] {
+	return iterateTokens[ForwardingRule](ctx, c, "listing forwarding rules", "/v1/logsDataForwarding/rules", nil)
+}
+
+// GetForwardingRule returns the data forwarding rule of the partition or
+// scheduled view with the ID.
+func (c *Client) GetForwardingRule(ctx context.Context, indexID string) (*ForwardingRule, error) {
+	var rule ForwardingRule
+	if err := c.get(ctx, pathf("/v1/logsDataForwarding/rules/%s", indexID), nil, &rule); err != nil {
+		return nil, fmt.Errorf("error getting forwarding rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// CreateForwardingRule creates a data forwarding rule and returns it. A
+// partition or scheduled view has at most one rule.
+func (c *Client) CreateForwardingRule(ctx context.Context, rule ForwardingRule) (*ForwardingRule, error) {
+	if rule.IndexID == "" || rule.DestinationID == "" {
+		return nil, fmt.Errorf("error creating forwarding rule: index id and destination id are required")
+	}
+	var created ForwardingRule
+	if err := c.post(ctx, "/v1/logsDataForwarding/rules", forwardingRuleBody(rule), &created); err != nil {
+		return nil, fmt.Errorf("error creating forwarding rule: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateForwardingRule replaces the data forwarding rule with the same
+// IndexID and returns the updated rule.
+func (c *Client) UpdateForwardingRule(ctx context.Context, rule ForwardingRule) (*ForwardingRule, error) {
+	var updated ForwardingRule
+	if err := c.put(ctx, pathf("/v1/logsDataForwarding/rules/%s", rule.IndexID), forwardingRuleBody(rule), &updated); err != nil {
+		return nil, fmt.Errorf("error updating forwarding rule: %w", err)
+	}
+	return &updated, nil
+}
+
+// EnableForwardingRule enables the data forwarding rule of the partition or
+// scheduled view with the ID and returns the updated rule.
+func (c *Client) EnableForwardingRule(ctx context.Context, indexID string) (*ForwardingRule, error) {
+	return c.setForwardingRuleEnabled(ctx, indexID, true)
+}
+
+// DisableForwardingRule disables the data forwarding rule of the partition
+// or scheduled view with the ID and returns the updated rule.
+func (c *Client) DisableForwardingRule(ctx context.Context, indexID string) (*ForwardingRule, error) {
+	return c.setForwardingRuleEnabled(ctx, indexID, false)
+}
+
+// setForwardingRuleEnabled reads the rule and updates it with the enabled
+// state.
+func (c *Client) setForwardingRuleEnabled(ctx context.Context, indexID string, enabled bool) (*ForwardingRule, error) {
+	rule, err := c.GetForwardingRule(ctx, indexID)
+	if err != nil {
+		return nil, err
+	}
+	rule.Enabled = enabled
+	return c.UpdateForwardingRule(ctx, *rule)
+}
+
+// DeleteForwardingRule deletes the data forwarding rule of the partition or
+// scheduled view with the ID.
+func (c *Client) DeleteForwardingRule(ctx context.Context, indexID string) error {
+	if err := c.delete(ctx, pathf("/v1/logsDataForwarding/rules/%s", indexID)); err != nil {
+		return fmt.Errorf("error deleting forwarding rule: %w", err)
+	}
+	return nil
+}
+
+// forwardingDestinationBody returns the fields of the destination that can
+// be set, and the properties in Extra.
+func forwardingDestinationBody(dest ForwardingDestination) any {
+	if dest.AuthenticationMode == "" {
+		dest.AuthenticationMode = ForwardingAuthRoleBased
+	}
+	return withExtra(struct {
+		Name                   string `json:"destinationName"`
+		Description            string `json:"description,omitempty"`
+		BucketName             string `json:"bucketName"`
+		S3Region               string `json:"S3Region,omitempty"`
+		AuthenticationMode     string `json:"authenticationMode"`
+		RoleARN                string `json:"roleArn,omitempty"`
+		AccessKeyID            string `json:"accessKeyId,omitempty"`
+		SecretAccessKey        string `json:"secretAccessKey,omitempty"`
+		S3ServerSideEncryption bool   `json:"s3ServerSideEncryption"`
+		Enabled                bool   `json:"enabled"`
+	}{
+		dest.Name, dest.Description, dest.BucketName, dest.S3Region, dest.AuthenticationMode,
+		dest.RoleARN, dest.AccessKeyID, dest.SecretAccessKey, dest.S3ServerSideEncryption, dest.Enabled,
+	}, dest.Extra)
+}
+
+// forwardingRuleBody returns the fields of the rule that can be set, and the
+// properties in Extra.
+func forwardingRuleBody(rule ForwardingRule) any {
+	return withExtra(struct {
+		IndexID       string `json:"indexId"`
+		DestinationID string `json:"destinationId"`
+		Enabled       bool   `json:"enabled"`
+		FileFormat    string `json:"fileFormat,omitempty"`
+		Format        string `json:"format,omitempty"`
+		PayloadSchema string `json:"payloadSchema,omitempty"`
+	}{rule.IndexID, rule.DestinationID, rule.Enabled, rule.FileFormat, rule.Format, rule.PayloadSchema}, rule.Extra)
+}
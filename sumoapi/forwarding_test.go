@@ -0,0 +1,134 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestForwardingDestinations(t *testing.T) {
+	ctx := context.Background()
+	dest := `{"id": "d1", "destinationName": "archive", "bucketName": "logs", "authenticationMode": "RoleBased", "roleArn": "arn:aws:iam::1:role/sumo", "enabled": true, "encrypted": true}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				dests, err := c.ListForwardingDestinations(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(dests[0].Name, "archive")
+			},
+			response: `{"data": [` + dest + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/logsDataForwarding/destinations",
+			query:    "limit=100",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				d, err := c.GetForwardingDestination(ctx, "d1")
+				if err != nil {
+					return err
+				}
+				return expect(string(d.Extra["encrypted"]), "true")
+			},
+			response: dest,
+			method:   http.MethodGet,
+			path:     "/v1/logsDataForwarding/destinations/d1",
+		},
+		{
+			name: "create with the default authentication",
+			call: func(c *Client) error {
+				_, err := c.CreateForwardingDestination(ctx, ForwardingDestination{Name: "archive", BucketName: "logs", RoleARN: "arn:aws:iam::1:role/sumo"})
+				return err
+			},
+			response: dest,
+			method:   http.MethodPost,
+			path:     "/v1/logsDataForwarding/destinations",
+			body:     `{"destinationName": "archive", "bucketName": "logs", "authenticationMode": "RoleBased", "roleArn": "arn:aws:iam::1:role/sumo", "s3ServerSideEncryption": false, "enabled": false}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteForwardingDestination(ctx, "d1") },
+			method: http.MethodDelete,
+			path:   "/v1/logsDataForwarding/destinations/d1",
+		},
+	})
+}
+
+func TestForwardingRules(t *testing.T) {
+	ctx := context.Background()
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				rules, err := c.ListForwardingRules(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(rules[0].DestinationID, "d1")
+			},
+			response: `{"data": [{"indexId": "i1", "destinationId": "d1", "enabled": true}]}`,
+			method:   http.MethodGet,
+			path:     "/v1/logsDataForwarding/rules",
+			query:    "limit=100",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateForwardingRule(ctx, ForwardingRule{IndexID: "i1", DestinationID: "d1", Enabled: true, Format: ForwardingFormatJSON, PayloadSchema: ForwardingSchemaAllFields})
+				return err
+			},
+			method: http.MethodPost,
+			path:   "/v1/logsDataForwarding/rules",
+			body:   `{"indexId": "i1", "destinationId": "d1", "enabled": true, "format": "Json", "payloadSchema": "allFields"}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteForwardingRule(ctx, "i1") },
+			method: http.MethodDelete,
+			path:   "/v1/logsDataForwarding/rules/i1",
+		},
+	})
+}
+
+func TestEnableForwarding(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("GET /v1/logsDataForwarding/destinations/d1", `{"id": "d1", "destinationName": "archive", "bucketName": "logs", "authenticationMode": "RoleBased", "enabled": false, "encrypted": true}`)
+	api.respond("PUT /v1/logsDataForwarding/destinations/d1", `{"id": "d1", "enabled": true}`)
+	api.respond("GET /v1/logsDataForwarding/rules/i1", `{"indexId": "i1", "destinationId": "d1", "enabled": true, "fileFormat": "{index}/{day}", "version": 2}`)
+	api.respond("PUT /v1/logsDataForwarding/rules/i1", `{"indexId": "i1", "enabled": false}`)
+	c := api.client()
+
+	dest, err := c.EnableForwardingDestination(context.Background(), "d1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dest.Enabled {
+		t.Errorf("EnableForwardingDestination = %+v", dest)
+	}
+	// The destination is updated with the properties it was read with.
+	checkRequest(t, api.last(), http.MethodPut, "/v1/logsDataForwarding/destinations/d1",
+		`{"destinationName": "archive", "bucketName": "logs", "authenticationMode": "RoleBased", "s3ServerSideEncryption": false, "enabled": true, "encrypted": true}`)
+
+	if _, err := c.DisableForwardingRule(context.Background(), "i1"); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.last(), http.MethodPut, "/v1/logsDataForwarding/rules/i1",
+		`{"indexId": "i1", "destinationId": "d1", "enabled": false, "fileFormat": "{index}/{day}", "version": 2}`)
+}
+
+func TestCreateForwardingValidation(t *testing.T) {
+	api := newTestAPI(t)
+	c := api.client()
+	if _, err := c.CreateForwardingDestination(context.Background(), ForwardingDestination{Name: "archive"}); err == nil {
+		t.Error("CreateForwardingDestination without a bucket succeeded")
+	}
+	if _, err := c.CreateForwardingRule(context.Background(), ForwardingRule{IndexID: "i1"}); err == nil {
+		t.Error("CreateForwardingRule without a destination succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
@@ -0,0 +1,117 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Types of the resources health events are reported for.
+const (
+	ResourceCollector      = "Collector"
+	ResourceSource         = "Source"
+	ResourceIngestBudget   = "IngestBudget"
+	ResourceOrganisation   = "Organisation"
+	ResourceDataForwarding = "DataForwarding"
+)
+
+// Severity levels of a health event.
+const (
+	SeverityError   = "Error"
+	SeverityWarning = "Warning"
+)
+
+// HealthEvent is a problem Sumo Logic detected with a collector, source or
+// other resource, such as a source that failed to authenticate with AWS.
+type HealthEvent struct {
+	EventID          string             `json:"eventId"`
+	EventName        string             `json:"eventName"`
+	Details          HealthEventDetails `json:"details"`
+	ResourceIdentity ResourceIdentity   `json:"resourceIdentity"`
+	EventTime        time.Time          `json:"eventTime"`
+	Subsystem        string             `json:"subsystem"`
+	// SeverityLevel is SeverityError or SeverityWarning.
+	SeverityLevel string `json:"severityLevel"`
+
+	// Extra holds the properties of the health event that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the health event, keeping the properties that have
+// no field in Extra.
+func (e *HealthEvent) UnmarshalJSON(b []byte) error {
+	type plain HealthEvent
+	return unmarshalExtra(b, (*plain)(e), &e.Extra)
+}
+
+// MarshalJSON encodes the health event along with the properties in Extra.
+func (e HealthEvent) MarshalJSON() ([]byte, error) {
+	type plain HealthEvent
+	return marshalExtra(plain(e), e.Extra)
+}
+
+// HealthEventDetails describes what went wrong.
+type HealthEventDetails struct {
+	TrackerID   string `json:"trackerId"`
+	Error       string `json:"error"`
+	Description string `json:"description"`
+}
+
+// ResourceIdentity identifies the resource a health event is for. Sources
+// also identify their collector.
+type ResourceIdentity struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// Type is one of the Resource constants.
+	Type          string `json:"type"`
+	CollectorID   string `json:"collectorId,omitempty"`
+	CollectorName string `json:"collectorName,omitempty"`
+}
+
+// CollectorResource returns the ResourceIdentity of the collector with the
+// ID, for use with ListHealthEventsForResources.
+func CollectorResource(id int64) ResourceIdentity {
+	return ResourceIdentity{ID: strconv.FormatInt(id, 10), Type: ResourceCollector}
+}
+
+// SourceResource returns the ResourceIdentity of the source with the ID
+// under the collector, for use with ListHealthEventsForResources.
+func SourceResource(collectorID, sourceID int64) ResourceIdentity {
+	return ResourceIdentity{
+		ID:          strconv.FormatInt(sourceID, 10),
+		Type:        ResourceSource,
+		CollectorID: strconv.FormatInt(collectorID, 10),
+	}
+}
+
+// ListHealthEvents returns every open health event of the account,
+// following pagination.
+func (c *Client) ListHealthEvents(ctx context.Context) ([]HealthEvent, error) {
+	return Collect(c.IterateHealthEvents(ctx))
+}
+
+// IterateHealthEvents returns an Iterator over the health events returned by
+// ListHealthEvents, requesting each page as it is read.
+func (c *Client) IterateHealthEvents(ctx context.Context) *Iterator[HealthEvent] {
+	return iterateTokens[HealthEvent](ctx, c, "listing health events", "/v1/healthEvents", nil)
+}
+
+// ListHealthEventsForResources returns the open health events of the
+// resources, such as those returned by CollectorResource and
+// SourceResource. No events means the resources are healthy.
+func (c *Client) ListHealthEventsForResources(ctx context.Context, resources ...ResourceIdentity) ([]HealthEvent, error) {
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	body := struct {
+		Data []ResourceIdentity `json:"data"`
+	}{resources}
+	var resp tokenPage[HealthEvent]
+	if err := c.post(ctx, "/v1/healthEvents/resources", body, &resp); err != nil {
+		return nil, fmt.Errorf("error listing health events: %w", err)
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,69 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestHealthEvents(t *testing.T) {
+	ctx := context.Background()
+	event := `{
+		"eventId": "e1",
+		"eventName": "S3AccessDenied",
+		"details": {"trackerId": "t1", "error": "Access denied", "description": "The role cannot read the bucket"},
+		"resourceIdentity": {"id": "2", "name": "logs", "type": "Source", "collectorId": "1"},
+		"eventTime": "2024-01-02T03:04:05Z",
+		"subsystem": "Ingest",
+		"severityLevel": "Error",
+		"type": "Health"
+	}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				events, err := c.ListHealthEvents(ctx)
+				if err != nil {
+					return err
+				}
+				if len(events) != 1 {
+					return expect(len(events), 1)
+				}
+				e := events[0]
+				if err := expect(e.ResourceIdentity, ResourceIdentity{ID: "2", Name: "logs", Type: ResourceSource, CollectorID: "1"}); err != nil {
+					return err
+				}
+				return expect(string(e.Extra["type"]), `"Health"`)
+			},
+			response: `{"data": [` + event + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/healthEvents",
+			query:    "limit=100",
+		},
+		{
+			name: "for resources",
+			call: func(c *Client) error {
+				events, err := c.ListHealthEventsForResources(ctx, CollectorResource(1), SourceResource(1, 2))
+				if err != nil {
+					return err
+				}
+				return expect(len(events), 1)
+			},
+			response: `{"data": [` + event + `]}`,
+			method:   http.MethodPost,
+			path:     "/v1/healthEvents/resources",
+			body:     `{"data": [{"id": "1", "type": "Collector"}, {"id": "2", "type": "Source", "collectorId": "1"}]}`,
+		},
+	})
+}
+
+func TestListHealthEventsForNoResources(t *testing.T) {
+	api := newTestAPI(t)
+	events, err := api.client().ListHealthEventsForResources(context.Background())
+	if err != nil || events != nil {
+		t.Errorf("ListHealthEventsForResources() = %v, %v, want no events", events, err)
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
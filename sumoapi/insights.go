@@ -0,0 +1,252 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Statuses of an insight.
+const (
+	InsightNew        = "new"
+	InsightInProgress = "inprogress"
+	InsightClosed     = "closed"
+)
+
+// Resolutions of a closed insight.
+const (
+	InsightResolved      = "Resolved"
+	InsightFalsePositive = "False Positive"
+	InsightNoAction      = "No Action"
+	InsightDuplicate     = "Duplicate"
+)
+
+// Severities of an insight.
+const (
+	InsightSeverityLow      = "LOW"
+	InsightSeverityMedium   = "MEDIUM"
+	InsightSeverityHigh     = "HIGH"
+	InsightSeverityCritical = "CRITICAL"
+)
+
+// Insight is a Cloud SIEM insight, raised when the signals of an entity
+// exceed its activity score threshold.
+type Insight struct {
+	ID string `json:"id"`
+	// ReadableID is the ID shown in the UI, such as INSIGHT-123.
+	ReadableID  string        `json:"readableId"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Status      InsightStatus `json:"status"`
+	// Resolution is set once the insight is closed, to one of the Insight
+	// resolution constants.
+	Resolution string `json:"resolution,omitempty"`
+	// Severity is one of the InsightSeverity constants.
+	Severity   string           `json:"severity"`
+	Confidence float64          `json:"confidence,omitempty"`
+	Entity     InsightEntity    `json:"entity"`
+	Assignee   *InsightAssignee `json:"assignee,omitempty"`
+	Tags       []string         `json:"tags,omitempty"`
+	// Source is "ALGORITHM" for insights raised by Cloud SIEM and "USER"
+	// for those created from signals by hand.
+	Source      string          `json:"source,omitempty"`
+	Created     CSETime         `json:"created"`
+	LastUpdated CSETime         `json:"lastUpdated"`
+	Closed      CSETime         `json:"closed"`
+	ClosedBy    string          `json:"closedBy,omitempty"`
+	Signals     []InsightSignal `json:"signals,omitempty"`
+
+	// Extra holds the properties of the insight that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the insight, keeping the properties that have no
+// field in Extra.
+func (i *Insight) UnmarshalJSON(b []byte) error {
+	type plain Insight
+	return unmarshalExtra(b, (*plain)(i), &i.Extra)
+}
+
+// MarshalJSON encodes the insight along with the properties in Extra.
+func (i Insight) MarshalJSON() ([]byte, error) {
+	type plain Insight
+	return marshalExtra(plain(i), i.Extra)
+}
+
+// InsightStatus is the status of an insight.
+type InsightStatus struct {
+	// Name is one of the Insight status constants.
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// InsightEntity is the entity an insight was raised for, such as a host or
+// user.
+type InsightEntity struct {
+	ID         string `json:"id"`
+	Name       string `json:"name,omitempty"`
+	EntityType string `json:"entityType"`
+	Value      string `json:"value"`
+}
+
+// InsightAssignee is the user or team an insight is assigned to.
+type InsightAssignee struct {
+	// Type is "USER" or "TEAM".
+	Type string `json:"type"`
+	// Value is the email of the user or the ID of the team.
+	Value string `json:"value"`
+}
+
+// InsightSignal is a signal that contributed to an insight.
+type InsightSignal struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	RuleID      string  `json:"ruleId,omitempty"`
+	Severity    int     `json:"severity"`
+	Stage       string  `json:"stage,omitempty"`
+	Timestamp   CSETime `json:"timestamp"`
+}
+
+// InsightComment is a comment on an insight.
+type InsightComment struct {
+	ID     string `json:"id,omitempty"`
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	Body      string  `json:"body"`
+	Timestamp CSETime `json:"timestamp"`
+}
+
+// InsightFilter selects insights. Every field that is set must match.
+type InsightFilter struct {
+	// Statuses matches insights with any of the statuses.
+	Statuses []string
+	// Severity is one of the InsightSeverity constants.
+	Severity string
+	// Assignee matches insights assigned to the user with the email.
+	Assignee string
+	// Tags matches insights with every tag.
+	Tags []string
+	// Query is added to the query built from the other fields, in the
+	// query language of the insights list, such as created:>2024-01-01.
+	Query string
+}
+
+// String returns the query of the filter.
+func (f InsightFilter) String() string {
+	var terms []string
+	if len(f.Statuses) > 0 {
+		quoted := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			quoted[i] = strconv.Quote(s)
+		}
+		terms = append(terms, "status:in("+strings.Join(quoted, ", ")+")")
+	}
+	if f.Severity != "" {
+		terms = append(terms, "severity:"+strconv.Quote(f.Severity))
+	}
+	if f.Assignee != "" {
+		terms = append(terms, "assignee:"+strconv.Quote(f.Assignee))
+	}
+	for _, t := range f.Tags {
+		terms = append(terms, "tag:"+strconv.Quote(t))
+	}
+	if f.Query != "" {
+		terms = append(terms, f.Query)
+	}
+	return strings.Join(terms, " ")
+}
+
+// ListInsights returns the insights matching the filter, following
+// pagination.
+func (c *Client) ListInsights(ctx context.Context, filter InsightFilter) ([]Insight, error) {
+	return Collect(c.IterateInsights(ctx, filter))
+}
+
+// IterateInsights returns an Iterator over the insights returned by
+// ListInsights, requesting each page as it is read.
+func (c *Client) IterateInsights(ctx context.Context, filter InsightFilter) *Iterator[Insight] {
+	var query url.Values
+	if q := filter.String(); q != "" {
+		query = url.Values{"q": {q}}
+	}
+	return cseIterate[Insight](ctx, c, "listing insights", "/sec/v1/insights", query)
+}
+
+// GetInsight returns the insight with the ID, including its signals.
+func (c *Client) GetInsight(ctx context.Context, id string) (*Insight, error) {
+	var resp cseResponse[Insight]
+	if err := c.get(ctx, pathf("/sec/v1/insights/%s", id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("error getting insight: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// ListInsightComments returns the comments on the insight with the ID.
+func (c *Client) ListInsightComments(ctx context.Context, id string) ([]InsightComment, error) {
+	var resp cseResponse[struct {
+		Comments []InsightComment `json:"comments"`
+	}]
+	if err := c.get(ctx, pathf("/sec/v1/insights/%s/comments", id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("error listing insight comments: %w", err)
+	}
+	return resp.Data.Comments, nil
+}
+
+// AddInsightComment adds a comment to the insight with the ID and returns
+// it.
+func (c *Client) AddInsightComment(ctx context.Context, id, body string) (*InsightComment, error) {
+	if body == "" {
+		return nil, fmt.Errorf("error adding insight comment: body is required")
+	}
+	req := struct {
+		Body string `json:"body"`
+	}{body}
+	var resp cseResponse[InsightComment]
+	if err := c.post(ctx, pathf("/sec/v1/insights/%s/comments", id), req, &resp); err != nil {
+		return nil, fmt.Errorf("error adding insight comment: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// SetInsightStatus changes the status of the insight with the ID and returns
+// the updated insight. The resolution, one of the Insight resolution
+// constants, is required when closing it and ignored otherwise.
+func (c *Client) SetInsightStatus(ctx context.Context, id, status, resolution string) (*Insight, error) {
+	if status == InsightClosed && resolution == "" {
+		return nil, fmt.Errorf("error setting insight status: resolution is required to close an insight")
+	}
+	if status != InsightClosed {
+		resolution = ""
+	}
+	body := struct {
+		Status     string `json:"status"`
+		Resolution string `json:"resolution,omitempty"`
+	}{status, resolution}
+	var resp cseResponse[Insight]
+	if err := c.put(ctx, pathf("/sec/v1/insights/%s/status", id), body, &resp); err != nil {
+		return nil, fmt.Errorf("error setting insight status: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// AssignInsight assigns the insight with the ID to the user with the email
+// and returns the updated insight. An empty email unassigns it.
+func (c *Client) AssignInsight(ctx context.Context, id, email string) (*Insight, error) {
+	body := struct {
+		Assignee *InsightAssignee `json:"assignee"`
+	}{}
+	if email != "" {
+		body.Assignee = &InsightAssignee{Type: "USER", Value: email}
+	}
+	var resp cseResponse[Insight]
+	if err := c.put(ctx, pathf("/sec/v1/insights/%s/assignee", id), body, &resp); err != nil {
+		return nil, fmt.Errorf("error assigning insight: %w", err)
+	}
+	return &resp.Data, nil
+}
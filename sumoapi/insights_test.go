@@ -0,0 +1,199 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCSETime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{`"2026-01-02T03:04:05.123456"`, time.Date(2026, 1, 2, 3, 4, 5, 123456000, time.UTC)},
+		{`"2026-01-02T03:04:05Z"`, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{`"2026-01-02T04:04:05+01:00"`, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{`""`, time.Time{}},
+		{`null`, time.Time{}},
+	}
+	for _, tt := range tests {
+		var got CSETime
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("Unmarshal(%s) = %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	var bad CSETime
+	if err := json.Unmarshal([]byte(`"yesterday"`), &bad); err == nil {
+		t.Error("Unmarshal of an invalid time succeeded")
+	}
+
+	b, _ := json.Marshal(struct {
+		Set, Unset CSETime
+	}{Set: CSETime{time.Date(2026, 1, 2, 4, 4, 5, 0, time.FixedZone("CET", 3600))}})
+	if string(b) != `{"Set":"2026-01-02T03:04:05Z","Unset":null}` {
+		t.Errorf("Marshal = %s", b)
+	}
+}
+
+func TestInsightFilterString(t *testing.T) {
+	tests := []struct {
+		f    InsightFilter
+		want string
+	}{
+		{InsightFilter{}, ""},
+		{InsightFilter{Statuses: []string{InsightNew, InsightInProgress}}, `status:in("new", "inprogress")`},
+		{
+			InsightFilter{Severity: InsightSeverityHigh, Assignee: "ada@example.com", Tags: []string{"aws", "prod"}, Query: "entity.ip:10.0.0.1"},
+			`severity:"HIGH" assignee:"ada@example.com" tag:"aws" tag:"prod" entity.ip:10.0.0.1`,
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.f.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestListInsightsPages(t *testing.T) {
+	api := newTestAPI(t)
+	api.handle("GET /sec/v1/insights", func(w http.ResponseWriter, r *http.Request) {
+		// The first page is full and the second is the last.
+		if r.URL.Query().Get("offset") == "0" {
+			fmt.Fprint(w, `{"data": {"objects": [{"id": "i1"}, {"id": "i2"}], "hasNextPage": true}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"objects": [{"id": "i3"}], "hasNextPage": false}}`)
+	})
+	insights, err := api.client().ListInsights(context.Background(), InsightFilter{Statuses: []string{InsightNew}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 3 || insights[2].ID != "i3" {
+		t.Errorf("ListInsights = %+v", insights)
+	}
+	var queries []string
+	for _, r := range api.received() {
+		queries = append(queries, r.query.Encode())
+	}
+	want := []string{`limit=100&offset=0&q=status%3Ain%28%22new%22%29`, `limit=100&offset=2&q=status%3Ain%28%22new%22%29`}
+	if err := expect(queries, want); err != nil {
+		t.Errorf("queries: %v", err)
+	}
+}
+
+func TestInsights(t *testing.T) {
+	ctx := context.Background()
+	insight := `{"data": {"id": "i1", "readableId": "INSIGHT-1", "name": "Recon", "status": {"name": "closed"}, "resolution": "Resolved", "severity": "HIGH", "entity": {"id": "e1", "entityType": "_ip", "value": "10.0.0.1"}, "created": "2026-01-02T03:04:05.123", "closed": null, "timeToDetection": 60}}`
+	testCalls(t, []apiCall{
+		{
+			name: "get",
+			call: func(c *Client) error {
+				i, err := c.GetInsight(ctx, "i1")
+				if err != nil {
+					return err
+				}
+				if err := expect(i.Created.UnixMilli(), time.Date(2026, 1, 2, 3, 4, 5, 123000000, time.UTC).UnixMilli()); err != nil {
+					return err
+				}
+				return expect(string(i.Extra["timeToDetection"]), "60")
+			},
+			response: insight,
+			method:   http.MethodGet,
+			path:     "/sec/v1/insights/i1",
+		},
+		{
+			name: "comments",
+			call: func(c *Client) error {
+				comments, err := c.ListInsightComments(ctx, "i1")
+				if err != nil {
+					return err
+				}
+				return expect(comments[0].Author.Username, "ada")
+			},
+			response: `{"data": {"comments": [{"id": "c1", "author": {"username": "ada"}, "body": "looking"}]}}`,
+			method:   http.MethodGet,
+			path:     "/sec/v1/insights/i1/comments",
+		},
+		{
+			name: "add comment",
+			call: func(c *Client) error {
+				_, err := c.AddInsightComment(ctx, "i1", "looking")
+				return err
+			},
+			response: `{"data": {"id": "c1", "body": "looking"}}`,
+			method:   http.MethodPost,
+			path:     "/sec/v1/insights/i1/comments",
+			body:     `{"body": "looking"}`,
+		},
+		{
+			name: "close",
+			call: func(c *Client) error {
+				i, err := c.SetInsightStatus(ctx, "i1", InsightClosed, InsightFalsePositive)
+				if err != nil {
+					return err
+				}
+				return expect(i.Status.Name, InsightClosed)
+			},
+			response: insight,
+			method:   http.MethodPut,
+			path:     "/sec/v1/insights/i1/status",
+			body:     `{"status": "closed", "resolution": "False Positive"}`,
+		},
+		{
+			name: "reopen",
+			call: func(c *Client) error {
+				_, err := c.SetInsightStatus(ctx, "i1", InsightInProgress, InsightResolved)
+				return err
+			},
+			response: insight,
+			method:   http.MethodPut,
+			path:     "/sec/v1/insights/i1/status",
+			// Only closed insights have a resolution.
+			body: `{"status": "inprogress"}`,
+		},
+		{
+			name: "assign",
+			call: func(c *Client) error {
+				_, err := c.AssignInsight(ctx, "i1", "ada@example.com")
+				return err
+			},
+			response: insight,
+			method:   http.MethodPut,
+			path:     "/sec/v1/insights/i1/assignee",
+			body:     `{"assignee": {"type": "USER", "value": "ada@example.com"}}`,
+		},
+		{
+			name: "unassign",
+			call: func(c *Client) error {
+				_, err := c.AssignInsight(ctx, "i1", "")
+				return err
+			},
+			response: insight,
+			method:   http.MethodPut,
+			path:     "/sec/v1/insights/i1/assignee",
+			body:     `{"assignee": null}`,
+		},
+	})
+}
+
+func TestInsightValidation(t *testing.T) {
+	api := newTestAPI(t)
+	c := api.client()
+	if _, err := c.SetInsightStatus(context.Background(), "i1", InsightClosed, ""); err == nil {
+		t.Error("SetInsightStatus closing without a resolution succeeded")
+	}
+	if _, err := c.AddInsightComment(context.Background(), "i1", ""); err == nil {
+		t.Error("AddInsightComment without a body succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
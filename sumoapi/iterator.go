@@ -0,0 +1,142 @@
+package sumoapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Iterator reads the items of a paginated list, requesting each page as the
+// previous one has been read, so lists of any size can be processed without
+// holding every item in memory.
+//
+//	it := client.IterateUsers(ctx, "")
+//	for it.Next() {
+//		fmt.Println(it.Value().Email)
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type Iterator[T any] struct {
+	ctx context.Context
+	// op describes the list in errors, such as "listing users".
+	op string
+	// fetch returns the page at the cursor and the cursor of the next page,
+	// which is empty on the last page. The first page has an empty cursor.
+	fetch func(ctx context.Context, cursor string) ([]T, string, error)
+
+	page    []T
+	pos     int
+	next    string
+	started bool
+	done    bool
+	err     error
+}
+
+// newIterator returns an Iterator reading the pages returned by fetch.
+func newIterator[T any](ctx context.Context, op string, fetch func(ctx context.Context, cursor string) ([]T, string, error)) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, op: op, fetch: fetch}
+}
+
+// Next advances to the next item, requesting the next page when the current
+// one has been read. It returns false once there are no more items or a
+// request failed, see Err.
+func (it *Iterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+	it.pos++
+	for it.pos >= len(it.page) {
+		if it.started && it.next == "" {
+			it.done, it.page = true, nil
+			return false
+		}
+		page, next, err := it.fetch(it.ctx, it.next)
+		it.started = true
+		if err != nil {
+			it.err = fmt.Errorf("error %s: %w", it.op, err)
+			it.done, it.page = true, nil
+			return false
+		}
+		it.page, it.pos, it.next = page, 0, next
+	}
+	return true
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	if it.pos < 0 || it.pos >= len(it.page) {
+		var zero T
+		return zero
+	}
+	return it.page[it.pos]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Collect reads every remaining item of the iterator. If a request fails it
+// returns the items read before the error along with it.
+func Collect[T any](it *Iterator[T]) ([]T, error) {
+	var all []T
+	for it.Next() {
+		all = append(all, it.Value())
+	}
+	return all, it.Err()
+}
+
+// listPageSize is the number of items requested per page by iterators.
+const listPageSize = 100
+
+// tokenPage is a page of a list that is paginated with a continuation
+// token.
+type tokenPage[T any] struct {
+	Data []T    `json:"data"`
+	Next string `json:"next"`
+}
+
+// iterateTokens returns an Iterator over a list paginated with a
+// continuation token, which is the cursor of each page.
+func iterateTokens[T any](ctx context.Context, c *Client, op, path string, query url.Values) *Iterator[T] {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("limit", strconv.Itoa(listPageSize))
+	return newIterator(ctx, op, func(ctx context.Context, token string) ([]T, string, error) {
+		if token != "" {
+			q.Set("token", token)
+		}
+		var page tokenPage[T]
+		if err := c.get(ctx, path, q, &page); err != nil {
+			return nil, "", err
+		}
+		return page.Data, page.Next, nil
+	})
+}
+
+// iterateOffsets returns an Iterator over a list paginated by offset, where
+// fetch returns the page of up to listPageSize items at the offset. A short
+// page is the last one.
+func iterateOffsets[T any](ctx context.Context, op string, fetch func(ctx context.Context, offset, limit int) ([]T, error)) *Iterator[T] {
+	return newIterator(ctx, op, func(ctx context.Context, cursor string) ([]T, string, error) {
+		offset := 0
+		if cursor != "" {
+			var err error
+			if offset, err = strconv.Atoi(cursor); err != nil {
+				return nil, "", err
+			}
+		}
+		page, err := fetch(ctx, offset, listPageSize)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(page) < listPageSize {
+			return page, "", nil
+		}
+		return page, strconv.Itoa(offset + len(page)), nil
+	})
+}
@@ -0,0 +1,151 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// serveCollectors serves n collectors paginated by offset, failing with a
+// 400 on the request at failAt, if it is positive.
+func serveCollectors(api *testAPI, n, failAt int) {
+	var requests atomic.Int32
+	api.handle("GET /v1/collectors", func(w http.ResponseWriter, r *http.Request) {
+		if int(requests.Add(1)) == failAt {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors": [{"code": "bad.request"}]}`)
+			return
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		page := []Collector{}
+		for i := offset; i < min(offset+limit, n); i++ {
+			page = append(page, Collector{ID: int64(i)})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"collectors": page})
+	})
+}
+
+func TestIterateOffsets(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		pages   []string
+		wantLen int
+	}{
+		{"one short page", 5, []string{"filter=hosted&limit=100&offset=0"}, 5},
+		{"two pages", 150, []string{"filter=hosted&limit=100&offset=0", "filter=hosted&limit=100&offset=100"}, 150},
+		// A full last page is followed by an empty one.
+		{"full pages", 200, []string{"filter=hosted&limit=100&offset=0", "filter=hosted&limit=100&offset=100", "filter=hosted&limit=100&offset=200"}, 200},
+		{"empty", 0, []string{"filter=hosted&limit=100&offset=0"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI(t)
+			serveCollectors(api, tt.n, 0)
+			collectors, err := Collect(api.client().IterateCollectors(context.Background(), "hosted"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(collectors) != tt.wantLen {
+				t.Fatalf("Collect returned %d collectors, want %d", len(collectors), tt.wantLen)
+			}
+			for i, c := range collectors {
+				if c.ID != int64(i) {
+					t.Fatalf("collector %d has ID %d", i, c.ID)
+				}
+			}
+			var pages []string
+			for _, r := range api.received() {
+				pages = append(pages, r.query.Encode())
+			}
+			if err := expect(pages, tt.pages); err != nil {
+				t.Errorf("pages: %v", err)
+			}
+		})
+	}
+}
+
+func TestIteratorError(t *testing.T) {
+	api := newTestAPI(t)
+	serveCollectors(api, 250, 2)
+	it := api.client().IterateCollectors(context.Background(), "")
+	collectors, err := Collect(it)
+	// The items read before the failed page are returned with the error.
+	if len(collectors) != 100 {
+		t.Errorf("Collect returned %d collectors, want the first page", len(collectors))
+	}
+	var apiErr Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Collect error = %v, want the API error", err)
+	}
+	if !strings.HasPrefix(err.Error(), "error listing collectors: ") {
+		t.Errorf("error = %q, want it to describe the list", err)
+	}
+	// The iteration stays stopped.
+	if it.Next() {
+		t.Error("Next after an error = true")
+	}
+	if n := len(api.received()); n != 2 {
+		t.Errorf("received %d requests, want 2", n)
+	}
+	var zero Collector
+	if it.Value().ID != zero.ID {
+		t.Errorf("Value after the end = %+v", it.Value())
+	}
+}
+
+func TestIterateTokens(t *testing.T) {
+	api := newTestAPI(t)
+	api.handle("GET /v1/users", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("token") {
+		case "":
+			fmt.Fprint(w, `{"data": [{"id": "u1"}, {"id": "u2"}], "next": "p2"}`)
+		case "p2":
+			// An empty page with a token is skipped.
+			fmt.Fprint(w, `{"data": [], "next": "p3"}`)
+		case "p3":
+			fmt.Fprint(w, `{"data": [{"id": "u3"}]}`)
+		}
+	})
+	it := api.client().IterateUsers(context.Background(), "ada@example.com")
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := expect(ids, []string{"u1", "u2", "u3"}); err != nil {
+		t.Errorf("users: %v", err)
+	}
+	var pages []string
+	for _, r := range api.received() {
+		pages = append(pages, r.query.Encode())
+	}
+	want := []string{
+		"email=ada%40example.com&limit=100",
+		"email=ada%40example.com&limit=100&token=p2",
+		"email=ada%40example.com&limit=100&token=p3",
+	}
+	if err := expect(pages, want); err != nil {
+		t.Errorf("pages: %v", err)
+	}
+}
+
+func TestIteratorContext(t *testing.T) {
+	api := newTestAPI(t)
+	serveCollectors(api, 5, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Collect(api.client().IterateCollectors(ctx, ""))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Collect with a cancelled context = %v, want context.Canceled", err)
+	}
+}
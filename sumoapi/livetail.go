@@ -0,0 +1,288 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultLiveTailPollInterval is how often a live tail session is polled for
+// new messages.
+const DefaultLiveTailPollInterval = time.Second
+
+// liveTailDeleteTimeout bounds the request deleting the session of a tail
+// that stopped, which is made after its context may have been cancelled.
+const liveTailDeleteTimeout = 10 * time.Second
+
+// ErrLiveTailExpired is returned by a LiveTail when its session expired or
+// was stopped by Sumo Logic and LiveTailOptions.NoRestart is set.
+var ErrLiveTailExpired = errors.New("live tail session expired")
+
+// LiveTailOptions configures a LiveTail.
+type LiveTailOptions struct {
+	// PollInterval is how often the session is polled for new messages. The
+	// default is DefaultLiveTailPollInterval. Polling also keeps the session
+	// alive.
+	PollInterval time.Duration
+	// Buffer is the capacity of the channel messages are delivered on.
+	Buffer int
+	// NoRestart stops the tail with ErrLiveTailExpired when its session
+	// expires, instead of starting a new session with the same filter.
+	// Messages received while a new session is started are missed.
+	NoRestart bool
+}
+
+// LiveTailMessage is a message streamed by a LiveTail.
+type LiveTailMessage struct {
+	// Raw is the message as it was sent.
+	Raw            string
+	MessageTime    time.Time
+	ReceiptTime    time.Time
+	SourceCategory string
+	SourceHost     string
+	SourceName     string
+	Collector      string
+	// Map holds every field of the message, including the ones above.
+	Map map[string]string
+}
+
+// LiveTail streams the messages matching a filter as they are received by
+// Sumo Logic, by polling a live tail session. Messages are delivered on C,
+// which is closed once the tail stops, after which Err returns the reason it
+// stopped. The session is deleted when the tail stops, unless it expired.
+//
+//	tail, err := client.LiveTail(ctx, "_sourceCategory=prod/web", sumoapi.LiveTailOptions{})
+//	if err != nil {
+//		return err
+//	}
+//	defer tail.Close()
+//	for msg := range tail.C {
+//		fmt.Println(msg.Raw)
+//	}
+//	return tail.Err()
+type LiveTail struct {
+	// C delivers the messages of the tail.
+	C <-chan LiveTailMessage
+
+	c      *Client
+	filter string
+	opts   LiveTailOptions
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu        sync.Mutex
+	sessionID string
+	err       error
+	// closed is set by Close, so that the cancellation of the context is
+	// not reported by Err, and deleteErr is the error deleting the session.
+	closed    bool
+	deleteErr error
+}
+
+// LiveTail starts a live tail session for the filter, such as
+// _sourceCategory=prod/web, and returns a LiveTail streaming its messages
+// until the context is done or Close is called. If the context is done Err
+// returns its error.
+func (c *Client) LiveTail(ctx context.Context, filter string, opts LiveTailOptions) (*LiveTail, error) {
+	if filter == "" {
+		return nil, fmt.Errorf("error starting live tail: filter is required")
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultLiveTailPollInterval
+	}
+	id, err := c.createLiveTailSession(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	ch := make(chan LiveTailMessage, opts.Buffer)
+	t := &LiveTail{
+		C:         ch,
+		c:         c,
+		filter:    filter,
+		opts:      opts,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		sessionID: id,
+	}
+	go t.run(ctx, ch)
+	return t, nil
+}
+
+// SessionID returns the ID of the current live tail session, which changes
+// when an expired session is restarted.
+func (t *LiveTail) SessionID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sessionID
+}
+
+// Err returns the reason the tail stopped, once C is closed, such as the
+// error of the context passed to Client.LiveTail. It is nil if the tail was
+// stopped by Close.
+func (t *LiveTail) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// Close stops the tail, waits for C to be closed and for the session to be
+// deleted, and returns any error deleting it.
+func (t *LiveTail) Close() error {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+	t.cancel()
+	<-t.done
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.deleteErr
+}
+
+// run polls the session and delivers its messages until the context is done
+// or polling fails, and then deletes the session.
+func (t *LiveTail) run(ctx context.Context, ch chan<- LiveTailMessage) {
+	defer close(t.done)
+	err := t.poll(ctx, ch)
+	t.mu.Lock()
+	if t.closed && errors.Is(err, context.Canceled) {
+		// Close cancels the context, which is not an error of the tail.
+		err = nil
+	}
+	t.err = err
+	id := t.sessionID
+	t.mu.Unlock()
+	close(ch)
+	if errors.Is(err, ErrLiveTailExpired) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), liveTailDeleteTimeout)
+	defer cancel()
+	err = t.c.deleteLiveTailSession(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		// The session expired before it could be restarted.
+		err = nil
+	}
+	t.mu.Lock()
+	t.deleteErr = err
+	t.mu.Unlock()
+}
+
+// poll delivers the messages of the session, starting a new session when it
+// expires, until the context is done or a request fails.
+func (t *LiveTail) poll(ctx context.Context, ch chan<- LiveTailMessage) error {
+	ticker := time.NewTicker(t.opts.PollInterval)
+	defer ticker.Stop()
+	offset := 0
+	for {
+		page, err := t.c.latestLiveTail(ctx, t.SessionID(), offset)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if err != nil || page.IsStopped {
+			if t.opts.NoRestart {
+				return ErrLiveTailExpired
+			}
+			id, err := t.c.createLiveTailSession(ctx, t.filter)
+			if err != nil {
+				return err
+			}
+			t.mu.Lock()
+			t.sessionID = id
+			t.mu.Unlock()
+			offset = 0
+		} else {
+			for _, m := range page.Messages {
+				select {
+				case ch <- newLiveTailMessage(m):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			if page.Offset > offset {
+				offset = page.Offset
+			} else {
+				offset += len(page.Messages)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// liveTailPage is a poll of a live tail session.
+type liveTailPage struct {
+	Messages  []map[string]any `json:"messages"`
+	Offset    int              `json:"offset"`
+	IsStopped bool             `json:"isStopped"`
+}
+
+// createLiveTailSession starts a live tail session and returns its ID.
+func (c *Client) createLiveTailSession(ctx context.Context, filter string) (string, error) {
+	body := struct {
+		Filter string `json:"filter"`
+	}{filter}
+	var resp struct {
+		ID        string `json:"id"`
+		SessionID string `json:"sessionId"`
+	}
+	if err := c.post(ctx, "/v1/livetail/session", body, &resp); err != nil {
+		return "", fmt.Errorf("error starting live tail: %w", err)
+	}
+	if resp.SessionID != "" {
+		return resp.SessionID, nil
+	}
+	return resp.ID, nil
+}
+
+// deleteLiveTailSession stops the live tail session.
+func (c *Client) deleteLiveTailSession(ctx context.Context, sessionID string) error {
+	if err := c.delete(ctx, pathf("/v1/livetail/session/%s", sessionID)); err != nil {
+		return fmt.Errorf("error stopping live tail: %w", err)
+	}
+	return nil
+}
+
+// latestLiveTail returns the messages of the session after the offset.
+func (c *Client) latestLiveTail(ctx context.Context, sessionID string, offset int) (*liveTailPage, error) {
+	var page liveTailPage
+	if err := c.get(ctx, pathf("/v1/livetail/session/%s/latest/%s", sessionID, offset), nil, &page); err != nil {
+		return nil, fmt.Errorf("error polling live tail: %w", err)
+	}
+	return &page, nil
+}
+
+// newLiveTailMessage converts a message of a poll. Values that are not
+// strings are formatted as such.
+func newLiveTailMessage(m map[string]any) LiveTailMessage {
+	fields := make(map[string]string, len(m))
+	for k, v := range m {
+		switch v := v.(type) {
+		case string:
+			fields[k] = v
+		case float64:
+			fields[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		case nil:
+			fields[k] = ""
+		default:
+			fields[k] = fmt.Sprint(v)
+		}
+	}
+	msg := LiveTailMessage{
+		Raw:            fields["_raw"],
+		SourceCategory: fields["_sourcecategory"],
+		SourceHost:     fields["_sourcehost"],
+		SourceName:     fields["_sourcename"],
+		Collector:      fields["_collector"],
+		Map:            fields,
+	}
+	msg.MessageTime, _ = parseTime(fields["_messagetime"])
+	msg.ReceiptTime, _ = parseTime(fields["_receipttime"])
+	return msg
+}
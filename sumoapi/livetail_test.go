@@ -0,0 +1,115 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newLiveTailAPI serves a live tail session that returns a message on its
+// first poll, and counts the sessions that were deleted.
+func newLiveTailAPI(t *testing.T) (*testAPI, *atomic.Int32) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/livetail/session", `{"id": "s1"}`)
+	api.respond("GET /v1/livetail/session/s1/latest/0", `{"messages": [{"_raw": "one", "_sourcecategory": "app", "_messagetime": 1700000000000}], "offset": 1}`)
+	api.respond("GET /v1/livetail/session/s1/latest/1", `{"messages": [], "offset": 1}`)
+	var deleted atomic.Int32
+	api.handle("DELETE /v1/livetail/session/s1", func(w http.ResponseWriter, r *http.Request) {
+		deleted.Add(1)
+	})
+	return api, &deleted
+}
+
+func TestLiveTail(t *testing.T) {
+	api, deleted := newLiveTailAPI(t)
+	tail, err := api.client().LiveTail(context.Background(), "_sourceCategory=app", LiveTailOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.received()[0], http.MethodPost, "/v1/livetail/session", `{"filter": "_sourceCategory=app"}`)
+	msg := <-tail.C
+	if msg.Raw != "one" || msg.SourceCategory != "app" || !msg.MessageTime.Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("message = %+v", msg)
+	}
+	if err := tail.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-tail.C; ok {
+		t.Error("C is open after Close")
+	}
+	if err := tail.Err(); err != nil {
+		t.Errorf("Err after Close = %v, want nil", err)
+	}
+	if n := deleted.Load(); n != 1 {
+		t.Errorf("session deleted %d times, want once", n)
+	}
+}
+
+func TestLiveTailContextDone(t *testing.T) {
+	api, deleted := newLiveTailAPI(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	tail, err := api.client().LiveTail(ctx, "_sourceCategory=app", LiveTailOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tail.Close()
+	<-tail.C
+	cancel()
+	for range tail.C {
+	}
+	if err := tail.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Err = %v, want context.Canceled", err)
+	}
+	if err := tail.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := deleted.Load(); n != 1 {
+		t.Errorf("session deleted %d times, want once", n)
+	}
+}
+
+func TestLiveTailExpired(t *testing.T) {
+	api := newTestAPI(t)
+	var sessions atomic.Int32
+	api.handle("POST /v1/livetail/session", func(w http.ResponseWriter, r *http.Request) {
+		if sessions.Add(1) == 1 {
+			w.Write([]byte(`{"id": "s1"}`))
+			return
+		}
+		w.Write([]byte(`{"sessionId": "s2"}`))
+	})
+	api.respond("GET /v1/livetail/session/s1/latest/0", `{"isStopped": true}`)
+	api.respond("GET /v1/livetail/session/s2/latest/0", `{"messages": [{"_raw": "two"}], "offset": 1}`)
+	api.respond("GET /v1/livetail/session/s2/latest/1", `{"offset": 1}`)
+	api.respond("DELETE /v1/livetail/session/s2", ``)
+	c := api.client()
+	tail, err := c.LiveTail(context.Background(), "*", LiveTailOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The stopped session is replaced with a new one.
+	if msg := <-tail.C; msg.Raw != "two" || tail.SessionID() != "s2" {
+		t.Errorf("message = %+v from session %s, want two from s2", msg, tail.SessionID())
+	}
+	if err := tail.Close(); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.last(), http.MethodDelete, "/v1/livetail/session/s2", "")
+
+	sessions.Store(0)
+	tail, err = c.LiveTail(context.Background(), "*", LiveTailOptions{PollInterval: time.Millisecond, NoRestart: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range tail.C {
+	}
+	if err := tail.Err(); !errors.Is(err, ErrLiveTailExpired) {
+		t.Errorf("Err = %v, want ErrLiveTailExpired", err)
+	}
+	if r := api.last(); r.method == http.MethodDelete {
+		t.Errorf("expired session was deleted: %s %s", r.method, r.path)
+	}
+}
@@ -0,0 +1,315 @@
+package sumoapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrLookupJobFailed is returned, wrapped with the reason, when a lookup
+// table upload or truncate job fails.
+var ErrLookupJobFailed = errors.New("lookup table job failed")
+
+// Types of the fields of a lookup table.
+const (
+	LookupBoolean = "boolean"
+	LookupInt     = "int"
+	LookupLong    = "long"
+	LookupDouble  = "double"
+	LookupString  = "string"
+)
+
+// Actions taken when a lookup table reaches its size limit.
+const (
+	LookupStopIncomingMessages = "StopIncomingMessages"
+	LookupDeleteOldData        = "DeleteOldData"
+)
+
+// LookupTable is a lookup table, which holds rows that searches can enrich
+// logs with.
+type LookupTable struct {
+	ID          string        `json:"id,omitempty"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Fields      []LookupField `json:"fields"`
+	// PrimaryKeys are the names of the fields that identify a row.
+	PrimaryKeys []string `json:"primaryKeys"`
+	// TTL is how many minutes rows are kept after they were last updated.
+	// Zero keeps rows forever.
+	TTL int `json:"ttl,omitempty"`
+	// SizeLimitAction is LookupStopIncomingMessages or
+	// LookupDeleteOldData.
+	SizeLimitAction string `json:"sizeLimitAction,omitempty"`
+	// ParentFolderID is the content folder the table is in.
+	ParentFolderID string    `json:"parentFolderId"`
+	Size           int64     `json:"size,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	CreatedBy      string    `json:"createdBy,omitempty"`
+	ModifiedAt     time.Time `json:"modifiedAt"`
+	ModifiedBy     string    `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the lookup table that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the lookup table, keeping the properties that have
+// no field in Extra.
+func (t *LookupTable) UnmarshalJSON(b []byte) error {
+	type plain LookupTable
+	return unmarshalExtra(b, (*plain)(t), &t.Extra)
+}
+
+// MarshalJSON encodes the lookup table along with the properties in Extra.
+func (t LookupTable) MarshalJSON() ([]byte, error) {
+	type plain LookupTable
+	return marshalExtra(plain(t), t.Extra)
+}
+
+// LookupField is a field, or column, of a lookup table.
+type LookupField struct {
+	FieldName string `json:"fieldName"`
+	// FieldType is one of the Lookup type constants.
+	FieldType string `json:"fieldType"`
+
+	// Extra holds the properties of the lookup field that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the lookup field, keeping the properties that have
+// no field in Extra.
+func (f *LookupField) UnmarshalJSON(b []byte) error {
+	type plain LookupField
+	return unmarshalExtra(b, (*plain)(f), &f.Extra)
+}
+
+// MarshalJSON encodes the lookup field along with the properties in Extra.
+func (f LookupField) MarshalJSON() ([]byte, error) {
+	type plain LookupField
+	return marshalExtra(plain(f), f.Extra)
+}
+
+// LookupColumn is the value of a column in a row of a lookup table.
+type LookupColumn struct {
+	ColumnName  string `json:"columnName"`
+	ColumnValue string `json:"columnValue"`
+}
+
+// LookupRow returns the columns of a row from a map of field names to
+// values.
+func LookupRow(values map[string]string) []LookupColumn {
+	row := make([]LookupColumn, 0, len(values))
+	for k, v := range values {
+		row = append(row, LookupColumn{ColumnName: k, ColumnValue: v})
+	}
+	return row
+}
+
+// Statuses of a lookup table job.
+const (
+	LookupJobPending    = "Pending"
+	LookupJobInProgress = "InProgress"
+	LookupJobSuccess    = "Success"
+	LookupJobFailed     = "Failed"
+)
+
+// LookupJobStatus is the progress of a lookup table upload or truncate job.
+type LookupJobStatus struct {
+	JobID          string        `json:"jobId"`
+	Status         string        `json:"status"`
+	StatusMessages []string      `json:"statusMessages,omitempty"`
+	Errors         []ErrorDetail `json:"errors,omitempty"`
+	Warnings       []ErrorDetail `json:"warnings,omitempty"`
+	LookupTableID  string        `json:"lookupContentId,omitempty"`
+	StartTime      time.Time     `json:"startTime"`
+	EndTime        time.Time     `json:"endTime"`
+}
+
+// Done reports whether the job has finished, successfully or not.
+func (s LookupJobStatus) Done() bool {
+	return s.Status == LookupJobSuccess || s.Status == LookupJobFailed
+}
+
+// UploadOptions configures how a CSV file is uploaded to a lookup table.
+type UploadOptions struct {
+	// Merge adds the rows of the file to the table, replacing rows with the
+	// same primary key, instead of replacing the whole table.
+	Merge bool
+	// FileEncoding is the encoding of the file. It defaults to UTF-8.
+	FileEncoding string
+}
+
+// GetLookupTable returns the lookup table with the ID.
+func (c *Client) GetLookupTable(ctx context.Context, id string) (*LookupTable, error) {
+	var t LookupTable
+	if err := c.get(ctx, pathf("/v1/lookupTables/%s", id), nil, &t); err != nil {
+		return nil, fmt.Errorf("error getting lookup table: %w", err)
+	}
+	return &t, nil
+}
+
+// CreateLookupTable creates a lookup table with the schema of the table and
+// returns it. The table is empty until rows are inserted or a CSV file is
+// uploaded.
+func (c *Client) CreateLookupTable(ctx context.Context, table LookupTable) (*LookupTable, error) {
+	if table.Name == "" || len(table.Fields) == 0 || len(table.PrimaryKeys) == 0 {
+		return nil, fmt.Errorf("error creating lookup table: name, fields and primary keys are required")
+	}
+	body := struct {
+		Name            string        `json:"name"`
+		Description     string        `json:"description"`
+		Fields          []LookupField `json:"fields"`
+		PrimaryKeys     []string      `json:"primaryKeys"`
+		TTL             int           `json:"ttl,omitempty"`
+		SizeLimitAction string        `json:"sizeLimitAction,omitempty"`
+		ParentFolderID  string        `json:"parentFolderId"`
+	}{table.Name, table.Description, table.Fields, table.PrimaryKeys, table.TTL, table.SizeLimitAction, table.ParentFolderID}
+	var created LookupTable
+	if err := c.post(ctx, "/v1/lookupTables", body, &created); err != nil {
+		return nil, fmt.Errorf("error creating lookup table: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateLookupTable changes the description, TTL and size limit action of
+// the lookup table with the same ID and returns the updated table. The
+// schema of a table cannot be changed.
+func (c *Client) UpdateLookupTable(ctx context.Context, table LookupTable) (*LookupTable, error) {
+	body := struct {
+		Description     string `json:"description"`
+		TTL             int    `json:"ttl"`
+		SizeLimitAction string `json:"sizeLimitAction,omitempty"`
+	}{table.Description, table.TTL, table.SizeLimitAction}
+	var updated LookupTable
+	if err := c.put(ctx, pathf("/v1/lookupTables/%s", table.ID), body, &updated); err != nil {
+		return nil, fmt.Errorf("error updating lookup table: %w", err)
+	}
+	return &updated, nil
+}
+
+// DeleteLookupTable deletes the lookup table with the ID, along with its
+// rows.
+func (c *Client) DeleteLookupTable(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/lookupTables/%s", id)); err != nil {
+		return fmt.Errorf("error deleting lookup table: %w", err)
+	}
+	return nil
+}
+
+// UploadLookupCSV uploads a CSV file, whose header row names the fields of
+// the lookup table, and returns the ID of the job that loads it. Use
+// WaitLookupJob to wait for the rows to be loaded.
+func (c *Client) UploadLookupCSV(ctx context.Context, id, fileName string, csv io.Reader, opts UploadOptions) (string, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", fileName)
+	if err != nil {
+		return "", fmt.Errorf("error uploading lookup table: %w", err)
+	}
+	if _, err := io.Copy(part, csv); err != nil {
+		return "", fmt.Errorf("error uploading lookup table: error reading csv: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return "", fmt.Errorf("error uploading lookup table: %w", err)
+	}
+	encoding := opts.FileEncoding
+	if encoding == "" {
+		encoding = "UTF-8"
+	}
+	var job struct {
+		ID string `json:"id"`
+	}
+	r := request{
+		method: http.MethodPost,
+		path:   pathf("/v1/lookupTables/%s/upload", id),
+		query:  url.Values{"merge": {strconv.FormatBool(opts.Merge)}, "fileEncoding": {encoding}},
+		header: http.Header{"Content-Type": {mw.FormDataContentType()}},
+		body:   &body,
+		out:    &job,
+	}
+	if _, err := c.do(ctx, r); err != nil {
+		return "", fmt.Errorf("error uploading lookup table: %w", err)
+	}
+	return job.ID, nil
+}
+
+// LookupJobStatus returns the progress of the lookup table job.
+func (c *Client) LookupJobStatus(ctx context.Context, jobID string) (*LookupJobStatus, error) {
+	var status LookupJobStatus
+	if err := c.get(ctx, pathf("/v1/lookupTables/jobs/%s/status", jobID), nil, &status); err != nil {
+		return nil, fmt.Errorf("error getting lookup table job status: %w", err)
+	}
+	return &status, nil
+}
+
+// WaitLookupJob polls the status of the lookup table job until it has
+// finished, and returns the final status. If the job failed the error wraps
+// ErrLookupJobFailed.
+func (c *Client) WaitLookupJob(ctx context.Context, jobID string) (*LookupJobStatus, error) {
+	for {
+		status, err := c.LookupJobStatus(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if status.Status == LookupJobFailed {
+			reasons := status.StatusMessages
+			for _, e := range status.Errors {
+				reasons = append(reasons, e.Message)
+			}
+			return status, fmt.Errorf("%w: %s", ErrLookupJobFailed, strings.Join(reasons, "; "))
+		}
+		if status.Done() {
+			return status, nil
+		}
+		if err := c.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// UpsertLookupRow inserts the row into the lookup table with the ID, or
+// updates the row with the same primary key.
+func (c *Client) UpsertLookupRow(ctx context.Context, id string, row []LookupColumn) error {
+	body := struct {
+		Row []LookupColumn `json:"row"`
+	}{row}
+	if err := c.put(ctx, pathf("/v1/lookupTables/%s/row", id), body, nil); err != nil {
+		return fmt.Errorf("error updating lookup table row: %w", err)
+	}
+	return nil
+}
+
+// DeleteLookupRow deletes the row with the primary key, given as the values
+// of the primary key columns, from the lookup table with the ID.
+func (c *Client) DeleteLookupRow(ctx context.Context, id string, primaryKey []LookupColumn) error {
+	body := struct {
+		PrimaryKey []LookupColumn `json:"primaryKey"`
+	}{primaryKey}
+	if err := c.put(ctx, pathf("/v1/lookupTables/%s/deleteTableRow", id), body, nil); err != nil {
+		return fmt.Errorf("error deleting lookup table row: %w", err)
+	}
+	return nil
+}
+
+// TruncateLookupTable deletes every row of the lookup table with the ID,
+// keeping its schema, and returns the ID of the job doing so. Use
+// WaitLookupJob to wait for it to finish.
+func (c *Client) TruncateLookupTable(ctx context.Context, id string) (string, error) {
+	var job struct {
+		ID string `json:"id"`
+	}
+	if err := c.post(ctx, pathf("/v1/lookupTables/%s/truncate", id), nil, &job); err != nil {
+		return "", fmt.Errorf("error truncating lookup table: %w", err)
+	}
+	return job.ID, nil
+}
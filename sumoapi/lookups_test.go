@@ -0,0 +1,172 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLookupTables(t *testing.T) {
+	ctx := context.Background()
+	table := `{"id": "l1", "name": "hosts", "description": "", "fields": [{"fieldName": "host", "fieldType": "string", "nullable": false}], "primaryKeys": ["host"], "parentFolderId": "f1", "contentPath": "/Library/hosts"}`
+	testCalls(t, []apiCall{
+		{
+			name: "get",
+			call: func(c *Client) error {
+				l, err := c.GetLookupTable(ctx, "l1")
+				if err != nil {
+					return err
+				}
+				if err := expect(string(l.Extra["contentPath"]), `"/Library/hosts"`); err != nil {
+					return err
+				}
+				return expect(string(l.Fields[0].Extra["nullable"]), "false")
+			},
+			response: table,
+			method:   http.MethodGet,
+			path:     "/v1/lookupTables/l1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateLookupTable(ctx, LookupTable{
+					Name:            "hosts",
+					Fields:          []LookupField{{FieldName: "host", FieldType: LookupString}, {FieldName: "owner", FieldType: LookupString}},
+					PrimaryKeys:     []string{"host"},
+					TTL:             60,
+					SizeLimitAction: LookupDeleteOldData,
+					ParentFolderID:  "f1",
+				})
+				return err
+			},
+			response: table,
+			method:   http.MethodPost,
+			path:     "/v1/lookupTables",
+			body: `{
+				"name": "hosts",
+				"description": "",
+				"fields": [{"fieldName": "host", "fieldType": "string"}, {"fieldName": "owner", "fieldType": "string"}],
+				"primaryKeys": ["host"],
+				"ttl": 60,
+				"sizeLimitAction": "DeleteOldData",
+				"parentFolderId": "f1"
+			}`,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateLookupTable(ctx, LookupTable{ID: "l1", Name: "hosts", Description: "owners"})
+				return err
+			},
+			response: table,
+			method:   http.MethodPut,
+			path:     "/v1/lookupTables/l1",
+			// A TTL of zero is sent, which turns expiry off.
+			body: `{"description": "owners", "ttl": 0}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteLookupTable(ctx, "l1") },
+			method: http.MethodDelete,
+			path:   "/v1/lookupTables/l1",
+		},
+		{
+			name: "upsert row",
+			call: func(c *Client) error {
+				return c.UpsertLookupRow(ctx, "l1", LookupRow(map[string]string{"host": "web-1"}))
+			},
+			method: http.MethodPut,
+			path:   "/v1/lookupTables/l1/row",
+			body:   `{"row": [{"columnName": "host", "columnValue": "web-1"}]}`,
+		},
+		{
+			name: "delete row",
+			call: func(c *Client) error {
+				return c.DeleteLookupRow(ctx, "l1", LookupRow(map[string]string{"host": "web-1"}))
+			},
+			method: http.MethodPut,
+			path:   "/v1/lookupTables/l1/deleteTableRow",
+			body:   `{"primaryKey": [{"columnName": "host", "columnValue": "web-1"}]}`,
+		},
+		{
+			name: "truncate",
+			call: func(c *Client) error {
+				id, err := c.TruncateLookupTable(ctx, "l1")
+				if err != nil {
+					return err
+				}
+				return expect(id, "j1")
+			},
+			response: `{"id": "j1"}`,
+			method:   http.MethodPost,
+			path:     "/v1/lookupTables/l1/truncate",
+		},
+	})
+}
+
+func TestUploadLookupCSV(t *testing.T) {
+	api := newTestAPI(t)
+	api.handle("POST /v1/lookupTables/l1/upload", func(w http.ResponseWriter, r *http.Request) {
+		f, h, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+		data, _ := io.ReadAll(f)
+		if h.Filename != "hosts.csv" || string(data) != "host,owner\nweb-1,ops\n" {
+			http.Error(w, fmt.Sprintf("unexpected file %s: %q", h.Filename, data), http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, `{"id": "j1"}`)
+	})
+	id, err := api.client().UploadLookupCSV(context.Background(), "l1", "hosts.csv", strings.NewReader("host,owner\nweb-1,ops\n"), UploadOptions{Merge: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "j1" {
+		t.Errorf("job ID = %q", id)
+	}
+	if got := api.last().query.Encode(); got != "fileEncoding=UTF-8&merge=true" {
+		t.Errorf("query = %s", got)
+	}
+}
+
+func TestWaitLookupJob(t *testing.T) {
+	tests := []struct {
+		name    string
+		final   string
+		wantErr error
+	}{
+		{"success", `{"jobId": "j1", "status": "Success", "lookupContentId": "l1"}`, nil},
+		{"failed", `{"jobId": "j1", "status": "Failed", "statusMessages": ["bad row"], "errors": [{"code": "lookup:invalid", "message": "missing key"}]}`, ErrLookupJobFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI(t)
+			var n atomic.Int32
+			api.handle("GET /v1/lookupTables/jobs/j1/status", func(w http.ResponseWriter, r *http.Request) {
+				if n.Add(1) == 1 {
+					io.WriteString(w, `{"jobId": "j1", "status": "Pending"}`)
+					return
+				}
+				io.WriteString(w, tt.final)
+			})
+			status, err := api.client().WaitLookupJob(context.Background(), "j1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WaitLookupJob = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.HasSuffix(err.Error(), "bad row; missing key") {
+				t.Errorf("error = %q, want the status messages and errors", err)
+			}
+			if status == nil || !status.Done() || n.Load() != 2 {
+				t.Errorf("WaitLookupJob = %+v after %d polls, want the final status", status, n.Load())
+			}
+		})
+	}
+}
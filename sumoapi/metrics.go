@@ -0,0 +1,239 @@
+package sumoapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Rollups of a metrics query, which combine the data points in each
+// quantization interval.
+const (
+	RollupAvg   = "Avg"
+	RollupMin   = "Min"
+	RollupMax   = "Max"
+	RollupSum   = "Sum"
+	RollupCount = "Count"
+)
+
+// MetricsQuery is a single query of a MetricsQueryRequest.
+type MetricsQuery struct {
+	// RowID names the query, such as "A", so other queries can refer to it
+	// with #A.
+	RowID string
+	// Query is the metrics query, such as metric=CPU_Idle | avg by host.
+	Query string
+	// Quantization is the interval data points are combined over. Sumo
+	// Logic picks one based on the time range when it is zero.
+	Quantization time.Duration
+	// Rollup is one of the Rollup constants. It defaults to RollupAvg.
+	Rollup string
+}
+
+// MetricsQueryRequest runs one or more metrics queries over a time range.
+type MetricsQueryRequest struct {
+	Queries []MetricsQuery
+	From    time.Time
+	To      time.Time
+}
+
+// MetricsQueryResult is the result of a MetricsQueryRequest, with a row for
+// each query in the same order.
+type MetricsQueryResult struct {
+	Rows []MetricsQueryRow
+}
+
+// Row returns the row for the query with the row ID.
+func (r MetricsQueryResult) Row(rowID string) (MetricsQueryRow, bool) {
+	for _, row := range r.Rows {
+		if row.RowID == rowID {
+			return row, true
+		}
+	}
+	return MetricsQueryRow{}, false
+}
+
+// MetricsQueryRow holds the time series returned by one query.
+type MetricsQueryRow struct {
+	RowID  string
+	Series []TimeSeries
+}
+
+// TimeSeries is a series of data points of a metric. Timestamps and Values
+// have the same length.
+type TimeSeries struct {
+	Metric     string
+	Dimensions map[string]string
+	Timestamps []time.Time
+	Values     []float64
+}
+
+// Pairs returns the data points of the series as [timestamp, value] pairs,
+// with timestamps in milliseconds since the epoch.
+func (ts TimeSeries) Pairs() [][]float64 {
+	pairs := make([][]float64, len(ts.Values))
+	for i, v := range ts.Values {
+		pairs[i] = []float64{float64(ts.Timestamps[i].UnixMilli()), v}
+	}
+	return pairs
+}
+
+// Matrix aligns every series of the row on the union of their timestamps,
+// and returns the timestamps along with a matrix holding a row for each
+// series and a column for each timestamp. Series without a data point at a
+// timestamp have math.NaN() there.
+func (r MetricsQueryRow) Matrix() ([]time.Time, [][]float64) {
+	var millis []int64
+	for _, s := range r.Series {
+		for _, t := range s.Timestamps {
+			millis = append(millis, t.UnixMilli())
+		}
+	}
+	slices.Sort(millis)
+	millis = slices.Compact(millis)
+	col := make(map[int64]int, len(millis))
+	timestamps := make([]time.Time, len(millis))
+	for i, ms := range millis {
+		col[ms] = i
+		timestamps[i] = time.UnixMilli(ms).UTC()
+	}
+	matrix := make([][]float64, len(r.Series))
+	for i, s := range r.Series {
+		row := make([]float64, len(millis))
+		for j := range row {
+			row[j] = math.NaN()
+		}
+		for j, t := range s.Timestamps {
+			row[col[t.UnixMilli()]] = s.Values[j]
+		}
+		matrix[i] = row
+	}
+	return timestamps, matrix
+}
+
+// Dense returns the matrix of Matrix flattened in row-major order along with
+// its dimensions, the form taken by gonum's mat.NewDense(rows, cols, data).
+func (r MetricsQueryRow) Dense() (rows, cols int, data []float64) {
+	timestamps, matrix := r.Matrix()
+	data = make([]float64, 0, len(matrix)*len(timestamps))
+	for _, row := range matrix {
+		data = append(data, row...)
+	}
+	return len(matrix), len(timestamps), data
+}
+
+// QueryMetrics runs the metrics queries of the request and returns their
+// results.
+func (c *Client) QueryMetrics(ctx context.Context, req MetricsQueryRequest) (*MetricsQueryResult, error) {
+	if len(req.Queries) == 0 {
+		return nil, fmt.Errorf("error querying metrics: at least one query is required")
+	}
+	if req.From.IsZero() || req.To.IsZero() {
+		return nil, fmt.Errorf("error querying metrics: from and to are required")
+	}
+	var resp metricsResponse
+	if err := c.post(ctx, "/v1/metricsQueries", newMetricsBody(req), &resp); err != nil {
+		return nil, fmt.Errorf("error querying metrics: %w", err)
+	}
+	if len(resp.Errors.Errors) > 0 {
+		return nil, fmt.Errorf("error querying metrics: %w", Error{StatusCode: http.StatusOK, ID: resp.Errors.ID, Errors: resp.Errors.Errors})
+	}
+	result := &MetricsQueryResult{Rows: make([]MetricsQueryRow, len(resp.QueryResult))}
+	for i, qr := range resp.QueryResult {
+		row := MetricsQueryRow{RowID: qr.RowID, Series: make([]TimeSeries, len(qr.TimeSeriesList.TimeSeries))}
+		for j, ts := range qr.TimeSeriesList.TimeSeries {
+			series := TimeSeries{
+				Metric:     ts.MetricDefinition.Metric,
+				Dimensions: ts.MetricDefinition.Dimensions,
+				Timestamps: make([]time.Time, len(ts.Points.Timestamps)),
+				Values:     ts.Points.Values,
+			}
+			for k, ms := range ts.Points.Timestamps {
+				series.Timestamps[k] = time.UnixMilli(ms).UTC()
+			}
+			if len(series.Values) != len(series.Timestamps) {
+				return nil, fmt.Errorf("error querying metrics: series %d of row %s has %d timestamps and %d values", j, qr.RowID, len(series.Timestamps), len(series.Values))
+			}
+			row.Series[j] = series
+		}
+		result.Rows[i] = row
+	}
+	return result, nil
+}
+
+// metricsBody is the body of a metrics query request.
+type metricsBody struct {
+	Queries   []metricsBodyQuery `json:"queries"`
+	TimeRange any                `json:"timeRange"`
+}
+
+type metricsBodyQuery struct {
+	RowID        string `json:"rowId"`
+	Query        string `json:"query"`
+	Quantization int64  `json:"quantization,omitempty"`
+	Rollup       string `json:"rollup,omitempty"`
+}
+
+// newMetricsBody returns the body of the request.
+func newMetricsBody(req MetricsQueryRequest) metricsBody {
+	body := metricsBody{
+		Queries:   make([]metricsBodyQuery, len(req.Queries)),
+		TimeRange: epochTimeRange(req.From, req.To),
+	}
+	for i, q := range req.Queries {
+		rowID := q.RowID
+		if rowID == "" {
+			rowID = string(rune('A' + i%26))
+		}
+		body.Queries[i] = metricsBodyQuery{
+			RowID:        rowID,
+			Query:        q.Query,
+			Quantization: q.Quantization.Milliseconds(),
+			Rollup:       q.Rollup,
+		}
+	}
+	return body
+}
+
+// epochTimeRange returns a time range between two absolute times.
+func epochTimeRange(from, to time.Time) any {
+	type boundary struct {
+		Type        string `json:"type"`
+		EpochMillis int64  `json:"epochMillis"`
+	}
+	return struct {
+		Type string   `json:"type"`
+		From boundary `json:"from"`
+		To   boundary `json:"to"`
+	}{
+		Type: "BeginBoundedTimeRange",
+		From: boundary{"EpochTimeRangeBoundary", from.UnixMilli()},
+		To:   boundary{"EpochTimeRangeBoundary", to.UnixMilli()},
+	}
+}
+
+// metricsResponse is the response to a metrics query request.
+type metricsResponse struct {
+	QueryResult []struct {
+		RowID          string `json:"rowId"`
+		TimeSeriesList struct {
+			TimeSeries []struct {
+				MetricDefinition struct {
+					Metric     string            `json:"metric"`
+					Dimensions map[string]string `json:"dimensions"`
+				} `json:"metricDefinition"`
+				Points struct {
+					Timestamps []int64   `json:"timestamps"`
+					Values     []float64 `json:"values"`
+				} `json:"points"`
+			} `json:"timeSeries"`
+		} `json:"timeSeriesList"`
+	} `json:"queryResult"`
+	Errors struct {
+		ID     string        `json:"id"`
+		Errors []ErrorDetail `json:"errors"`
+	} `json:"errors"`
+}
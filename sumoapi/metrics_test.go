@@ -0,0 +1,113 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestQueryMetrics(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/metricsQueries", `{"queryResult": [{"rowId": "A", "timeSeriesList": {"timeSeries": [
+		{"metricDefinition": {"metric": "CPU_Idle", "dimensions": {"host": "web-1"}}, "points": {"timestamps": [1000, 2000], "values": [90, 80]}}
+	]}}]}`)
+	from, to := time.UnixMilli(1000), time.UnixMilli(61000)
+	result, err := api.client().QueryMetrics(context.Background(), MetricsQueryRequest{
+		Queries: []MetricsQuery{{Query: "metric=CPU_Idle", Quantization: time.Minute, Rollup: RollupMax}, {RowID: "total", Query: "#A | sum"}},
+		From:    from,
+		To:      to,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.last(), http.MethodPost, "/v1/metricsQueries", `{
+		"queries": [
+			{"rowId": "A", "query": "metric=CPU_Idle", "quantization": 60000, "rollup": "Max"},
+			{"rowId": "total", "query": "#A | sum"}
+		],
+		"timeRange": {
+			"type": "BeginBoundedTimeRange",
+			"from": {"type": "EpochTimeRangeBoundary", "epochMillis": 1000},
+			"to": {"type": "EpochTimeRangeBoundary", "epochMillis": 61000}
+		}
+	}`)
+	row, ok := result.Row("A")
+	if !ok || len(row.Series) != 1 {
+		t.Fatalf("Row(A) = %+v, %v", row, ok)
+	}
+	s := row.Series[0]
+	if s.Metric != "CPU_Idle" || s.Dimensions["host"] != "web-1" || !s.Timestamps[1].Equal(time.UnixMilli(2000)) {
+		t.Errorf("series = %+v", s)
+	}
+	if err := expect(s.Pairs(), [][]float64{{1000, 90}, {2000, 80}}); err != nil {
+		t.Errorf("Pairs: %v", err)
+	}
+	if _, ok := result.Row("B"); ok {
+		t.Error("Row(B) found a row that was not queried")
+	}
+}
+
+func TestQueryMetricsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      MetricsQueryRequest
+		response string
+	}{
+		{"no queries", MetricsQueryRequest{From: time.UnixMilli(1), To: time.UnixMilli(2)}, ""},
+		{"no time range", MetricsQueryRequest{Queries: []MetricsQuery{{Query: "metric=CPU_Idle"}}}, ""},
+		{
+			"query error",
+			MetricsQueryRequest{Queries: []MetricsQuery{{Query: "metric="}}, From: time.UnixMilli(1), To: time.UnixMilli(2)},
+			`{"errors": {"id": "E1", "errors": [{"code": "metrics:parse", "message": "bad query"}]}}`,
+		},
+		{
+			"mismatched points",
+			MetricsQueryRequest{Queries: []MetricsQuery{{Query: "metric=CPU_Idle"}}, From: time.UnixMilli(1), To: time.UnixMilli(2)},
+			`{"queryResult": [{"rowId": "A", "timeSeriesList": {"timeSeries": [{"points": {"timestamps": [1, 2], "values": [1]}}]}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI(t)
+			api.respond("POST /v1/metricsQueries", tt.response)
+			_, err := api.client().QueryMetrics(context.Background(), tt.req)
+			if err == nil {
+				t.Fatal("QueryMetrics succeeded")
+			}
+			var apiErr Error
+			if tt.name == "query error" && (!errors.As(err, &apiErr) || apiErr.ID != "E1") {
+				t.Errorf("QueryMetrics = %v, want an Error with the ID", err)
+			}
+			if n := len(api.received()); tt.response == "" && n != 0 {
+				t.Errorf("received %d requests, want none", n)
+			}
+		})
+	}
+}
+
+func TestMetricsRowMatrix(t *testing.T) {
+	row := MetricsQueryRow{Series: []TimeSeries{
+		{Timestamps: []time.Time{time.UnixMilli(1000), time.UnixMilli(3000)}, Values: []float64{1, 3}},
+		{Timestamps: []time.Time{time.UnixMilli(2000), time.UnixMilli(3000)}, Values: []float64{20, 30}},
+	}}
+	timestamps, matrix := row.Matrix()
+	if err := expect(timestamps, []time.Time{time.UnixMilli(1000).UTC(), time.UnixMilli(2000).UTC(), time.UnixMilli(3000).UTC()}); err != nil {
+		t.Errorf("timestamps: %v", err)
+	}
+	want := [][]float64{{1, math.NaN(), 3}, {math.NaN(), 20, 30}}
+	for i := range want {
+		for j := range want[i] {
+			if got := matrix[i][j]; got != want[i][j] && !(math.IsNaN(got) && math.IsNaN(want[i][j])) {
+				t.Errorf("matrix[%d][%d] = %v, want %v", i, j, got, want[i][j])
+			}
+		}
+	}
+
+	rows, cols, data := row.Dense()
+	if rows != 2 || cols != 3 || len(data) != 6 || !math.IsNaN(data[3]) || data[4] != 20 {
+		t.Errorf("Dense = %d, %d, %v", rows, cols, data)
+	}
+}
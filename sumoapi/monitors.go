@@ -0,0 +1,334 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types of the items in the monitors library.
+const (
+	MonitorTypeMonitor = "MonitorsLibraryMonitor"
+	MonitorTypeFolder  = "MonitorsLibraryFolder"
+)
+
+// Statuses of a monitor, used with ListMonitorsByStatus.
+const (
+	MonitorStatusNormal       = "Normal"
+	MonitorStatusCritical     = "Critical"
+	MonitorStatusWarning      = "Warning"
+	MonitorStatusMissingData  = "MissingData"
+	MonitorStatusDisabled     = "Disabled"
+	MonitorStatusAllTriggered = "AllTriggered"
+)
+
+// Monitor is an item in the monitors library, which is either a monitor or a
+// folder of monitors, as told by Type. Fields that do not apply to the type
+// of the item are left empty.
+type Monitor struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Type is MonitorTypeMonitor or MonitorTypeFolder.
+	Type       string    `json:"type"`
+	ParentID   string    `json:"parentId,omitempty"`
+	Version    int       `json:"version,omitempty"`
+	IsLocked   bool      `json:"isLocked,omitempty"`
+	IsSystem   bool      `json:"isSystem,omitempty"`
+	IsMutable  bool      `json:"isMutable,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	CreatedBy  string    `json:"createdBy,omitempty"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+	ModifiedBy string    `json:"modifiedBy,omitempty"`
+
+	// MonitorType is "Logs", "Metrics" or "Slo".
+	MonitorType        string                `json:"monitorType,omitempty"`
+	EvaluationDelay    string                `json:"evaluationDelay,omitempty"`
+	Queries            []MonitorQuery        `json:"queries,omitempty"`
+	Triggers           []MonitorTrigger      `json:"triggers,omitempty"`
+	Notifications      []MonitorNotification `json:"notifications,omitempty"`
+	IsDisabled         bool                  `json:"isDisabled,omitempty"`
+	GroupNotifications bool                  `json:"groupNotifications,omitempty"`
+	Playbook           string                `json:"playbook,omitempty"`
+	// Status lists the current statuses of a monitor, such as
+	// MonitorStatusCritical.
+	Status []string `json:"status,omitempty"`
+
+	// Children are the items in a folder.
+	Children []Monitor `json:"children,omitempty"`
+
+	// Extra holds the properties of the monitor that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the monitor, keeping the properties that have no
+// field in Extra.
+func (m *Monitor) UnmarshalJSON(b []byte) error {
+	type plain Monitor
+	return unmarshalExtra(b, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes the monitor along with the properties in Extra.
+func (m Monitor) MarshalJSON() ([]byte, error) {
+	type plain Monitor
+	return marshalExtra(plain(m), m.Extra)
+}
+
+// IsFolder reports whether the item is a folder.
+func (m Monitor) IsFolder() bool {
+	return m.Type == MonitorTypeFolder
+}
+
+// MonitorQuery is a query evaluated by a monitor. RowID names the query so
+// triggers can refer to it.
+type MonitorQuery struct {
+	RowID string `json:"rowId"`
+	Query string `json:"query"`
+
+	// Extra holds the properties of the monitor query that have no
+	// field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the monitor query, keeping the properties that have
+// no field in Extra.
+func (q *MonitorQuery) UnmarshalJSON(b []byte) error {
+	type plain MonitorQuery
+	return unmarshalExtra(b, (*plain)(q), &q.Extra)
+}
+
+// MarshalJSON encodes the monitor query along with the properties in Extra.
+func (q MonitorQuery) MarshalJSON() ([]byte, error) {
+	type plain MonitorQuery
+	return marshalExtra(plain(q), q.Extra)
+}
+
+// MonitorTrigger is a condition that changes the status of a monitor.
+type MonitorTrigger struct {
+	// TriggerType is the status the trigger sets, such as "Critical", or
+	// "ResolvedCritical" for the condition that resolves it.
+	TriggerType     string  `json:"triggerType"`
+	Threshold       float64 `json:"threshold"`
+	ThresholdType   string  `json:"thresholdType,omitempty"`
+	TimeRange       string  `json:"timeRange"`
+	OccurrenceType  string  `json:"occurrenceType,omitempty"`
+	TriggerSource   string  `json:"triggerSource,omitempty"`
+	DetectionMethod string  `json:"detectionMethod,omitempty"`
+
+	// Extra holds the properties of the trigger that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the trigger, keeping the properties that have no
+// field in Extra.
+func (t *MonitorTrigger) UnmarshalJSON(b []byte) error {
+	type plain MonitorTrigger
+	return unmarshalExtra(b, (*plain)(t), &t.Extra)
+}
+
+// MarshalJSON encodes the trigger along with the properties in Extra.
+func (t MonitorTrigger) MarshalJSON() ([]byte, error) {
+	type plain MonitorTrigger
+	return marshalExtra(plain(t), t.Extra)
+}
+
+// MonitorNotification sends a notification when a monitor triggers.
+type MonitorNotification struct {
+	Notification       MonitorNotificationAction `json:"notification"`
+	RunForTriggerTypes []string                  `json:"runForTriggerTypes"`
+
+	// Extra holds the properties of the notification that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the notification, keeping the properties that have
+// no field in Extra.
+func (n *MonitorNotification) UnmarshalJSON(b []byte) error {
+	type plain MonitorNotification
+	return unmarshalExtra(b, (*plain)(n), &n.Extra)
+}
+
+// MarshalJSON encodes the notification along with the properties in Extra.
+func (n MonitorNotification) MarshalJSON() ([]byte, error) {
+	type plain MonitorNotification
+	return marshalExtra(plain(n), n.Extra)
+}
+
+// MonitorNotificationAction is where and how a notification is sent. Emails
+// use Recipients, Subject and MessageBody, while webhooks use ConnectionID
+// and PayloadOverride.
+type MonitorNotificationAction struct {
+	ConnectionType  string   `json:"connectionType"`
+	ConnectionID    string   `json:"connectionId,omitempty"`
+	PayloadOverride string   `json:"payloadOverride,omitempty"`
+	Recipients      []string `json:"recipients,omitempty"`
+	Subject         string   `json:"subject,omitempty"`
+	MessageBody     string   `json:"messageBody,omitempty"`
+	TimeZone        string   `json:"timeZone,omitempty"`
+
+	// Extra holds the properties of the notification action that have no
+	// field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the notification action, keeping the properties that
+// have no field in Extra.
+func (a *MonitorNotificationAction) UnmarshalJSON(b []byte) error {
+	type plain MonitorNotificationAction
+	return unmarshalExtra(b, (*plain)(a), &a.Extra)
+}
+
+// MarshalJSON encodes the notification action along with the properties in
+// Extra.
+func (a MonitorNotificationAction) MarshalJSON() ([]byte, error) {
+	type plain MonitorNotificationAction
+	return marshalExtra(plain(a), a.Extra)
+}
+
+// MonitorSearchResult is a monitor found by ListMonitorsByStatus along with
+// its path in the monitors library.
+type MonitorSearchResult struct {
+	Item Monitor `json:"item"`
+	Path string  `json:"path"`
+}
+
+// GetMonitorRootFolder returns the root folder of the monitors library,
+// including its children.
+func (c *Client) GetMonitorRootFolder(ctx context.Context) (*Monitor, error) {
+	var m Monitor
+	if err := c.get(ctx, "/v1/monitors/root", nil, &m); err != nil {
+		return nil, fmt.Errorf("error getting monitors root folder: %w", err)
+	}
+	return &m, nil
+}
+
+// GetMonitor returns the monitor or folder with the ID. Folders include
+// their children.
+func (c *Client) GetMonitor(ctx context.Context, id string) (*Monitor, error) {
+	var m Monitor
+	if err := c.get(ctx, pathf("/v1/monitors/%s", id), nil, &m); err != nil {
+		return nil, fmt.Errorf("error getting monitor: %w", err)
+	}
+	return &m, nil
+}
+
+// CreateMonitor creates the monitor in the folder with the parent ID and
+// returns it. Type defaults to MonitorTypeMonitor.
+func (c *Client) CreateMonitor(ctx context.Context, parentID string, monitor Monitor) (*Monitor, error) {
+	if monitor.Type == "" {
+		monitor.Type = MonitorTypeMonitor
+	}
+	monitor.Children = nil
+	var created Monitor
+	r := request{
+		method: http.MethodPost,
+		path:   "/v1/monitors",
+		query:  url.Values{"parentId": {parentID}},
+		body:   monitorBody(monitor),
+		out:    &created,
+	}
+	if _, err := c.do(ctx, r); err != nil {
+		return nil, fmt.Errorf("error creating monitor: %w", err)
+	}
+	return &created, nil
+}
+
+// CreateMonitorFolder creates a folder in the folder with the parent ID and
+// returns it.
+func (c *Client) CreateMonitorFolder(ctx context.Context, parentID, name, description string) (*Monitor, error) {
+	folder, err := c.CreateMonitor(ctx, parentID, Monitor{Name: name, Description: description, Type: MonitorTypeFolder})
+	if err != nil {
+		return nil, fmt.Errorf("error creating monitor folder: %w", err)
+	}
+	return folder, nil
+}
+
+// UpdateMonitor replaces the monitor or folder with the same ID and returns
+// the updated item. The Version must match the current version of the item.
+func (c *Client) UpdateMonitor(ctx context.Context, monitor Monitor) (*Monitor, error) {
+	monitor.Children = nil
+	var updated Monitor
+	if err := c.put(ctx, pathf("/v1/monitors/%s", monitor.ID), monitorBody(monitor), &updated); err != nil {
+		return nil, fmt.Errorf("error updating monitor: %w", err)
+	}
+	return &updated, nil
+}
+
+// DeleteMonitor deletes the monitor or folder with the ID. Deleting a folder
+// deletes everything in it.
+func (c *Client) DeleteMonitor(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/monitors/%s", id)); err != nil {
+		return fmt.Errorf("error deleting monitor: %w", err)
+	}
+	return nil
+}
+
+// ExportMonitor returns the JSON definition of the monitor or folder with the
+// ID, which can be kept in version control and passed to ImportMonitor.
+func (c *Client) ExportMonitor(ctx context.Context, id string) (json.RawMessage, error) {
+	var def json.RawMessage
+	if err := c.get(ctx, pathf("/v1/monitors/%s/export", id), nil, &def); err != nil {
+		return nil, fmt.Errorf("error exporting monitor: %w", err)
+	}
+	return def, nil
+}
+
+// ImportMonitor creates the monitor or folder from a definition returned by
+// ExportMonitor in the folder with the parent ID, and returns it.
+func (c *Client) ImportMonitor(ctx context.Context, parentID string, def json.RawMessage) (*Monitor, error) {
+	var imported Monitor
+	if err := c.post(ctx, pathf("/v1/monitors/%s/import", parentID), def, &imported); err != nil {
+		return nil, fmt.Errorf("error importing monitor: %w", err)
+	}
+	return &imported, nil
+}
+
+// ListMonitorsByStatus returns the monitors that have any of the statuses,
+// such as MonitorStatusCritical, following pagination.
+func (c *Client) ListMonitorsByStatus(ctx context.Context, statuses ...string) ([]MonitorSearchResult, error) {
+	return Collect(c.IterateMonitorsByStatus(ctx, statuses...))
+}
+
+// IterateMonitorsByStatus returns an Iterator over the monitors returned by
+// ListMonitorsByStatus, requesting each page as it is read.
+func (c *Client) IterateMonitorsByStatus(ctx context.Context, statuses ...string) *Iterator[MonitorSearchResult] {
+	terms := make([]string, len(statuses))
+	for i, s := range statuses {
+		terms[i] = "monitorStatus:" + s
+	}
+	return iterateOffsets(ctx, "listing monitors", func(ctx context.Context, offset, limit int) ([]MonitorSearchResult, error) {
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("at least one status is required")
+		}
+		query := url.Values{
+			"query":  {strings.Join(terms, " ")},
+			"limit":  {strconv.Itoa(limit)},
+			"offset": {strconv.Itoa(offset)},
+		}
+		var page []MonitorSearchResult
+		if err := c.get(ctx, "/v1/monitors/search", query, &page); err != nil {
+			return nil, err
+		}
+		return page, nil
+	})
+}
+
+// monitorBody returns the monitor without the fields that are only set by
+// the API.
+func monitorBody(m Monitor) any {
+	type body Monitor
+	return withExtra(struct {
+		body
+		CreatedAt  *time.Time `json:"createdAt,omitempty"`
+		ModifiedAt *time.Time `json:"modifiedAt,omitempty"`
+		Status     []string   `json:"status,omitempty"`
+	}{body: body(m)}, m.Extra)
+}
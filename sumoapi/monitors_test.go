@@ -0,0 +1,146 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestMonitors(t *testing.T) {
+	ctx := context.Background()
+	monitor := `{"id": "m1", "name": "Errors", "type": "MonitorsLibraryMonitor", "version": 2, "createdAt": "2024-01-02T03:04:05Z", "modifiedAt": "2024-01-02T03:04:05Z", "status": ["Critical"], "alertName": "{{Name}}"}`
+	testCalls(t, []apiCall{
+		{
+			name: "root folder",
+			call: func(c *Client) error {
+				root, err := c.GetMonitorRootFolder(ctx)
+				if err != nil {
+					return err
+				}
+				if !root.IsFolder() || len(root.Children) != 1 {
+					return expect(root, "a folder with one child")
+				}
+				return expect(root.Children[0].IsFolder(), false)
+			},
+			response: `{"id": "root", "name": "Root", "type": "MonitorsLibraryFolder", "children": [` + monitor + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/monitors/root",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				m, err := c.GetMonitor(ctx, "m1")
+				if err != nil {
+					return err
+				}
+				return expect(string(m.Extra["alertName"]), `"{{Name}}"`)
+			},
+			response: monitor,
+			method:   http.MethodGet,
+			path:     "/v1/monitors/m1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateMonitor(ctx, "f1", Monitor{
+					Name:    "Errors",
+					Queries: []MonitorQuery{{RowID: "A", Query: "error"}},
+					// Children are not sent for a monitor.
+					Children: []Monitor{{Name: "child"}},
+				})
+				return err
+			},
+			response: monitor,
+			method:   http.MethodPost,
+			path:     "/v1/monitors",
+			query:    "parentId=f1",
+			body:     `{"name": "Errors", "type": "MonitorsLibraryMonitor", "queries": [{"rowId": "A", "query": "error"}]}`,
+		},
+		{
+			name: "create folder",
+			call: func(c *Client) error {
+				_, err := c.CreateMonitorFolder(ctx, "root", "Web", "web monitors")
+				return err
+			},
+			response: `{"id": "f1", "name": "Web", "type": "MonitorsLibraryFolder"}`,
+			method:   http.MethodPost,
+			path:     "/v1/monitors",
+			query:    "parentId=root",
+			body:     `{"name": "Web", "description": "web monitors", "type": "MonitorsLibraryFolder"}`,
+		},
+		{
+			name: "update keeps extra",
+			call: func(c *Client) error {
+				var m Monitor
+				if err := json.Unmarshal([]byte(monitor), &m); err != nil {
+					return err
+				}
+				_, err := c.UpdateMonitor(ctx, m)
+				return err
+			},
+			response: monitor,
+			method:   http.MethodPut,
+			path:     "/v1/monitors/m1",
+			// The fields set by the API are not sent back.
+			body: `{"id": "m1", "name": "Errors", "type": "MonitorsLibraryMonitor", "version": 2, "alertName": "{{Name}}"}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteMonitor(ctx, "m1") },
+			method: http.MethodDelete,
+			path:   "/v1/monitors/m1",
+		},
+		{
+			name: "export",
+			call: func(c *Client) error {
+				def, err := c.ExportMonitor(ctx, "m1")
+				if err != nil {
+					return err
+				}
+				return expect(compact(t, string(def)), `{"name":"Errors","type":"MonitorsLibraryMonitorExport"}`)
+			},
+			response: `{"name": "Errors", "type": "MonitorsLibraryMonitorExport"}`,
+			method:   http.MethodGet,
+			path:     "/v1/monitors/m1/export",
+		},
+		{
+			name: "import",
+			call: func(c *Client) error {
+				_, err := c.ImportMonitor(ctx, "f1", json.RawMessage(`{"name": "Errors", "type": "MonitorsLibraryMonitorExport"}`))
+				return err
+			},
+			response: monitor,
+			method:   http.MethodPost,
+			path:     "/v1/monitors/f1/import",
+			body:     `{"name": "Errors", "type": "MonitorsLibraryMonitorExport"}`,
+		},
+		{
+			name: "list by status",
+			call: func(c *Client) error {
+				results, err := c.ListMonitorsByStatus(ctx, MonitorStatusCritical, MonitorStatusWarning)
+				if err != nil {
+					return err
+				}
+				if len(results) != 1 {
+					return expect(len(results), 1)
+				}
+				return expect(results[0].Path, "/Monitor/Errors")
+			},
+			response: `[{"item": ` + monitor + `, "path": "/Monitor/Errors"}]`,
+			method:   http.MethodGet,
+			path:     "/v1/monitors/search",
+			query:    "limit=100&offset=0&query=monitorStatus%3ACritical+monitorStatus%3AWarning",
+		},
+	})
+}
+
+func TestListMonitorsByStatusRequiresStatus(t *testing.T) {
+	api := newTestAPI(t)
+	if _, err := api.client().ListMonitorsByStatus(context.Background()); err == nil {
+		t.Error("ListMonitorsByStatus without a status succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
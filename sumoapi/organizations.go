@@ -0,0 +1,176 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Statuses of a child organization.
+const (
+	OrganizationActive      = "Active"
+	OrganizationInactive    = "Inactive"
+	OrganizationProvisioned = "Provisioned"
+)
+
+// Organization is a child organization of the account, which must be a
+// parent organization to manage them.
+type Organization struct {
+	OrgID   string `json:"orgId,omitempty"`
+	OrgName string `json:"orgName"`
+	// Email, FirstName and LastName are those of the user that owns the
+	// organization, who is invited when it is created.
+	Email     string `json:"email"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	// Deployment is where the organization is created, such as "us2". It
+	// defaults to the deployment of the parent organization.
+	Deployment Deployment `json:"deployment,omitempty"`
+	// Status is one of the Organization constants.
+	Status       string `json:"status,omitempty"`
+	SubdomainURL string `json:"subdomainUrl,omitempty"`
+	PlanType     string `json:"planType,omitempty"`
+	// TrialPlanPeriod is the number of days of the trial plan, for trial
+	// organizations.
+	TrialPlanPeriod int `json:"trialPlanPeriod,omitempty"`
+	// Baselines are the daily usage the credits of the organization are
+	// allocated for.
+	Baselines OrganizationBaselines `json:"baselines"`
+	CreatedAt time.Time             `json:"createdAt"`
+
+	// Extra holds the properties of the organization that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the organization, keeping the properties that have
+// no field in Extra.
+func (o *Organization) UnmarshalJSON(b []byte) error {
+	type plain Organization
+	return unmarshalExtra(b, (*plain)(o), &o.Extra)
+}
+
+// MarshalJSON encodes the organization along with the properties in Extra.
+func (o Organization) MarshalJSON() ([]byte, error) {
+	type plain Organization
+	return marshalExtra(plain(o), o.Extra)
+}
+
+// OrganizationBaselines is the expected usage of an organization, in GB per
+// day for ingest and GB for storage, from which its credits are allocated.
+type OrganizationBaselines struct {
+	ContinuousIngest  int64 `json:"continuousIngest,omitempty"`
+	ContinuousStorage int64 `json:"continuousStorage,omitempty"`
+	FrequentIngest    int64 `json:"frequentIngest,omitempty"`
+	FrequentStorage   int64 `json:"frequentStorage,omitempty"`
+	InfrequentIngest  int64 `json:"infrequentIngest,omitempty"`
+	InfrequentStorage int64 `json:"infrequentStorage,omitempty"`
+	TracingIngest     int64 `json:"tracingIngest,omitempty"`
+	CSEIngest         int64 `json:"cseIngest,omitempty"`
+	CSEStorage        int64 `json:"cseStorage,omitempty"`
+	// Metrics is in thousands of data points per minute.
+	Metrics int64 `json:"metrics,omitempty"`
+
+	// Extra holds the properties of the baselines that have no field,
+	// which are kept when they are encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the baselines, keeping the properties that have no
+// field in Extra.
+func (b *OrganizationBaselines) UnmarshalJSON(data []byte) error {
+	type plain OrganizationBaselines
+	return unmarshalExtra(data, (*plain)(b), &b.Extra)
+}
+
+// MarshalJSON encodes the baselines along with the properties in Extra.
+func (b OrganizationBaselines) MarshalJSON() ([]byte, error) {
+	type plain OrganizationBaselines
+	return marshalExtra(plain(b), b.Extra)
+}
+
+// OrganizationCredits is the allocation of credits of an organization.
+type OrganizationCredits struct {
+	OrgID            string    `json:"orgId"`
+	AllocatedCredits float64   `json:"allocatedCredits"`
+	UsedCredits      float64   `json:"usedCredits"`
+	RemainingCredits float64   `json:"remainingCredits"`
+	StartDate        time.Time `json:"startDate"`
+	EndDate          time.Time `json:"endDate"`
+}
+
+// ListOrganizations returns every child organization, following pagination.
+func (c *Client) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	return Collect(c.IterateOrganizations(ctx))
+}
+
+// IterateOrganizations returns an Iterator over the organizations returned
+// by ListOrganizations, requesting each page as it is read.
+func (c *Client) IterateOrganizations(ctx context.Context) *Iterator[Organization] {
+	return iterateTokens[Organization](ctx, c, "listing organizations", "/v1/organizations", nil)
+}
+
+// GetOrganization returns the child organization with the ID.
+func (c *Client) GetOrganization(ctx context.Context, orgID string) (*Organization, error) {
+	var org Organization
+	if err := c.get(ctx, pathf("/v1/organizations/%s", orgID), nil, &org); err != nil {
+		return nil, fmt.Errorf("error getting organization: %w", err)
+	}
+	return &org, nil
+}
+
+// CreateOrganization creates a child organization and returns it. Its owner
+// is sent an invitation to activate it.
+func (c *Client) CreateOrganization(ctx context.Context, org Organization) (*Organization, error) {
+	if org.OrgName == "" || org.Email == "" {
+		return nil, fmt.Errorf("error creating organization: name and email are required")
+	}
+	body := struct {
+		OrgName         string                `json:"orgName"`
+		Email           string                `json:"email"`
+		FirstName       string                `json:"firstName"`
+		LastName        string                `json:"lastName"`
+		Deployment      Deployment            `json:"deployment,omitempty"`
+		TrialPlanPeriod int                   `json:"trialPlanPeriod,omitempty"`
+		Baselines       OrganizationBaselines `json:"baselines"`
+	}{org.OrgName, org.Email, org.FirstName, org.LastName, org.Deployment, org.TrialPlanPeriod, org.Baselines}
+	var created Organization
+	if err := c.post(ctx, "/v1/organizations", body, &created); err != nil {
+		return nil, fmt.Errorf("error creating organization: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateOrganization changes the baselines of the child organization with the
+// ID, which reallocates its credits, and returns the updated organization.
+func (c *Client) UpdateOrganization(ctx context.Context, orgID string, baselines OrganizationBaselines) (*Organization, error) {
+	body := struct {
+		Baselines OrganizationBaselines `json:"baselines"`
+	}{baselines}
+	var updated Organization
+	if err := c.put(ctx, pathf("/v1/organizations/%s", orgID), body, &updated); err != nil {
+		return nil, fmt.Errorf("error updating organization: %w", err)
+	}
+	return &updated, nil
+}
+
+// OrganizationCredits returns the credits allocated to the child
+// organization with the ID and how many it used.
+func (c *Client) OrganizationCredits(ctx context.Context, orgID string) (*OrganizationCredits, error) {
+	var credits OrganizationCredits
+	if err := c.get(ctx, pathf("/v1/organizations/%s/credits", orgID), nil, &credits); err != nil {
+		return nil, fmt.Errorf("error getting organization credits: %w", err)
+	}
+	return &credits, nil
+}
+
+// DeactivateOrganization deactivates the child organization with the ID,
+// returning its unused credits to the parent organization. Its users can no
+// longer log in.
+func (c *Client) DeactivateOrganization(ctx context.Context, orgID string) error {
+	if err := c.delete(ctx, pathf("/v1/organizations/%s", orgID)); err != nil {
+		return fmt.Errorf("error deactivating organization: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestOrganizations(t *testing.T) {
+	ctx := context.Background()
+	org := `{"orgId": "000000000000000A", "orgName": "Acme", "email": "ops@acme.example", "firstName": "Ada", "lastName": "Lovelace", "deployment": "us2", "status": "Active", "baselines": {"continuousIngest": 10, "cseIngest": 2, "logsIngest": 5}, "isParent": false}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				orgs, err := c.ListOrganizations(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(orgs[0].Deployment, DeploymentUS2)
+			},
+			response: `{"data": [` + org + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/organizations",
+			query:    "limit=100",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				o, err := c.GetOrganization(ctx, "000000000000000A")
+				if err != nil {
+					return err
+				}
+				if err := expect(string(o.Extra["isParent"]), "false"); err != nil {
+					return err
+				}
+				return expect(string(o.Baselines.Extra["logsIngest"]), "5")
+			},
+			response: org,
+			method:   http.MethodGet,
+			path:     "/v1/organizations/000000000000000A",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateOrganization(ctx, Organization{
+					OrgName:    "Acme",
+					Email:      "ops@acme.example",
+					FirstName:  "Ada",
+					LastName:   "Lovelace",
+					Deployment: DeploymentUS2,
+					Status:     OrganizationActive,
+					Baselines:  OrganizationBaselines{ContinuousIngest: 10},
+				})
+				return err
+			},
+			response: org,
+			method:   http.MethodPost,
+			path:     "/v1/organizations",
+			body:     `{"orgName": "Acme", "email": "ops@acme.example", "firstName": "Ada", "lastName": "Lovelace", "deployment": "us2", "baselines": {"continuousIngest": 10}}`,
+		},
+		{
+			name: "update baselines",
+			call: func(c *Client) error {
+				_, err := c.UpdateOrganization(ctx, "000000000000000A", OrganizationBaselines{FrequentIngest: 3, Metrics: 1000})
+				return err
+			},
+			response: org,
+			method:   http.MethodPut,
+			path:     "/v1/organizations/000000000000000A",
+			body:     `{"baselines": {"frequentIngest": 3, "metrics": 1000}}`,
+		},
+		{
+			name: "credits",
+			call: func(c *Client) error {
+				credits, err := c.OrganizationCredits(ctx, "000000000000000A")
+				if err != nil {
+					return err
+				}
+				return expect(credits.RemainingCredits, 75.5)
+			},
+			response: `{"orgId": "000000000000000A", "allocatedCredits": 100, "usedCredits": 24.5, "remainingCredits": 75.5}`,
+			method:   http.MethodGet,
+			path:     "/v1/organizations/000000000000000A/credits",
+		},
+		{
+			name:   "deactivate",
+			call:   func(c *Client) error { return c.DeactivateOrganization(ctx, "000000000000000A") },
+			method: http.MethodDelete,
+			path:   "/v1/organizations/000000000000000A",
+		},
+	})
+}
+
+func TestCreateOrganizationValidation(t *testing.T) {
+	api := newTestAPI(t)
+	if _, err := api.client().CreateOrganization(context.Background(), Organization{OrgName: "Acme"}); err == nil {
+		t.Error("CreateOrganization without an email succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
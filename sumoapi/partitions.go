@@ -0,0 +1,141 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Analytics tiers of a partition.
+const (
+	AnalyticsTierContinuous = "continuous"
+	AnalyticsTierFrequent   = "frequent"
+	AnalyticsTierInfrequent = "infrequent"
+)
+
+// Partition is an index that logs matching its routing expression are
+// stored in, with its own retention.
+type Partition struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	// RoutingExpression is the query selecting the logs stored in the
+	// partition, such as _sourceCategory=prod/*.
+	RoutingExpression string `json:"routingExpression"`
+	// AnalyticsTier is one of the AnalyticsTier constants. It defaults to
+	// AnalyticsTierContinuous and cannot be changed after creation.
+	AnalyticsTier string `json:"analyticsTier,omitempty"`
+	// RetentionPeriod is the number of days logs are kept. The retention of
+	// the account is used when it is zero.
+	RetentionPeriod int `json:"retentionPeriod,omitempty"`
+	// IsCompliant partitions cannot have their retention reduced or be
+	// deleted.
+	IsCompliant      bool   `json:"isCompliant"`
+	DataForwardingID string `json:"dataForwardingId,omitempty"`
+	// IsActive is false once the partition has been decommissioned.
+	IsActive   bool      `json:"isActive,omitempty"`
+	TotalBytes int64     `json:"totalBytes,omitempty"`
+	IndexType  string    `json:"indexType,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	CreatedBy  string    `json:"createdBy,omitempty"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+	ModifiedBy string    `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the partition that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the partition, keeping the properties that have no
+// field in Extra.
+func (p *Partition) UnmarshalJSON(b []byte) error {
+	type plain Partition
+	return unmarshalExtra(b, (*plain)(p), &p.Extra)
+}
+
+// MarshalJSON encodes the partition along with the properties in Extra.
+func (p Partition) MarshalJSON() ([]byte, error) {
+	type plain Partition
+	return marshalExtra(plain(p), p.Extra)
+}
+
+// PartitionUpdate holds the settings of a partition that can be changed
+// after it is created.
+type PartitionUpdate struct {
+	RoutingExpression string `json:"routingExpression,omitempty"`
+	RetentionPeriod   int    `json:"retentionPeriod,omitempty"`
+	// ReduceRetentionPeriodImmediately deletes data older than a reduced
+	// RetentionPeriod straight away, instead of after the current retention
+	// period has passed.
+	ReduceRetentionPeriodImmediately bool   `json:"reduceRetentionPeriodImmediately"`
+	IsCompliant                      bool   `json:"isCompliant"`
+	DataForwardingID                 string `json:"dataForwardingId,omitempty"`
+}
+
+// ListPartitions returns every partition, following pagination. Partitions
+// that have been decommissioned are only included when inactive is true.
+func (c *Client) ListPartitions(ctx context.Context, inactive bool) ([]Partition, error) {
+	return Collect(c.IteratePartitions(ctx, inactive))
+}
+
+// IteratePartitions returns an Iterator over the partitions returned by
+// ListPartitions, requesting each page as it is read.
+func (c *Client) IteratePartitions(ctx context.Context, inactive bool) *Iterator[Partition] {
+	var query url.Values
+	if inactive {
+		query = url.Values{"viewInactive": {"true"}}
+	}
+	return iterateTokens[Partition](ctx, c, "listing partitions", "/v1/partitions", query)
+}
+
+// GetPartition returns the partition with the ID.
+func (c *Client) GetPartition(ctx context.Context, id string) (*Partition, error) {
+	var p Partition
+	if err := c.get(ctx, pathf("/v1/partitions/%s", id), nil, &p); err != nil {
+		return nil, fmt.Errorf("error getting partition: %w", err)
+	}
+	return &p, nil
+}
+
+// CreatePartition creates a partition and returns it.
+func (c *Client) CreatePartition(ctx context.Context, partition Partition) (*Partition, error) {
+	if partition.Name == "" || partition.RoutingExpression == "" {
+		return nil, fmt.Errorf("error creating partition: name and routing expression are required")
+	}
+	body := struct {
+		Name              string `json:"name"`
+		RoutingExpression string `json:"routingExpression"`
+		AnalyticsTier     string `json:"analyticsTier,omitempty"`
+		RetentionPeriod   int    `json:"retentionPeriod,omitempty"`
+		IsCompliant       bool   `json:"isCompliant"`
+		DataForwardingID  string `json:"dataForwardingId,omitempty"`
+	}{
+		partition.Name, partition.RoutingExpression, partition.AnalyticsTier,
+		partition.RetentionPeriod, partition.IsCompliant, partition.DataForwardingID,
+	}
+	var created Partition
+	if err := c.post(ctx, "/v1/partitions", body, &created); err != nil {
+		return nil, fmt.Errorf("error creating partition: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdatePartition changes the settings of the partition with the ID and
+// returns the updated partition.
+func (c *Client) UpdatePartition(ctx context.Context, id string, update PartitionUpdate) (*Partition, error) {
+	var updated Partition
+	if err := c.put(ctx, pathf("/v1/partitions/%s", id), update, &updated); err != nil {
+		return nil, fmt.Errorf("error updating partition: %w", err)
+	}
+	return &updated, nil
+}
+
+// DecommissionPartition decommissions the partition with the ID, so it no
+// longer receives logs. Its data is kept until its retention period passes.
+func (c *Client) DecommissionPartition(ctx context.Context, id string) error {
+	if err := c.post(ctx, pathf("/v1/partitions/%s/decommission", id), nil, nil); err != nil {
+		return fmt.Errorf("error decommissioning partition: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestPartitions(t *testing.T) {
+	ctx := context.Background()
+	response := `{"id": "p1", "name": "web", "routingExpression": "_sourceCategory=web", "retentionPeriod": 30, "isActive": true, "dataTier": "Continuous"}`
+	testCalls(t, []apiCall{
+		{
+			name: "list inactive",
+			call: func(c *Client) error {
+				partitions, err := c.ListPartitions(ctx, true)
+				if err != nil {
+					return err
+				}
+				if len(partitions) != 1 {
+					return expect(len(partitions), 1)
+				}
+				return expect(string(partitions[0].Extra["dataTier"]), `"Continuous"`)
+			},
+			response: `{"data": [` + response + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/partitions",
+			query:    "limit=100&viewInactive=true",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				p, err := c.GetPartition(ctx, "p1")
+				if err != nil {
+					return err
+				}
+				return expect(p.RetentionPeriod, 30)
+			},
+			response: response,
+			method:   http.MethodGet,
+			path:     "/v1/partitions/p1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreatePartition(ctx, Partition{Name: "web", RoutingExpression: "_sourceCategory=web", RetentionPeriod: 30, IsActive: true})
+				return err
+			},
+			response: response,
+			method:   http.MethodPost,
+			path:     "/v1/partitions",
+			body:     `{"name": "web", "routingExpression": "_sourceCategory=web", "retentionPeriod": 30, "isCompliant": false}`,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdatePartition(ctx, "p1", PartitionUpdate{RetentionPeriod: 7, ReduceRetentionPeriodImmediately: true})
+				return err
+			},
+			response: response,
+			method:   http.MethodPut,
+			path:     "/v1/partitions/p1",
+			body:     `{"retentionPeriod": 7, "reduceRetentionPeriodImmediately": true, "isCompliant": false}`,
+		},
+		{
+			name:   "decommission",
+			call:   func(c *Client) error { return c.DecommissionPartition(ctx, "p1") },
+			method: http.MethodPost,
+			path:   "/v1/partitions/p1/decommission",
+		},
+	})
+}
+
+func TestCreatePartitionRequiresRouting(t *testing.T) {
+	api := newTestAPI(t)
+	if _, err := api.client().CreatePartition(context.Background(), Partition{Name: "web"}); err == nil {
+		t.Error("CreatePartition without a routing expression succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
@@ -0,0 +1,75 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// PasswordPolicy is the policy the passwords of users of the account must
+// follow, and how repeated failed logins lock them out.
+type PasswordPolicy struct {
+	MinLength               int  `json:"minLength"`
+	MaxLength               int  `json:"maxLength"`
+	MustContainLowercase    bool `json:"mustContainLowercase"`
+	MustContainUppercase    bool `json:"mustContainUppercase"`
+	MustContainDigits       bool `json:"mustContainDigits"`
+	MustContainSpecialChars bool `json:"mustContainSpecialChars"`
+	// MaxPasswordAgeInDays is how long a password can be used before it
+	// must be changed. Passwords never expire when it is -1.
+	MaxPasswordAgeInDays int `json:"maxPasswordAgeInDays"`
+	// MinUniquePasswords is the number of previous passwords that cannot be
+	// reused.
+	MinUniquePasswords int `json:"minUniquePasswords"`
+	// AccountLockoutThreshold is the number of failed logins within
+	// FailedLoginResetDurationInMins after which the user is locked out for
+	// AccountLockoutDurationInMins.
+	AccountLockoutThreshold        int `json:"accountLockoutThreshold"`
+	FailedLoginResetDurationInMins int `json:"failedLoginResetDurationInMins"`
+	AccountLockoutDurationInMins   int `json:"accountLockoutDurationInMins"`
+	// RequireMFA requires users to set up multi-factor authentication, and
+	// RememberMFA lets them skip it on browsers they used before.
+	RequireMFA  bool `json:"requireMfa"`
+	RememberMFA bool `json:"rememberMfa"`
+
+	// Extra holds the properties of the password policy that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the password policy, keeping the properties that
+// have no field in Extra.
+func (p *PasswordPolicy) UnmarshalJSON(b []byte) error {
+	type plain PasswordPolicy
+	return unmarshalExtra(b, (*plain)(p), &p.Extra)
+}
+
+// MarshalJSON encodes the password policy along with the properties in
+// Extra.
+func (p PasswordPolicy) MarshalJSON() ([]byte, error) {
+	type plain PasswordPolicy
+	return marshalExtra(plain(p), p.Extra)
+}
+
+// PasswordPolicy returns the password policy of the account.
+func (c *Client) PasswordPolicy(ctx context.Context) (*PasswordPolicy, error) {
+	var policy PasswordPolicy
+	if err := c.get(ctx, "/v1/passwordPolicy", nil, &policy); err != nil {
+		return nil, fmt.Errorf("error getting password policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// UpdatePasswordPolicy replaces the password policy of the account and
+// returns the updated policy. Existing passwords are checked against it the
+// next time they are changed.
+func (c *Client) UpdatePasswordPolicy(ctx context.Context, policy PasswordPolicy) (*PasswordPolicy, error) {
+	if policy.MinLength <= 0 || (policy.MaxLength > 0 && policy.MaxLength < policy.MinLength) {
+		return nil, fmt.Errorf("error updating password policy: invalid length range %d to %d", policy.MinLength, policy.MaxLength)
+	}
+	var updated PasswordPolicy
+	if err := c.put(ctx, "/v1/passwordPolicy", policy, &updated); err != nil {
+		return nil, fmt.Errorf("error updating password policy: %w", err)
+	}
+	return &updated, nil
+}
@@ -0,0 +1,65 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+	policy := `{
+		"minLength": 12, "maxLength": 128,
+		"mustContainLowercase": true, "mustContainUppercase": true, "mustContainDigits": true, "mustContainSpecialChars": false,
+		"maxPasswordAgeInDays": 365, "minUniquePasswords": 10,
+		"accountLockoutThreshold": 6, "failedLoginResetDurationInMins": 10, "accountLockoutDurationInMins": 30,
+		"requireMfa": true, "rememberMfa": false,
+		"passwordExpirationWarning": 7
+	}`
+	testCalls(t, []apiCall{
+		{
+			name: "get",
+			call: func(c *Client) error {
+				p, err := c.PasswordPolicy(ctx)
+				if err != nil {
+					return err
+				}
+				if err := expect(p.MinLength, 12); err != nil {
+					return err
+				}
+				return expect(string(p.Extra["passwordExpirationWarning"]), "7")
+			},
+			response: policy,
+			method:   http.MethodGet,
+			path:     "/v1/passwordPolicy",
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				var p PasswordPolicy
+				if err := json.Unmarshal([]byte(policy), &p); err != nil {
+					return err
+				}
+				_, err := c.UpdatePasswordPolicy(ctx, p)
+				return err
+			},
+			response: policy,
+			method:   http.MethodPut,
+			path:     "/v1/passwordPolicy",
+			body:     policy,
+		},
+	})
+}
+
+func TestUpdatePasswordPolicyValidation(t *testing.T) {
+	api := newTestAPI(t)
+	for _, p := range []PasswordPolicy{{}, {MinLength: 12, MaxLength: 8}} {
+		if _, err := api.client().UpdatePasswordPolicy(context.Background(), p); err == nil {
+			t.Errorf("UpdatePasswordPolicy(%+v) succeeded", p)
+		}
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
@@ -0,0 +1,57 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestContentPermissions(t *testing.T) {
+	ctx := context.Background()
+	assignments := `{
+		"contentPermissionAssignments": [
+			{"permissionName": "View", "sourceType": "user", "sourceId": "u1", "contentId": "d1"},
+			{"permissionName": "Edit", "sourceType": "role", "sourceId": "r1", "contentId": "d2"}
+		],
+		"notifyRecipients": false,
+		"notificationMessage": ""
+	}`
+	perms := []ContentPermission{
+		UserPermission("", "u1", PermissionView),
+		RolePermission("d2", "r1", PermissionEdit),
+	}
+	testCalls(t, []apiCall{
+		{
+			name: "get",
+			call: func(c *Client) error {
+				p, err := c.ContentPermissions(ctx, "d1", true)
+				if err != nil {
+					return err
+				}
+				if len(p.Explicit) != 1 {
+					return expect(len(p.Explicit), 1)
+				}
+				return expect(string(p.Explicit[0].Extra["inherited"]), "false")
+			},
+			response: `{"explicitPermissions": [{"permissionName": "View", "sourceType": "user", "sourceId": "u1", "contentId": "d1", "inherited": false}], "implicitPermissions": []}`,
+			method:   http.MethodGet,
+			path:     "/v2/content/d1/permissions",
+			query:    "explicitOnly=true",
+		},
+		{
+			name: "add",
+			call: func(c *Client) error { return c.AddContentPermissions(ctx, "d1", perms...) },
+			// Permissions without a content ID are for the content.
+			method: http.MethodPut,
+			path:   "/v2/content/d1/permissions/add",
+			body:   assignments,
+		},
+		{
+			name:   "remove",
+			call:   func(c *Client) error { return c.RemoveContentPermissions(ctx, "d1", perms...) },
+			method: http.MethodPut,
+			path:   "/v2/content/d1/permissions/remove",
+			body:   assignments,
+		},
+	})
+}
@@ -0,0 +1,125 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/byitkc/gosumo"
+)
+
+// RateLimit is the rate limit state the management API reported in the
+// headers of a response.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current window, and
+	// Remaining the number left. They are -1 when not reported.
+	Limit     int
+	Remaining int
+	// Reset is when the window ends and Remaining is replenished. It is the
+	// zero time when not reported.
+	Reset time.Time
+	// RetryAfter is the delay a rate limited response asked for with its
+	// Retry-After header.
+	RetryAfter time.Duration
+}
+
+// Exhausted reports whether no requests remain until Reset.
+func (l RateLimit) Exhausted() bool {
+	return l.Remaining == 0
+}
+
+// wait returns how long to wait before the next request: the delay asked
+// for by Retry-After, or the time until Reset if no requests remain.
+func (l RateLimit) wait(now time.Time) time.Duration {
+	if l.RetryAfter > 0 {
+		return l.RetryAfter
+	}
+	if l.Exhausted() && l.Reset.After(now) {
+		return l.Reset.Sub(now)
+	}
+	return 0
+}
+
+// parseRateLimit reads the rate limit headers of the response, either the
+// X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset headers or
+// their RateLimit- equivalents, along with Retry-After. The reset is either a
+// number of seconds from now or, for large values, a Unix time in seconds. It
+// returns false if the response has none of them.
+func parseRateLimit(h http.Header, now time.Time) (RateLimit, bool) {
+	l := RateLimit{Limit: -1, Remaining: -1}
+	found := false
+	header := func(name string) string {
+		if v := h.Get("X-RateLimit-" + name); v != "" {
+			return v
+		}
+		return h.Get("RateLimit-" + name)
+	}
+	if n, err := strconv.Atoi(header("Limit")); err == nil && n >= 0 {
+		l.Limit, found = n, true
+	}
+	if n, err := strconv.Atoi(header("Remaining")); err == nil && n >= 0 {
+		l.Remaining, found = n, true
+	}
+	if n, err := strconv.ParseInt(header("Reset"), 10, 64); err == nil && n >= 0 {
+		// A delay would not exceed a day, while Unix times are far larger.
+		if n > 86400 {
+			l.Reset = time.Unix(n, 0)
+		} else {
+			l.Reset = now.Add(time.Duration(n) * time.Second)
+		}
+		found = true
+	}
+	if d, ok := gosumo.ParseRetryAfter(h.Get("Retry-After")); ok {
+		l.RetryAfter, found = d, true
+	}
+	return l, found
+}
+
+// RateLimit returns the rate limit state reported by the most recent
+// response that had rate limit headers. It returns false if no response has
+// had them yet.
+func (c *Client) RateLimit() (RateLimit, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.rateLimit == nil {
+		return RateLimit{}, false
+	}
+	return *c.rateLimit, true
+}
+
+// observeRateLimit records the rate limit headers of the response, if it has
+// any.
+func (c *Client) observeRateLimit(resp *http.Response) {
+	l, ok := parseRateLimit(resp.Header, time.Now())
+	if !ok {
+		return
+	}
+	// Retry-After only applies to the response that asked for it.
+	l.RetryAfter = 0
+	c.mu.Lock()
+	c.rateLimit = &l
+	c.mu.Unlock()
+}
+
+// throttle waits until the rate limit window resets when the most recent
+// response reported that no requests remain, so bulk operations slow down
+// instead of being rejected. The wait is capped by the MaxBackoff of the
+// retry policy, and skipped if the policy disables retries.
+func (c *Client) throttle(ctx context.Context) error {
+	if c.retry.MaxAttempts <= 1 {
+		return nil
+	}
+	l, ok := c.RateLimit()
+	if !ok {
+		return nil
+	}
+	d := l.wait(time.Now())
+	if c.retry.MaxBackoff > 0 && d > c.retry.MaxBackoff {
+		d = c.retry.MaxBackoff
+	}
+	if d <= 0 {
+		return nil
+	}
+	return sleep(ctx, d)
+}
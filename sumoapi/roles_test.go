@@ -0,0 +1,101 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestRoles(t *testing.T) {
+	ctx := context.Background()
+	role := `{"id": "r1", "name": "ops", "capabilities": ["viewCollectors", "manageCollectors"], "users": ["u1"], "systemDefined": false, "createdAt": "2024-01-02T03:04:05Z", "modifiedAt": "2024-01-02T03:04:05Z", "logAnalyticsFilter": "*"}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				roles, err := c.ListRoles(ctx)
+				if err != nil {
+					return err
+				}
+				if len(roles) != 1 {
+					return expect(len(roles), 1)
+				}
+				if err := expect(roles[0].HasCapability(CapabilityManageCollectors), true); err != nil {
+					return err
+				}
+				return expect(string(roles[0].Extra["logAnalyticsFilter"]), `"*"`)
+			},
+			response: `{"data": [` + role + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/roles",
+			query:    "limit=100",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				r, err := c.GetRole(ctx, "r1")
+				if err != nil {
+					return err
+				}
+				return expect(r.HasCapability(CapabilityManageSaml), false)
+			},
+			response: role,
+			method:   http.MethodGet,
+			path:     "/v1/roles/r1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateRole(ctx, Role{Name: "ops", FilterPredicate: "_sourceCategory=ops", Capabilities: []Capability{CapabilityViewCollectors}, AutofillDependencies: true})
+				return err
+			},
+			response: role,
+			method:   http.MethodPost,
+			path:     "/v1/roles",
+			body:     `{"name": "ops", "filterPredicate": "_sourceCategory=ops", "capabilities": ["viewCollectors"], "autofillDependencies": true}`,
+		},
+		{
+			name: "update without capabilities",
+			call: func(c *Client) error {
+				_, err := c.UpdateRole(ctx, Role{
+					ID:            "r1",
+					Name:          "ops",
+					Users:         []string{"u1"},
+					SystemDefined: true,
+					Extra:         map[string]json.RawMessage{"logAnalyticsFilter": json.RawMessage(`"*"`)},
+				})
+				return err
+			},
+			response: role,
+			method:   http.MethodPut,
+			path:     "/v1/roles/r1",
+			body:     `{"name": "ops", "users": ["u1"], "capabilities": [], "logAnalyticsFilter": "*"}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteRole(ctx, "r1") },
+			method: http.MethodDelete,
+			path:   "/v1/roles/r1",
+		},
+		{
+			name: "assign to user",
+			call: func(c *Client) error {
+				r, err := c.AssignRoleToUser(ctx, "r1", "u1")
+				if err != nil {
+					return err
+				}
+				return expect(r.Users, []string{"u1"})
+			},
+			response: role,
+			method:   http.MethodPut,
+			path:     "/v1/roles/r1/users/u1",
+		},
+		{
+			name:   "remove from user",
+			call:   func(c *Client) error { return c.RemoveRoleFromUser(ctx, "r1", "u1") },
+			method: http.MethodDelete,
+			path:   "/v1/roles/r1/users/u1",
+		},
+	})
+}
@@ -0,0 +1,232 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SAMLConfiguration is the configuration of a SAML identity provider users
+// can sign on with.
+type SAMLConfiguration struct {
+	ID                string `json:"id,omitempty"`
+	ConfigurationName string `json:"configurationName"`
+	// Issuer is the entity ID of the identity provider.
+	Issuer string `json:"issuer"`
+	// X509Cert1 is the certificate the identity provider signs assertions
+	// with. X509Cert2 and X509Cert3 allow certificates to be rotated.
+	X509Cert1 string `json:"x509cert1"`
+	X509Cert2 string `json:"x509cert2,omitempty"`
+	X509Cert3 string `json:"x509cert3,omitempty"`
+
+	// SPInitiatedLoginEnabled lets users start signing on from Sumo Logic,
+	// at SPInitiatedLoginPath, which sends them to AuthnRequestURL.
+	SPInitiatedLoginEnabled bool   `json:"spInitiatedLoginEnabled"`
+	SPInitiatedLoginPath    string `json:"spInitiatedLoginPath,omitempty"`
+	AuthnRequestURL         string `json:"authnRequestUrl,omitempty"`
+
+	// OnDemandProvisioning creates users the first time they sign on.
+	OnDemandProvisioning *SAMLProvisioning `json:"onDemandProvisioningEnabled,omitempty"`
+	// RolesAttribute is the assertion attribute holding the names of the
+	// roles of the user.
+	RolesAttribute string `json:"rolesAttribute,omitempty"`
+	// EmailAttribute is the assertion attribute holding the email of the
+	// user, when it is not the subject.
+	EmailAttribute string `json:"emailAttribute,omitempty"`
+
+	LogoutEnabled                bool   `json:"logoutEnabled"`
+	LogoutURL                    string `json:"logoutUrl,omitempty"`
+	DebugMode                    bool   `json:"debugMode"`
+	SignAuthnRequest             bool   `json:"signAuthnRequest"`
+	DisableRequestedAuthnContext bool   `json:"disableRequestedAuthnContext"`
+	IsRedirectBinding            bool   `json:"isRedirectBinding"`
+
+	// Certificate, AssertionConsumerURL and EntityID describe Sumo Logic as
+	// the service provider, and are only returned by the API.
+	Certificate          string    `json:"certificate,omitempty"`
+	AssertionConsumerURL string    `json:"assertionConsumerUrl,omitempty"`
+	EntityID             string    `json:"entityId,omitempty"`
+	CreatedAt            time.Time `json:"createdAt"`
+	CreatedBy            string    `json:"createdBy,omitempty"`
+	ModifiedAt           time.Time `json:"modifiedAt"`
+	ModifiedBy           string    `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the SAML configuration that have no
+	// field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the SAML configuration, keeping the properties that
+// have no field in Extra.
+func (s *SAMLConfiguration) UnmarshalJSON(b []byte) error {
+	type plain SAMLConfiguration
+	return unmarshalExtra(b, (*plain)(s), &s.Extra)
+}
+
+// MarshalJSON encodes the SAML configuration along with the properties in
+// Extra.
+func (s SAMLConfiguration) MarshalJSON() ([]byte, error) {
+	type plain SAMLConfiguration
+	return marshalExtra(plain(s), s.Extra)
+}
+
+// SAMLProvisioning configures the users created on demand when they first
+// sign on.
+type SAMLProvisioning struct {
+	FirstNameAttribute string `json:"firstNameAttribute,omitempty"`
+	LastNameAttribute  string `json:"lastNameAttribute,omitempty"`
+	// Roles are the names of the roles given to new users.
+	Roles []string `json:"onDemandProvisioningRoles"`
+
+	// Extra holds the properties of the provisioning settings that have
+	// no field, which are kept when they are encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the provisioning settings, keeping the properties
+// that have no field in Extra.
+func (p *SAMLProvisioning) UnmarshalJSON(b []byte) error {
+	type plain SAMLProvisioning
+	return unmarshalExtra(b, (*plain)(p), &p.Extra)
+}
+
+// MarshalJSON encodes the provisioning settings along with the properties in
+// Extra.
+func (p SAMLProvisioning) MarshalJSON() ([]byte, error) {
+	type plain SAMLProvisioning
+	return marshalExtra(plain(p), p.Extra)
+}
+
+// AllowlistedUser is a user that can sign on with a password while SAML
+// lockdown is enabled.
+type AllowlistedUser struct {
+	UserID        string    `json:"userId"`
+	FirstName     string    `json:"firstName"`
+	LastName      string    `json:"lastName"`
+	Email         string    `json:"email"`
+	CanManageSaml bool      `json:"canManageSaml"`
+	IsActive      bool      `json:"isActive"`
+	LastLogin     time.Time `json:"lastLogin"`
+
+	// Extra holds the properties of the allowlisted user that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the allowlisted user, keeping the properties that
+// have no field in Extra.
+func (u *AllowlistedUser) UnmarshalJSON(b []byte) error {
+	type plain AllowlistedUser
+	return unmarshalExtra(b, (*plain)(u), &u.Extra)
+}
+
+// MarshalJSON encodes the allowlisted user along with the properties in
+// Extra.
+func (u AllowlistedUser) MarshalJSON() ([]byte, error) {
+	type plain AllowlistedUser
+	return marshalExtra(plain(u), u.Extra)
+}
+
+// ListSAMLConfigurations returns the SAML identity provider configurations
+// of the account.
+func (c *Client) ListSAMLConfigurations(ctx context.Context) ([]SAMLConfiguration, error) {
+	var configs []SAMLConfiguration
+	if err := c.get(ctx, "/v1/saml/identityProviders", nil, &configs); err != nil {
+		return nil, fmt.Errorf("error listing saml configurations: %w", err)
+	}
+	return configs, nil
+}
+
+// CreateSAMLConfiguration creates a SAML identity provider configuration and
+// returns it.
+func (c *Client) CreateSAMLConfiguration(ctx context.Context, config SAMLConfiguration) (*SAMLConfiguration, error) {
+	if config.ConfigurationName == "" || config.Issuer == "" || config.X509Cert1 == "" {
+		return nil, fmt.Errorf("error creating saml configuration: configuration name, issuer and certificate are required")
+	}
+	var created SAMLConfiguration
+	if err := c.post(ctx, "/v1/saml/identityProviders", samlBody(config), &created); err != nil {
+		return nil, fmt.Errorf("error creating saml configuration: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateSAMLConfiguration replaces the SAML identity provider configuration
+// with the same ID and returns the updated configuration.
+func (c *Client) UpdateSAMLConfiguration(ctx context.Context, config SAMLConfiguration) (*SAMLConfiguration, error) {
+	var updated SAMLConfiguration
+	if err := c.put(ctx, pathf("/v1/saml/identityProviders/%s", config.ID), samlBody(config), &updated); err != nil {
+		return nil, fmt.Errorf("error updating saml configuration: %w", err)
+	}
+	return &updated, nil
+}
+
+// DeleteSAMLConfiguration deletes the SAML identity provider configuration
+// with the ID.
+func (c *Client) DeleteSAMLConfiguration(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/saml/identityProviders/%s", id)); err != nil {
+		return fmt.Errorf("error deleting saml configuration: %w", err)
+	}
+	return nil
+}
+
+// ListAllowlistedUsers returns the users that can sign on with a password
+// while SAML lockdown is enabled.
+func (c *Client) ListAllowlistedUsers(ctx context.Context) ([]AllowlistedUser, error) {
+	var users []AllowlistedUser
+	if err := c.get(ctx, "/v1/saml/allowlistedUsers", nil, &users); err != nil {
+		return nil, fmt.Errorf("error listing allowlisted users: %w", err)
+	}
+	return users, nil
+}
+
+// AllowlistUser lets the user with the ID sign on with a password while SAML
+// lockdown is enabled.
+func (c *Client) AllowlistUser(ctx context.Context, userID string) (*AllowlistedUser, error) {
+	var user AllowlistedUser
+	if err := c.post(ctx, pathf("/v1/saml/allowlistedUsers/%s", userID), nil, &user); err != nil {
+		return nil, fmt.Errorf("error allowlisting user: %w", err)
+	}
+	return &user, nil
+}
+
+// RemoveAllowlistedUser stops the user with the ID from signing on with a
+// password while SAML lockdown is enabled.
+func (c *Client) RemoveAllowlistedUser(ctx context.Context, userID string) error {
+	if err := c.delete(ctx, pathf("/v1/saml/allowlistedUsers/%s", userID)); err != nil {
+		return fmt.Errorf("error removing allowlisted user: %w", err)
+	}
+	return nil
+}
+
+// EnableSAMLLockdown requires every user except those allowlisted to sign on
+// with SAML.
+func (c *Client) EnableSAMLLockdown(ctx context.Context) error {
+	if err := c.post(ctx, "/v1/saml/lockdown/enable", nil, nil); err != nil {
+		return fmt.Errorf("error enabling saml lockdown: %w", err)
+	}
+	return nil
+}
+
+// DisableSAMLLockdown lets every user sign on with a password again.
+func (c *Client) DisableSAMLLockdown(ctx context.Context) error {
+	if err := c.post(ctx, "/v1/saml/lockdown/disable", nil, nil); err != nil {
+		return fmt.Errorf("error disabling saml lockdown: %w", err)
+	}
+	return nil
+}
+
+// samlBody returns the configuration without the fields that are only set
+// by the API.
+func samlBody(s SAMLConfiguration) any {
+	type body SAMLConfiguration
+	return withExtra(struct {
+		body
+		ID                   string     `json:"id,omitempty"`
+		Certificate          string     `json:"certificate,omitempty"`
+		AssertionConsumerURL string     `json:"assertionConsumerUrl,omitempty"`
+		EntityID             string     `json:"entityId,omitempty"`
+		CreatedAt            *time.Time `json:"createdAt,omitempty"`
+		ModifiedAt           *time.Time `json:"modifiedAt,omitempty"`
+	}{body: body(s)}, s.Extra)
+}
@@ -0,0 +1,148 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestSAML(t *testing.T) {
+	ctx := context.Background()
+	config := `{
+		"id": "s1",
+		"configurationName": "okta",
+		"issuer": "http://www.okta.com/x",
+		"x509cert1": "cert",
+		"spInitiatedLoginEnabled": false,
+		"onDemandProvisioningEnabled": {"onDemandProvisioningRoles": ["viewer"], "defaultRole": "viewer"},
+		"logoutEnabled": false,
+		"debugMode": false,
+		"signAuthnRequest": false,
+		"disableRequestedAuthnContext": false,
+		"isRedirectBinding": false,
+		"certificate": "sp-cert",
+		"assertionConsumerUrl": "https://service.sumologic.com/sumo/saml/consume/1",
+		"entityId": "https://service.sumologic.com/sumo/saml/1",
+		"createdAt": "2024-01-02T03:04:05Z",
+		"modifiedAt": "2024-01-02T03:04:05Z",
+		"sessionTimeout": 3600
+	}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				configs, err := c.ListSAMLConfigurations(ctx)
+				if err != nil {
+					return err
+				}
+				if len(configs) != 1 {
+					return expect(len(configs), 1)
+				}
+				return expect(configs[0].OnDemandProvisioning.Roles, []string{"viewer"})
+			},
+			response: `[` + config + `]`,
+			method:   http.MethodGet,
+			path:     "/v1/saml/identityProviders",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateSAMLConfiguration(ctx, SAMLConfiguration{ConfigurationName: "okta", Issuer: "http://www.okta.com/x", X509Cert1: "cert"})
+				return err
+			},
+			response: config,
+			method:   http.MethodPost,
+			path:     "/v1/saml/identityProviders",
+			body: `{
+				"configurationName": "okta",
+				"issuer": "http://www.okta.com/x",
+				"x509cert1": "cert",
+				"spInitiatedLoginEnabled": false,
+				"logoutEnabled": false,
+				"debugMode": false,
+				"signAuthnRequest": false,
+				"disableRequestedAuthnContext": false,
+				"isRedirectBinding": false
+			}`,
+		},
+		{
+			name: "update keeps extra",
+			call: func(c *Client) error {
+				var s SAMLConfiguration
+				if err := json.Unmarshal([]byte(config), &s); err != nil {
+					return err
+				}
+				_, err := c.UpdateSAMLConfiguration(ctx, s)
+				return err
+			},
+			response: config,
+			method:   http.MethodPut,
+			path:     "/v1/saml/identityProviders/s1",
+			// The fields set by Sumo Logic are not sent back.
+			body: `{
+				"configurationName": "okta",
+				"issuer": "http://www.okta.com/x",
+				"x509cert1": "cert",
+				"spInitiatedLoginEnabled": false,
+				"onDemandProvisioningEnabled": {"onDemandProvisioningRoles": ["viewer"], "defaultRole": "viewer"},
+				"logoutEnabled": false,
+				"debugMode": false,
+				"signAuthnRequest": false,
+				"disableRequestedAuthnContext": false,
+				"isRedirectBinding": false,
+				"sessionTimeout": 3600
+			}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteSAMLConfiguration(ctx, "s1") },
+			method: http.MethodDelete,
+			path:   "/v1/saml/identityProviders/s1",
+		},
+		{
+			name: "list allowlisted users",
+			call: func(c *Client) error {
+				users, err := c.ListAllowlistedUsers(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(len(users), 1)
+			},
+			response: `[{"userId": "u1", "email": "ada@example.com", "canManageSaml": true, "isActive": true}]`,
+			method:   http.MethodGet,
+			path:     "/v1/saml/allowlistedUsers",
+		},
+		{
+			name: "allowlist user",
+			call: func(c *Client) error {
+				u, err := c.AllowlistUser(ctx, "u1")
+				if err != nil {
+					return err
+				}
+				return expect(u.UserID, "u1")
+			},
+			response: `{"userId": "u1"}`,
+			method:   http.MethodPost,
+			path:     "/v1/saml/allowlistedUsers/u1",
+		},
+		{
+			name:   "remove allowlisted user",
+			call:   func(c *Client) error { return c.RemoveAllowlistedUser(ctx, "u1") },
+			method: http.MethodDelete,
+			path:   "/v1/saml/allowlistedUsers/u1",
+		},
+		{
+			name:   "enable lockdown",
+			call:   func(c *Client) error { return c.EnableSAMLLockdown(ctx) },
+			method: http.MethodPost,
+			path:   "/v1/saml/lockdown/enable",
+		},
+		{
+			name:   "disable lockdown",
+			call:   func(c *Client) error { return c.DisableSAMLLockdown(ctx) },
+			method: http.MethodPost,
+			path:   "/v1/saml/lockdown/disable",
+		},
+	})
+}
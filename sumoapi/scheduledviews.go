@@ -0,0 +1,144 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ScheduledView is a view that runs a query continuously and indexes its
+// results, so aggregates over large amounts of data stay fast to search.
+type ScheduledView struct {
+	ID string `json:"id,omitempty"`
+	// IndexName is the name the view is searched by, such as
+	// _view=<IndexName>.
+	IndexName string `json:"indexName"`
+	Query     string `json:"query"`
+	// StartTime is how far back the view is backfilled from.
+	StartTime time.Time `json:"startTime"`
+	// RetentionPeriod is the number of days results are kept. The retention
+	// of the account is used when it is zero.
+	RetentionPeriod  int    `json:"retentionPeriod,omitempty"`
+	DataForwardingID string `json:"dataForwardingId,omitempty"`
+	// ParsingMode is ParsingModeAutoParse or ParsingModeManual.
+	ParsingMode       ParsingMode `json:"parsingMode,omitempty"`
+	TotalBytes        int64       `json:"totalBytes,omitempty"`
+	TotalMessageCount int64       `json:"totalMessageCount,omitempty"`
+	CreatedAt         time.Time   `json:"createdAt"`
+	CreatedBy         string      `json:"createdBy,omitempty"`
+	ModifiedAt        time.Time   `json:"modifiedAt"`
+	ModifiedBy        string      `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the scheduled view that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the scheduled view, keeping the properties that have
+// no field in Extra.
+func (v *ScheduledView) UnmarshalJSON(b []byte) error {
+	type plain ScheduledView
+	return unmarshalExtra(b, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON encodes the scheduled view along with the properties in Extra.
+func (v ScheduledView) MarshalJSON() ([]byte, error) {
+	type plain ScheduledView
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// ScheduledViewUpdate holds the settings of a scheduled view that can be
+// changed after it is created.
+type ScheduledViewUpdate struct {
+	RetentionPeriod int `json:"retentionPeriod,omitempty"`
+	// ReduceRetentionPeriodImmediately deletes data older than a reduced
+	// RetentionPeriod straight away, instead of after the current retention
+	// period has passed.
+	ReduceRetentionPeriodImmediately bool   `json:"reduceRetentionPeriodImmediately"`
+	DataForwardingID                 string `json:"dataForwardingId,omitempty"`
+}
+
+// ListScheduledViews returns every scheduled view, following pagination.
+func (c *Client) ListScheduledViews(ctx context.Context) ([]ScheduledView, error) {
+	return Collect(c.IterateScheduledViews(ctx))
+}
+
+// IterateScheduledViews returns an Iterator over the scheduled views
+// returned by ListScheduledViews, requesting each page as it is read.
+func (c *Client) IterateScheduledViews(ctx context.Context) *Iterator[ScheduledView] {
+	return iterateTokens[ScheduledView](ctx, c, "listing scheduled views", "/v1/scheduledViews", nil)
+}
+
+// GetScheduledView returns the scheduled view with the ID.
+func (c *Client) GetScheduledView(ctx context.Context, id string) (*ScheduledView, error) {
+	var v ScheduledView
+	if err := c.get(ctx, pathf("/v1/scheduledViews/%s", id), nil, &v); err != nil {
+		return nil, fmt.Errorf("error getting scheduled view: %w", err)
+	}
+	return &v, nil
+}
+
+// CreateScheduledView creates a scheduled view and returns it. The view
+// starts running straight away.
+func (c *Client) CreateScheduledView(ctx context.Context, view ScheduledView) (*ScheduledView, error) {
+	if view.IndexName == "" || view.Query == "" {
+		return nil, fmt.Errorf("error creating scheduled view: index name and query are required")
+	}
+	if view.StartTime.IsZero() {
+		return nil, fmt.Errorf("error creating scheduled view: start time is required")
+	}
+	body := struct {
+		IndexName        string      `json:"indexName"`
+		Query            string      `json:"query"`
+		StartTime        time.Time   `json:"startTime"`
+		RetentionPeriod  int         `json:"retentionPeriod,omitempty"`
+		DataForwardingID string      `json:"dataForwardingId,omitempty"`
+		ParsingMode      ParsingMode `json:"parsingMode,omitempty"`
+	}{
+		view.IndexName, view.Query, view.StartTime.UTC(),
+		view.RetentionPeriod, view.DataForwardingID, view.ParsingMode,
+	}
+	var created ScheduledView
+	if err := c.post(ctx, "/v1/scheduledViews", body, &created); err != nil {
+		return nil, fmt.Errorf("error creating scheduled view: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateScheduledView changes the settings of the scheduled view with the ID
+// and returns the updated view.
+func (c *Client) UpdateScheduledView(ctx context.Context, id string, update ScheduledViewUpdate) (*ScheduledView, error) {
+	var updated ScheduledView
+	if err := c.put(ctx, pathf("/v1/scheduledViews/%s", id), update, &updated); err != nil {
+		return nil, fmt.Errorf("error updating scheduled view: %w", err)
+	}
+	return &updated, nil
+}
+
+// PauseScheduledView pauses the scheduled view with the ID. Logs received
+// while it is paused are indexed once it is started again.
+func (c *Client) PauseScheduledView(ctx context.Context, id string) error {
+	if err := c.post(ctx, pathf("/v1/scheduledViews/%s/pause", id), nil, nil); err != nil {
+		return fmt.Errorf("error pausing scheduled view: %w", err)
+	}
+	return nil
+}
+
+// StartScheduledView starts the paused scheduled view with the ID.
+func (c *Client) StartScheduledView(ctx context.Context, id string) error {
+	if err := c.post(ctx, pathf("/v1/scheduledViews/%s/start", id), nil, nil); err != nil {
+		return fmt.Errorf("error starting scheduled view: %w", err)
+	}
+	return nil
+}
+
+// DisableScheduledView disables the scheduled view with the ID. Unlike a
+// paused view, a disabled view cannot be started again, but its data is kept
+// until its retention period passes.
+func (c *Client) DisableScheduledView(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/scheduledViews/%s/disable", id)); err != nil {
+		return fmt.Errorf("error disabling scheduled view: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestScheduledViews(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	response := `{"id": "v1", "indexName": "errors", "query": "error | count by _sourceHost", "startTime": "2024-01-02T02:04:05Z", "retentionPeriod": 30, "error": ""}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				views, err := c.ListScheduledViews(ctx)
+				if err != nil {
+					return err
+				}
+				if len(views) != 1 {
+					return expect(len(views), 1)
+				}
+				return expect(string(views[0].Extra["error"]), `""`)
+			},
+			response: `{"data": [` + response + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/scheduledViews",
+			query:    "limit=100",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				v, err := c.GetScheduledView(ctx, "v1")
+				if err != nil {
+					return err
+				}
+				return expect(v.StartTime.Equal(start), true)
+			},
+			response: response,
+			method:   http.MethodGet,
+			path:     "/v1/scheduledViews/v1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateScheduledView(ctx, ScheduledView{IndexName: "errors", Query: "error | count by _sourceHost", StartTime: start, RetentionPeriod: 30})
+				return err
+			},
+			response: response,
+			method:   http.MethodPost,
+			path:     "/v1/scheduledViews",
+			// The start time is sent in UTC.
+			body: `{"indexName": "errors", "query": "error | count by _sourceHost", "startTime": "2024-01-02T02:04:05Z", "retentionPeriod": 30}`,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateScheduledView(ctx, "v1", ScheduledViewUpdate{RetentionPeriod: 7})
+				return err
+			},
+			response: response,
+			method:   http.MethodPut,
+			path:     "/v1/scheduledViews/v1",
+			body:     `{"retentionPeriod": 7, "reduceRetentionPeriodImmediately": false}`,
+		},
+		{
+			name:   "pause",
+			call:   func(c *Client) error { return c.PauseScheduledView(ctx, "v1") },
+			method: http.MethodPost,
+			path:   "/v1/scheduledViews/v1/pause",
+		},
+		{
+			name:   "start",
+			call:   func(c *Client) error { return c.StartScheduledView(ctx, "v1") },
+			method: http.MethodPost,
+			path:   "/v1/scheduledViews/v1/start",
+		},
+		{
+			name:   "disable",
+			call:   func(c *Client) error { return c.DisableScheduledView(ctx, "v1") },
+			method: http.MethodDelete,
+			path:   "/v1/scheduledViews/v1/disable",
+		},
+	})
+}
+
+func TestCreateScheduledViewRequiresStartTime(t *testing.T) {
+	api := newTestAPI(t)
+	c := api.client()
+	if _, err := c.CreateScheduledView(context.Background(), ScheduledView{IndexName: "errors", Query: "error"}); err == nil {
+		t.Error("CreateScheduledView without a start time succeeded")
+	}
+	if _, err := c.CreateScheduledView(context.Background(), ScheduledView{Query: "error", StartTime: time.Now()}); err == nil {
+		t.Error("CreateScheduledView without an index name succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
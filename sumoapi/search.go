@@ -0,0 +1,486 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultSearchPageSize is the number of messages or records requested at a
+// time by a SearchIterator. The API allows at most 10000.
+const DefaultSearchPageSize = 1000
+
+// ErrSearchCancelled is returned when a search job is cancelled before it
+// finishes, for example because it expired.
+var ErrSearchCancelled = errors.New("search job was cancelled")
+
+// SearchState is the state of a search job.
+type SearchState string
+
+// The states a search job can be in.
+const (
+	SearchNotStarted          SearchState = "NOT STARTED"
+	SearchGatheringResults    SearchState = "GATHERING RESULTS"
+	SearchGatheringSubqueries SearchState = "GATHERING RESULTS FROM SUBQUERIES"
+	SearchForcePaused         SearchState = "FORCE PAUSED"
+	SearchDone                SearchState = "DONE GATHERING RESULTS"
+	SearchCancelled           SearchState = "CANCELLED"
+)
+
+// ParsingMode controls whether the fields of JSON messages are extracted
+// automatically, by a search job or a scheduled view.
+type ParsingMode string
+
+// The parsing modes.
+const (
+	ParsingModeAutoParse ParsingMode = "AutoParse"
+	ParsingModeManual    ParsingMode = "Manual"
+)
+
+// SearchJobRequest describes a search to run.
+type SearchJobRequest struct {
+	// Query is the search query.
+	Query string
+	// From and To are the time range to search.
+	From time.Time
+	To   time.Time
+	// TimeZone is the time zone used for time values in the results, such
+	// as "UTC" or "America/Los_Angeles". The default is UTC.
+	TimeZone string
+	// ByReceiptTime searches by the time logs were received instead of
+	// their message time.
+	ByReceiptTime bool
+	// ParsingMode is ParsingModeAutoParse to extract the fields of JSON
+	// messages automatically, or ParsingModeManual to only return the
+	// fields parsed by the query. The default is the setting of the
+	// account.
+	ParsingMode ParsingMode
+	// RecordsOnly skips gathering raw messages, which speeds up aggregate
+	// queries. The job then only returns records.
+	RecordsOnly bool
+}
+
+// MarshalJSON encodes the request, with the time range in milliseconds since
+// the epoch.
+func (r SearchJobRequest) MarshalJSON() ([]byte, error) {
+	tz := r.TimeZone
+	if tz == "" {
+		tz = "UTC"
+	}
+	var requiresRawMessages *bool
+	if r.RecordsOnly {
+		requiresRawMessages = new(bool)
+	}
+	return json.Marshal(struct {
+		Query               string      `json:"query"`
+		From                int64       `json:"from"`
+		To                  int64       `json:"to"`
+		TimeZone            string      `json:"timeZone"`
+		ByReceiptTime       bool        `json:"byReceiptTime,omitempty"`
+		AutoParsingMode     ParsingMode `json:"autoParsingMode,omitempty"`
+		RequiresRawMessages *bool       `json:"requiresRawMessages,omitempty"`
+	}{r.Query, r.From.UnixMilli(), r.To.UnixMilli(), tz, r.ByReceiptTime, r.ParsingMode, requiresRawMessages})
+}
+
+// SearchJob identifies a search job that was created.
+type SearchJob struct {
+	ID string `json:"id"`
+}
+
+// SearchJobStatus is the progress of a search job.
+type SearchJobStatus struct {
+	State           SearchState `json:"state"`
+	MessageCount    int         `json:"messageCount"`
+	RecordCount     int         `json:"recordCount"`
+	PendingWarnings []string    `json:"pendingWarnings"`
+	PendingErrors   []string    `json:"pendingErrors"`
+}
+
+// Done reports whether the job has finished gathering results. A job that
+// is force paused, because it reached the limit on the number of results,
+// gathers no more, but the results it has can still be read.
+func (s SearchJobStatus) Done() bool {
+	switch s.State {
+	case SearchDone, SearchForcePaused, SearchCancelled:
+		return true
+	}
+	return false
+}
+
+// SearchField describes a field of the search results.
+type SearchField struct {
+	Name      string `json:"name"`
+	FieldType string `json:"fieldType"`
+	KeyField  bool   `json:"keyField"`
+}
+
+// SearchResult is a single message or record returned by a search job. The
+// values of every field are returned as strings.
+type SearchResult struct {
+	Map map[string]string `json:"map"`
+}
+
+// SearchMessage is a raw message matched by a search job.
+type SearchMessage struct {
+	SearchResult
+}
+
+// Raw returns the message as it was sent.
+func (m SearchMessage) Raw() string { return m.Map["_raw"] }
+
+// MessageTime returns the time of the message, or the zero time if it has
+// none.
+func (m SearchMessage) MessageTime() time.Time {
+	t, _ := parseTime(m.Map["_messagetime"])
+	return t
+}
+
+// ReceiptTime returns the time the message was received by Sumo Logic.
+func (m SearchMessage) ReceiptTime() time.Time {
+	t, _ := parseTime(m.Map["_receipttime"])
+	return t
+}
+
+// SourceCategory returns the source category of the message.
+func (m SearchMessage) SourceCategory() string { return m.Map["_sourcecategory"] }
+
+// SourceHost returns the source host of the message.
+func (m SearchMessage) SourceHost() string { return m.Map["_sourcehost"] }
+
+// SourceName returns the source name of the message.
+func (m SearchMessage) SourceName() string { return m.Map["_sourcename"] }
+
+// SearchRecord is an aggregate record produced by a search job.
+type SearchRecord struct {
+	SearchResult
+}
+
+// Float returns the value of the field of the record as a number, such as
+// the _count of a count aggregate.
+func (r SearchRecord) Float(field string) (float64, error) {
+	s, ok := r.Map[field]
+	if !ok {
+		return 0, fmt.Errorf("record has no field %q", field)
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// SearchMessages is a page of messages from a search job.
+type SearchMessages struct {
+	Fields   []SearchField   `json:"fields"`
+	Messages []SearchMessage `json:"messages"`
+}
+
+// SearchRecords is a page of aggregate records from a search job.
+type SearchRecords struct {
+	Fields  []SearchField  `json:"fields"`
+	Records []SearchRecord `json:"records"`
+}
+
+// CreateSearchJob starts a search job and returns its ID. The job runs in the
+// background, use SearchJobStatus or WaitSearchJob to follow its progress.
+func (c *Client) CreateSearchJob(ctx context.Context, req SearchJobRequest) (*SearchJob, error) {
+	var job SearchJob
+	if err := c.post(ctx, "/v1/search/jobs", req, &job); err != nil {
+		return nil, fmt.Errorf("error creating search job: %w", err)
+	}
+	return &job, nil
+}
+
+// SearchJobStatus returns the progress of the search job.
+func (c *Client) SearchJobStatus(ctx context.Context, id string) (*SearchJobStatus, error) {
+	var status SearchJobStatus
+	if err := c.get(ctx, pathf("/v1/search/jobs/%s", id), nil, &status); err != nil {
+		return nil, fmt.Errorf("error getting search job status: %w", err)
+	}
+	return &status, nil
+}
+
+// WaitSearchJob polls the status of the search job until it has finished
+// gathering results or is force paused, and returns the final status. It
+// returns ErrSearchCancelled if the job was cancelled.
+func (c *Client) WaitSearchJob(ctx context.Context, id string) (*SearchJobStatus, error) {
+	for {
+		status, err := c.SearchJobStatus(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if status.State == SearchCancelled {
+			return status, ErrSearchCancelled
+		}
+		if status.Done() {
+			return status, nil
+		}
+		if err := c.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// SearchJobMessages returns up to limit messages of the search job, starting
+// at offset. Messages can be requested while the job is still gathering
+// results, up to its current message count.
+func (c *Client) SearchJobMessages(ctx context.Context, id string, offset, limit int) (*SearchMessages, error) {
+	var page SearchMessages
+	if err := c.get(ctx, pathf("/v1/search/jobs/%s/messages", id), pageQuery(offset, limit), &page); err != nil {
+		return nil, fmt.Errorf("error getting search job messages: %w", err)
+	}
+	return &page, nil
+}
+
+// SearchJobRecords returns up to limit aggregate records of the search job,
+// starting at offset. Records are only complete once the job is done.
+func (c *Client) SearchJobRecords(ctx context.Context, id string, offset, limit int) (*SearchRecords, error) {
+	var page SearchRecords
+	if err := c.get(ctx, pathf("/v1/search/jobs/%s/records", id), pageQuery(offset, limit), &page); err != nil {
+		return nil, fmt.Errorf("error getting search job records: %w", err)
+	}
+	return &page, nil
+}
+
+// DeleteSearchJob cancels the search job, if it is still running, and
+// deletes it along with its results.
+func (c *Client) DeleteSearchJob(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/search/jobs/%s", id)); err != nil {
+		return fmt.Errorf("error deleting search job: %w", err)
+	}
+	return nil
+}
+
+// pageQuery returns the query parameters for an offset based page.
+func pageQuery(offset, limit int) url.Values {
+	return url.Values{
+		"offset": {strconv.Itoa(offset)},
+		"limit":  {strconv.Itoa(limit)},
+	}
+}
+
+// wait sleeps for the poll interval, returning early if the context is done.
+func (c *Client) wait(ctx context.Context) error {
+	t := time.NewTimer(c.pollInterval)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// SearchIterator streams the results of a search job. It creates the job on
+// the first call to Next, returns results as they become available, and
+// deletes the job once every result has been read or Close is called.
+//
+//	it := client.SearchMessages(ctx, req)
+//	defer it.Close()
+//	for it.Next() {
+//		fmt.Println(it.Message().Raw())
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type SearchIterator struct {
+	c       *Client
+	ctx     context.Context
+	req     SearchJobRequest
+	records bool
+
+	jobID  string
+	status *SearchJobStatus
+	fields []SearchField
+	page   []SearchResult
+	pos    int
+	offset int
+	done   bool
+	err    error
+}
+
+// SearchMessages returns a SearchIterator over the messages matched by the
+// search, read with Message. Messages are streamed while the job is still
+// gathering results. It returns nothing if req.RecordsOnly is set.
+func (c *Client) SearchMessages(ctx context.Context, req SearchJobRequest) *SearchIterator {
+	return &SearchIterator{c: c, ctx: ctx, req: req}
+}
+
+// SearchRecords returns a SearchIterator over the aggregate records produced
+// by the search, read with Record, which are available once the job is
+// done. Set req.RecordsOnly when the messages are not needed.
+func (c *Client) SearchRecords(ctx context.Context, req SearchJobRequest) *SearchIterator {
+	return &SearchIterator{c: c, ctx: ctx, req: req, records: true}
+}
+
+// Next advances to the next result, returning false once there are no more
+// results or an error occurred, see Err.
+func (it *SearchIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	it.pos++
+	for it.pos >= len(it.page) {
+		if !it.fetch() {
+			it.finish()
+			return false
+		}
+	}
+	return true
+}
+
+// Result returns the current result.
+func (it *SearchIterator) Result() SearchResult {
+	if it.pos < 0 || it.pos >= len(it.page) {
+		return SearchResult{}
+	}
+	return it.page[it.pos]
+}
+
+// Message returns the current result of an iterator returned by
+// SearchMessages.
+func (it *SearchIterator) Message() SearchMessage {
+	return SearchMessage{it.Result()}
+}
+
+// Record returns the current result of an iterator returned by
+// SearchRecords.
+func (it *SearchIterator) Record() SearchRecord {
+	return SearchRecord{it.Result()}
+}
+
+// Fields returns the fields of the results, once the first page has been
+// read.
+func (it *SearchIterator) Fields() []SearchField {
+	return it.fields
+}
+
+// JobID returns the ID of the search job, once it has been created.
+func (it *SearchIterator) JobID() string {
+	return it.jobID
+}
+
+// Status returns the last status of the search job that was read.
+func (it *SearchIterator) Status() *SearchJobStatus {
+	return it.status
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *SearchIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iteration and deletes the search job. It is safe to call
+// more than once.
+func (it *SearchIterator) Close() error {
+	if it.done && it.jobID == "" {
+		return nil
+	}
+	it.done = true
+	return it.deleteJob()
+}
+
+// fetch loads the next page of results, waiting for the job to produce them.
+// It returns false when there are no more results.
+func (it *SearchIterator) fetch() bool {
+	if it.jobID == "" {
+		job, err := it.c.CreateSearchJob(it.ctx, it.req)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.jobID = job.ID
+	}
+	for {
+		status, err := it.c.SearchJobStatus(it.ctx, it.jobID)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.status = status
+		if status.State == SearchCancelled {
+			it.err = ErrSearchCancelled
+			return false
+		}
+		available := status.MessageCount
+		if it.records {
+			available = status.RecordCount
+		}
+		// Records are only final once the job is done, while messages can
+		// be read as they arrive.
+		if it.offset < available && (!it.records || status.Done()) {
+			return it.load(min(DefaultSearchPageSize, available-it.offset))
+		}
+		if status.Done() {
+			return false
+		}
+		if err := it.c.wait(it.ctx); err != nil {
+			it.err = err
+			return false
+		}
+	}
+}
+
+// load reads a page of limit results at the current offset.
+func (it *SearchIterator) load(limit int) bool {
+	var fields []SearchField
+	var results []SearchResult
+	if it.records {
+		page, err := it.c.SearchJobRecords(it.ctx, it.jobID, it.offset, limit)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		fields = page.Fields
+		for _, r := range page.Records {
+			results = append(results, r.SearchResult)
+		}
+	} else {
+		page, err := it.c.SearchJobMessages(it.ctx, it.jobID, it.offset, limit)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		fields = page.Fields
+		for _, m := range page.Messages {
+			results = append(results, m.SearchResult)
+		}
+	}
+	if len(results) == 0 {
+		return false
+	}
+	if it.fields == nil {
+		it.fields = fields
+	}
+	it.page = results
+	it.pos = 0
+	it.offset += len(results)
+	return true
+}
+
+// finish ends the iteration and deletes the job.
+func (it *SearchIterator) finish() {
+	it.done = true
+	it.page = nil
+	if err := it.deleteJob(); err != nil && it.err == nil {
+		it.err = err
+	}
+}
+
+// deleteJob deletes the search job, if it was created. It uses a fresh
+// context when the iterator's context is done, so the job does not keep
+// running on the server.
+func (it *SearchIterator) deleteJob() error {
+	if it.jobID == "" {
+		return nil
+	}
+	ctx := it.ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+	}
+	id := it.jobID
+	it.jobID = ""
+	return it.c.DeleteSearchJob(ctx, id)
+}
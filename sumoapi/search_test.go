@@ -0,0 +1,199 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// serveStates serves the job statuses in turn, repeating the last one, with
+// the message and record counts of the final status.
+func serveStates(api *testAPI, messages, records int, states ...SearchState) {
+	var n atomic.Int32
+	api.handle("GET /v1/search/jobs/j1", func(w http.ResponseWriter, r *http.Request) {
+		i := min(int(n.Add(1))-1, len(states)-1)
+		if i < len(states)-1 {
+			fmt.Fprintf(w, `{"state": %q}`, states[i])
+			return
+		}
+		fmt.Fprintf(w, `{"state": %q, "messageCount": %d, "recordCount": %d}`, states[i], messages, records)
+	})
+}
+
+func TestCreateSearchJob(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/search/jobs", `{"id": "j1"}`)
+	from := time.UnixMilli(1700000000000)
+	job, err := api.client().CreateSearchJob(context.Background(), SearchJobRequest{
+		Query:       "error | count",
+		From:        from,
+		To:          from.Add(time.Hour),
+		ParsingMode: ParsingModeAutoParse,
+		RecordsOnly: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "j1" {
+		t.Errorf("job ID = %q, want j1", job.ID)
+	}
+	checkRequest(t, api.last(), http.MethodPost, "/v1/search/jobs", `{
+		"query": "error | count",
+		"from": 1700000000000,
+		"to": 1700003600000,
+		"timeZone": "UTC",
+		"autoParsingMode": "AutoParse",
+		"requiresRawMessages": false
+	}`)
+}
+
+func TestWaitSearchJob(t *testing.T) {
+	tests := []struct {
+		name    string
+		states  []SearchState
+		wantErr error
+	}{
+		{"done", []SearchState{SearchNotStarted, SearchGatheringResults, SearchDone}, nil},
+		// A job that reached the result limit gathers no more results, so
+		// waiting for it to be done would never return.
+		{"force paused", []SearchState{SearchGatheringResults, SearchForcePaused}, nil},
+		{"cancelled", []SearchState{SearchGatheringResults, SearchCancelled}, ErrSearchCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI(t)
+			serveStates(api, 5, 0, tt.states...)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			status, err := api.client().WaitSearchJob(ctx, "j1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WaitSearchJob error = %v, want %v", err, tt.wantErr)
+			}
+			if want := tt.states[len(tt.states)-1]; status.State != want {
+				t.Errorf("state = %q, want %q", status.State, want)
+			}
+			if n := len(api.received()); n != len(tt.states) {
+				t.Errorf("polled %d times, want %d", n, len(tt.states))
+			}
+		})
+	}
+}
+
+func TestSearchMessages(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/search/jobs", `{"id": "j1"}`)
+	serveStates(api, 2, 0, SearchGatheringResults, SearchForcePaused)
+	api.respond("GET /v1/search/jobs/j1/messages", `{
+		"fields": [{"name": "_raw", "fieldType": "string"}],
+		"messages": [{"map": {"_raw": "one", "_sourcecategory": "app"}}, {"map": {"_raw": "two"}}]
+	}`)
+	api.respond("DELETE /v1/search/jobs/j1", ``)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	it := api.client().SearchMessages(ctx, SearchJobRequest{Query: "*"})
+	defer it.Close()
+	var raw []string
+	for it.Next() {
+		raw = append(raw, it.Message().Raw())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(raw, []string{"one", "two"}) {
+		t.Errorf("messages = %q, want one and two", raw)
+	}
+	if f := it.Fields(); len(f) != 1 || f[0].Name != "_raw" {
+		t.Errorf("fields = %+v", f)
+	}
+	last := api.last()
+	checkRequest(t, last, http.MethodDelete, "/v1/search/jobs/j1", "")
+	var page apiRequest
+	for _, r := range api.received() {
+		if r.path == "/v1/search/jobs/j1/messages" {
+			page = r
+		}
+	}
+	if page.query.Get("offset") != "0" || page.query.Get("limit") != "2" {
+		t.Errorf("messages requested with %v, want offset 0 and limit 2", page.query)
+	}
+}
+
+func TestSearchRecordsWaitForJob(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/search/jobs", `{"id": "j1"}`)
+	serveStates(api, 0, 1, SearchGatheringResults, SearchGatheringResults, SearchDone)
+	api.respond("GET /v1/search/jobs/j1/records", `{"records": [{"map": {"_count": "42"}}]}`)
+	api.respond("DELETE /v1/search/jobs/j1", ``)
+	it := api.client().SearchRecords(context.Background(), SearchJobRequest{Query: "* | count", RecordsOnly: true})
+	defer it.Close()
+	if !it.Next() {
+		t.Fatalf("Next = false, err = %v", it.Err())
+	}
+	if n, err := it.Record().Float("_count"); err != nil || n != 42 {
+		t.Errorf("_count = %v, %v, want 42", n, err)
+	}
+	if it.Next() {
+		t.Error("Next = true after the last record")
+	}
+	if _, err := it.Record().Float("missing"); err == nil {
+		t.Error("Float of a missing field succeeded")
+	}
+}
+
+func TestSearchCancelled(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/search/jobs", `{"id": "j1"}`)
+	serveStates(api, 0, 0, SearchCancelled)
+	api.respond("DELETE /v1/search/jobs/j1", ``)
+	it := api.client().SearchMessages(context.Background(), SearchJobRequest{Query: "*"})
+	if it.Next() {
+		t.Fatal("Next = true for a cancelled job")
+	}
+	if !errors.Is(it.Err(), ErrSearchCancelled) {
+		t.Errorf("Err = %v, want ErrSearchCancelled", it.Err())
+	}
+}
+
+func TestParsingMode(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/search/jobs", `{"id": "j1"}`)
+	api.respond("POST /v1/logSearches/estimatedUsage", `{"estimatedUsageDetails": {"dataScannedInBytes": 1}}`)
+	api.respond("POST /v1/scheduledViews", `{"id": "v1"}`)
+	c := api.client()
+	ctx := context.Background()
+	for _, mode := range []ParsingMode{"", ParsingModeAutoParse, ParsingModeManual} {
+		req := SearchJobRequest{Query: "*", ParsingMode: mode}
+		if _, err := c.CreateSearchJob(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+		checkParsingMode(t, api.last(), "autoParsingMode", mode)
+		if _, err := c.EstimateSearchUsage(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+		checkParsingMode(t, api.last(), "autoParsingMode", mode)
+		if _, err := c.CreateScheduledView(ctx, ScheduledView{IndexName: "v", Query: "*", StartTime: time.Now(), ParsingMode: mode}); err != nil {
+			t.Fatal(err)
+		}
+		checkParsingMode(t, api.last(), "parsingMode", mode)
+	}
+}
+
+// checkParsingMode checks the parsing mode in the body of the request, which
+// is left out when it is empty.
+func checkParsingMode(t *testing.T, r apiRequest, key string, want ParsingMode) {
+	t.Helper()
+	var body map[string]any
+	if err := json.Unmarshal([]byte(r.body), &body); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := body[key]
+	if want == "" && ok || want != "" && got != string(want) {
+		t.Errorf("%s %s: %s = %v, want %q", r.method, r.path, key, got, want)
+	}
+}
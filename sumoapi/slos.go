@@ -0,0 +1,355 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types of the items in the SLO library.
+const (
+	SLOTypeSLO    = "SlosLibrarySlo"
+	SLOTypeFolder = "SlosLibraryFolder"
+)
+
+// Evaluation types of a service level indicator.
+const (
+	// SLIWindowBased evaluates the indicator over fixed windows, each of
+	// which is good or bad depending on whether it meets the threshold.
+	SLIWindowBased = "WindowBasedEvaluation"
+	// SLIRequestBased evaluates the indicator as the ratio of successful
+	// requests to total requests.
+	SLIRequestBased = "RequestBasedEvaluation"
+)
+
+// Compliance types of an SLO.
+const (
+	ComplianceRolling  = "Rolling"
+	ComplianceCalendar = "Calendar"
+)
+
+// SLO is an item in the SLO library, which is either a service level
+// objective or a folder of them, as told by Type. Fields that do not apply
+// to the type of the item are left empty.
+type SLO struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Type is SLOTypeSLO or SLOTypeFolder.
+	Type       string    `json:"type"`
+	ParentID   string    `json:"parentId,omitempty"`
+	Version    int       `json:"version,omitempty"`
+	IsLocked   bool      `json:"isLocked,omitempty"`
+	IsSystem   bool      `json:"isSystem,omitempty"`
+	IsMutable  bool      `json:"isMutable,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	CreatedBy  string    `json:"createdBy,omitempty"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+	ModifiedBy string    `json:"modifiedBy,omitempty"`
+
+	// SignalType is "Latency", "Error", "Throughput", "Availability" or
+	// "Other".
+	SignalType  string         `json:"signalType,omitempty"`
+	Compliance  *SLOCompliance `json:"compliance,omitempty"`
+	Indicator   *SLOIndicator  `json:"indicator,omitempty"`
+	Service     string         `json:"service,omitempty"`
+	Application string         `json:"application,omitempty"`
+
+	// Children are the items in a folder.
+	Children []SLO `json:"children,omitempty"`
+
+	// Extra holds the properties of the SLO that have no field, which are
+	// kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the SLO, keeping the properties that have no field
+// in Extra.
+func (s *SLO) UnmarshalJSON(b []byte) error {
+	type plain SLO
+	return unmarshalExtra(b, (*plain)(s), &s.Extra)
+}
+
+// MarshalJSON encodes the SLO along with the properties in Extra.
+func (s SLO) MarshalJSON() ([]byte, error) {
+	type plain SLO
+	return marshalExtra(plain(s), s.Extra)
+}
+
+// IsFolder reports whether the item is a folder.
+func (s SLO) IsFolder() bool {
+	return s.Type == SLOTypeFolder
+}
+
+// SLOCompliance is the target of an SLO and the period it is measured over.
+type SLOCompliance struct {
+	// ComplianceType is ComplianceRolling or ComplianceCalendar.
+	ComplianceType string `json:"complianceType"`
+	// Target is the percentage of good windows or requests, such as 99.9.
+	Target   float64 `json:"target"`
+	Timezone string  `json:"timezone"`
+	// Size is the period, such as "7d" for a rolling compliance, or "Week",
+	// "Month" or "Quarter" for a calendar one.
+	Size string `json:"size"`
+	// StartFrom is the day a calendar week starts on, such as "Monday".
+	StartFrom string `json:"startFrom,omitempty"`
+
+	// Extra holds the properties of the compliance that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the compliance, keeping the properties that have no
+// field in Extra.
+func (c *SLOCompliance) UnmarshalJSON(b []byte) error {
+	type plain SLOCompliance
+	return unmarshalExtra(b, (*plain)(c), &c.Extra)
+}
+
+// MarshalJSON encodes the compliance along with the properties in Extra.
+func (c SLOCompliance) MarshalJSON() ([]byte, error) {
+	type plain SLOCompliance
+	return marshalExtra(plain(c), c.Extra)
+}
+
+// Period returns the length of the compliance period. Calendar months and
+// quarters vary in length, so they are given as 30 and 90 days.
+func (c SLOCompliance) Period() (time.Duration, error) {
+	switch strings.ToLower(c.Size) {
+	case "week":
+		return 7 * 24 * time.Hour, nil
+	case "month":
+		return 30 * 24 * time.Hour, nil
+	case "quarter":
+		return 90 * 24 * time.Hour, nil
+	}
+	if days, ok := strings.CutSuffix(c.Size, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid compliance size: %q", c.Size)
+}
+
+// ErrorBudget returns how much of the compliance period may be bad while
+// still meeting the target, such as about 10 minutes for a 99.9% target
+// over 7 days.
+func (c SLOCompliance) ErrorBudget() (time.Duration, error) {
+	period, err := c.Period()
+	if err != nil {
+		return 0, err
+	}
+	if c.Target <= 0 || c.Target > 100 {
+		return 0, fmt.Errorf("invalid compliance target: %v", c.Target)
+	}
+	return time.Duration(float64(period) * (100 - c.Target) / 100), nil
+}
+
+// SLOIndicator is the service level indicator an SLO is measured with.
+type SLOIndicator struct {
+	// Type is SLIWindowBased or SLIRequestBased.
+	Type string `json:"evaluationType"`
+	// QueryType is "Logs" or "Metrics".
+	QueryType string          `json:"queryType"`
+	Queries   []SLIQueryGroup `json:"queries"`
+	// Threshold, Op and Aggregation decide whether a window is good for a
+	// window based indicator, such as an average latency less than 500.
+	// Request based indicators only use them when they have a single query
+	// group of type SLIQueryThreshold.
+	Threshold   float64 `json:"threshold,omitempty"`
+	Op          string  `json:"op,omitempty"`
+	Aggregation string  `json:"aggregation,omitempty"`
+	// Size is the length of a window, such as "1m", for a window based
+	// indicator.
+	Size string `json:"size,omitempty"`
+
+	// Extra holds the properties of the indicator that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the indicator, keeping the properties that have no
+// field in Extra.
+func (i *SLOIndicator) UnmarshalJSON(b []byte) error {
+	type plain SLOIndicator
+	return unmarshalExtra(b, (*plain)(i), &i.Extra)
+}
+
+// MarshalJSON encodes the indicator along with the properties in Extra.
+func (i SLOIndicator) MarshalJSON() ([]byte, error) {
+	type plain SLOIndicator
+	return marshalExtra(plain(i), i.Extra)
+}
+
+// Types of the query groups of an indicator.
+const (
+	SLIQuerySuccessful   = "Successful"
+	SLIQueryUnsuccessful = "Unsuccessful"
+	SLIQueryTotal        = "Total"
+	SLIQueryThreshold    = "Threshold"
+)
+
+// SLIQueryGroup is a group of queries of an indicator, such as the queries
+// counting successful requests.
+type SLIQueryGroup struct {
+	// QueryGroupType is one of the SLIQuery constants.
+	QueryGroupType string     `json:"queryGroupType"`
+	QueryGroup     []SLIQuery `json:"queryGroup"`
+
+	// Extra holds the properties of the query group that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the query group, keeping the properties that have no
+// field in Extra.
+func (g *SLIQueryGroup) UnmarshalJSON(b []byte) error {
+	type plain SLIQueryGroup
+	return unmarshalExtra(b, (*plain)(g), &g.Extra)
+}
+
+// MarshalJSON encodes the query group along with the properties in Extra.
+func (g SLIQueryGroup) MarshalJSON() ([]byte, error) {
+	type plain SLIQueryGroup
+	return marshalExtra(plain(g), g.Extra)
+}
+
+// SLIQuery is a single query of an indicator.
+type SLIQuery struct {
+	RowID string `json:"rowId"`
+	Query string `json:"query"`
+	// UseRowCount counts the rows returned by the query, rather than using
+	// the value of Field.
+	UseRowCount bool   `json:"useRowCount"`
+	Field       string `json:"field,omitempty"`
+
+	// Extra holds the properties of the query that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the query, keeping the properties that have no field
+// in Extra.
+func (q *SLIQuery) UnmarshalJSON(b []byte) error {
+	type plain SLIQuery
+	return unmarshalExtra(b, (*plain)(q), &q.Extra)
+}
+
+// MarshalJSON encodes the query along with the properties in Extra.
+func (q SLIQuery) MarshalJSON() ([]byte, error) {
+	type plain SLIQuery
+	return marshalExtra(plain(q), q.Extra)
+}
+
+// SLIStatus is the current state of an SLO.
+type SLIStatus struct {
+	ID string `json:"id"`
+	// SLIPercentage is the current percentage of good windows or requests.
+	SLIPercentage float64 `json:"sliPercentage"`
+	// ErrorBudgetRemaining is the percentage of the error budget that is
+	// left.
+	ErrorBudgetRemaining float64 `json:"errorBudgetRemaining"`
+	// BurnRate is how fast the error budget is being used, where 1 uses it
+	// all by the end of the compliance period.
+	BurnRate float64 `json:"burnRate"`
+	Status   string  `json:"status,omitempty"`
+}
+
+// GetSLORootFolder returns the root folder of the SLO library, including its
+// children.
+func (c *Client) GetSLORootFolder(ctx context.Context) (*SLO, error) {
+	var s SLO
+	if err := c.get(ctx, "/v1/slos/root", nil, &s); err != nil {
+		return nil, fmt.Errorf("error getting slos root folder: %w", err)
+	}
+	return &s, nil
+}
+
+// GetSLO returns the SLO or folder with the ID. Folders include their
+// children.
+func (c *Client) GetSLO(ctx context.Context, id string) (*SLO, error) {
+	var s SLO
+	if err := c.get(ctx, pathf("/v1/slos/%s", id), nil, &s); err != nil {
+		return nil, fmt.Errorf("error getting slo: %w", err)
+	}
+	return &s, nil
+}
+
+// CreateSLO creates the SLO in the folder with the parent ID and returns it.
+// Type defaults to SLOTypeSLO.
+func (c *Client) CreateSLO(ctx context.Context, parentID string, slo SLO) (*SLO, error) {
+	if slo.Type == "" {
+		slo.Type = SLOTypeSLO
+	}
+	if slo.Type == SLOTypeSLO && (slo.Compliance == nil || slo.Indicator == nil) {
+		return nil, fmt.Errorf("error creating slo: compliance and indicator are required")
+	}
+	var created SLO
+	r := request{
+		method: http.MethodPost,
+		path:   "/v1/slos",
+		query:  url.Values{"parentId": {parentID}},
+		body:   sloBody(slo),
+		out:    &created,
+	}
+	if _, err := c.do(ctx, r); err != nil {
+		return nil, fmt.Errorf("error creating slo: %w", err)
+	}
+	return &created, nil
+}
+
+// CreateSLOFolder creates a folder in the folder with the parent ID and
+// returns it.
+func (c *Client) CreateSLOFolder(ctx context.Context, parentID, name, description string) (*SLO, error) {
+	return c.CreateSLO(ctx, parentID, SLO{Name: name, Description: description, Type: SLOTypeFolder})
+}
+
+// UpdateSLO replaces the SLO or folder with the same ID and returns the
+// updated item. The Version must match the current version of the item.
+func (c *Client) UpdateSLO(ctx context.Context, slo SLO) (*SLO, error) {
+	var updated SLO
+	if err := c.put(ctx, pathf("/v1/slos/%s", slo.ID), sloBody(slo), &updated); err != nil {
+		return nil, fmt.Errorf("error updating slo: %w", err)
+	}
+	return &updated, nil
+}
+
+// DeleteSLO deletes the SLO or folder with the ID. Deleting a folder deletes
+// everything in it.
+func (c *Client) DeleteSLO(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/slos/%s", id)); err != nil {
+		return fmt.Errorf("error deleting slo: %w", err)
+	}
+	return nil
+}
+
+// SLIStatuses returns the current state of the SLOs with the IDs, including
+// how much of their error budget is left.
+func (c *Client) SLIStatuses(ctx context.Context, ids ...string) ([]SLIStatus, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var resp struct {
+		Data []SLIStatus `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/slos/sli", url.Values{"ids": {strings.Join(ids, ",")}}, &resp); err != nil {
+		return nil, fmt.Errorf("error getting sli statuses: %w", err)
+	}
+	return resp.Data, nil
+}
+
+// sloBody returns the SLO without the fields that are only set by the API.
+func sloBody(s SLO) any {
+	type body SLO
+	return withExtra(struct {
+		body
+		CreatedAt  *time.Time `json:"createdAt,omitempty"`
+		ModifiedAt *time.Time `json:"modifiedAt,omitempty"`
+		Children   []SLO      `json:"children,omitempty"`
+	}{body: body(s)}, s.Extra)
+}
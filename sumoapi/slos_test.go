@@ -0,0 +1,156 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSLOs(t *testing.T) {
+	ctx := context.Background()
+	slo := `{"id": "s1", "name": "Availability", "type": "SlosLibrarySlo", "version": 1, "createdAt": "2024-01-02T03:04:05Z", "modifiedAt": "2024-01-02T03:04:05Z", "tags": {"team": "web"}}`
+	testCalls(t, []apiCall{
+		{
+			name: "root folder",
+			call: func(c *Client) error {
+				root, err := c.GetSLORootFolder(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(root.IsFolder() && len(root.Children) == 1, true)
+			},
+			response: `{"id": "root", "type": "SlosLibraryFolder", "children": [` + slo + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/slos/root",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				s, err := c.GetSLO(ctx, "s1")
+				if err != nil {
+					return err
+				}
+				return expect(string(s.Extra["tags"]), `{"team": "web"}`)
+			},
+			response: slo,
+			method:   http.MethodGet,
+			path:     "/v1/slos/s1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateSLO(ctx, "f1", SLO{
+					Name:       "Availability",
+					Compliance: &SLOCompliance{ComplianceType: ComplianceRolling, Target: 99.9, Timezone: "UTC", Size: "7d"},
+					Indicator: &SLOIndicator{
+						Type:      SLIRequestBased,
+						QueryType: "Logs",
+						Queries: []SLIQueryGroup{{
+							QueryGroupType: SLIQuerySuccessful,
+							QueryGroup:     []SLIQuery{{RowID: "A", Query: "status=200", UseRowCount: true}},
+						}},
+					},
+				})
+				return err
+			},
+			response: slo,
+			method:   http.MethodPost,
+			path:     "/v1/slos",
+			query:    "parentId=f1",
+			body: `{
+				"name": "Availability",
+				"type": "SlosLibrarySlo",
+				"compliance": {"complianceType": "Rolling", "target": 99.9, "timezone": "UTC", "size": "7d"},
+				"indicator": {
+					"evaluationType": "RequestBasedEvaluation",
+					"queryType": "Logs",
+					"queries": [{"queryGroupType": "Successful", "queryGroup": [{"rowId": "A", "query": "status=200", "useRowCount": true}]}]
+				}
+			}`,
+		},
+		{
+			name: "create folder",
+			call: func(c *Client) error {
+				_, err := c.CreateSLOFolder(ctx, "root", "Web", "")
+				return err
+			},
+			response: `{"id": "f1", "name": "Web", "type": "SlosLibraryFolder"}`,
+			method:   http.MethodPost,
+			path:     "/v1/slos",
+			query:    "parentId=root",
+			body:     `{"name": "Web", "type": "SlosLibraryFolder"}`,
+		},
+		{
+			name: "update keeps extra",
+			call: func(c *Client) error {
+				var s SLO
+				if err := json.Unmarshal([]byte(slo), &s); err != nil {
+					return err
+				}
+				_, err := c.UpdateSLO(ctx, s)
+				return err
+			},
+			response: slo,
+			method:   http.MethodPut,
+			path:     "/v1/slos/s1",
+			body:     `{"id": "s1", "name": "Availability", "type": "SlosLibrarySlo", "version": 1, "tags": {"team": "web"}}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteSLO(ctx, "s1") },
+			method: http.MethodDelete,
+			path:   "/v1/slos/s1",
+		},
+		{
+			name: "sli statuses",
+			call: func(c *Client) error {
+				statuses, err := c.SLIStatuses(ctx, "s1", "s2")
+				if err != nil {
+					return err
+				}
+				return expect(statuses, []SLIStatus{{ID: "s1", SLIPercentage: 99.95, ErrorBudgetRemaining: 50, BurnRate: 0.5}})
+			},
+			response: `{"data": [{"id": "s1", "sliPercentage": 99.95, "errorBudgetRemaining": 50, "burnRate": 0.5}]}`,
+			method:   http.MethodGet,
+			path:     "/v1/slos/sli",
+			query:    "ids=s1%2Cs2",
+		},
+	})
+}
+
+func TestCreateSLORequiresIndicator(t *testing.T) {
+	api := newTestAPI(t)
+	if _, err := api.client().CreateSLO(context.Background(), "f1", SLO{Name: "Availability"}); err == nil {
+		t.Error("CreateSLO without compliance and indicator succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
+
+func TestSLOErrorBudget(t *testing.T) {
+	tests := []struct {
+		size    string
+		target  float64
+		want    time.Duration
+		wantErr bool
+	}{
+		{"7d", 99.9, 604800 * time.Millisecond, false},
+		{"Week", 99, 7 * 24 * time.Hour / 100, false},
+		{"month", 99.9, 30 * 24 * time.Hour / 1000, false},
+		{"Quarter", 100, 0, false},
+		{"0d", 99, 0, true},
+		{"year", 99, 0, true},
+		{"7d", 0, 0, true},
+		{"7d", 101, 0, true},
+	}
+	for _, tt := range tests {
+		c := SLOCompliance{Size: tt.size, Target: tt.target}
+		got, err := c.ErrorBudget()
+		if (err != nil) != tt.wantErr || got.Round(time.Millisecond) != tt.want {
+			t.Errorf("ErrorBudget of %v over %s = %v, %v, want %v (error %v)", tt.target, tt.size, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/byitkc/gosumo"
+)
+
+// Source types.
+const (
+	SourceHTTP = "HTTP"
+)
+
+// Content types of HTTP sources that receive data other than logs.
+const (
+	// SourceContentTypeTraces is the content type of an HTTP Traces source,
+	// which receives OTLP traces.
+	SourceContentTypeTraces = "Zipkin"
+	// SourceContentTypeRUM is the content type of a RUM source, which
+	// receives the traces of browsers running the RUM script.
+	SourceContentTypeRUM = "Rum"
+)
+
+// Source is a source under a collector. Sources of every type share this
+// model, so fields that do not apply to a type are left empty.
+// AutomaticDateParsing and MultilineProcessingEnabled are left to the
+// defaults of the API, which turn them on, when they are nil.
+type Source struct {
+	ID                         int64             `json:"id,omitempty"`
+	Name                       string            `json:"name"`
+	Description                string            `json:"description,omitempty"`
+	Category                   string            `json:"category,omitempty"`
+	HostName                   string            `json:"hostName,omitempty"`
+	TimeZone                   string            `json:"timeZone,omitempty"`
+	SourceType                 string            `json:"sourceType"`
+	ContentType                string            `json:"contentType,omitempty"`
+	Fields                     map[string]string `json:"fields,omitempty"`
+	AutomaticDateParsing       *bool             `json:"automaticDateParsing,omitempty"`
+	MultilineProcessingEnabled *bool             `json:"multilineProcessingEnabled,omitempty"`
+	UseAutolineMatching        bool              `json:"useAutolineMatching"`
+	ManualPrefixRegexp         string            `json:"manualPrefixRegexp,omitempty"`
+	ForceTimeZone              bool              `json:"forceTimeZone"`
+	DefaultDateFormats         []DateFormat      `json:"defaultDateFormats,omitempty"`
+	Filters                    []SourceFilter    `json:"filters,omitempty"`
+	CutoffTimestamp            int64             `json:"cutoffTimestamp,omitempty"`
+	CutoffRelativeTime         string            `json:"cutoffRelativeTime,omitempty"`
+	MessagePerRequest          bool              `json:"messagePerRequest,omitempty"`
+	Alive                      bool              `json:"alive,omitempty"`
+	// RUM holds the settings of a RUM source.
+	RUM *RUMSettings `json:"path,omitempty"`
+	// URL is the unique upload URL of an HTTP source, which is only
+	// returned by the API.
+	URL string `json:"url,omitempty"`
+
+	// ETag is the version of the source returned by GetSource. When it is
+	// set, UpdateSource only succeeds if the source has not been changed
+	// since, and otherwise fails with ErrPreconditionFailed.
+	ETag string `json:"-"`
+
+	// Extra holds the properties of the source that have no field, which are
+	// kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the source, keeping the properties that have no
+// field in Extra.
+func (s *Source) UnmarshalJSON(b []byte) error {
+	type plain Source
+	return unmarshalExtra(b, (*plain)(s), &s.Extra)
+}
+
+// MarshalJSON encodes the source along with the properties in Extra.
+func (s Source) MarshalJSON() ([]byte, error) {
+	type plain Source
+	return marshalExtra(plain(s), s.Extra)
+}
+
+// DateFormat is a timestamp format used to parse the logs of a source.
+type DateFormat struct {
+	Format  string `json:"format"`
+	Locator string `json:"locator,omitempty"`
+
+	// Extra holds the properties of the date format that have no field,
+	// which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the date format, keeping the properties that have no
+// field in Extra.
+func (f *DateFormat) UnmarshalJSON(b []byte) error {
+	type plain DateFormat
+	return unmarshalExtra(b, (*plain)(f), &f.Extra)
+}
+
+// MarshalJSON encodes the date format along with the properties in Extra.
+func (f DateFormat) MarshalJSON() ([]byte, error) {
+	type plain DateFormat
+	return marshalExtra(plain(f), f.Extra)
+}
+
+// SourceFilter is a processing rule of a source, such as an exclude or mask
+// filter.
+type SourceFilter struct {
+	Name       string `json:"name"`
+	FilterType string `json:"filterType"`
+	Regexp     string `json:"regexp"`
+	Mask       string `json:"mask,omitempty"`
+
+	// Extra holds the properties of the filter that have no field, which
+	// are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the filter, keeping the properties that have no
+// field in Extra.
+func (f *SourceFilter) UnmarshalJSON(b []byte) error {
+	type plain SourceFilter
+	return unmarshalExtra(b, (*plain)(f), &f.Extra)
+}
+
+// MarshalJSON encodes the filter along with the properties in Extra.
+func (f SourceFilter) MarshalJSON() ([]byte, error) {
+	type plain SourceFilter
+	return marshalExtra(plain(f), f.Extra)
+}
+
+// RUMSettings configures the traces collected by a RUM source.
+type RUMSettings struct {
+	// Type is set by CreateRUMSource.
+	Type                  string `json:"type"`
+	ApplicationName       string `json:"applicationName,omitempty"`
+	ServiceName           string `json:"serviceName"`
+	DeploymentEnvironment string `json:"deploymentEnvironment,omitempty"`
+	// SamplingRate is the fraction of page loads traced, from 0 to 1.
+	SamplingRate float64 `json:"samplingRate,omitempty"`
+	// IgnoreURLs are not traced, and PropagateTraceHeaderCORSURLs are sent
+	// the trace header on cross-origin requests.
+	IgnoreURLs                   []string          `json:"ignoreUrls,omitempty"`
+	PropagateTraceHeaderCORSURLs []string          `json:"propagateTraceHeaderCorsUrls,omitempty"`
+	CustomTags                   map[string]string `json:"customTags,omitempty"`
+
+	// Extra holds the properties of the RUM settings that have no field,
+	// which are kept when they are encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the RUM settings, keeping the properties that have
+// no field in Extra.
+func (s *RUMSettings) UnmarshalJSON(b []byte) error {
+	type plain RUMSettings
+	return unmarshalExtra(b, (*plain)(s), &s.Extra)
+}
+
+// MarshalJSON encodes the RUM settings along with the properties in Extra.
+func (s RUMSettings) MarshalJSON() ([]byte, error) {
+	type plain RUMSettings
+	return marshalExtra(plain(s), s.Extra)
+}
+
+// LogEndpoint returns a gosumo.LogEndpoint for the upload URL of an HTTP
+// source.
+func (s Source) LogEndpoint() (gosumo.LogEndpoint, error) {
+	if s.URL == "" {
+		return gosumo.LogEndpoint{}, fmt.Errorf("source %d has no upload url", s.ID)
+	}
+	return gosumo.NewLogEndpoint(s.URL)
+}
+
+// NewClient returns a gosumo.Client that posts to the upload URL of an HTTP
+// source, so a program can provision its own source and start using it
+// straight away.
+func (s Source) NewClient(opts ...gosumo.Option) (*gosumo.Client, error) {
+	if s.URL == "" {
+		return nil, gosumo.ErrBuildingClient{Message: fmt.Sprintf("unable to build client: source %d has no upload url", s.ID)}
+	}
+	return gosumo.NewClient(s.URL, opts...)
+}
+
+// TraceEndpoint returns a gosumo.TraceEndpoint that posts OTLP traces to an
+// HTTP Traces source. The options are the same as for gosumo.NewClient.
+func (s Source) TraceEndpoint(opts ...gosumo.Option) (*gosumo.TraceEndpoint, error) {
+	if s.URL == "" {
+		return nil, gosumo.ErrBuildingClient{Message: fmt.Sprintf("unable to build client: source %d has no upload url", s.ID)}
+	}
+	return gosumo.NewTraceEndpoint(strings.TrimSuffix(s.URL, "/")+"/v1/traces", opts...)
+}
+
+// sourceBody is the envelope used for a single source.
+type sourceBody struct {
+	Source Source `json:"source"`
+}
+
+// ListSources returns the sources of the collector.
+func (c *Client) ListSources(ctx context.Context, collectorID int64) ([]Source, error) {
+	var resp struct {
+		Sources []Source `json:"sources"`
+	}
+	if err := c.get(ctx, pathf("/v1/collectors/%s/sources", collectorID), nil, &resp); err != nil {
+		return nil, fmt.Errorf("error listing sources: %w", err)
+	}
+	return resp.Sources, nil
+}
+
+// GetSource returns the source with the ID, including its ETag.
+func (c *Client) GetSource(ctx context.Context, collectorID, sourceID int64) (*Source, error) {
+	var body sourceBody
+	resp, err := c.do(ctx, request{method: http.MethodGet, path: pathf("/v1/collectors/%s/sources/%s", collectorID, sourceID), out: &body})
+	if err != nil {
+		return nil, fmt.Errorf("error getting source: %w", err)
+	}
+	body.Source.ETag = resp.Header.Get("ETag")
+	return &body.Source, nil
+}
+
+// CreateSource creates a source under the collector and returns it.
+func (c *Client) CreateSource(ctx context.Context, collectorID int64, source Source) (*Source, error) {
+	if source.SourceType == "" {
+		return nil, fmt.Errorf("error creating source: source type is required")
+	}
+	var body sourceBody
+	resp, err := c.do(ctx, request{method: http.MethodPost, path: pathf("/v1/collectors/%s/sources", collectorID), body: sourceBody{source}, out: &body})
+	if err != nil {
+		return nil, fmt.Errorf("error creating source: %w", err)
+	}
+	body.Source.ETag = resp.Header.Get("ETag")
+	return &body.Source, nil
+}
+
+// CreateHTTPSource creates an HTTP source under the collector and returns
+// it, including its upload URL.
+func (c *Client) CreateHTTPSource(ctx context.Context, collectorID int64, source Source) (*Source, error) {
+	source.SourceType = SourceHTTP
+	created, err := c.CreateSource(ctx, collectorID, source)
+	if err != nil {
+		return nil, err
+	}
+	if created.URL == "" {
+		// The URL is not included in every response, so read it back.
+		return c.GetSource(ctx, collectorID, created.ID)
+	}
+	return created, nil
+}
+
+// CreateTracesSource creates an HTTP Traces source under the collector and
+// returns it, including its upload URL. Use Source.TraceEndpoint to post
+// traces to it.
+func (c *Client) CreateTracesSource(ctx context.Context, collectorID int64, source Source) (*Source, error) {
+	source.ContentType = SourceContentTypeTraces
+	source.RUM = nil
+	return c.CreateHTTPSource(ctx, collectorID, source)
+}
+
+// CreateRUMSource creates a RUM source under the collector and returns it,
+// including its upload URL, which the RUM script is configured with as its
+// collection source URL. The service name of the settings is required.
+func (c *Client) CreateRUMSource(ctx context.Context, collectorID int64, source Source, rum RUMSettings) (*Source, error) {
+	if rum.ServiceName == "" {
+		return nil, fmt.Errorf("error creating source: service name is required for a rum source")
+	}
+	rum.Type = "RumPath"
+	source.ContentType = SourceContentTypeRUM
+	source.RUM = &rum
+	return c.CreateHTTPSource(ctx, collectorID, source)
+}
+
+// HTTPSourceURL returns the upload URL of an HTTP source.
+func (c *Client) HTTPSourceURL(ctx context.Context, collectorID, sourceID int64) (string, error) {
+	s, err := c.GetSource(ctx, collectorID, sourceID)
+	if err != nil {
+		return "", err
+	}
+	if s.URL == "" {
+		return "", fmt.Errorf("source %d has no upload url", sourceID)
+	}
+	return s.URL, nil
+}
+
+// UpdateSource replaces the source with the same ID under the collector and
+// returns the updated source. If the source has an ETag the update is
+// conditional on it, see Source.ETag.
+func (c *Client) UpdateSource(ctx context.Context, collectorID int64, source Source) (*Source, error) {
+	var body sourceBody
+	r := request{
+		method: http.MethodPut,
+		path:   pathf("/v1/collectors/%s/sources/%s", collectorID, source.ID),
+		header: ifMatch(source.ETag),
+		body:   sourceBody{source},
+		out:    &body,
+	}
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("error updating source: %w", err)
+	}
+	body.Source.ETag = resp.Header.Get("ETag")
+	return &body.Source, nil
+}
+
+// DeleteSource deletes the source with the ID from the collector.
+func (c *Client) DeleteSource(ctx context.Context, collectorID, sourceID int64) error {
+	if err := c.delete(ctx, pathf("/v1/collectors/%s/sources/%s", collectorID, sourceID)); err != nil {
+		return fmt.Errorf("error deleting source: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/byitkc/gosumo"
+)
+
+func TestCreateSourceDefaults(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/collectors/1/sources", `{"source": {"id": 2, "name": "app", "sourceType": "HTTP", "url": "https://collectors.sumologic.com/receiver/v1/http/x"}}`)
+	c := api.client()
+	off := false
+	tests := []struct {
+		name   string
+		source Source
+		want   map[string]any
+	}{
+		// The API turns date parsing and multiline processing on when they
+		// are not sent.
+		{"zero value", Source{Name: "app"}, map[string]any{}},
+		{"turned off", Source{Name: "app", AutomaticDateParsing: &off, MultilineProcessingEnabled: &off}, map[string]any{
+			"automaticDateParsing":       false,
+			"multilineProcessingEnabled": false,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.CreateHTTPSource(context.Background(), 1, tt.source); err != nil {
+				t.Fatal(err)
+			}
+			r := api.last()
+			checkRequest(t, r, http.MethodPost, "/v1/collectors/1/sources", "")
+			var body struct {
+				Source map[string]any `json:"source"`
+			}
+			if err := json.Unmarshal([]byte(r.body), &body); err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range []string{"automaticDateParsing", "multilineProcessingEnabled"} {
+				got, ok := body.Source[key]
+				want, wantOK := tt.want[key]
+				if ok != wantOK || got != want {
+					t.Errorf("%s = %v (sent %v), want %v (sent %v)", key, got, ok, want, wantOK)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateHTTPSourceReadsURL(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/collectors/1/sources", `{"source": {"id": 2, "name": "app", "sourceType": "HTTP"}}`)
+	api.respond("GET /v1/collectors/1/sources/2", `{"source": {"id": 2, "name": "app", "sourceType": "HTTP", "url": "https://collectors.sumologic.com/receiver/v1/http/x"}}`)
+	s, err := api.client().CreateHTTPSource(context.Background(), 1, Source{Name: "app", Category: "prod/app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs := api.received()
+	if len(reqs) != 2 {
+		t.Fatalf("received %d requests, want the create and a get of the URL", len(reqs))
+	}
+	checkRequest(t, reqs[0], http.MethodPost, "/v1/collectors/1/sources",
+		`{"source": {"name": "app", "category": "prod/app", "sourceType": "HTTP", "useAutolineMatching": false, "forceTimeZone": false}}`)
+	checkRequest(t, reqs[1], http.MethodGet, "/v1/collectors/1/sources/2", "")
+	if s.URL != "https://collectors.sumologic.com/receiver/v1/http/x" {
+		t.Errorf("URL = %q", s.URL)
+	}
+	client, err := s.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client == nil {
+		t.Error("NewClient returned nil")
+	}
+	if _, err := (Source{ID: 3}).LogEndpoint(); err == nil {
+		t.Error("LogEndpoint of a source without a URL succeeded")
+	}
+}
+
+func TestCreateSourceRequiresType(t *testing.T) {
+	api := newTestAPI(t)
+	if _, err := api.client().CreateSource(context.Background(), 1, Source{Name: "app"}); err == nil {
+		t.Error("CreateSource without a source type succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
+
+func TestListSources(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("GET /v1/collectors/1/sources", `{"sources": [{"id": 2, "name": "app", "sourceType": "HTTP", "hashAlgorithm": "SHA-256"}]}`)
+	sources, err := api.client().ListSources(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.last(), http.MethodGet, "/v1/collectors/1/sources", "")
+	if len(sources) != 1 || sources[0].ID != 2 || string(sources[0].Extra["hashAlgorithm"]) != `"SHA-256"` {
+		t.Errorf("ListSources = %+v", sources)
+	}
+}
+
+func TestUpdateSource(t *testing.T) {
+	api := newTestAPI(t)
+	api.handle("PUT /v1/collectors/1/sources/2", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Match") != `"v1"` {
+			http.Error(w, `{"errors": [{"code": "sources.version.mismatch"}]}`, http.StatusPreconditionFailed)
+			return
+		}
+		w.Header().Set("ETag", `"v2"`)
+		io.WriteString(w, `{"source": {"id": 2, "name": "app", "sourceType": "HTTP"}}`)
+	})
+	c := api.client()
+	source := Source{ID: 2, Name: "app", SourceType: SourceHTTP, ETag: `"v1"`, Extra: map[string]json.RawMessage{"hashAlgorithm": json.RawMessage(`"SHA-256"`)}}
+	got, err := c.UpdateSource(context.Background(), 1, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ETag != `"v2"` {
+		t.Errorf("ETag = %q, want the new version", got.ETag)
+	}
+	checkRequest(t, api.last(), http.MethodPut, "/v1/collectors/1/sources/2",
+		`{"source": {"id": 2, "name": "app", "sourceType": "HTTP", "useAutolineMatching": false, "forceTimeZone": false, "hashAlgorithm": "SHA-256"}}`)
+
+	source.ETag = `"v0"`
+	if _, err := c.UpdateSource(context.Background(), 1, source); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("UpdateSource with a stale ETag = %v, want ErrPreconditionFailed", err)
+	}
+}
+
+func TestDeleteSource(t *testing.T) {
+	api := newTestAPI(t)
+	api.handle("DELETE /v1/collectors/1/sources/2", func(w http.ResponseWriter, r *http.Request) {})
+	if err := api.client().DeleteSource(context.Background(), 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.last(), http.MethodDelete, "/v1/collectors/1/sources/2", "")
+}
+
+func TestCreateTracesSource(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/collectors/1/sources", `{"source": {"id": 2, "name": "traces", "sourceType": "HTTP", "contentType": "Zipkin", "url": "https://collectors.sumologic.com/receiver/v1/trace/x"}}`)
+	// Settings of a RUM source are not sent for a traces source.
+	s, err := api.client().CreateTracesSource(context.Background(), 1, Source{Name: "traces", RUM: &RUMSettings{ServiceName: "web"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.last(), http.MethodPost, "/v1/collectors/1/sources",
+		`{"source": {"name": "traces", "sourceType": "HTTP", "contentType": "Zipkin", "useAutolineMatching": false, "forceTimeZone": false}}`)
+	if s.ContentType != SourceContentTypeTraces {
+		t.Errorf("ContentType = %q", s.ContentType)
+	}
+}
+
+func TestCreateRUMSource(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/collectors/1/sources", `{"source": {"id": 2, "name": "rum", "sourceType": "HTTP", "contentType": "Rum", "url": "https://rum.example.com/x", "path": {"type": "RumPath", "serviceName": "web", "samplingRate": 0.5, "selectedCountries": []}}}`)
+	c := api.client()
+	s, err := c.CreateRUMSource(context.Background(), 1, Source{Name: "rum"}, RUMSettings{
+		ServiceName:  "web",
+		SamplingRate: 0.5,
+		IgnoreURLs:   []string{"/health"},
+		CustomTags:   map[string]string{"team": "web"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.last(), http.MethodPost, "/v1/collectors/1/sources", `{"source": {
+		"name": "rum", "sourceType": "HTTP", "contentType": "Rum", "useAutolineMatching": false, "forceTimeZone": false,
+		"path": {"type": "RumPath", "serviceName": "web", "samplingRate": 0.5, "ignoreUrls": ["/health"], "customTags": {"team": "web"}}
+	}}`)
+	if s.RUM == nil || s.RUM.SamplingRate != 0.5 || string(s.RUM.Extra["selectedCountries"]) != "[]" {
+		t.Errorf("RUM = %+v", s.RUM)
+	}
+
+	if _, err := c.CreateRUMSource(context.Background(), 1, Source{Name: "rum"}, RUMSettings{}); err == nil {
+		t.Error("CreateRUMSource without a service name succeeded")
+	}
+	if n := len(api.received()); n != 1 {
+		t.Errorf("received %d requests, want only the first create", n)
+	}
+}
+
+func TestSourceTraceEndpoint(t *testing.T) {
+	var path, contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+	}))
+	defer srv.Close()
+
+	s := Source{ID: 2, URL: srv.URL + "/receiver/v1/trace/x/"}
+	// The options are passed to the gosumo.Client of the endpoint.
+	endpoint, err := s.TraceEndpoint(gosumo.WithInsecureURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := endpoint.PostTraces(context.Background(), []byte("{}"), gosumo.TraceFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/receiver/v1/trace/x/v1/traces" || contentType != gosumo.ContentTypeJSON {
+		t.Errorf("posted to %s as %s, want the source URL with /v1/traces as JSON", path, contentType)
+	}
+
+	if _, err := (Source{ID: 3}).TraceEndpoint(); err == nil {
+		t.Error("TraceEndpoint of a source without a URL succeeded")
+	}
+}
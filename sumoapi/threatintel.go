@@ -0,0 +1,208 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ThreatIndicatorBatchSize is the number of indicators uploaded per request
+// by UploadThreatIndicators.
+const ThreatIndicatorBatchSize = 1000
+
+// Types of a threat indicator, named after the STIX cyber observables.
+const (
+	ThreatIndicatorIPv4       = "ipv4-addr"
+	ThreatIndicatorIPv6       = "ipv6-addr"
+	ThreatIndicatorDomainName = "domain-name"
+	ThreatIndicatorURL        = "url"
+	ThreatIndicatorEmail      = "email-addr"
+	ThreatIndicatorFileSHA256 = "file:hashes.'SHA-256'"
+	ThreatIndicatorFileSHA1   = "file:hashes.'SHA-1'"
+	ThreatIndicatorFileMD5    = "file:hashes.MD5"
+)
+
+// ThreatIntelSource is a source of threat intelligence indicators in Cloud
+// SIEM.
+type ThreatIntelSource struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// SourceType is "CUSTOM" for sources indicators are uploaded to.
+	SourceType  string  `json:"sourceType,omitempty"`
+	Created     CSETime `json:"created"`
+	CreatedBy   string  `json:"createdBy,omitempty"`
+	LastUpdated CSETime `json:"lastUpdated"`
+
+	// Extra holds the properties of the threat intel source that have no
+	// field, which are kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the threat intel source, keeping the properties that
+// have no field in Extra.
+func (s *ThreatIntelSource) UnmarshalJSON(b []byte) error {
+	type plain ThreatIntelSource
+	return unmarshalExtra(b, (*plain)(s), &s.Extra)
+}
+
+// MarshalJSON encodes the threat intel source along with the properties in
+// Extra.
+func (s ThreatIntelSource) MarshalJSON() ([]byte, error) {
+	type plain ThreatIntelSource
+	return marshalExtra(plain(s), s.Extra)
+}
+
+// ThreatIndicator is a threat intelligence indicator in the normalized
+// format, which holds the usual properties of a STIX indicator without its
+// pattern syntax.
+type ThreatIndicator struct {
+	// ID identifies the indicator within its source. Uploading an
+	// indicator with the same ID replaces it.
+	ID string `json:"id"`
+	// Value is the value matched, such as 192.0.2.10 for
+	// ThreatIndicatorIPv4.
+	Value string `json:"indicator"`
+	// Type is one of the ThreatIndicator constants.
+	Type string `json:"type"`
+	// Source is the name of the source of the indicator. It defaults to
+	// the source uploaded to.
+	Source    string    `json:"source,omitempty"`
+	ValidFrom time.Time `json:"validFrom"`
+	// ValidUntil is when the indicator expires. It never does when nil.
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
+	// Confidence is from 0 to 100.
+	Confidence int `json:"confidence,omitempty"`
+	// ThreatType is the kind of threat, from the STIX indicator type
+	// vocabulary such as "malicious-activity".
+	ThreatType string   `json:"threatType,omitempty"`
+	Actors     []string `json:"actors,omitempty"`
+	// KillChain is the phases of the kill chain, such as "reconnaissance".
+	KillChain []string `json:"killChain,omitempty"`
+	// Fields holds further properties of the indicator.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// ListThreatIntelSources returns every threat intelligence source.
+func (c *Client) ListThreatIntelSources(ctx context.Context) ([]ThreatIntelSource, error) {
+	return Collect(c.IterateThreatIntelSources(ctx))
+}
+
+// IterateThreatIntelSources returns an Iterator over the threat intel
+// sources returned by ListThreatIntelSources, requesting each page as it is
+// read.
+func (c *Client) IterateThreatIntelSources(ctx context.Context) *Iterator[ThreatIntelSource] {
+	return cseIterate[ThreatIntelSource](ctx, c, "listing threat intel sources", "/sec/v1/threat-intel-sources", nil)
+}
+
+// GetThreatIntelSource returns the threat intelligence source with the ID.
+func (c *Client) GetThreatIntelSource(ctx context.Context, id string) (*ThreatIntelSource, error) {
+	var resp cseResponse[ThreatIntelSource]
+	if err := c.get(ctx, pathf("/sec/v1/threat-intel-sources/%s", id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("error getting threat intel source: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// CreateThreatIntelSource creates a custom threat intelligence source and
+// returns it.
+func (c *Client) CreateThreatIntelSource(ctx context.Context, name, description string) (*ThreatIntelSource, error) {
+	if name == "" {
+		return nil, fmt.Errorf("error creating threat intel source: name is required")
+	}
+	body := struct {
+		Fields ThreatIntelSource `json:"fields"`
+	}{ThreatIntelSource{Name: name, Description: description, SourceType: "CUSTOM"}}
+	var resp cseResponse[ThreatIntelSource]
+	if err := c.post(ctx, "/sec/v1/threat-intel-sources", body, &resp); err != nil {
+		return nil, fmt.Errorf("error creating threat intel source: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// UpdateThreatIntelSource changes the name and description of the threat
+// intelligence source with the same ID and returns the updated source.
+func (c *Client) UpdateThreatIntelSource(ctx context.Context, source ThreatIntelSource) (*ThreatIntelSource, error) {
+	body := struct {
+		Fields struct {
+			Name        string `json:"name"`
+			Description string `json:"description,omitempty"`
+		} `json:"fields"`
+	}{}
+	body.Fields.Name, body.Fields.Description = source.Name, source.Description
+	var resp cseResponse[ThreatIntelSource]
+	if err := c.put(ctx, pathf("/sec/v1/threat-intel-sources/%s", source.ID), body, &resp); err != nil {
+		return nil, fmt.Errorf("error updating threat intel source: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// DeleteThreatIntelSource deletes the threat intelligence source with the
+// ID along with its indicators.
+func (c *Client) DeleteThreatIntelSource(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/sec/v1/threat-intel-sources/%s", id)); err != nil {
+		return fmt.Errorf("error deleting threat intel source: %w", err)
+	}
+	return nil
+}
+
+// UploadThreatIndicators uploads the indicators to the source with the name,
+// in batches of ThreatIndicatorBatchSize. If a batch fails, the indicators
+// of the previous batches have already been uploaded, and the error reports
+// how many.
+func (c *Client) UploadThreatIndicators(ctx context.Context, source string, indicators []ThreatIndicator) error {
+	for i, ind := range indicators {
+		if ind.ID == "" || ind.Value == "" || ind.Type == "" {
+			return fmt.Errorf("error uploading threat indicators: indicator %d: id, value and type are required", i)
+		}
+		if ind.ValidFrom.IsZero() {
+			return fmt.Errorf("error uploading threat indicators: indicator %d: valid from is required", i)
+		}
+	}
+	for start := 0; start < len(indicators); start += ThreatIndicatorBatchSize {
+		batch := indicators[start:min(start+ThreatIndicatorBatchSize, len(indicators))]
+		body := struct {
+			Source     string            `json:"source"`
+			Indicators []ThreatIndicator `json:"indicators"`
+		}{source, batch}
+		if err := c.post(ctx, "/v1/threatIntel/datastore/indicators/normalized", body, nil); err != nil {
+			return fmt.Errorf("error uploading threat indicators: %d of %d uploaded: %w", start, len(indicators), err)
+		}
+	}
+	return nil
+}
+
+// UploadSTIXIndicators uploads STIX 2.1 indicator objects, as they are
+// published by threat feeds, to the source with the name.
+func (c *Client) UploadSTIXIndicators(ctx context.Context, source string, indicators []json.RawMessage) error {
+	for start := 0; start < len(indicators); start += ThreatIndicatorBatchSize {
+		batch := indicators[start:min(start+ThreatIndicatorBatchSize, len(indicators))]
+		body := struct {
+			Source     string            `json:"source"`
+			Indicators []json.RawMessage `json:"indicators"`
+		}{source, batch}
+		if err := c.post(ctx, "/v1/threatIntel/datastore/indicators/stix", body, nil); err != nil {
+			return fmt.Errorf("error uploading stix indicators: %d of %d uploaded: %w", start, len(indicators), err)
+		}
+	}
+	return nil
+}
+
+// DeleteThreatIndicators deletes the indicators with the IDs from the source
+// with the name.
+func (c *Client) DeleteThreatIndicators(ctx context.Context, source string, ids ...string) error {
+	r := request{
+		method: http.MethodDelete,
+		path:   "/v1/threatIntel/datastore/indicators",
+		body: struct {
+			Source       string   `json:"source"`
+			IndicatorIDs []string `json:"indicatorIds"`
+		}{source, ids},
+	}
+	if _, err := c.do(ctx, r); err != nil {
+		return fmt.Errorf("error deleting threat indicators: %w", err)
+	}
+	return nil
+}
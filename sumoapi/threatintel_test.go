@@ -0,0 +1,163 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestThreatIntelSources(t *testing.T) {
+	ctx := context.Background()
+	source := `{"data": {"id": "s1", "name": "feeds", "sourceType": "CUSTOM", "created": "2026-01-02T03:04:05", "indicatorCount": 12}}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				sources, err := c.ListThreatIntelSources(ctx)
+				if err != nil {
+					return err
+				}
+				return expect(sources[0].Name, "feeds")
+			},
+			response: `{"data": {"objects": [{"id": "s1", "name": "feeds"}]}}`,
+			method:   http.MethodGet,
+			path:     "/sec/v1/threat-intel-sources",
+			query:    "limit=100&offset=0",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				s, err := c.GetThreatIntelSource(ctx, "s1")
+				if err != nil {
+					return err
+				}
+				return expect(string(s.Extra["indicatorCount"]), "12")
+			},
+			response: source,
+			method:   http.MethodGet,
+			path:     "/sec/v1/threat-intel-sources/s1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateThreatIntelSource(ctx, "feeds", "partner feeds")
+				return err
+			},
+			response: source,
+			method:   http.MethodPost,
+			path:     "/sec/v1/threat-intel-sources",
+			body:     `{"fields": {"name": "feeds", "description": "partner feeds", "sourceType": "CUSTOM", "created": null, "lastUpdated": null}}`,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateThreatIntelSource(ctx, ThreatIntelSource{ID: "s1", Name: "feeds", SourceType: "CUSTOM"})
+				return err
+			},
+			response: source,
+			method:   http.MethodPut,
+			path:     "/sec/v1/threat-intel-sources/s1",
+			body:     `{"fields": {"name": "feeds"}}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteThreatIntelSource(ctx, "s1") },
+			method: http.MethodDelete,
+			path:   "/sec/v1/threat-intel-sources/s1",
+		},
+		{
+			name:   "delete indicators",
+			call:   func(c *Client) error { return c.DeleteThreatIndicators(ctx, "feeds", "i1", "i2") },
+			method: http.MethodDelete,
+			path:   "/v1/threatIntel/datastore/indicators",
+			body:   `{"source": "feeds", "indicatorIds": ["i1", "i2"]}`,
+		},
+		{
+			name: "upload stix",
+			call: func(c *Client) error {
+				return c.UploadSTIXIndicators(ctx, "feeds", []json.RawMessage{json.RawMessage(`{"type": "indicator", "id": "indicator--1"}`)})
+			},
+			method: http.MethodPost,
+			path:   "/v1/threatIntel/datastore/indicators/stix",
+			body:   `{"source": "feeds", "indicators": [{"type": "indicator", "id": "indicator--1"}]}`,
+		},
+	})
+}
+
+func TestUploadThreatIndicators(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("POST /v1/threatIntel/datastore/indicators/normalized", `{}`)
+	validFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := validFrom.Add(24 * time.Hour)
+	indicator := ThreatIndicator{ID: "i1", Value: "10.0.0.1", Type: ThreatIndicatorIPv4, ValidFrom: validFrom, ValidUntil: &until, Confidence: 80, Fields: map[string]string{"feed": "partner"}}
+	if err := api.client().UploadThreatIndicators(context.Background(), "feeds", []ThreatIndicator{indicator}); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, api.last(), http.MethodPost, "/v1/threatIntel/datastore/indicators/normalized", `{
+		"source": "feeds",
+		"indicators": [{
+			"id": "i1", "indicator": "10.0.0.1", "type": "ipv4-addr",
+			"validFrom": "2026-01-01T00:00:00Z", "validUntil": "2026-01-02T00:00:00Z",
+			"confidence": 80, "fields": {"feed": "partner"}
+		}]
+	}`)
+}
+
+func TestUploadThreatIndicatorsBatches(t *testing.T) {
+	api := newTestAPI(t)
+	var n atomic.Int32
+	api.handle("POST /v1/threatIntel/datastore/indicators/normalized", func(w http.ResponseWriter, r *http.Request) {
+		if n.Add(1) == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors": [{"code": "bad.indicator"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	})
+	indicators := make([]ThreatIndicator, 2*ThreatIndicatorBatchSize+1)
+	for i := range indicators {
+		indicators[i] = ThreatIndicator{ID: fmt.Sprint(i), Value: "10.0.0.1", Type: ThreatIndicatorIPv4, ValidFrom: time.Now()}
+	}
+	err := api.client().UploadThreatIndicators(context.Background(), "feeds", indicators)
+	if err == nil || !strings.Contains(err.Error(), "1000 of 2001 uploaded") {
+		t.Errorf("UploadThreatIndicators = %v, want the number uploaded before the failure", err)
+	}
+	reqs := api.received()
+	if len(reqs) != 2 {
+		t.Fatalf("received %d requests, want the upload to stop at the failed batch", len(reqs))
+	}
+	var batch struct {
+		Indicators []ThreatIndicator `json:"indicators"`
+	}
+	if err := json.Unmarshal([]byte(reqs[1].body), &batch); err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.Indicators) != ThreatIndicatorBatchSize || batch.Indicators[0].ID != "1000" {
+		t.Errorf("second batch has %d indicators starting at %s", len(batch.Indicators), batch.Indicators[0].ID)
+	}
+}
+
+func TestThreatIntelValidation(t *testing.T) {
+	api := newTestAPI(t)
+	c := api.client()
+	if _, err := c.CreateThreatIntelSource(context.Background(), "", ""); err == nil {
+		t.Error("CreateThreatIntelSource without a name succeeded")
+	}
+	tests := []ThreatIndicator{
+		{Value: "10.0.0.1", Type: ThreatIndicatorIPv4, ValidFrom: time.Now()},
+		{ID: "i1", Value: "10.0.0.1", Type: ThreatIndicatorIPv4},
+	}
+	for _, ind := range tests {
+		if err := c.UploadThreatIndicators(context.Background(), "feeds", []ThreatIndicator{ind}); err == nil {
+			t.Errorf("UploadThreatIndicators(%+v) succeeded", ind)
+		}
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
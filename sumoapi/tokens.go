@@ -0,0 +1,130 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TokenCollectorRegistration is the type of a token used to register
+// installed collectors.
+const TokenCollectorRegistration = "CollectorRegistration"
+
+// Token statuses.
+const (
+	TokenActive   = "Active"
+	TokenInactive = "Inactive"
+)
+
+// Token is a collector registration token, which installed collectors use in
+// place of an access key to register with the account.
+type Token struct {
+	ID          string `json:"id,omitempty"`
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Status is TokenActive or TokenInactive. Collectors cannot register
+	// with an inactive token.
+	Status string `json:"status"`
+	// EncodedTokenAndURL is the value passed to the collector installer.
+	EncodedTokenAndURL string    `json:"encodedTokenAndUrl,omitempty"`
+	Version            int       `json:"version,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+	CreatedBy          string    `json:"createdBy,omitempty"`
+	ModifiedAt         time.Time `json:"modifiedAt"`
+	ModifiedBy         string    `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the token that have no field, which are
+	// kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the token, keeping the properties that have no field
+// in Extra.
+func (t *Token) UnmarshalJSON(b []byte) error {
+	type plain Token
+	return unmarshalExtra(b, (*plain)(t), &t.Extra)
+}
+
+// MarshalJSON encodes the token along with the properties in Extra.
+func (t Token) MarshalJSON() ([]byte, error) {
+	type plain Token
+	return marshalExtra(plain(t), t.Extra)
+}
+
+// ListTokens returns every token of the account.
+func (c *Client) ListTokens(ctx context.Context) ([]Token, error) {
+	var resp struct {
+		Data []Token `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/tokens", nil, &resp); err != nil {
+		return nil, fmt.Errorf("error listing tokens: %w", err)
+	}
+	return resp.Data, nil
+}
+
+// GetToken returns the token with the ID.
+func (c *Client) GetToken(ctx context.Context, id string) (*Token, error) {
+	var t Token
+	if err := c.get(ctx, pathf("/v1/tokens/%s", id), nil, &t); err != nil {
+		return nil, fmt.Errorf("error getting token: %w", err)
+	}
+	return &t, nil
+}
+
+// CreateToken creates a collector registration token with the name and
+// returns it, including its EncodedTokenAndURL.
+func (c *Client) CreateToken(ctx context.Context, name, description string) (*Token, error) {
+	if name == "" {
+		return nil, fmt.Errorf("error creating token: name is required")
+	}
+	body := struct {
+		Type        string `json:"type"`
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Status      string `json:"status"`
+	}{TokenCollectorRegistration, name, description, TokenActive}
+	var created Token
+	if err := c.post(ctx, "/v1/tokens", body, &created); err != nil {
+		return nil, fmt.Errorf("error creating token: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateToken changes the name, description and status of the token with
+// the same ID and returns the updated token. The Version must match the
+// current version of the token.
+func (c *Client) UpdateToken(ctx context.Context, token Token) (*Token, error) {
+	if token.Type == "" {
+		token.Type = TokenCollectorRegistration
+	}
+	body := struct {
+		Type        string `json:"type"`
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Status      string `json:"status"`
+		Version     int    `json:"version"`
+	}{token.Type, token.Name, token.Description, token.Status, token.Version}
+	var updated Token
+	if err := c.put(ctx, pathf("/v1/tokens/%s", token.ID), body, &updated); err != nil {
+		return nil, fmt.Errorf("error updating token: %w", err)
+	}
+	return &updated, nil
+}
+
+// RevokeToken deactivates the token, so no more collectors can register
+// with it, and returns the updated token. Collectors already registered
+// with it are not affected.
+func (c *Client) RevokeToken(ctx context.Context, token Token) (*Token, error) {
+	token.Status = TokenInactive
+	return c.UpdateToken(ctx, token)
+}
+
+// DeleteToken deletes the token with the ID.
+func (c *Client) DeleteToken(ctx context.Context, id string) error {
+	if err := c.delete(ctx, pathf("/v1/tokens/%s", id)); err != nil {
+		return fmt.Errorf("error deleting token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	ctx := context.Background()
+	token := `{"id": "t1", "type": "CollectorRegistration", "name": "prod", "status": "Active", "encodedTokenAndUrl": "abc", "version": 2, "lastUsed": null}`
+	testCalls(t, []apiCall{
+		{
+			name: "list",
+			call: func(c *Client) error {
+				tokens, err := c.ListTokens(ctx)
+				if err != nil {
+					return err
+				}
+				if len(tokens) != 1 {
+					return expect(len(tokens), 1)
+				}
+				return expect(string(tokens[0].Extra["lastUsed"]), "null")
+			},
+			response: `{"data": [` + token + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/tokens",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				tok, err := c.GetToken(ctx, "t1")
+				if err != nil {
+					return err
+				}
+				return expect(tok.EncodedTokenAndURL, "abc")
+			},
+			response: token,
+			method:   http.MethodGet,
+			path:     "/v1/tokens/t1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateToken(ctx, "prod", "production collectors")
+				return err
+			},
+			response: token,
+			method:   http.MethodPost,
+			path:     "/v1/tokens",
+			body:     `{"type": "CollectorRegistration", "name": "prod", "description": "production collectors", "status": "Active"}`,
+		},
+		{
+			name: "revoke",
+			call: func(c *Client) error {
+				_, err := c.RevokeToken(ctx, Token{ID: "t1", Name: "prod", Status: TokenActive, Version: 2, EncodedTokenAndURL: "abc"})
+				return err
+			},
+			response: token,
+			method:   http.MethodPut,
+			path:     "/v1/tokens/t1",
+			body:     `{"type": "CollectorRegistration", "name": "prod", "status": "Inactive", "version": 2}`,
+		},
+		{
+			name:   "delete",
+			call:   func(c *Client) error { return c.DeleteToken(ctx, "t1") },
+			method: http.MethodDelete,
+			path:   "/v1/tokens/t1",
+		},
+	})
+}
@@ -0,0 +1,277 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrSpanQueryFailed is returned, wrapped with the reason, when a span query
+// fails.
+var ErrSpanQueryFailed = errors.New("span query failed")
+
+// Span is a span of a trace.
+type Span struct {
+	ID       string
+	TraceID  string
+	ParentID string
+	// Name is the operation of the span, such as GET /api/orders.
+	Name    string
+	Service string
+	// Kind is the kind of the span, such as "SERVER" or "CLIENT".
+	Kind       string
+	StatusCode string
+	Start      time.Time
+	Duration   time.Duration
+	// Fields holds the attributes of the span and its resource.
+	Fields map[string]string
+}
+
+// Trace summarizes a trace.
+type Trace struct {
+	ID             string
+	RootService    string
+	RootOperation  string
+	Start          time.Time
+	Duration       time.Duration
+	NumberOfSpans  int
+	NumberOfErrors int
+	Services       []string
+}
+
+// SpanQuery selects spans over a time range.
+type SpanQuery struct {
+	// Query selects the spans, in the span analytics query language, such
+	// as service=checkout and statusCode=ERROR.
+	Query string
+	From  time.Time
+	To    time.Time
+	// Limit bounds the number of spans returned by SearchSpans. There is no
+	// limit when it is zero.
+	Limit int
+}
+
+// SpanMetrics aggregates the spans matched by a SpanQuery for each group.
+type SpanMetrics struct {
+	// Group is the value of each field the spans were grouped by.
+	Group       map[string]string
+	Count       int64
+	ErrorCount  int64
+	AvgDuration time.Duration
+	P50Duration time.Duration
+	P95Duration time.Duration
+	P99Duration time.Duration
+}
+
+// SearchSpans returns the spans matching the query.
+func (c *Client) SearchSpans(ctx context.Context, q SpanQuery) ([]Span, error) {
+	queryID, err := c.runSpanQuery(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error searching spans: %w", err)
+	}
+	query := url.Values{"limit": {strconv.Itoa(listPageSize)}}
+	var spans []Span
+	for {
+		var page struct {
+			SpanPage []spanResponse `json:"spanPage"`
+			Next     string         `json:"next"`
+		}
+		if err := c.get(ctx, pathf("/v1/spans/spanQuery/%s/rows/%s/spans", queryID, "A"), query, &page); err != nil {
+			return nil, fmt.Errorf("error searching spans: %w", err)
+		}
+		for _, s := range page.SpanPage {
+			spans = append(spans, s.span())
+			if q.Limit > 0 && len(spans) >= q.Limit {
+				return spans, nil
+			}
+		}
+		if page.Next == "" {
+			return spans, nil
+		}
+		query.Set("token", page.Next)
+	}
+}
+
+// SpanMetrics returns the number, errors and duration percentiles of the
+// spans matching the query, grouped by the fields, such as service and
+// operationName. Every span is in a single group when there are no fields.
+func (c *Client) SpanMetrics(ctx context.Context, q SpanQuery, groupBy ...string) ([]SpanMetrics, error) {
+	queryID, err := c.runSpanQuery(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error getting span metrics: %w", err)
+	}
+	query := url.Values{}
+	for _, g := range groupBy {
+		query.Add("groupBy", g)
+	}
+	var resp struct {
+		Aggregates []struct {
+			Group       map[string]string `json:"group"`
+			Count       int64             `json:"count"`
+			ErrorCount  int64             `json:"errorCount"`
+			AvgDuration int64             `json:"avgDuration"`
+			P50Duration int64             `json:"p50Duration"`
+			P95Duration int64             `json:"p95Duration"`
+			P99Duration int64             `json:"p99Duration"`
+		} `json:"aggregates"`
+	}
+	if err := c.get(ctx, pathf("/v1/spans/spanQuery/%s/rows/%s/aggregates", queryID, "A"), query, &resp); err != nil {
+		return nil, fmt.Errorf("error getting span metrics: %w", err)
+	}
+	metrics := make([]SpanMetrics, len(resp.Aggregates))
+	for i, a := range resp.Aggregates {
+		// Durations are returned in nanoseconds.
+		metrics[i] = SpanMetrics{
+			Group:       a.Group,
+			Count:       a.Count,
+			ErrorCount:  a.ErrorCount,
+			AvgDuration: time.Duration(a.AvgDuration),
+			P50Duration: time.Duration(a.P50Duration),
+			P95Duration: time.Duration(a.P95Duration),
+			P99Duration: time.Duration(a.P99Duration),
+		}
+	}
+	return metrics, nil
+}
+
+// GetTrace returns the summary of the trace with the ID.
+func (c *Client) GetTrace(ctx context.Context, traceID string) (*Trace, error) {
+	var resp struct {
+		ID               string `json:"id"`
+		RootServiceName  string `json:"rootServiceName"`
+		RootOperation    string `json:"rootOperationName"`
+		StartTimestamp   int64  `json:"startTimestamp"`
+		DurationNanos    int64  `json:"duration"`
+		NumberOfSpans    int    `json:"numberOfSpans"`
+		NumberOfErrors   int    `json:"numberOfErrors"`
+		ServiceSummaries []struct {
+			Name string `json:"name"`
+		} `json:"serviceSummaries"`
+	}
+	if err := c.get(ctx, pathf("/v1/tracing/traces/%s", traceID), nil, &resp); err != nil {
+		return nil, fmt.Errorf("error getting trace: %w", err)
+	}
+	t := &Trace{
+		ID:             resp.ID,
+		RootService:    resp.RootServiceName,
+		RootOperation:  resp.RootOperation,
+		Start:          time.UnixMilli(resp.StartTimestamp).UTC(),
+		Duration:       time.Duration(resp.DurationNanos),
+		NumberOfSpans:  resp.NumberOfSpans,
+		NumberOfErrors: resp.NumberOfErrors,
+	}
+	for _, s := range resp.ServiceSummaries {
+		t.Services = append(t.Services, s.Name)
+	}
+	return t, nil
+}
+
+// TraceSpans returns every span of the trace with the ID, following
+// pagination.
+func (c *Client) TraceSpans(ctx context.Context, traceID string) ([]Span, error) {
+	return Collect(c.IterateTraceSpans(ctx, traceID))
+}
+
+// IterateTraceSpans returns an Iterator over the spans returned by
+// TraceSpans, requesting each page as it is read.
+func (c *Client) IterateTraceSpans(ctx context.Context, traceID string) *Iterator[Span] {
+	query := url.Values{"limit": {strconv.Itoa(listPageSize)}}
+	return newIterator(ctx, "listing trace spans", func(ctx context.Context, token string) ([]Span, string, error) {
+		if token != "" {
+			query.Set("token", token)
+		}
+		var page struct {
+			SpanPage []spanResponse `json:"spanPage"`
+			Next     string         `json:"next"`
+		}
+		if err := c.get(ctx, pathf("/v1/tracing/traces/%s/spans", traceID), query, &page); err != nil {
+			return nil, "", err
+		}
+		spans := make([]Span, len(page.SpanPage))
+		for i, s := range page.SpanPage {
+			spans[i] = s.span()
+			if spans[i].TraceID == "" {
+				spans[i].TraceID = traceID
+			}
+		}
+		return spans, page.Next, nil
+	})
+}
+
+// runSpanQuery starts a span query and waits for it to finish, returning
+// its ID. The query is its only row, with the row ID A.
+func (c *Client) runSpanQuery(ctx context.Context, q SpanQuery) (string, error) {
+	if q.From.IsZero() || q.To.IsZero() {
+		return "", fmt.Errorf("from and to are required")
+	}
+	type row struct {
+		Query string `json:"query"`
+		RowID string `json:"rowId"`
+	}
+	body := struct {
+		QueryRows []row `json:"queryRows"`
+		TimeRange any   `json:"timeRange"`
+	}{[]row{{q.Query, "A"}}, epochTimeRange(q.From, q.To)}
+	var job struct {
+		QueryID string `json:"queryId"`
+	}
+	if err := c.post(ctx, "/v1/spans/spanQuery", body, &job); err != nil {
+		return "", err
+	}
+	for {
+		var status struct {
+			Status string        `json:"status"`
+			Errors []ErrorDetail `json:"errors"`
+		}
+		if err := c.get(ctx, pathf("/v1/spans/spanQuery/%s/status", job.QueryID), nil, &status); err != nil {
+			return "", err
+		}
+		switch status.Status {
+		case "Finished":
+			return job.QueryID, nil
+		case "Failed":
+			reason := status.Status
+			if len(status.Errors) > 0 {
+				reason = status.Errors[0].Message
+			}
+			return "", fmt.Errorf("%w: %s", ErrSpanQueryFailed, reason)
+		}
+		if err := c.wait(ctx); err != nil {
+			return "", err
+		}
+	}
+}
+
+// spanResponse is a span as returned by the tracing APIs.
+type spanResponse struct {
+	ID             string            `json:"id"`
+	TraceID        string            `json:"traceId"`
+	ParentID       string            `json:"parentId"`
+	OperationName  string            `json:"operationName"`
+	ServiceName    string            `json:"serviceName"`
+	Kind           string            `json:"kind"`
+	StatusCode     string            `json:"statusCode"`
+	StartTimestamp int64             `json:"startTimestamp"`
+	DurationNanos  int64             `json:"duration"`
+	Fields         map[string]string `json:"fields"`
+}
+
+// span converts the response, with timestamps in milliseconds since the
+// epoch and durations in nanoseconds.
+func (s spanResponse) span() Span {
+	return Span{
+		ID:         s.ID,
+		TraceID:    s.TraceID,
+		ParentID:   s.ParentID,
+		Name:       s.OperationName,
+		Service:    s.ServiceName,
+		Kind:       s.Kind,
+		StatusCode: s.StatusCode,
+		Start:      time.UnixMilli(s.StartTimestamp).UTC(),
+		Duration:   time.Duration(s.DurationNanos),
+		Fields:     s.Fields,
+	}
+}
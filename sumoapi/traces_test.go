@@ -0,0 +1,169 @@
+package sumoapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// serveSpanQuery serves a span query q1 whose status is Running and then
+// final.
+func serveSpanQuery(api *testAPI, final string) {
+	api.respond("POST /v1/spans/spanQuery", `{"queryId": "q1"}`)
+	var n atomic.Int32
+	api.handle("GET /v1/spans/spanQuery/q1/status", func(w http.ResponseWriter, r *http.Request) {
+		if n.Add(1) == 1 {
+			io.WriteString(w, `{"status": "Running"}`)
+			return
+		}
+		io.WriteString(w, final)
+	})
+}
+
+func TestSearchSpans(t *testing.T) {
+	api := newTestAPI(t)
+	serveSpanQuery(api, `{"status": "Finished"}`)
+	api.handle("GET /v1/spans/spanQuery/q1/rows/A/spans", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token") == "" {
+			io.WriteString(w, `{"spanPage": [{"id": "s1", "traceId": "t1", "operationName": "GET /", "serviceName": "web", "startTimestamp": 1000, "duration": 2000000, "fields": {"http.status": "200"}}], "next": "p2"}`)
+			return
+		}
+		io.WriteString(w, `{"spanPage": [{"id": "s2"}, {"id": "s3"}], "next": "p3"}`)
+	})
+	from := time.UnixMilli(1000)
+	spans, err := api.client().SearchSpans(context.Background(), SpanQuery{Query: "service=web", From: from, To: from.Add(time.Hour), Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The spans stop at the limit, without requesting the third page.
+	if len(spans) != 2 || spans[1].ID != "s2" {
+		t.Fatalf("SearchSpans = %+v", spans)
+	}
+	s := spans[0]
+	if s.Name != "GET /" || s.Service != "web" || s.Duration != 2*time.Millisecond || !s.Start.Equal(time.UnixMilli(1000)) || s.Fields["http.status"] != "200" {
+		t.Errorf("span = %+v", s)
+	}
+	reqs := api.received()
+	checkPaths(t, reqs,
+		"POST /v1/spans/spanQuery",
+		"GET /v1/spans/spanQuery/q1/status",
+		"GET /v1/spans/spanQuery/q1/status",
+		"GET /v1/spans/spanQuery/q1/rows/A/spans",
+		"GET /v1/spans/spanQuery/q1/rows/A/spans")
+	checkRequest(t, reqs[0], http.MethodPost, "/v1/spans/spanQuery", `{
+		"queryRows": [{"query": "service=web", "rowId": "A"}],
+		"timeRange": {
+			"type": "BeginBoundedTimeRange",
+			"from": {"type": "EpochTimeRangeBoundary", "epochMillis": 1000},
+			"to": {"type": "EpochTimeRangeBoundary", "epochMillis": 3601000}
+		}
+	}`)
+	if got := reqs[4].query.Encode(); got != "limit=100&token=p2" {
+		t.Errorf("second page query = %s", got)
+	}
+}
+
+func TestSpanQueryErrors(t *testing.T) {
+	api := newTestAPI(t)
+	serveSpanQuery(api, `{"status": "Failed", "errors": [{"code": "spans:parse", "message": "bad query"}]}`)
+	c := api.client()
+	if _, err := c.SearchSpans(context.Background(), SpanQuery{Query: "service="}); err == nil {
+		t.Error("SearchSpans without a time range succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+	from := time.UnixMilli(1000)
+	_, err := c.SpanMetrics(context.Background(), SpanQuery{Query: "service=", From: from, To: from.Add(time.Hour)})
+	if !errors.Is(err, ErrSpanQueryFailed) || !strings.HasSuffix(err.Error(), "bad query") {
+		t.Errorf("SpanMetrics of a failed query = %v, want ErrSpanQueryFailed with the reason", err)
+	}
+}
+
+func TestSpanMetrics(t *testing.T) {
+	api := newTestAPI(t)
+	serveSpanQuery(api, `{"status": "Finished"}`)
+	api.respond("GET /v1/spans/spanQuery/q1/rows/A/aggregates", `{"aggregates": [{"group": {"service": "web"}, "count": 10, "errorCount": 1, "avgDuration": 1000000, "p99Duration": 5000000}]}`)
+	from := time.UnixMilli(1000)
+	metrics, err := api.client().SpanMetrics(context.Background(), SpanQuery{Query: "*", From: from, To: from.Add(time.Hour)}, "service", "operation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SpanMetrics{{Group: map[string]string{"service": "web"}, Count: 10, ErrorCount: 1, AvgDuration: time.Millisecond, P99Duration: 5 * time.Millisecond}}
+	if err := expect(metrics, want); err != nil {
+		t.Errorf("SpanMetrics: %v", err)
+	}
+	if got := api.last().query.Encode(); got != "groupBy=service&groupBy=operation" {
+		t.Errorf("query = %s", got)
+	}
+}
+
+func TestTraces(t *testing.T) {
+	ctx := context.Background()
+	testCalls(t, []apiCall{
+		{
+			name: "get",
+			call: func(c *Client) error {
+				tr, err := c.GetTrace(ctx, "t1")
+				if err != nil {
+					return err
+				}
+				return expect(*tr, Trace{
+					ID:             "t1",
+					RootService:    "web",
+					RootOperation:  "GET /",
+					Start:          time.UnixMilli(1000).UTC(),
+					Duration:       3 * time.Millisecond,
+					NumberOfSpans:  4,
+					NumberOfErrors: 1,
+					Services:       []string{"web", "db"},
+				})
+			},
+			response: `{"id": "t1", "rootServiceName": "web", "rootOperationName": "GET /", "startTimestamp": 1000, "duration": 3000000, "numberOfSpans": 4, "numberOfErrors": 1, "serviceSummaries": [{"name": "web"}, {"name": "db"}]}`,
+			method:   http.MethodGet,
+			path:     "/v1/tracing/traces/t1",
+		},
+		{
+			name: "spans",
+			call: func(c *Client) error {
+				spans, err := c.TraceSpans(ctx, "t1")
+				if err != nil {
+					return err
+				}
+				// Spans without a trace ID get the ID of the trace.
+				return expect(spans[0].TraceID, "t1")
+			},
+			response: `{"spanPage": [{"id": "s1", "parentId": "s0"}]}`,
+			method:   http.MethodGet,
+			path:     "/v1/tracing/traces/t1/spans",
+			query:    "limit=100",
+		},
+	})
+}
+
+func TestTraceSpansPages(t *testing.T) {
+	api := newTestAPI(t)
+	api.handle("GET /v1/tracing/traces/t1/spans", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("token") {
+		case "":
+			fmt.Fprint(w, `{"spanPage": [{"id": "s1"}], "next": "p2"}`)
+		case "p2":
+			fmt.Fprint(w, `{"spanPage": [{"id": "s2"}]}`)
+		default:
+			http.Error(w, "unexpected token", http.StatusBadRequest)
+		}
+	})
+	spans, err := api.client().TraceSpans(context.Background(), "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 2 || spans[1].ID != "s2" {
+		t.Errorf("TraceSpans = %+v", spans)
+	}
+}
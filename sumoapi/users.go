@@ -0,0 +1,173 @@
+package sumoapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// User is a user of the account.
+type User struct {
+	ID        string   `json:"id,omitempty"`
+	FirstName string   `json:"firstName"`
+	LastName  string   `json:"lastName"`
+	Email     string   `json:"email"`
+	RoleIDs   []string `json:"roleIds"`
+	// IsActive is false for users that have been disabled.
+	IsActive           bool      `json:"isActive"`
+	IsLocked           bool      `json:"isLocked,omitempty"`
+	IsMFAEnabled       bool      `json:"isMfaEnabled,omitempty"`
+	LastLoginTimestamp time.Time `json:"lastLoginTimestamp"`
+	CreatedAt          time.Time `json:"createdAt"`
+	CreatedBy          string    `json:"createdBy,omitempty"`
+	ModifiedAt         time.Time `json:"modifiedAt"`
+	ModifiedBy         string    `json:"modifiedBy,omitempty"`
+
+	// Extra holds the properties of the user that have no field, which are
+	// kept when it is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the user, keeping the properties that have no field
+// in Extra.
+func (u *User) UnmarshalJSON(b []byte) error {
+	type plain User
+	return unmarshalExtra(b, (*plain)(u), &u.Extra)
+}
+
+// MarshalJSON encodes the user along with the properties in Extra.
+func (u User) MarshalJSON() ([]byte, error) {
+	type plain User
+	return marshalExtra(plain(u), u.Extra)
+}
+
+// ListUsers returns every user, following pagination. If email is not
+// empty only the user with that email is returned.
+func (c *Client) ListUsers(ctx context.Context, email string) ([]User, error) {
+	return Collect(c.IterateUsers(ctx, email))
+}
+
+// IterateUsers returns an Iterator over the users returned by ListUsers,
+// requesting each page as it is read.
+func (c *Client) IterateUsers(ctx context.Context, email string) *Iterator[User] {
+	var query url.Values
+	if email != "" {
+		query = url.Values{"email": {email}}
+	}
+	return iterateTokens[User](ctx, c, "listing users", "/v1/users", query)
+}
+
+// GetUser returns the user with the ID.
+func (c *Client) GetUser(ctx context.Context, id string) (*User, error) {
+	var u User
+	if err := c.get(ctx, pathf("/v1/users/%s", id), nil, &u); err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+	return &u, nil
+}
+
+// CreateUser creates a user and returns it. Sumo Logic emails the user an
+// invitation to set their password.
+func (c *Client) CreateUser(ctx context.Context, user User) (*User, error) {
+	if user.Email == "" || len(user.RoleIDs) == 0 {
+		return nil, fmt.Errorf("error creating user: email and at least one role are required")
+	}
+	body := struct {
+		FirstName string   `json:"firstName"`
+		LastName  string   `json:"lastName"`
+		Email     string   `json:"email"`
+		RoleIDs   []string `json:"roleIds"`
+	}{user.FirstName, user.LastName, user.Email, user.RoleIDs}
+	var created User
+	if err := c.post(ctx, "/v1/users", body, &created); err != nil {
+		return nil, fmt.Errorf("error creating user: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateUser changes the name, roles and active state of the user with the
+// same ID and returns the updated user. The email of a user cannot be
+// changed this way.
+func (c *Client) UpdateUser(ctx context.Context, user User) (*User, error) {
+	body := struct {
+		FirstName string   `json:"firstName"`
+		LastName  string   `json:"lastName"`
+		IsActive  bool     `json:"isActive"`
+		RoleIDs   []string `json:"roleIds"`
+	}{user.FirstName, user.LastName, user.IsActive, user.RoleIDs}
+	var updated User
+	if err := c.put(ctx, pathf("/v1/users/%s", user.ID), body, &updated); err != nil {
+		return nil, fmt.Errorf("error updating user: %w", err)
+	}
+	return &updated, nil
+}
+
+// DisableUser disables the user with the ID, so they can no longer log in,
+// and returns the updated user.
+func (c *Client) DisableUser(ctx context.Context, id string) (*User, error) {
+	return c.setUserActive(ctx, id, false)
+}
+
+// EnableUser enables the disabled user with the ID and returns the updated
+// user.
+func (c *Client) EnableUser(ctx context.Context, id string) (*User, error) {
+	return c.setUserActive(ctx, id, true)
+}
+
+// setUserActive reads the user and updates it with the active state.
+func (c *Client) setUserActive(ctx context.Context, id string, active bool) (*User, error) {
+	u, err := c.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	u.IsActive = active
+	return c.UpdateUser(ctx, *u)
+}
+
+// DeleteUser deletes the user with the ID. If transferTo is not empty, the
+// content of the user is moved to the user with that ID, and otherwise it is
+// deleted along with them.
+func (c *Client) DeleteUser(ctx context.Context, id, transferTo string) error {
+	r := request{method: http.MethodDelete, path: pathf("/v1/users/%s", id)}
+	if transferTo != "" {
+		r.query = url.Values{"transferTo": {transferTo}}
+	}
+	if _, err := c.do(ctx, r); err != nil {
+		return fmt.Errorf("error deleting user: %w", err)
+	}
+	return nil
+}
+
+// ResetUserPassword emails the user with the ID a link to reset their
+// password.
+func (c *Client) ResetUserPassword(ctx context.Context, id string) error {
+	if err := c.post(ctx, pathf("/v1/users/%s/password/reset", id), nil, nil); err != nil {
+		return fmt.Errorf("error resetting user password: %w", err)
+	}
+	return nil
+}
+
+// DisableUserMFA turns off multi-factor authentication for the user with the
+// ID, so they can set it up again, such as after losing their device. The
+// email and password are those of the user.
+func (c *Client) DisableUserMFA(ctx context.Context, id, email, password string) error {
+	body := struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}{email, password}
+	if err := c.put(ctx, pathf("/v1/users/%s/mfa/disable", id), body, nil); err != nil {
+		return fmt.Errorf("error disabling user mfa: %w", err)
+	}
+	return nil
+}
+
+// UnlockUser unlocks the user with the ID after too many failed logins.
+func (c *Client) UnlockUser(ctx context.Context, id string) error {
+	if err := c.post(ctx, pathf("/v1/users/%s/unlock", id), nil, nil); err != nil {
+		return fmt.Errorf("error unlocking user: %w", err)
+	}
+	return nil
+}
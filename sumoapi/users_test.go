@@ -0,0 +1,118 @@
+package sumoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestUsers(t *testing.T) {
+	ctx := context.Background()
+	user := `{"id": "u1", "firstName": "Ada", "lastName": "Lovelace", "email": "ada@example.com", "roleIds": ["r1"], "isActive": true, "lastLoginTimestamp": "2024-01-02T03:04:05Z", "displayName": "Ada"}`
+	testCalls(t, []apiCall{
+		{
+			name: "list by email",
+			call: func(c *Client) error {
+				users, err := c.ListUsers(ctx, "ada@example.com")
+				if err != nil {
+					return err
+				}
+				if len(users) != 1 {
+					return expect(len(users), 1)
+				}
+				return expect(string(users[0].Extra["displayName"]), `"Ada"`)
+			},
+			response: `{"data": [` + user + `]}`,
+			method:   http.MethodGet,
+			path:     "/v1/users",
+			query:    "email=ada%40example.com&limit=100",
+		},
+		{
+			name: "get",
+			call: func(c *Client) error {
+				u, err := c.GetUser(ctx, "u1")
+				if err != nil {
+					return err
+				}
+				return expect(u.RoleIDs, []string{"r1"})
+			},
+			response: user,
+			method:   http.MethodGet,
+			path:     "/v1/users/u1",
+		},
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateUser(ctx, User{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", RoleIDs: []string{"r1"}, IsActive: true})
+				return err
+			},
+			response: user,
+			method:   http.MethodPost,
+			path:     "/v1/users",
+			body:     `{"firstName": "Ada", "lastName": "Lovelace", "email": "ada@example.com", "roleIds": ["r1"]}`,
+		},
+		{
+			name: "update",
+			call: func(c *Client) error {
+				_, err := c.UpdateUser(ctx, User{ID: "u1", FirstName: "Ada", LastName: "King", Email: "ada@example.com", RoleIDs: []string{"r1", "r2"}, IsActive: true})
+				return err
+			},
+			response: user,
+			method:   http.MethodPut,
+			path:     "/v1/users/u1",
+			// The email cannot be changed, so it is not sent.
+			body: `{"firstName": "Ada", "lastName": "King", "isActive": true, "roleIds": ["r1", "r2"]}`,
+		},
+		{
+			name:   "delete transferring content",
+			call:   func(c *Client) error { return c.DeleteUser(ctx, "u1", "u2") },
+			method: http.MethodDelete,
+			path:   "/v1/users/u1",
+			query:  "transferTo=u2",
+		},
+		{
+			name:   "reset password",
+			call:   func(c *Client) error { return c.ResetUserPassword(ctx, "u1") },
+			method: http.MethodPost,
+			path:   "/v1/users/u1/password/reset",
+		},
+		{
+			name:   "disable mfa",
+			call:   func(c *Client) error { return c.DisableUserMFA(ctx, "u1", "ada@example.com", "secret") },
+			method: http.MethodPut,
+			path:   "/v1/users/u1/mfa/disable",
+			body:   `{"email": "ada@example.com", "password": "secret"}`,
+		},
+		{
+			name:   "unlock",
+			call:   func(c *Client) error { return c.UnlockUser(ctx, "u1") },
+			method: http.MethodPost,
+			path:   "/v1/users/u1/unlock",
+		},
+	})
+}
+
+func TestDisableUser(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond("GET /v1/users/u1", `{"id": "u1", "firstName": "Ada", "lastName": "Lovelace", "roleIds": ["r1"], "isActive": true}`)
+	api.respond("PUT /v1/users/u1", `{"id": "u1", "isActive": false}`)
+	u, err := api.client().DisableUser(context.Background(), "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.IsActive {
+		t.Error("IsActive = true, want the updated user")
+	}
+	// The rest of the user is sent back as it is.
+	checkRequest(t, api.last(), http.MethodPut, "/v1/users/u1", `{"firstName": "Ada", "lastName": "Lovelace", "isActive": false, "roleIds": ["r1"]}`)
+}
+
+func TestCreateUserRequiresRole(t *testing.T) {
+	api := newTestAPI(t)
+	if _, err := api.client().CreateUser(context.Background(), User{Email: "ada@example.com"}); err == nil {
+		t.Error("CreateUser without a role succeeded")
+	}
+	if n := len(api.received()); n != 0 {
+		t.Errorf("received %d requests, want none", n)
+	}
+}
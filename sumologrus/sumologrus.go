@@ -0,0 +1,100 @@
+// Package sumologrus provides a logrus.Hook that ships entries to Sumo Logic
+// using the gosumo BufferedSender.
+package sumologrus
+
+import (
+	"bytes"
+
+	"github.com/byitkc/gosumo"
+	"github.com/sirupsen/logrus"
+)
+
+// Hook is a logrus.Hook that formats entries as JSON and queues them on a
+// BufferedSender, which posts them to Sumo Logic in batches.
+type Hook struct {
+	sender    *gosumo.BufferedSender
+	levels    []logrus.Level
+	formatter logrus.Formatter
+	blocking  bool
+}
+
+// Option configures a Hook when it is created with NewHook.
+type Option func(*Hook)
+
+// WithLevels sets the levels the Hook fires for. By default the Hook fires
+// for all levels.
+func WithLevels(levels ...logrus.Level) Option {
+	return func(h *Hook) {
+		h.levels = levels
+	}
+}
+
+// WithMinLevel makes the Hook fire only for entries at the provided level or
+// more severe.
+func WithMinLevel(level logrus.Level) Option {
+	return func(h *Hook) {
+		h.levels = nil
+		for _, l := range logrus.AllLevels {
+			if l <= level {
+				h.levels = append(h.levels, l)
+			}
+		}
+	}
+}
+
+// WithFormatter sets the Formatter used to encode entries. It must produce a
+// single line per entry. By default a logrus.JSONFormatter is used.
+func WithFormatter(f logrus.Formatter) Option {
+	return func(h *Hook) {
+		h.formatter = f
+	}
+}
+
+// WithBlocking makes the Hook flush the BufferedSender on every entry and
+// return any error from posting, instead of queueing the entry and returning
+// immediately.
+func WithBlocking(blocking bool) Option {
+	return func(h *Hook) {
+		h.blocking = blocking
+	}
+}
+
+// NewHook creates and returns a new Hook that queues entries on the provided
+// BufferedSender. The BufferedSender must be closed by the caller when
+// logging is finished.
+func NewHook(s *gosumo.BufferedSender, opts ...Option) *Hook {
+	h := &Hook{
+		sender:    s,
+		levels:    logrus.AllLevels,
+		formatter: &logrus.JSONFormatter{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Levels returns the levels the Hook fires for.
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire formats the entry and queues it to be posted. In blocking mode it also
+// flushes the BufferedSender and returns any error from posting.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	b, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	line := bytes.TrimRight(b, "\r\n")
+	if len(line) == 0 {
+		return nil
+	}
+	if err := h.sender.Enqueue(line); err != nil {
+		return err
+	}
+	if h.blocking {
+		return h.sender.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package sumologrus
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+	"github.com/byitkc/gosumo/gosumotest"
+	"github.com/sirupsen/logrus"
+)
+
+// newBufferedSender returns a BufferedSender sending to fake that only
+// flushes when asked to.
+func newBufferedSender(t *testing.T, fake *gosumotest.FakeSender) *gosumo.BufferedSender {
+	t.Helper()
+	s, err := gosumo.NewBufferedSender(fake, gosumo.WithFlushInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// newLogger returns a logger that only writes to the hook, at every level.
+func newLogger(h *Hook) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.TraceLevel)
+	logger.AddHook(h)
+	return logger
+}
+
+func TestHook(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	logger := newLogger(NewHook(s))
+
+	logger.WithField("port", 8080).Info("started")
+	logger.WithFields(logrus.Fields{"request_id": "abc", "status": 200}).Warn("slow request")
+	logger.WithError(errors.New("connection refused")).Error("failed")
+	if len(fake.Payloads()) != 0 {
+		t.Fatal("entries were posted before the flush")
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := fake.Lines()
+	if len(lines) != 3 {
+		t.Fatalf("posted %d entries, want 3: %q", len(lines), lines)
+	}
+	tests := []struct {
+		level, msg string
+		check      func(m map[string]any) bool
+	}{
+		{"info", "started", func(m map[string]any) bool { return m["port"] == 8080.0 }},
+		{"warning", "slow request", func(m map[string]any) bool { return m["request_id"] == "abc" && m["status"] == 200.0 }},
+		{"error", "failed", func(m map[string]any) bool { return m["error"] == "connection refused" }},
+	}
+	for i, tt := range tests {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(lines[i]), &m); err != nil {
+			t.Fatalf("invalid JSON entry %q: %v", lines[i], err)
+		}
+		if m["level"] != tt.level || m["msg"] != tt.msg || !tt.check(m) {
+			t.Errorf("entry %d = %v, want %s %q with its fields", i, m, tt.level, tt.msg)
+		}
+	}
+}
+
+func TestHookLevels(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+		want []logrus.Level
+	}{
+		{"all", nil, logrus.AllLevels},
+		{"min level", WithMinLevel(logrus.WarnLevel), []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}},
+		{"levels", WithLevels(logrus.InfoLevel, logrus.ErrorLevel), []logrus.Level{logrus.InfoLevel, logrus.ErrorLevel}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.opt != nil {
+				opts = append(opts, tt.opt)
+			}
+			h := NewHook(newBufferedSender(t, gosumotest.NewFakeSender()), opts...)
+			if got := h.Levels(); !slices.Equal(got, tt.want) {
+				t.Errorf("Levels = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHookMinLevel(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	logger := newLogger(NewHook(s, WithMinLevel(logrus.WarnLevel)))
+	logger.Debug("dropped")
+	logger.Info("dropped")
+	logger.Warn("kept")
+	logger.Error("kept")
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(fake.Lines()); n != 2 {
+		t.Errorf("posted %d entries, want the 2 at warn or above", n)
+	}
+}
+
+func TestHookFormatter(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	f := &logrus.TextFormatter{DisableTimestamp: true, DisableColors: true}
+	logger := newLogger(NewHook(s, WithFormatter(f)))
+	logger.WithField("port", 8080).Info("started")
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := `level=info msg=started port=8080`
+	if got := fake.Lines(); len(got) != 1 || got[0] != want {
+		t.Errorf("lines = %q, want [%s]", got, want)
+	}
+}
+
+func TestHookBlocking(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	h := NewHook(s, WithBlocking(true))
+	logger := newLogger(h)
+
+	// Each entry is posted before the log call returns.
+	logger.Info("one")
+	logger.Info("two")
+	if n := len(fake.Payloads()); n != 2 {
+		t.Fatalf("sent %d payloads, want one per entry", n)
+	}
+
+	sendErr := errors.New("unavailable")
+	fake.SetError(sendErr)
+	if err := h.Fire(logger.WithField("a", 1)); !errors.Is(err, sendErr) {
+		t.Errorf("Fire = %v, want the send error", err)
+	}
+}
+
+func TestHookClosedSender(t *testing.T) {
+	s := newBufferedSender(t, gosumotest.NewFakeSender())
+	h := NewHook(s)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Fire(logrus.NewEntry(logrus.New())); !errors.Is(err, gosumo.ErrSenderClosed) {
+		t.Errorf("Fire = %v, want ErrSenderClosed", err)
+	}
+}
@@ -0,0 +1,156 @@
+// Package sumoprom exports the internal counters and request latency of a
+// gosumo Client as Prometheus metrics.
+package sumoprom
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/byitkc/gosumo"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StatsSource is anything that reports gosumo.Stats, such as a gosumo.Client
+// or gosumo.BufferedSender.
+type StatsSource interface {
+	Stats() gosumo.Stats
+}
+
+// Collector is a prometheus.Collector that exports the counters of the
+// tracked StatsSources and a histogram of request latency.
+type Collector struct {
+	latency *prometheus.HistogramVec
+
+	logsSent    *prometheus.Desc
+	bytesSent   *prometheus.Desc
+	batchesSent *prometheus.Desc
+	retries     *prometheus.Desc
+	failures    *prometheus.Desc
+	dropped     *prometheus.Desc
+	sampled     *prometheus.Desc
+	queueDepth  *prometheus.Desc
+
+	mu      sync.Mutex
+	sources map[string]StatsSource
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// Option configures a Collector when it is created with NewCollector.
+type Option func(*config)
+
+type config struct {
+	namespace string
+	buckets   []float64
+}
+
+// WithNamespace sets the namespace of the exported metrics. The default is
+// "gosumo".
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets sets the buckets of the request latency histogram, in seconds.
+// The default is prometheus.DefBuckets.
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// NewCollector creates and returns a new Collector. Use ClientOption when
+// creating a Client to record its request latency, and Track to export its
+// counters.
+func NewCollector(opts ...Option) *Collector {
+	cfg := config{namespace: "gosumo", buckets: prometheus.DefBuckets}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	desc := func(name, help string, gauge bool) *prometheus.Desc {
+		if !gauge {
+			name += "_total"
+		}
+		return prometheus.NewDesc(prometheus.BuildFQName(cfg.namespace, "", name), help, []string{"sender"}, nil)
+	}
+	return &Collector{
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of requests to Sumo Logic, by status code.",
+			Buckets:   cfg.buckets,
+		}, []string{"code"}),
+		logsSent:    desc("logs_sent", "Number of logs accepted by Sumo Logic.", false),
+		bytesSent:   desc("bytes_sent", "Number of payload bytes accepted by Sumo Logic.", false),
+		batchesSent: desc("batches_sent", "Number of requests accepted by Sumo Logic.", false),
+		retries:     desc("retries", "Number of retried requests.", false),
+		failures:    desc("failures", "Number of requests that failed after all attempts.", false),
+		dropped:     desc("dropped", "Number of logs dropped because the buffer was full.", false),
+		sampled:     desc("sampled", "Number of logs discarded by sampling.", false),
+		queueDepth:  desc("queue_depth", "Number of logs currently buffered.", true),
+		sources:     make(map[string]StatsSource),
+	}
+}
+
+// ClientOption returns a gosumo.Option that records the latency of every
+// request made by the Client in the Collector's histogram.
+func (c *Collector) ClientOption() gosumo.Option {
+	return gosumo.WithAttemptObserver(func(info gosumo.AttemptInfo) {
+		code := "error"
+		if info.StatusCode != 0 {
+			code = strconv.Itoa(info.StatusCode)
+		}
+		c.latency.WithLabelValues(code).Observe(info.Duration.Seconds())
+	})
+}
+
+// Track exports the counters of src with the provided name as the value of
+// the "sender" label. Tracking another source with the same name replaces
+// it.
+func (c *Collector) Track(name string, src StatsSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sources[name] = src
+}
+
+// Untrack stops exporting the counters of the source with the provided name.
+func (c *Collector) Untrack(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sources, name)
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.latency.Describe(ch)
+	ch <- c.logsSent
+	ch <- c.bytesSent
+	ch <- c.batchesSent
+	ch <- c.retries
+	ch <- c.failures
+	ch <- c.dropped
+	ch <- c.sampled
+	ch <- c.queueDepth
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.latency.Collect(ch)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, src := range c.sources {
+		st := src.Stats()
+		counter := func(d *prometheus.Desc, v uint64) {
+			ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, float64(v), name)
+		}
+		counter(c.logsSent, st.LogsSent)
+		counter(c.bytesSent, st.BytesSent)
+		counter(c.batchesSent, st.BatchesSent)
+		counter(c.retries, st.Retries)
+		counter(c.failures, st.Failures)
+		counter(c.dropped, st.Dropped)
+		counter(c.sampled, st.Sampled)
+		ch <- prometheus.MustNewConstMetric(c.queueDepth, prometheus.GaugeValue, float64(st.QueueDepth), name)
+	}
+}
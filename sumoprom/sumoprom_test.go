@@ -0,0 +1,180 @@
+package sumoprom
+
+import (
+	"maps"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+	"github.com/byitkc/gosumo/gosumotest"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// fixedStats is a StatsSource reporting the same Stats every time.
+type fixedStats gosumo.Stats
+
+func (s fixedStats) Stats() gosumo.Stats {
+	return gosumo.Stats(s)
+}
+
+// gather registers the collector with a pedantic registry, which checks the
+// metrics against their descriptions, and returns the metric families by
+// name.
+func gather(t *testing.T, c *Collector) map[string]*dto.MetricFamily {
+	t.Helper()
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(c); err != nil {
+		t.Fatal(err)
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]*dto.MetricFamily)
+	for _, f := range families {
+		byName[f.GetName()] = f
+	}
+	return byName
+}
+
+// senderValues returns the value of each metric of the family by the value
+// of its sender label.
+func senderValues(f *dto.MetricFamily) map[string]float64 {
+	values := make(map[string]float64)
+	for _, m := range f.GetMetric() {
+		var sender string
+		for _, l := range m.GetLabel() {
+			if l.GetName() == "sender" {
+				sender = l.GetValue()
+			}
+		}
+		switch {
+		case m.Counter != nil:
+			values[sender] = m.GetCounter().GetValue()
+		case m.Gauge != nil:
+			values[sender] = m.GetGauge().GetValue()
+		}
+	}
+	return values
+}
+
+func TestCollectorStats(t *testing.T) {
+	c := NewCollector()
+	c.Track("app", fixedStats{LogsSent: 10, BytesSent: 2048, BatchesSent: 2, Retries: 3, Failures: 1, Dropped: 4, Sampled: 5, QueueDepth: 6})
+	c.Track("audit", fixedStats{LogsSent: 1})
+	families := gather(t, c)
+
+	tests := []struct {
+		name string
+		typ  dto.MetricType
+		want map[string]float64
+	}{
+		{"gosumo_logs_sent_total", dto.MetricType_COUNTER, map[string]float64{"app": 10, "audit": 1}},
+		{"gosumo_bytes_sent_total", dto.MetricType_COUNTER, map[string]float64{"app": 2048, "audit": 0}},
+		{"gosumo_batches_sent_total", dto.MetricType_COUNTER, map[string]float64{"app": 2, "audit": 0}},
+		{"gosumo_retries_total", dto.MetricType_COUNTER, map[string]float64{"app": 3, "audit": 0}},
+		{"gosumo_failures_total", dto.MetricType_COUNTER, map[string]float64{"app": 1, "audit": 0}},
+		{"gosumo_dropped_total", dto.MetricType_COUNTER, map[string]float64{"app": 4, "audit": 0}},
+		{"gosumo_sampled_total", dto.MetricType_COUNTER, map[string]float64{"app": 5, "audit": 0}},
+		{"gosumo_queue_depth", dto.MetricType_GAUGE, map[string]float64{"app": 6, "audit": 0}},
+	}
+	for _, tt := range tests {
+		f, ok := families[tt.name]
+		if !ok {
+			t.Errorf("%s was not collected", tt.name)
+			continue
+		}
+		if f.GetType() != tt.typ {
+			t.Errorf("%s type = %v, want %v", tt.name, f.GetType(), tt.typ)
+		}
+		if got := senderValues(f); !maps.Equal(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCollectorTrack(t *testing.T) {
+	c := NewCollector()
+	c.Track("app", fixedStats{LogsSent: 1})
+	c.Track("audit", fixedStats{LogsSent: 2})
+	// Tracking a name again replaces its source.
+	c.Track("app", fixedStats{LogsSent: 3})
+	c.Untrack("audit")
+	got := senderValues(gather(t, c)["gosumo_logs_sent_total"])
+	if len(got) != 1 || got["app"] != 3 {
+		t.Errorf("logs sent = %v, want only app with 3", got)
+	}
+
+	c.Untrack("app")
+	if f, ok := gather(t, c)["gosumo_logs_sent_total"]; ok {
+		t.Errorf("logs sent = %v, want nothing once every source is untracked", senderValues(f))
+	}
+}
+
+func TestCollectorNamespace(t *testing.T) {
+	c := NewCollector(WithNamespace("shipper"))
+	c.Track("app", fixedStats{})
+	families := gather(t, c)
+	for _, name := range []string{"shipper_logs_sent_total", "shipper_queue_depth"} {
+		if _, ok := families[name]; !ok {
+			t.Errorf("%s was not collected", name)
+		}
+	}
+	if _, ok := families["gosumo_logs_sent_total"]; ok {
+		t.Error("the default namespace was used")
+	}
+}
+
+func TestClientOption(t *testing.T) {
+	c := NewCollector(WithBuckets([]float64{0.5, 10}))
+	col := gosumotest.NewCollector()
+	defer col.Close()
+	col.RespondWithStatus(http.StatusServiceUnavailable)
+	client, err := col.NewClient(c.ClientOption(), gosumo.WithRetry(gosumo.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Track("app", client)
+	if err := client.PostLogsString("one"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A request that fails without a response is recorded as an error.
+	down := gosumotest.NewCollector()
+	down.Close()
+	unreachable, err := gosumo.NewClient(down.URL(), gosumo.WithInsecureURL(), c.ClientOption(), gosumo.WithRetry(gosumo.RetryPolicy{MaxAttempts: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unreachable.PostLogsString("two"); err == nil {
+		t.Fatal("post to a closed collector succeeded")
+	}
+
+	families := gather(t, c)
+	latency, ok := families["gosumo_request_duration_seconds"]
+	if !ok {
+		t.Fatal("request latency was not collected")
+	}
+	counts := make(map[string]uint64)
+	for _, m := range latency.GetMetric() {
+		h := m.GetHistogram()
+		if n := len(h.GetBucket()); n != 2 {
+			t.Errorf("histogram has %d buckets, want the 2 configured", n)
+		}
+		counts[m.GetLabel()[0].GetValue()] = h.GetSampleCount()
+	}
+	want := map[string]uint64{"503": 1, "200": 1, "error": 1}
+	if !maps.Equal(counts, want) {
+		t.Errorf("requests by code = %v, want %v", counts, want)
+	}
+
+	// The counters of a tracked Client are exported.
+	if got := senderValues(families["gosumo_retries_total"]); got["app"] != 1 {
+		t.Errorf("retries = %v, want 1 for app", got)
+	}
+	if got := senderValues(families["gosumo_logs_sent_total"]); got["app"] != 1 {
+		t.Errorf("logs sent = %v, want 1 for app", got)
+	}
+}
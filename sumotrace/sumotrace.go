@@ -0,0 +1,58 @@
+// Package sumotrace provides an OpenTelemetry SpanExporter that posts spans
+// to a Sumo Logic traces source using a gosumo TraceEndpoint.
+package sumotrace
+
+import (
+	"context"
+	"sync"
+
+	"github.com/byitkc/gosumo"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// Exporter is a sdktrace.SpanExporter that encodes spans as an OTLP protobuf
+// ExportTraceServiceRequest and posts them to a TraceEndpoint. Use it with
+// sdktrace.WithBatcher to export spans in batches.
+type Exporter struct {
+	endpoint *gosumo.TraceEndpoint
+	opts     []gosumo.PostOption
+
+	mu       sync.Mutex
+	shutdown bool
+}
+
+// NewExporter creates and returns a new Exporter that posts to the provided
+// TraceEndpoint. The PostOptions are applied to every export.
+func NewExporter(e *gosumo.TraceEndpoint, opts ...gosumo.PostOption) *Exporter {
+	return &Exporter{endpoint: e, opts: opts}
+}
+
+// ExportSpans encodes the spans and posts them to the TraceEndpoint.
+func (e *Exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	shutdown := e.shutdown
+	e.mu.Unlock()
+	if shutdown || len(spans) == 0 {
+		return nil
+	}
+	payload, err := Marshal(spans)
+	if err != nil {
+		return err
+	}
+	return e.endpoint.PostTraces(ctx, payload, gosumo.TraceFormatProtobuf, e.opts...)
+}
+
+// Shutdown stops the Exporter. Spans exported after Shutdown are dropped.
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	e.shutdown = true
+	e.mu.Unlock()
+	return ctx.Err()
+}
+
+// Marshal encodes the spans as an OTLP protobuf ExportTraceServiceRequest,
+// ready to be posted with TraceEndpoint.PostTraces.
+func Marshal(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	return proto.Marshal(toRequest(spans))
+}
@@ -0,0 +1,251 @@
+package sumotrace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+	"github.com/byitkc/gosumo/gosumotest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	traceID = trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	start   = time.Unix(1700000000, 0)
+)
+
+// spanContext returns a span context in the test trace with the span ID.
+func spanContext(id byte) trace.SpanContext {
+	return trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: trace.SpanID{id}})
+}
+
+func TestToSpan(t *testing.T) {
+	stub := tracetest.SpanStub{
+		Name:        "GET /users",
+		SpanContext: spanContext(2),
+		Parent:      spanContext(1),
+		SpanKind:    trace.SpanKindServer,
+		StartTime:   start,
+		EndTime:     start.Add(time.Second),
+		Attributes: []attribute.KeyValue{
+			attribute.String("http.method", "GET"),
+			attribute.Int("http.status_code", 500),
+			attribute.Bool("retried", true),
+			attribute.Float64("ratio", 0.5),
+			attribute.StringSlice("tags", []string{"a", "b"}),
+		},
+		Events:            []sdktrace.Event{{Name: "exception", Time: start.Add(time.Millisecond), DroppedAttributeCount: 1}},
+		Links:             []sdktrace.Link{{SpanContext: spanContext(9), Attributes: []attribute.KeyValue{attribute.String("kind", "batch")}}},
+		Status:            sdktrace.Status{Code: codes.Error, Description: "internal error"},
+		DroppedAttributes: 3,
+	}
+	got := toSpan(stub.Snapshot())
+	want := &tracepb.Span{
+		TraceId:           traceID[:],
+		SpanId:            []byte{2, 0, 0, 0, 0, 0, 0, 0},
+		ParentSpanId:      []byte{1, 0, 0, 0, 0, 0, 0, 0},
+		Name:              "GET /users",
+		Kind:              tracepb.Span_SPAN_KIND_SERVER,
+		StartTimeUnixNano: uint64(start.UnixNano()),
+		EndTimeUnixNano:   uint64(start.Add(time.Second).UnixNano()),
+		Attributes: []*commonpb.KeyValue{
+			{Key: "http.method", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "GET"}}},
+			{Key: "http.status_code", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: 500}}},
+			{Key: "retried", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: true}}},
+			{Key: "ratio", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: 0.5}}},
+			{Key: "tags", Value: arrayValue([]*commonpb.AnyValue{
+				{Value: &commonpb.AnyValue_StringValue{StringValue: "a"}},
+				{Value: &commonpb.AnyValue_StringValue{StringValue: "b"}},
+			})},
+		},
+		DroppedAttributesCount: 3,
+		Events: []*tracepb.Span_Event{
+			{Name: "exception", TimeUnixNano: uint64(start.Add(time.Millisecond).UnixNano()), DroppedAttributesCount: 1},
+		},
+		Links: []*tracepb.Span_Link{{
+			TraceId:    traceID[:],
+			SpanId:     []byte{9, 0, 0, 0, 0, 0, 0, 0},
+			Attributes: []*commonpb.KeyValue{{Key: "kind", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "batch"}}}},
+		}},
+		Status: &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: "internal error"},
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("toSpan =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestToSpanRoot(t *testing.T) {
+	stub := tracetest.SpanStub{Name: "root", SpanContext: spanContext(1), StartTime: start, EndTime: start}
+	got := toSpan(stub.Snapshot())
+	if got.ParentSpanId != nil {
+		t.Errorf("ParentSpanId = %x, want none for a root span", got.ParentSpanId)
+	}
+	if got.Status.Code != tracepb.Status_STATUS_CODE_UNSET {
+		t.Errorf("status = %v, want unset", got.Status.Code)
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want tracepb.Status_StatusCode
+	}{
+		{codes.Unset, tracepb.Status_STATUS_CODE_UNSET},
+		{codes.Error, tracepb.Status_STATUS_CODE_ERROR},
+		{codes.Ok, tracepb.Status_STATUS_CODE_OK},
+	}
+	for _, tt := range tests {
+		if got := toStatus(sdktrace.Status{Code: tt.code}).Code; got != tt.want {
+			t.Errorf("toStatus(%v) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestToRequestGroupsSpans(t *testing.T) {
+	api := resource.NewSchemaless(attribute.String("service.name", "api"))
+	worker := resource.NewSchemaless(attribute.String("service.name", "worker"))
+	httpScope := instrumentation.Scope{Name: "net/http", Version: "1.0.0"}
+	sqlScope := instrumentation.Scope{Name: "database/sql"}
+	stubs := tracetest.SpanStubs{
+		{Name: "a", SpanContext: spanContext(1), Resource: api, InstrumentationScope: httpScope},
+		{Name: "b", SpanContext: spanContext(2), Resource: worker, InstrumentationScope: httpScope},
+		{Name: "c", SpanContext: spanContext(3), Resource: api, InstrumentationScope: sqlScope},
+		{Name: "d", SpanContext: spanContext(4), Resource: api, InstrumentationScope: httpScope},
+	}
+	req := toRequest(stubs.Snapshots())
+
+	// Resources and scopes are kept in the order they are first seen.
+	type group struct {
+		service, scope string
+		spans          []string
+	}
+	want := []group{
+		{"api", "net/http", []string{"a", "d"}},
+		{"api", "database/sql", []string{"c"}},
+		{"worker", "net/http", []string{"b"}},
+	}
+	var got []group
+	for _, rs := range req.ResourceSpans {
+		service := rs.Resource.Attributes[0].Value.GetStringValue()
+		for _, ss := range rs.ScopeSpans {
+			g := group{service: service, scope: ss.Scope.Name}
+			for _, s := range ss.Spans {
+				g.spans = append(g.spans, s.Name)
+			}
+			got = append(got, g)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("groups = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].service != want[i].service || got[i].scope != want[i].scope || len(got[i].spans) != len(want[i].spans) {
+			t.Fatalf("groups = %v, want %v", got, want)
+		}
+		for j := range want[i].spans {
+			if got[i].spans[j] != want[i].spans[j] {
+				t.Fatalf("groups = %v, want %v", got, want)
+			}
+		}
+	}
+	if v := req.ResourceSpans[0].ScopeSpans[0].Scope.Version; v != "1.0.0" {
+		t.Errorf("scope version = %q, want 1.0.0", v)
+	}
+}
+
+// newExporter returns an Exporter posting to a test collector.
+func newExporter(t *testing.T, opts ...gosumo.PostOption) (*Exporter, *gosumotest.Collector) {
+	t.Helper()
+	col := gosumotest.NewCollector()
+	t.Cleanup(col.Close)
+	e, err := gosumo.NewTraceEndpoint(col.URL(), gosumo.WithInsecureURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewExporter(e, opts...), col
+}
+
+func TestExporter(t *testing.T) {
+	exp, col := newExporter(t, gosumo.PostWithSourceCategory("traces/test"))
+	spans := tracetest.SpanStubs{
+		{Name: "a", SpanContext: spanContext(1), StartTime: start, EndTime: start},
+		{Name: "b", SpanContext: spanContext(2), Parent: spanContext(1), StartTime: start, EndTime: start},
+	}.Snapshots()
+	if err := exp.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatal(err)
+	}
+	reqs := col.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("collector received %d requests, want 1", len(reqs))
+	}
+	r := reqs[0]
+	if r.ContentType != gosumo.ContentTypeProtobuf {
+		t.Errorf("Content-Type = %q", r.ContentType)
+	}
+	if r.SourceCategory != "traces/test" {
+		t.Errorf("source category = %q, want the post options of the exporter", r.SourceCategory)
+	}
+	want, err := Marshal(spans)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r.Body, want) {
+		t.Errorf("body is not the marshalled spans")
+	}
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(r.Body, &req); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(req.ResourceSpans[0].ScopeSpans[0].Spans); n != 2 {
+		t.Errorf("posted %d spans, want 2", n)
+	}
+}
+
+func TestExporterEmptyAndShutdown(t *testing.T) {
+	exp, col := newExporter(t)
+	if err := exp.ExportSpans(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	spans := tracetest.SpanStubs{{Name: "a", SpanContext: spanContext(1)}}.Snapshots()
+	if err := exp.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("ExportSpans after Shutdown = %v, want the spans dropped", err)
+	}
+	if n := len(col.Requests()); n != 0 {
+		t.Errorf("collector received %d requests, want none", n)
+	}
+}
+
+func TestExporterError(t *testing.T) {
+	exp, col := newExporter(t)
+	col.RespondWithStatus(http.StatusBadRequest)
+	spans := tracetest.SpanStubs{{Name: "a", SpanContext: spanContext(1)}}.Snapshots()
+	if err := exp.ExportSpans(context.Background(), spans); err == nil {
+		t.Error("ExportSpans succeeded, want the post error")
+	}
+}
+
+func TestShutdownCancelled(t *testing.T) {
+	exp, _ := newExporter(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := exp.Shutdown(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Shutdown = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,177 @@
+package sumotrace
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// toRequest groups the spans by resource and instrumentation scope and
+// converts them to an OTLP ExportTraceServiceRequest.
+func toRequest(spans []sdktrace.ReadOnlySpan) *coltracepb.ExportTraceServiceRequest {
+	type scopeKey struct {
+		res   attribute.Distinct
+		scope instrumentation.Scope
+	}
+	resources := make(map[attribute.Distinct]*tracepb.ResourceSpans)
+	scopes := make(map[scopeKey]*tracepb.ScopeSpans)
+	req := &coltracepb.ExportTraceServiceRequest{}
+	for _, s := range spans {
+		res := s.Resource()
+		resKey := res.Equivalent()
+		rs, ok := resources[resKey]
+		if !ok {
+			rs = &tracepb.ResourceSpans{
+				Resource:  toResource(res),
+				SchemaUrl: res.SchemaURL(),
+			}
+			resources[resKey] = rs
+			req.ResourceSpans = append(req.ResourceSpans, rs)
+		}
+		sk := scopeKey{res: resKey, scope: s.InstrumentationScope()}
+		ss, ok := scopes[sk]
+		if !ok {
+			ss = &tracepb.ScopeSpans{
+				Scope: &commonpb.InstrumentationScope{
+					Name:    sk.scope.Name,
+					Version: sk.scope.Version,
+				},
+				SchemaUrl: sk.scope.SchemaURL,
+			}
+			scopes[sk] = ss
+			rs.ScopeSpans = append(rs.ScopeSpans, ss)
+		}
+		ss.Spans = append(ss.Spans, toSpan(s))
+	}
+	return req
+}
+
+// toResource converts a resource to its OTLP representation.
+func toResource(r *resource.Resource) *resourcepb.Resource {
+	if r == nil {
+		return nil
+	}
+	return &resourcepb.Resource{Attributes: toAttributes(r.Attributes())}
+}
+
+// toSpan converts a single span to its OTLP representation.
+func toSpan(s sdktrace.ReadOnlySpan) *tracepb.Span {
+	sc := s.SpanContext()
+	tid := sc.TraceID()
+	sid := sc.SpanID()
+	span := &tracepb.Span{
+		TraceId:                tid[:],
+		SpanId:                 sid[:],
+		TraceState:             sc.TraceState().String(),
+		Name:                   s.Name(),
+		Kind:                   tracepb.Span_SpanKind(s.SpanKind()),
+		StartTimeUnixNano:      uint64(s.StartTime().UnixNano()),
+		EndTimeUnixNano:        uint64(s.EndTime().UnixNano()),
+		Attributes:             toAttributes(s.Attributes()),
+		DroppedAttributesCount: uint32(s.DroppedAttributes()),
+		DroppedEventsCount:     uint32(s.DroppedEvents()),
+		DroppedLinksCount:      uint32(s.DroppedLinks()),
+		Status:                 toStatus(s.Status()),
+	}
+	if parent := s.Parent(); parent.SpanID().IsValid() {
+		psid := parent.SpanID()
+		span.ParentSpanId = psid[:]
+	}
+	for _, ev := range s.Events() {
+		span.Events = append(span.Events, &tracepb.Span_Event{
+			TimeUnixNano:           uint64(ev.Time.UnixNano()),
+			Name:                   ev.Name,
+			Attributes:             toAttributes(ev.Attributes),
+			DroppedAttributesCount: uint32(ev.DroppedAttributeCount),
+		})
+	}
+	for _, l := range s.Links() {
+		ltid := l.SpanContext.TraceID()
+		lsid := l.SpanContext.SpanID()
+		span.Links = append(span.Links, &tracepb.Span_Link{
+			TraceId:                ltid[:],
+			SpanId:                 lsid[:],
+			TraceState:             l.SpanContext.TraceState().String(),
+			Attributes:             toAttributes(l.Attributes),
+			DroppedAttributesCount: uint32(l.DroppedAttributeCount),
+		})
+	}
+	return span
+}
+
+// toStatus converts a span status to its OTLP representation. The OTLP
+// status codes are ordered differently from the OpenTelemetry API codes.
+func toStatus(s sdktrace.Status) *tracepb.Status {
+	st := &tracepb.Status{Message: s.Description}
+	switch s.Code {
+	case codes.Ok:
+		st.Code = tracepb.Status_STATUS_CODE_OK
+	case codes.Error:
+		st.Code = tracepb.Status_STATUS_CODE_ERROR
+	default:
+		st.Code = tracepb.Status_STATUS_CODE_UNSET
+	}
+	return st
+}
+
+// toAttributes converts attributes to their OTLP representation.
+func toAttributes(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		out = append(out, &commonpb.KeyValue{Key: string(kv.Key), Value: toValue(kv.Value)})
+	}
+	return out
+}
+
+// toValue converts an attribute value to its OTLP representation.
+func toValue(v attribute.Value) *commonpb.AnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}}
+	case attribute.INT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}}
+	case attribute.FLOAT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}}
+	case attribute.STRING:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.AsString()}}
+	case attribute.BOOLSLICE:
+		var values []*commonpb.AnyValue
+		for _, b := range v.AsBoolSlice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: b}})
+		}
+		return arrayValue(values)
+	case attribute.INT64SLICE:
+		var values []*commonpb.AnyValue
+		for _, i := range v.AsInt64Slice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}})
+		}
+		return arrayValue(values)
+	case attribute.FLOAT64SLICE:
+		var values []*commonpb.AnyValue
+		for _, f := range v.AsFloat64Slice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}})
+		}
+		return arrayValue(values)
+	case attribute.STRINGSLICE:
+		var values []*commonpb.AnyValue
+		for _, s := range v.AsStringSlice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s}})
+		}
+		return arrayValue(values)
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Emit()}}
+}
+
+// arrayValue wraps values in an OTLP array value.
+func arrayValue(values []*commonpb.AnyValue) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}}
+}
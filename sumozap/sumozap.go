@@ -0,0 +1,55 @@
+// Package sumozap integrates the gosumo BufferedSender with zap, so zap
+// loggers can tee their output to Sumo Logic.
+package sumozap
+
+import (
+	"bytes"
+
+	"github.com/byitkc/gosumo"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WriteSyncer is a zapcore.WriteSyncer that queues each encoded entry on a
+// BufferedSender. Sync flushes all pending batches to Sumo Logic.
+type WriteSyncer struct {
+	sender *gosumo.BufferedSender
+}
+
+// NewWriteSyncer creates and returns a new WriteSyncer that queues entries on
+// the provided BufferedSender.
+func NewWriteSyncer(s *gosumo.BufferedSender) *WriteSyncer {
+	return &WriteSyncer{sender: s}
+}
+
+// Write queues p, without its trailing newline, as a single log line. zap
+// writes each entry with a single call to Write.
+func (w *WriteSyncer) Write(p []byte) (int, error) {
+	line := bytes.TrimRight(p, "\r\n")
+	if len(line) == 0 {
+		return len(p), nil
+	}
+	if err := w.sender.Enqueue(line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync posts all pending entries and waits for the requests to complete.
+func (w *WriteSyncer) Sync() error {
+	return w.sender.Flush()
+}
+
+// NewCore creates and returns a zapcore.Core that encodes entries as JSON
+// using zap's production encoder config and queues them on the provided
+// BufferedSender. Entries below the level enabler are dropped. Use
+// zapcore.NewTee to send logs to Sumo Logic alongside an existing core.
+func NewCore(s *gosumo.BufferedSender, enab zapcore.LevelEnabler) zapcore.Core {
+	return NewCoreWithEncoder(s, zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), enab)
+}
+
+// NewCoreWithEncoder is like NewCore but uses the provided encoder. The
+// encoder should produce a single line per entry.
+func NewCoreWithEncoder(s *gosumo.BufferedSender, enc zapcore.Encoder, enab zapcore.LevelEnabler) zapcore.Core {
+	return zapcore.NewCore(enc, NewWriteSyncer(s), enab)
+}
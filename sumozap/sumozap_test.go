@@ -0,0 +1,127 @@
+package sumozap
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+	"github.com/byitkc/gosumo/gosumotest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newBufferedSender returns a BufferedSender sending to fake that only
+// flushes when asked to.
+func newBufferedSender(t *testing.T, fake *gosumotest.FakeSender) *gosumo.BufferedSender {
+	t.Helper()
+	s, err := gosumo.NewBufferedSender(fake, gosumo.WithFlushInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestCore(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	logger := zap.New(NewCore(s, zapcore.InfoLevel))
+
+	logger.Debug("dropped")
+	logger.Info("started", zap.Int("port", 8080))
+	logger.With(zap.String("request_id", "abc")).Warn("slow request", zap.Namespace("http"), zap.Int("status", 200))
+	logger.Error("failed", zap.Error(errors.New("connection refused")))
+	if len(fake.Payloads()) != 0 {
+		t.Fatal("entries were posted before Sync")
+	}
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := fake.Lines()
+	if len(lines) != 3 {
+		t.Fatalf("posted %d entries, want 3: %q", len(lines), lines)
+	}
+	tests := []struct {
+		level, msg string
+		check      func(m map[string]any) bool
+	}{
+		{"info", "started", func(m map[string]any) bool { return m["port"] == 8080.0 }},
+		{"warn", "slow request", func(m map[string]any) bool {
+			http, _ := m["http"].(map[string]any)
+			return m["request_id"] == "abc" && http["status"] == 200.0
+		}},
+		{"error", "failed", func(m map[string]any) bool { return m["error"] == "connection refused" }},
+	}
+	for i, tt := range tests {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(lines[i]), &m); err != nil {
+			t.Fatalf("invalid JSON entry %q: %v", lines[i], err)
+		}
+		// The production encoder config names the fields level, msg and ts.
+		if m["level"] != tt.level || m["msg"] != tt.msg || !tt.check(m) {
+			t.Errorf("entry %d = %v, want %s %q with its fields", i, m, tt.level, tt.msg)
+		}
+		if _, ok := m["ts"]; !ok {
+			t.Errorf("entry %d has no timestamp", i)
+		}
+	}
+}
+
+func TestCoreWithEncoder(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	enc := zapcore.NewConsoleEncoder(zapcore.EncoderConfig{MessageKey: "msg", LevelKey: "level", EncodeLevel: zapcore.CapitalLevelEncoder})
+	logger := zap.New(NewCoreWithEncoder(s, enc, zapcore.WarnLevel))
+	logger.Info("dropped")
+	logger.Warn("disk low", zap.Int("free", 10))
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	want := "WARN\tdisk low\t{\"free\": 10}"
+	if got := fake.Lines(); len(got) != 1 || got[0] != want {
+		t.Errorf("lines = %q, want [%q]", got, want)
+	}
+}
+
+func TestWriteSyncer(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	w := NewWriteSyncer(s)
+	// Each write is a single line, without its trailing newline, and empty
+	// writes are skipped.
+	for _, p := range []string{"one\n", "two\r\n", "\n", ""} {
+		if n, err := w.Write([]byte(p)); err != nil || n != len(p) {
+			t.Fatalf("Write(%q) = %d, %v", p, n, err)
+		}
+	}
+	if err := w.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	payloads := fake.Payloads()
+	if len(payloads) != 1 || string(payloads[0]) != "one\ntwo" {
+		t.Errorf("payloads = %q, want one payload with both lines", payloads)
+	}
+}
+
+func TestWriteSyncerErrors(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	w := NewWriteSyncer(s)
+	if _, err := w.Write([]byte("one\n")); err != nil {
+		t.Fatal(err)
+	}
+	sendErr := errors.New("unavailable")
+	fake.SetError(sendErr)
+	if err := w.Sync(); !errors.Is(err, sendErr) {
+		t.Errorf("Sync = %v, want the send error", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("late\n")); !errors.Is(err, gosumo.ErrSenderClosed) {
+		t.Errorf("Write after Close = %v, want ErrSenderClosed", err)
+	}
+}
@@ -0,0 +1,78 @@
+// Package sumozerolog provides a zerolog compatible writer that ships events
+// to Sumo Logic using the gosumo BufferedSender.
+package sumozerolog
+
+import (
+	"bytes"
+
+	"github.com/byitkc/gosumo"
+	"github.com/rs/zerolog"
+)
+
+// Writer is a zerolog.LevelWriter that queues zerolog's JSON lines on a
+// BufferedSender. Events at or above the flush level are flushed immediately
+// so errors reach Sumo Logic without waiting for the next batch.
+type Writer struct {
+	sender     *gosumo.BufferedSender
+	flushLevel zerolog.Level
+}
+
+// Option configures a Writer when it is created with NewWriter.
+type Option func(*Writer)
+
+// WithFlushLevel sets the level at or above which events are flushed
+// immediately. The default is zerolog.ErrorLevel, and zerolog.Disabled turns
+// immediate flushing off.
+func WithFlushLevel(level zerolog.Level) Option {
+	return func(w *Writer) {
+		w.flushLevel = level
+	}
+}
+
+// NewWriter creates and returns a new Writer that queues events on the
+// provided BufferedSender. The BufferedSender must be closed by the caller
+// when logging is finished.
+func NewWriter(s *gosumo.BufferedSender, opts ...Option) *Writer {
+	w := &Writer{
+		sender:     s,
+		flushLevel: zerolog.ErrorLevel,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Write queues each JSON line in p to be posted.
+func (w *Writer) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if len(line) == 0 {
+			continue
+		}
+		if err := w.sender.Enqueue(line); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// WriteLevel queues the event like Write, and flushes the BufferedSender if
+// the level is at or above the flush level.
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	n, err := w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	if w.flushLevel != zerolog.Disabled && level >= w.flushLevel && level != zerolog.NoLevel {
+		if err := w.sender.Flush(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Flush posts all pending events and waits for the requests to complete.
+func (w *Writer) Flush() error {
+	return w.sender.Flush()
+}
@@ -0,0 +1,115 @@
+package sumozerolog
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/byitkc/gosumo"
+	"github.com/byitkc/gosumo/gosumotest"
+	"github.com/rs/zerolog"
+)
+
+// newBufferedSender returns a BufferedSender sending to fake that only
+// flushes when asked to.
+func newBufferedSender(t *testing.T, fake *gosumotest.FakeSender) *gosumo.BufferedSender {
+	t.Helper()
+	s, err := gosumo.NewBufferedSender(fake, gosumo.WithFlushInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestWriter(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	w := NewWriter(newBufferedSender(t, fake))
+	logger := zerolog.New(w).Level(zerolog.InfoLevel)
+
+	logger.Debug().Msg("dropped")
+	logger.Info().Int("port", 8080).Msg("started")
+	logger.Warn().Dict("http", zerolog.Dict().Int("status", 200)).Str("request_id", "abc").Msg("slow request")
+	if len(fake.Payloads()) != 0 {
+		t.Fatal("events below the flush level were posted before Flush")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := fake.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("posted %d events, want 2: %q", len(lines), lines)
+	}
+	var warn map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &warn); err != nil {
+		t.Fatal(err)
+	}
+	http, _ := warn["http"].(map[string]any)
+	if warn["level"] != "warn" || warn["message"] != "slow request" || warn["request_id"] != "abc" || http["status"] != 200.0 {
+		t.Errorf("event = %v, want the warn event with its fields", warn)
+	}
+}
+
+func TestWriterFlushLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		level     zerolog.Level
+		wantFlush bool
+	}{
+		{"default below", nil, zerolog.WarnLevel, false},
+		{"default at", nil, zerolog.ErrorLevel, true},
+		{"default above", nil, zerolog.FatalLevel, true},
+		{"custom", []Option{WithFlushLevel(zerolog.InfoLevel)}, zerolog.InfoLevel, true},
+		{"disabled", []Option{WithFlushLevel(zerolog.Disabled)}, zerolog.PanicLevel, false},
+		{"no level", []Option{WithFlushLevel(zerolog.TraceLevel)}, zerolog.NoLevel, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := gosumotest.NewFakeSender()
+			w := NewWriter(newBufferedSender(t, fake), tt.opts...)
+			if _, err := w.WriteLevel(tt.level, []byte(`{"message":"hi"}`+"\n")); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(fake.Payloads()) == 1; got != tt.wantFlush {
+				t.Errorf("flushed = %v, want %v", got, tt.wantFlush)
+			}
+		})
+	}
+}
+
+func TestWriterLines(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	w := NewWriter(newBufferedSender(t, fake))
+	// Every line of a write is queued, and empty lines are skipped.
+	p := "{\"a\":1}\r\n\n{\"b\":2}\n"
+	if n, err := w.Write([]byte(p)); err != nil || n != len(p) {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`{"a":1}`, `{"b":2}`}
+	if got := fake.Lines(); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	fake := gosumotest.NewFakeSender()
+	s := newBufferedSender(t, fake)
+	w := NewWriter(s)
+	sendErr := errors.New("unavailable")
+	fake.SetError(sendErr)
+	if _, err := w.WriteLevel(zerolog.ErrorLevel, []byte(`{"message":"failed"}`)); !errors.Is(err, sendErr) {
+		t.Errorf("WriteLevel = %v, want the send error", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(`{"message":"late"}`)); !errors.Is(err, gosumo.ErrSenderClosed) {
+		t.Errorf("Write after Close = %v, want ErrSenderClosed", err)
+	}
+}
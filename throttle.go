@@ -0,0 +1,141 @@
+package gosumo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ThrottleConfig controls how the Client slows down when Sumo Logic responds
+// with 429 Too Many Requests.
+type ThrottleConfig struct {
+	// InitialDelay is the delay added between requests after the first 429.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between requests. Each further 429 doubles the
+	// delay up to this limit.
+	MaxDelay time.Duration
+	// CoolDown is how long the Client must go without a 429 before the delay
+	// is removed and the normal send rate resumes.
+	CoolDown time.Duration
+}
+
+// DefaultThrottleConfig is a reasonable ThrottleConfig for posting to Sumo
+// Logic.
+var DefaultThrottleConfig = ThrottleConfig{
+	InitialDelay: 250 * time.Millisecond,
+	MaxDelay:     10 * time.Second,
+	CoolDown:     time.Minute,
+}
+
+// ThrottleState describes whether the Client is currently slowing down
+// because of throttling by Sumo Logic.
+type ThrottleState struct {
+	// Throttled reports whether requests are currently being delayed.
+	Throttled bool
+	// Delay is the delay currently added between requests.
+	Delay time.Duration
+	// LastThrottled is when the last 429 was received.
+	LastThrottled time.Time
+	// Until is when the normal send rate resumes if no further 429s are
+	// received.
+	Until time.Time
+}
+
+// WithAdaptiveThrottling makes the Client slow its send rate when Sumo Logic
+// responds with 429, rather than only retrying, and resume the normal rate
+// once the cool-down has passed. The current state is available from
+// Client.ThrottleState.
+func WithAdaptiveThrottling(cfg ThrottleConfig) Option {
+	return func(c *Client) error {
+		if cfg.InitialDelay <= 0 {
+			return fmt.Errorf("initial throttle delay must be greater than zero, got: %s", cfg.InitialDelay)
+		}
+		if cfg.MaxDelay < cfg.InitialDelay {
+			return fmt.Errorf("max throttle delay must be at least the initial delay, got: %s", cfg.MaxDelay)
+		}
+		if cfg.CoolDown <= 0 {
+			return fmt.Errorf("throttle cool-down must be greater than zero, got: %s", cfg.CoolDown)
+		}
+		c.throttle = &throttle{cfg: cfg}
+		return nil
+	}
+}
+
+// ThrottleState returns the current throttle state of the Client. It is
+// always unthrottled unless WithAdaptiveThrottling is used.
+func (c *Client) ThrottleState() ThrottleState {
+	if c.throttle == nil {
+		return ThrottleState{}
+	}
+	return c.throttle.state(time.Now())
+}
+
+// throttle tracks 429 responses and the delay added between requests.
+type throttle struct {
+	cfg ThrottleConfig
+
+	mu            sync.Mutex
+	delay         time.Duration
+	lastThrottled time.Time
+	next          time.Time
+}
+
+// state returns the throttle state at now.
+func (t *throttle) state(now time.Time) ThrottleState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expire(now)
+	if t.delay == 0 {
+		return ThrottleState{LastThrottled: t.lastThrottled}
+	}
+	return ThrottleState{
+		Throttled:     true,
+		Delay:         t.delay,
+		LastThrottled: t.lastThrottled,
+		Until:         t.lastThrottled.Add(t.cfg.CoolDown),
+	}
+}
+
+// expire removes the delay if the cool-down has passed. It must be called
+// with the lock held.
+func (t *throttle) expire(now time.Time) {
+	if t.delay > 0 && now.Sub(t.lastThrottled) >= t.cfg.CoolDown {
+		t.delay = 0
+	}
+}
+
+// throttled records a 429 response, increasing the delay between requests.
+// The server's Retry-After is used as the delay if it is longer.
+func (t *throttle) throttled(now time.Time, retryAfter time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expire(now)
+	if t.delay == 0 {
+		t.delay = t.cfg.InitialDelay
+	} else {
+		t.delay = min(t.delay*2, t.cfg.MaxDelay)
+	}
+	t.delay = max(t.delay, min(retryAfter, t.cfg.MaxDelay))
+	t.lastThrottled = now
+	t.next = now.Add(t.delay)
+}
+
+// wait blocks until the next request is allowed, spacing requests by the
+// current delay.
+func (t *throttle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	now := time.Now()
+	t.expire(now)
+	if t.delay == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	start := now
+	if t.next.After(now) {
+		start = t.next
+	}
+	t.next = start.Add(t.delay)
+	t.mu.Unlock()
+	return sleep(ctx, start.Sub(now))
+}
@@ -0,0 +1,54 @@
+package gosumo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottle(t *testing.T) {
+	start := time.Now()
+	th := &throttle{cfg: ThrottleConfig{InitialDelay: time.Second, MaxDelay: 5 * time.Second, CoolDown: time.Minute}}
+	steps := []struct {
+		at         time.Duration
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{0, 0, time.Second},
+		{time.Second, 0, 2 * time.Second},
+		{2 * time.Second, 0, 4 * time.Second},
+		// The delay doubles up to the maximum.
+		{3 * time.Second, 0, 5 * time.Second},
+		// A longer Retry-After is used, up to the maximum too.
+		{4 * time.Second, time.Hour, 5 * time.Second},
+	}
+	for _, step := range steps {
+		th.throttled(start.Add(step.at), step.retryAfter)
+		if state := th.state(start.Add(step.at)); !state.Throttled || state.Delay != step.want {
+			t.Fatalf("after a 429 at %s: state = %+v, want a delay of %s", step.at, state, step.want)
+		}
+	}
+	last := start.Add(4 * time.Second)
+	if state := th.state(last.Add(time.Minute - time.Nanosecond)); !state.Throttled || !state.Until.Equal(last.Add(time.Minute)) {
+		t.Errorf("state before the cool-down = %+v", state)
+	}
+	if state := th.state(last.Add(time.Minute)); state.Throttled || state.Delay != 0 || !state.LastThrottled.Equal(last) {
+		t.Errorf("state after the cool-down = %+v, want unthrottled", state)
+	}
+	// After the cool-down a 429 starts from the initial delay again.
+	th.throttled(last.Add(2*time.Minute), 3*time.Second)
+	if state := th.state(last.Add(2 * time.Minute)); state.Delay != 3*time.Second {
+		t.Errorf("delay after the cool-down = %s, want the Retry-After of 3s", state.Delay)
+	}
+}
+
+func TestWithAdaptiveThrottlingValidation(t *testing.T) {
+	for _, cfg := range []ThrottleConfig{
+		{MaxDelay: time.Second, CoolDown: time.Second},
+		{InitialDelay: time.Second, MaxDelay: time.Millisecond, CoolDown: time.Second},
+		{InitialDelay: time.Second, MaxDelay: time.Second},
+	} {
+		if _, err := NewClient("https://collectors.sumologic.com/receiver/v1/http/token", WithAdaptiveThrottling(cfg)); err == nil {
+			t.Errorf("WithAdaptiveThrottling(%+v) succeeded, want an error", cfg)
+		}
+	}
+}
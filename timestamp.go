@@ -0,0 +1,87 @@
+package gosumo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// TimestampFormat is the format of a timestamp injected into JSON logs.
+type TimestampFormat int
+
+const (
+	// TimestampEpochMillis writes the timestamp as a number of milliseconds
+	// since the Unix epoch.
+	TimestampEpochMillis TimestampFormat = iota
+	// TimestampRFC3339 writes the timestamp as an RFC 3339 string with
+	// millisecond precision.
+	TimestampRFC3339
+)
+
+// timestampConfig controls timestamp injection.
+type timestampConfig struct {
+	field  string
+	format TimestampFormat
+}
+
+// WithTimestampField makes the Client add a timestamp field with the given
+// name to every JSON object log that does not already have one. Without a
+// timestamp in the log Sumo Logic uses the time the log was received, which
+// skews searches when logs are buffered or retried. Logs that are not JSON
+// objects are left as-is.
+func WithTimestampField(field string, format TimestampFormat) Option {
+	return func(c *Client) error {
+		if field == "" {
+			return fmt.Errorf("timestamp field cannot be empty")
+		}
+		switch format {
+		case TimestampEpochMillis, TimestampRFC3339:
+		default:
+			return fmt.Errorf("unsupported timestamp format: %d", format)
+		}
+		c.timestamp = &timestampConfig{field: field, format: format}
+		return nil
+	}
+}
+
+// stamp returns the line with the timestamp field added, if it is a JSON
+// object without the field.
+func (t *timestampConfig) stamp(line []byte, now time.Time) []byte {
+	var value []byte
+	switch t.format {
+	case TimestampRFC3339:
+		value = strconv.AppendQuote(nil, now.UTC().Format("2006-01-02T15:04:05.000Z07:00"))
+	default:
+		value = strconv.AppendInt(nil, now.UnixMilli(), 10)
+	}
+	return addJSONField(line, t.field, value)
+}
+
+// addJSONField returns the line with the field added at the start, if it is
+// a JSON object that does not already have the field. Any other line is
+// returned as-is. The value must already be JSON encoded.
+func addJSONField(line []byte, field string, value []byte) []byte {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) < 2 || trimmed[0] != '{' {
+		return line
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &fields); err != nil {
+		return line
+	}
+	if _, ok := fields[field]; ok {
+		return line
+	}
+	key, _ := json.Marshal(field)
+	out := make([]byte, 0, len(trimmed)+len(key)+len(value)+2)
+	out = append(out, '{')
+	out = append(out, key...)
+	out = append(out, ':')
+	out = append(out, value...)
+	if len(fields) > 0 {
+		out = append(out, ',')
+	}
+	return append(out, trimmed[1:]...)
+}
@@ -0,0 +1,73 @@
+package gosumo
+
+import (
+	"context"
+	"fmt"
+)
+
+// TraceFormat is the encoding of an OTLP trace payload.
+type TraceFormat int
+
+const (
+	// TraceFormatProtobuf is the binary protobuf encoding of an OTLP
+	// ExportTraceServiceRequest.
+	TraceFormatProtobuf TraceFormat = iota
+	// TraceFormatJSON is the JSON encoding of an OTLP
+	// ExportTraceServiceRequest.
+	TraceFormatJSON
+)
+
+// Content types used to post OTLP traces.
+const (
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeJSON     = "application/json"
+)
+
+// contentType returns the Content-Type header value for the format.
+func (f TraceFormat) contentType() (string, error) {
+	switch f {
+	case TraceFormatProtobuf:
+		return ContentTypeProtobuf, nil
+	case TraceFormatJSON:
+		return ContentTypeJSON, nil
+	}
+	return "", fmt.Errorf("unsupported trace format: %d", f)
+}
+
+// TraceEndpoint posts OTLP/HTTP trace payloads to a Sumo Logic traces source.
+// The endpoint URL is the source URL including the /v1/traces path. It uses a
+// Client so traces share the same transport, retry and compression settings
+// as logs. Payloads are never split into batches.
+type TraceEndpoint struct {
+	client *Client
+}
+
+// NewTraceEndpoint creates and returns a new TraceEndpoint that posts to the
+// provided endpoint URL. The options are the same as for NewClient.
+func NewTraceEndpoint(endpointURL string, opts ...Option) (*TraceEndpoint, error) {
+	c, err := NewClient(endpointURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &TraceEndpoint{client: c}, nil
+}
+
+// Client returns the Client used by the TraceEndpoint.
+func (t *TraceEndpoint) Client() *Client {
+	return t.client
+}
+
+// PostTraces will post an encoded OTLP ExportTraceServiceRequest. It will
+// return an error if there are problems posting the payload.
+func (t *TraceEndpoint) PostTraces(ctx context.Context, payload []byte, format TraceFormat, opts ...PostOption) error {
+	contentType, err := format.contentType()
+	if err != nil {
+		return ErrParsingLogs{Message: err.Error(), Err: err}
+	}
+	cfg, err := t.client.newPostConfig(opts)
+	if err != nil {
+		return err
+	}
+	cfg.contentType = contentType
+	return t.client.post(ctx, payload, cfg)
+}
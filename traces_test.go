@@ -0,0 +1,68 @@
+package gosumo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/byitkc/gosumo"
+)
+
+func TestPostTraces(t *testing.T) {
+	tests := []struct {
+		name   string
+		format gosumo.TraceFormat
+		want   string
+	}{
+		{"protobuf", gosumo.TraceFormatProtobuf, gosumo.ContentTypeProtobuf},
+		{"json", gosumo.TraceFormatJSON, gosumo.ContentTypeJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := newCollector(t)
+			e, err := gosumo.NewTraceEndpoint(col.URL(), gosumo.WithInsecureURL(), gosumo.WithCompression(gosumo.Gzip), gosumo.WithMinCompressionSize(0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The payload is posted as it is, even when it contains
+			// newlines.
+			payload := []byte("{\"resourceSpans\": [\n]}\n")
+			if err := e.PostTraces(context.Background(), payload, tt.format, gosumo.PostWithSourceCategory("traces/test")); err != nil {
+				t.Fatal(err)
+			}
+			reqs := col.Requests()
+			if len(reqs) != 1 {
+				t.Fatalf("collector received %d requests, want 1", len(reqs))
+			}
+			r := reqs[0]
+			if r.ContentType != tt.want {
+				t.Errorf("Content-Type = %q, want %q", r.ContentType, tt.want)
+			}
+			if string(r.Body) != string(payload) {
+				t.Errorf("body = %q, want %q", r.Body, payload)
+			}
+			if r.ContentEncoding != "gzip" {
+				t.Errorf("Content-Encoding = %q, want the client compression", r.ContentEncoding)
+			}
+			if r.SourceCategory != "traces/test" {
+				t.Errorf("source category = %q", r.SourceCategory)
+			}
+		})
+	}
+}
+
+func TestPostTracesUnsupportedFormat(t *testing.T) {
+	col := newCollector(t)
+	e, err := gosumo.NewTraceEndpoint(col.URL(), gosumo.WithInsecureURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = e.PostTraces(context.Background(), []byte("{}"), gosumo.TraceFormat(7), gosumo.PostWithSourceCategory("traces/test"))
+	var parseErr gosumo.ErrParsingLogs
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("PostTraces = %v, want ErrParsingLogs", err)
+	}
+	if n := len(col.Requests()); n != 0 {
+		t.Errorf("collector received %d requests, want none", n)
+	}
+}
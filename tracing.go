@@ -0,0 +1,66 @@
+package gosumo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation name used for the Client's spans.
+const tracerName = "github.com/byitkc/gosumo"
+
+// postResult records the outcome of a post for instrumentation.
+type postResult struct {
+	attempts   int
+	statusCode int
+	bytes      int
+}
+
+// WithTracerProvider makes the Client create an OpenTelemetry span for every
+// post, covering all of its attempts. The span records the payload size, the
+// number of logs in batches of logs, the final status code and the number of
+// retries, to help debug ingestion latency. Errors are recorded with the
+// endpoint URL redacted.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Client) error {
+		if tp == nil {
+			return fmt.Errorf("tracer provider cannot be nil")
+		}
+		c.tracer = tp.Tracer(tracerName)
+		return nil
+	}
+}
+
+// tracedSend wraps send in a span when tracing is enabled.
+func (c *Client) tracedSend(ctx context.Context, payload []byte, cfg postConfig) error {
+	var res postResult
+	if c.tracer == nil {
+		return c.send(ctx, payload, cfg, &res)
+	}
+	ctx, span := c.tracer.Start(ctx, "gosumo.post", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+	span.SetAttributes(attribute.Int("gosumo.batch.bytes", len(payload)))
+	if cfg.logs > 0 {
+		span.SetAttributes(attribute.Int("gosumo.batch.logs", cfg.logs))
+	}
+	err := c.send(ctx, payload, cfg, &res)
+	span.SetAttributes(
+		attribute.Int("gosumo.request.bytes", res.bytes),
+		attribute.Int("gosumo.retries", max(res.attempts-1, 0)),
+	)
+	if res.statusCode != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", res.statusCode))
+	}
+	if err != nil {
+		// The error of a failed request contains the endpoint URL, which
+		// must not end up in the tracing backend.
+		msg := redactError(err, c.endpoint.URL)
+		span.RecordError(errors.New(msg))
+		span.SetStatus(codes.Error, msg)
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package gosumo
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spanAttr returns the value of the attribute of the span, if it is set.
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTracingBatchLogs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	c, err := NewClient(srv.URL, WithInsecureURL(), WithTracerProvider(tp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostLogsString("one\ntwo\nthree"); err != nil {
+		t.Fatal(err)
+	}
+	te, err := NewTraceEndpoint(srv.URL, WithInsecureURL(), WithTracerProvider(tp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Protobuf payloads can contain newlines, which must not be counted as
+	// logs.
+	if err := te.PostTraces(context.Background(), []byte("\n\x0a\n"), TraceFormatProtobuf); err != nil {
+		t.Fatal(err)
+	}
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	if v, ok := spanAttr(spans[0], "gosumo.batch.logs"); !ok || v.AsInt64() != 3 {
+		t.Errorf("logs span has gosumo.batch.logs = %v, want 3", v.Emit())
+	}
+	if v, ok := spanAttr(spans[1], "gosumo.batch.logs"); ok {
+		t.Errorf("traces span has gosumo.batch.logs = %v, want it unset", v.Emit())
+	}
+}
+
+func TestTracingRedactsErrors(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	c, err := NewClient("http://"+addr+"/receiver/v1/http/SECRETTOKEN", WithInsecureURL(), WithTracerProvider(tp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostLogsString("line"); err == nil {
+		t.Fatal("PostLogsString succeeded, want a connection error")
+	}
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	status := spans[0].Status()
+	if status.Code != codes.Error || !strings.Contains(status.Description, "/receiver/v1/http/REDACTED") {
+		t.Errorf("span status = %+v, want an error with the redacted URL", status)
+	}
+	for _, event := range spans[0].Events() {
+		for _, kv := range event.Attributes {
+			if strings.Contains(kv.Value.Emit(), "SECRETTOKEN") {
+				t.Errorf("span event %s has %s = %s, which contains the token", event.Name, kv.Key, kv.Value.Emit())
+			}
+		}
+	}
+	if strings.Contains(status.Description, "SECRETTOKEN") {
+		t.Errorf("span status %q contains the token", status.Description)
+	}
+}
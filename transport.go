@@ -0,0 +1,166 @@
+package gosumo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// transportConfig holds the proxy and TLS settings of a Client. They are
+// applied to a copy of the http.Client's transport when the Client is built.
+type transportConfig struct {
+	proxy      func(*http.Request) (*url.URL, error)
+	rootCAs    *x509.CertPool
+	certs      []tls.Certificate
+	minVersion uint16
+}
+
+// transportOption returns an Option that changes the Client's transport
+// settings.
+func transportOption(fn func(t *transportConfig) error) Option {
+	return func(c *Client) error {
+		if c.transport == nil {
+			c.transport = &transportConfig{}
+		}
+		return fn(c.transport)
+	}
+}
+
+// WithProxy makes the Client connect to Sumo Logic through the HTTP, HTTPS or
+// SOCKS5 proxy at the provided URL, for environments that can only reach the
+// internet through an egress proxy. Credentials can be included in the URL.
+func WithProxy(proxyURL string) Option {
+	return transportOption(func(t *transportConfig) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url: %w", err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("proxy url scheme must be http, https or socks5, got: %q", u.Scheme)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("proxy url must have a host, got: %q", proxyURL)
+		}
+		t.proxy = http.ProxyURL(u)
+		return nil
+	})
+}
+
+// WithProxyFromEnvironment makes the Client use the proxy configured by the
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables. This is already
+// the behaviour of http.DefaultTransport, so it is only needed when a custom
+// http.Client is provided with WithHTTPClient.
+func WithProxyFromEnvironment() Option {
+	return transportOption(func(t *transportConfig) error {
+		t.proxy = http.ProxyFromEnvironment
+		return nil
+	})
+}
+
+// WithCABundle adds the PEM encoded certificates to the certificate
+// authorities trusted by the Client, on top of the system ones, so the
+// certificate of a proxy that intercepts TLS can be verified.
+func WithCABundle(pem []byte) Option {
+	return transportOption(func(t *transportConfig) error {
+		if t.rootCAs == nil {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			t.rootCAs = pool
+		}
+		if !t.rootCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in ca bundle")
+		}
+		return nil
+	})
+}
+
+// WithCAFile is like WithCABundle but reads the PEM encoded certificates from
+// the file at path.
+func WithCAFile(path string) Option {
+	return func(c *Client) error {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading ca file: %w", err)
+		}
+		return WithCABundle(pem)(c)
+	}
+}
+
+// WithClientCertificate makes the Client present the certificate when a
+// server or proxy requests one, for environments that use mutual TLS.
+func WithClientCertificate(cert tls.Certificate) Option {
+	return transportOption(func(t *transportConfig) error {
+		if len(cert.Certificate) == 0 {
+			return fmt.Errorf("client certificate cannot be empty")
+		}
+		t.certs = append(t.certs, cert)
+		return nil
+	})
+}
+
+// WithClientCertificateFile is like WithClientCertificate but loads the PEM
+// encoded certificate and private key from the provided files.
+func WithClientCertificateFile(certFile, keyFile string) Option {
+	return func(c *Client) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("error loading client certificate: %w", err)
+		}
+		return WithClientCertificate(cert)(c)
+	}
+}
+
+// WithTLSMinVersion sets the minimum TLS version the Client will accept, such
+// as tls.VersionTLS12.
+func WithTLSMinVersion(version uint16) Option {
+	return transportOption(func(t *transportConfig) error {
+		if version < tls.VersionTLS10 || version > tls.VersionTLS13 {
+			return fmt.Errorf("unsupported tls version: %#04x", version)
+		}
+		t.minVersion = version
+		return nil
+	})
+}
+
+// apply returns a copy of the http.Client with the transport settings applied
+// to a clone of its transport, so a client shared with the rest of the
+// application is not modified. The transport must be an *http.Transport.
+func (t *transportConfig) apply(hc *http.Client) (*http.Client, error) {
+	var base *http.Transport
+	switch rt := hc.Transport.(type) {
+	case nil:
+		base = http.DefaultTransport.(*http.Transport)
+	case *http.Transport:
+		base = rt
+	default:
+		return nil, fmt.Errorf("proxy and tls options require an *http.Transport, got: %T", hc.Transport)
+	}
+	tr := base.Clone()
+	if t.proxy != nil {
+		tr.Proxy = t.proxy
+	}
+	if t.rootCAs != nil || len(t.certs) > 0 || t.minVersion != 0 {
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{}
+		}
+		if t.rootCAs != nil {
+			tr.TLSClientConfig.RootCAs = t.rootCAs
+		}
+		if len(t.certs) > 0 {
+			tr.TLSClientConfig.Certificates = append(tr.TLSClientConfig.Certificates, t.certs...)
+		}
+		if t.minVersion != 0 {
+			tr.TLSClientConfig.MinVersion = t.minVersion
+		}
+	}
+	copied := *hc
+	copied.Transport = tr
+	return &copied, nil
+}
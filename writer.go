@@ -0,0 +1,103 @@
+package gosumo
+
+import (
+	"bytes"
+	"context"
+	"sync"
+)
+
+// Writer is an io.WriteCloser that ships newline delimited writes to Sumo
+// Logic. Each complete line is buffered with a BufferedSender and posted in
+// the background, so a Writer can be passed to log.SetOutput or any library
+// that expects an io.Writer.
+type Writer struct {
+	sender *BufferedSender
+
+	// maxPartial is the most data without a newline that is held before it
+	// is queued as a log on its own.
+	maxPartial int
+
+	mu      sync.Mutex
+	partial []byte
+}
+
+// NewWriter creates and returns a new Writer that posts with the provided
+// LogSender, usually a Client. The BufferOptions configure the underlying
+// BufferedSender. Close must be called to post any remaining logs.
+func NewWriter(sender LogSender, opts ...BufferOption) (*Writer, error) {
+	s, err := NewBufferedSender(sender, opts...)
+	if err != nil {
+		return nil, err
+	}
+	w := &Writer{sender: s, maxPartial: DefaultMaxBatchBytes}
+	if c, ok := sender.(*Client); ok && c.maxBatchBytes > 0 {
+		w.maxPartial = c.maxBatchBytes
+	}
+	return w, nil
+}
+
+// Write buffers p and queues each complete line to be posted. Any trailing
+// data without a newline is held until the next Write or Close, unless it
+// grows past the maximum batch size of the Client, in which case it is
+// queued as a log of its own. If queueing a line fails, Write returns the
+// number of bytes of p that were queued before it.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := 0
+	for {
+		i := bytes.IndexByte(p[n:], '\n')
+		if i < 0 {
+			break
+		}
+		line := p[n : n+i]
+		if len(w.partial) > 0 {
+			line = append(w.partial, line...)
+		}
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if len(line) > 0 {
+			if err := w.sender.Enqueue(line); err != nil {
+				return n, err
+			}
+		}
+		w.partial = nil
+		n += i + 1
+	}
+	rest := p[n:]
+	if len(w.partial)+len(rest) > w.maxPartial {
+		if err := w.sender.Enqueue(append(w.partial, rest...)); err != nil {
+			return n, err
+		}
+		w.partial = nil
+		return len(p), nil
+	}
+	w.partial = append(w.partial, rest...)
+	return len(p), nil
+}
+
+// Flush posts all complete lines that have been written. Partial lines are
+// held until they are completed or the Writer is closed.
+func (w *Writer) Flush() error {
+	return w.sender.Flush()
+}
+
+// Close queues any partial line, stops the background flushing and posts all
+// remaining logs.
+func (w *Writer) Close() error {
+	return w.CloseContext(context.Background())
+}
+
+// CloseContext is like Close but uses the provided context for the final
+// flush.
+func (w *Writer) CloseContext(ctx context.Context) error {
+	w.mu.Lock()
+	partial := w.partial
+	w.partial = nil
+	w.mu.Unlock()
+	if len(partial) > 0 {
+		if err := w.sender.Enqueue(partial); err != nil {
+			return err
+		}
+	}
+	return w.sender.CloseContext(ctx)
+}
@@ -0,0 +1,110 @@
+package gosumo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lineServer is a collector that records the lines of the payloads posted to
+// it.
+type lineServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	lines []string
+}
+
+func newLineServer(t *testing.T) *lineServer {
+	s := &lineServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.lines = append(s.lines, strings.Split(string(body), "\n")...)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *lineServer) Lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.lines)
+}
+
+func TestWriter(t *testing.T) {
+	srv := newLineServer(t)
+	c, err := NewClient(srv.URL, WithInsecureURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWriter(c, WithFlushInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"one\r\ntw", "o\n", "\n\nthr", "ee\nfour"} {
+		if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v", s, n, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := srv.Lines(), []string{"one", "two", "three", "four"}; !slices.Equal(got, want) {
+		t.Errorf("posted %q, want %q", got, want)
+	}
+}
+
+func TestWriterReturnsBytesQueued(t *testing.T) {
+	sender := funcSender(func(ctx context.Context, payload []byte) error { return nil })
+	w, err := NewWriter(sender, WithFlushInterval(time.Hour), WithMaxBufferedLogs(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if n, err := w.Write([]byte("a")); n != 1 || err != nil {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	// "ab" and "c" fill the buffer, so "d" is rejected after the first four
+	// bytes of the write were queued.
+	n, err := w.Write([]byte("b\nc\nd\n"))
+	if !errors.Is(err, ErrBufferFull) {
+		t.Fatalf("Write error = %v, want ErrBufferFull", err)
+	}
+	if n != 4 {
+		t.Errorf("Write = %d, want 4", n)
+	}
+}
+
+func TestWriterCapsPartialLine(t *testing.T) {
+	srv := newLineServer(t)
+	c, err := NewClient(srv.URL, WithInsecureURL(), WithMaxBatchBytes(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWriter(c, WithFlushInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"0123", "4567", "89", "ab\n"} {
+		if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v", s, n, err)
+		}
+	}
+	if n := len(w.partial); n != 0 {
+		t.Errorf("holding %d bytes of a partial line, want 0", n)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := srv.Lines(), []string{"0123456789", "ab"}; !slices.Equal(got, want) {
+		t.Errorf("posted %q, want %q", got, want)
+	}
+}